@@ -0,0 +1,183 @@
+package main
+
+import (
+  "encoding/json"
+  "io/ioutil"
+  "os"
+  "sort"
+  "sync"
+
+	"github.com/computeportal/wtsuite/pkg/files"
+)
+
+const (
+  CRAWL_STATE_FILE = ".wt-crawl-state.json" // inside the output dir
+  SAVE_INTERVAL = 20 // number of pages between saves
+)
+
+type CrawlStatePage struct {
+  Done   bool `json:"done"`
+  Status int  `json:"status,omitempty"` // http status, 0 if read from disc, or if the request failed
+}
+
+func (c *CrawlStatePage) SetDone(status int) {
+  c.Done = true
+  c.Status = status
+}
+
+func (c *CrawlStatePage) IsDone() bool {
+  return c.Done
+}
+
+func (c *CrawlStatePage) IsPending() bool {
+  return !c.Done
+}
+
+func NewCrawlStatePage() *CrawlStatePage {
+  return &CrawlStatePage{false, 0}
+}
+
+// persisted so that an interrupted crawl can be resumed
+type CrawlState struct {
+  Pages map[string]*CrawlStatePage `json:"pages"` // key is the normalized url
+  path  string
+  lock  *sync.RWMutex
+  saveLock *sync.Mutex // workers can trigger a save concurrently
+  unsaved int
+}
+
+func NewCrawlState(path string) *CrawlState {
+  return &CrawlState{make(map[string]*CrawlStatePage), path, &sync.RWMutex{}, &sync.Mutex{}, 0}
+}
+
+// returns a new state if path doesn't exist yet
+func LoadCrawlState(path string) (*CrawlState, bool, error) {
+  cs := NewCrawlState(path)
+
+  if !files.IsFile(path) {
+    return cs, false, nil
+  }
+
+  b, err := ioutil.ReadFile(path)
+  if err != nil {
+    return nil, false, err
+  }
+
+  if err := json.Unmarshal(b, cs); err != nil {
+    return nil, false, err
+  }
+
+  return cs, true, nil
+}
+
+func (cs *CrawlState) HasPending() bool {
+  cs.lock.RLock()
+
+  defer cs.lock.RUnlock()
+
+  for _, p := range cs.Pages {
+    if p.IsPending() {
+      return true
+    }
+  }
+
+  return false
+}
+
+func (cs *CrawlState) GetAllPending() []string {
+  cs.lock.RLock()
+
+  res := []string{}
+
+  for url, p := range cs.Pages {
+    if p.IsPending() {
+      res = append(res, url)
+    }
+  }
+
+  cs.lock.RUnlock()
+
+  sort.Strings(res)
+
+  return res
+}
+
+// returns false if the url was already known
+func (cs *CrawlState) AddPending(url string) bool {
+  cs.lock.Lock()
+
+  defer cs.lock.Unlock()
+
+  if _, ok := cs.Pages[url]; !ok {
+    cs.Pages[url] = NewCrawlStatePage()
+    return true
+  }
+
+  return false
+}
+
+func (cs *CrawlState) SetDone(url string, status int) error {
+  cs.lock.Lock()
+
+  cs.Pages[url].SetDone(status)
+
+  cs.unsaved += 1
+  save := cs.unsaved >= SAVE_INTERVAL
+
+  cs.lock.Unlock()
+
+  if save {
+    return cs.Save()
+  }
+
+  return nil
+}
+
+func (cs *CrawlState) Len() int {
+  cs.lock.RLock()
+
+  defer cs.lock.RUnlock()
+
+  return len(cs.Pages)
+}
+
+func (cs *CrawlState) CountDone() int {
+  cs.lock.RLock()
+
+  count := 0
+
+  for _, pageState := range cs.Pages {
+    if pageState.IsDone() {
+      count += 1
+    }
+  }
+
+  cs.lock.RUnlock()
+
+  return count
+}
+
+// written to a temporary file first, so an interrupt can't leave a corrupt state behind
+func (cs *CrawlState) Save() error {
+  cs.saveLock.Lock()
+
+  defer cs.saveLock.Unlock()
+
+  cs.lock.Lock()
+
+  b, err := json.Marshal(cs)
+  cs.unsaved = 0
+
+  cs.lock.Unlock()
+
+  if err != nil {
+    return err
+  }
+
+  tmp := cs.path + ".tmp"
+  if err := ioutil.WriteFile(tmp, b, 0644); err != nil {
+    return err
+  }
+
+  return os.Rename(tmp, cs.path)
+}
@@ -0,0 +1,539 @@
+package main
+
+import (
+  "errors"
+  "fmt"
+  "io/ioutil"
+  "net/http"
+  "net/url"
+  "os"
+  "path/filepath"
+  "strings"
+  "sync"
+  "time"
+
+	"github.com/computeportal/wtsuite/pkg/files"
+	"github.com/computeportal/wtsuite/pkg/parsers"
+	tokens "github.com/computeportal/wtsuite/pkg/tokens/html"
+	"github.com/computeportal/wtsuite/pkg/tree"
+)
+
+// only pages below root are crawled, and saved in dstDir
+// the http.Client can be swapped (eg. for httptest.Server.Client())
+type Crawler struct {
+  root      *url.URL // normalized
+  rootPath  string   // without trailing slash
+  dstDir    string
+  nWorkers  int
+  userAgent string
+  delay     time.Duration // minimum time between requests to the same host
+  client    *http.Client
+  state     *CrawlState
+  resumed   bool
+
+  lock        *sync.Mutex
+  robots      map[string]*Robots // key is scheme://host
+  lastRequest map[string]time.Time
+}
+
+func NewCrawler(rootURL string, dstDir string, nWorkers int, userAgent string, delay time.Duration, client *http.Client, fresh bool) (*Crawler, error) {
+  root_, err := url.Parse(rootURL)
+  if err != nil {
+    return nil, err
+  }
+
+  if root_.Scheme != "http" && root_.Scheme != "https" {
+    return nil, errors.New("Error: root url must be http or https")
+  }
+
+  root := normalizeURL(root_)
+
+  var state *CrawlState = nil
+  resumed := false
+  statePath := filepath.Join(dstDir, CRAWL_STATE_FILE)
+  if fresh {
+    state = NewCrawlState(statePath)
+  } else {
+    state, resumed, err = LoadCrawlState(statePath)
+    if err != nil {
+      return nil, errors.New("Error: bad crawl state " + statePath + " (" + err.Error() + ")")
+    }
+  }
+
+  return &Crawler{
+    root,
+    strings.TrimRight(root.Path, "/"),
+    dstDir,
+    nWorkers,
+    userAgent,
+    delay,
+    client,
+    state,
+    resumed,
+    &sync.Mutex{},
+    make(map[string]*Robots),
+    make(map[string]time.Time),
+  }, nil
+}
+
+// fragments and queries are dropped, because the result is saved as static files
+func normalizeURL(u *url.URL) *url.URL {
+  n := *u
+
+  n.Scheme = strings.ToLower(n.Scheme)
+  n.Host = strings.ToLower(n.Host)
+  if (n.Scheme == "http" && strings.HasSuffix(n.Host, ":80")) || (n.Scheme == "https" && strings.HasSuffix(n.Host, ":443")) {
+    n.Host = n.Host[0:strings.LastIndex(n.Host, ":")]
+  }
+
+  n.User = nil
+  n.RawQuery = ""
+  n.ForceQuery = false
+  n.Fragment = ""
+  n.RawFragment = ""
+
+  if n.Path == "" {
+    n.Path = "/"
+    n.RawPath = ""
+  }
+
+  return &n
+}
+
+func hostKey(u *url.URL) string {
+  return u.Scheme + "://" + u.Host
+}
+
+func (c *Crawler) inScope(u *url.URL) bool {
+  return hostKey(u) == hostKey(c.root) && (c.rootPath == "" || u.Path == c.rootPath || strings.HasPrefix(u.Path, c.rootPath + "/"))
+}
+
+func (c *Crawler) urlToPath(u *url.URL) string {
+  path := filepath.Join(c.dstDir, filepath.FromSlash(strings.TrimPrefix(u.Path, c.rootPath)))
+
+  if filepath.Ext(path) != ".html" {
+    path = filepath.Join(path, "index.html")
+  }
+
+  return path
+}
+
+func (c *Crawler) hostDelay(host string) time.Duration {
+  delay := c.delay
+  if r, ok := c.robots[host]; ok && r.CrawlDelay() > delay {
+    delay = r.CrawlDelay()
+  }
+
+  return delay
+}
+
+// reserves the next request slot of the host, and sleeps until then
+func (c *Crawler) waitForHost(host string) {
+  c.lock.Lock()
+
+  now := time.Now()
+  next := now
+  if last, ok := c.lastRequest[host]; ok {
+    if t := last.Add(c.hostDelay(host)); t.After(now) {
+      next = t
+    }
+  }
+
+  c.lastRequest[host] = next
+
+  c.lock.Unlock()
+
+  time.Sleep(next.Sub(now))
+}
+
+// the caller must close the body
+func (c *Crawler) get(u *url.URL) (*http.Response, error) {
+  c.waitForHost(hostKey(u))
+
+  req, err := http.NewRequest("GET", u.String(), nil)
+  if err != nil {
+    return nil, err
+  }
+
+  req.Header.Set("User-Agent", c.userAgent)
+
+  return c.client.Do(req)
+}
+
+func (c *Crawler) getRobots(u *url.URL) *Robots {
+  host := hostKey(u)
+
+  c.lock.Lock()
+  r, ok := c.robots[host]
+  c.lock.Unlock()
+
+  if ok {
+    return r
+  }
+
+  robotsURL, err := url.Parse(host + "/robots.txt")
+  if err != nil {
+    panic(err)
+  }
+
+  r = NewPermissiveRobots()
+  resp, err := c.get(robotsURL)
+  if err != nil {
+    fmt.Fprintf(os.Stderr, "Warning: %s unreachable, not crawling %s\n", robotsURL.String(), host)
+    r = NewRestrictiveRobots()
+  } else {
+    defer resp.Body.Close()
+
+    switch {
+    case resp.StatusCode == 200:
+      if b, err := ioutil.ReadAll(resp.Body); err == nil {
+        r = ParseRobots(string(b), c.userAgent)
+      }
+    case resp.StatusCode >= 500:
+      fmt.Fprintf(os.Stderr, "Warning: %s unavailable (%d), not crawling %s\n", robotsURL.String(), resp.StatusCode, host)
+      r = NewRestrictiveRobots()
+    }
+    // other 4xx: no restrictions
+  }
+
+  c.lock.Lock()
+  c.robots[host] = r
+  c.lock.Unlock()
+
+  return r
+}
+
+func (c *Crawler) addPending(u *url.URL) {
+  if !c.inScope(u) {
+    return
+  }
+
+  if c.state.AddPending(u.String()) && VERBOSITY >= 2 {
+    fmt.Fprintf(os.Stdout, "Found %s\n", u.String())
+  }
+}
+
+func (c *Crawler) seedFromSitemap(u *url.URL, depth int, visited map[string]bool) {
+  if depth > MAX_SITEMAP_DEPTH || visited[u.String()] || !c.getRobots(u).Allowed(u.EscapedPath()) {
+    return
+  }
+
+  visited[u.String()] = true
+
+  resp, err := c.get(u)
+  if err != nil {
+    return
+  }
+
+  defer resp.Body.Close()
+
+  if resp.StatusCode != 200 {
+    return
+  }
+
+  b, err := ioutil.ReadAll(resp.Body)
+  if err != nil {
+    return
+  }
+
+  sm, err := ParseSitemap(b)
+  if err != nil {
+    fmt.Fprintf(os.Stderr, "Warning: bad sitemap %s (%s)\n", u.String(), err.Error())
+    return
+  }
+
+  for _, loc := range sm.PageURLs() {
+    if pu, err := u.Parse(loc); err == nil {
+      c.addPending(normalizeURL(pu))
+    }
+  }
+
+  for _, loc := range sm.SitemapURLs() {
+    if su, err := u.Parse(loc); err == nil {
+      c.seedFromSitemap(su, depth + 1, visited)
+    }
+  }
+}
+
+// sitemaps listed in robots.txt, or /sitemap.xml by default
+func (c *Crawler) seed() {
+  c.addPending(c.root)
+
+  sitemaps := c.getRobots(c.root).Sitemaps()
+  if len(sitemaps) == 0 {
+    sitemaps = []string{"/sitemap.xml"}
+  }
+
+  visited := make(map[string]bool)
+  for _, sitemap := range sitemaps {
+    if u, err := c.root.Parse(sitemap); err == nil {
+      c.seedFromSitemap(u, 0, visited)
+    }
+  }
+}
+
+func getAttribute(t tree.Tag, name string) (string, bool) {
+  attr := t.Attributes()
+  if attr == nil {
+    return "", false
+  }
+
+  value := ""
+  found := false
+
+  // attribute names of crawled pages aren't necessarily lowercase
+  attr.Loop(func(key *tokens.String, v tokens.Token, last bool) error {
+    if !found && strings.ToLower(key.Value()) == name {
+      if s, err := tokens.AssertString(v); err == nil {
+        value = s.Value()
+        found = true
+      }
+    }
+
+    return nil
+  })
+
+  return value, found
+}
+
+func hasToken(list string, token string) bool {
+  for _, f := range strings.Fields(strings.ToLower(strings.Replace(list, ",", " ", -1))) {
+    if f == token {
+      return true
+    }
+  }
+
+  return false
+}
+
+// <base href> changes the base url of the whole page, links with rel=nofollow are skipped
+// nil is returned if the page itself is nofollow
+func extractLinks(pageURL *url.URL, rawBytes []byte) ([]*url.URL, error) {
+  p, err := parsers.NewXMLParserFromBytes(rawBytes, pageURL.String())
+  if err != nil {
+    return nil, err
+  }
+
+  rawTags, err := p.BuildTags()
+  if err != nil {
+    return nil, err
+  }
+
+  if len(rawTags) == 0 {
+    return []*url.URL{}, nil
+  }
+
+  root, err := tree.BuildPermissive(rawTags)
+  if err != nil {
+    return nil, err
+  }
+
+  base := pageURL
+  hrefs := make([]string, 0)
+  noFollow := false
+
+  var walk func(t tree.Tag)
+  walk = func(t tree.Tag) {
+    switch strings.ToLower(t.Name()) {
+    case "base":
+      if href, ok := getAttribute(t, "href"); ok {
+        if b, err := pageURL.Parse(strings.TrimSpace(href)); err == nil {
+          base = b
+        }
+      }
+    case "meta":
+      if name, ok := getAttribute(t, "name"); ok && strings.ToLower(name) == "robots" {
+        if content, ok := getAttribute(t, "content"); ok && (hasToken(content, "nofollow") || hasToken(content, "none")) {
+          noFollow = true
+        }
+      }
+    case "a", "area":
+      if rel, ok := getAttribute(t, "rel"); ok && hasToken(rel, "nofollow") {
+        break
+      }
+
+      if href, ok := getAttribute(t, "href"); ok {
+        hrefs = append(hrefs, strings.TrimSpace(href))
+      }
+    }
+
+    for _, child := range t.Children() {
+      walk(child)
+    }
+  }
+
+  walk(root)
+
+  if noFollow {
+    return nil, nil
+  }
+
+  links := make([]*url.URL, 0)
+  for _, href := range hrefs {
+    // RFC 3986 reference resolution, also removes dot segments
+    u, err := base.Parse(href)
+    if err != nil {
+      if VERBOSITY >= 1 {
+        fmt.Fprintf(os.Stderr, "Warning: bad link %s in %s\n", href, pageURL.String())
+      }
+      continue
+    }
+
+    if u.Scheme == "http" || u.Scheme == "https" {
+      links = append(links, normalizeURL(u))
+    }
+  }
+
+  return links, nil
+}
+
+// returns the http status, errors are only returned if the result can't be saved
+func (c *Crawler) crawlPage(rawURL string) (int, error) {
+  u, err := url.Parse(rawURL)
+  if err != nil {
+    return 0, err
+  }
+
+  path := c.urlToPath(u)
+  status := 0
+
+  var rawBytes []byte = nil
+  if files.IsFile(path) {
+    // eg. saved by a crawl without state
+    rawBytes, err = ioutil.ReadFile(path)
+    if err != nil {
+      return 0, err
+    }
+  } else {
+    if !c.getRobots(u).Allowed(u.EscapedPath()) {
+      if VERBOSITY >= 1 {
+        fmt.Fprintf(os.Stdout, "Skipping %s (disallowed by robots.txt)\n", rawURL)
+      }
+
+      return 0, nil
+    }
+
+    resp, err := c.get(u)
+    if err != nil {
+      fmt.Fprintf(os.Stderr, "Warning: %s\n", err.Error()) // probably time-out error
+      return 0, nil
+    }
+
+    defer resp.Body.Close()
+
+    status = resp.StatusCode
+
+    // the body isn't read for anything else than html, so there is no need for an extension blacklist
+    if status != 200 || !strings.Contains(resp.Header.Get("Content-Type"), "text/html") {
+      return status, nil
+    }
+
+    // links are relative to the final url
+    if redirected := normalizeURL(resp.Request.URL); redirected.String() != u.String() {
+      if !c.inScope(redirected) {
+        return status, nil
+      }
+
+      u = redirected
+    }
+
+    rawBytes, err = ioutil.ReadAll(resp.Body)
+    if err != nil {
+      fmt.Fprintf(os.Stderr, "Warning: %s\n", err.Error())
+      return 0, nil
+    }
+
+    dir := filepath.Dir(path)
+    if !files.IsDir(dir) {
+      if err := os.MkdirAll(dir, 0755); err != nil {
+        return status, err
+      }
+    }
+
+    fmt.Fprintf(os.Stdout, "Saving %d/%d (%s)\n", c.state.CountDone(), c.state.Len(), strings.TrimPrefix(rawURL, c.root.String()))
+
+    if err := ioutil.WriteFile(path, rawBytes, 0644); err != nil {
+      return status, err
+    }
+  }
+
+  links, err := extractLinks(u, rawBytes)
+  if err != nil {
+    // the page has been saved, but can't be parsed for links
+    fmt.Fprintf(os.Stderr, "Warning: unable to parse %s (%s)\n", rawURL, err.Error())
+    return status, nil
+  }
+
+  for _, link := range links {
+    c.addPending(link)
+  }
+
+  return status, nil
+}
+
+func (c *Crawler) crawlGeneration(urls []string) error {
+  queue := make(chan string)
+
+  var wg sync.WaitGroup
+  var errLock sync.Mutex
+  var firstErr error = nil
+
+  for i := 0; i < c.nWorkers; i++ {
+    wg.Add(1)
+
+    go func() {
+      defer wg.Done()
+
+      for url := range queue {
+        status, err := c.crawlPage(url)
+        if err == nil {
+          err = c.state.SetDone(url, status)
+        }
+
+        if err != nil {
+          errLock.Lock()
+          if firstErr == nil {
+            firstErr = err
+          }
+          errLock.Unlock()
+        }
+      }
+    }()
+  }
+
+  for _, url := range urls {
+    queue <- url
+  }
+
+  close(queue)
+
+  wg.Wait()
+
+  return firstErr
+}
+
+func (c *Crawler) Run() error {
+  if c.resumed {
+    fmt.Fprintf(os.Stdout, "Resuming crawl (%d/%d pages done)\n", c.state.CountDone(), c.state.Len())
+  } else {
+    c.seed()
+  }
+
+  for gen := 0; c.state.HasPending(); gen++ {
+    urls := c.state.GetAllPending()
+
+    fmt.Fprintf(os.Stdout, "%d: crawling %d pages\n", gen, len(urls))
+
+    if err := c.crawlGeneration(urls); err != nil {
+      // keep what has been done so far
+      c.state.Save()
+      return err
+    }
+
+    if err := c.state.Save(); err != nil {
+      return err
+    }
+  }
+
+  return c.state.Save()
+}
@@ -0,0 +1,194 @@
+package main
+
+import (
+  "fmt"
+  "io/ioutil"
+  "net/http"
+  "net/http/httptest"
+  "os"
+  "path/filepath"
+  "sync"
+  "testing"
+)
+
+// records which paths have been requested
+type testSite struct {
+  lock      sync.Mutex
+  requested map[string]int
+  server    *httptest.Server
+}
+
+func newTestSite() *testSite {
+  s := &testSite{requested: make(map[string]int)}
+
+  pages := map[string]string{
+    "/site/": `<html><head><title>Root</title></head><body>
+<a href="a.html">a</a>
+<a rel="nofollow" href="nofollow.html">nofollow</a>
+<a href="private/secret.html">private</a>
+<a href="/outside.html">outside</a>
+</body></html>`,
+    "/site/a.html": `<html><head><base href="/site/sub/"></head><body>
+<a href="b.html">b</a>
+</body></html>`,
+    "/site/sub/b.html":        `<html><body><p>b</p></body></html>`,
+    "/site/orphan.html":       `<html><body><p>only in the sitemap</p></body></html>`,
+    "/site/nofollow.html":     `<html><body><p>not followed</p></body></html>`,
+    "/site/private/secret.html": `<html><body><p>disallowed</p></body></html>`,
+    "/outside.html":           `<html><body><p>out of scope</p></body></html>`,
+  }
+
+  s.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+    s.lock.Lock()
+    s.requested[r.URL.Path] += 1
+    s.lock.Unlock()
+
+    switch r.URL.Path {
+    case "/robots.txt":
+      fmt.Fprintf(w, "User-agent: *\nDisallow: /site/private/\n\nSitemap: %s/map.xml\n", s.server.URL)
+    case "/map.xml":
+      w.Header().Set("Content-Type", "application/xml")
+      fmt.Fprintf(w, `<?xml version="1.0" encoding="UTF-8"?>
+<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9"><url><loc>%s/site/orphan.html</loc></url></urlset>`, s.server.URL)
+    default:
+      content, ok := pages[r.URL.Path]
+      if !ok {
+        http.NotFound(w, r)
+        return
+      }
+
+      w.Header().Set("Content-Type", "text/html; charset=utf-8")
+      fmt.Fprint(w, content)
+    }
+  }))
+
+  return s
+}
+
+func (s *testSite) count(path string) int {
+  s.lock.Lock()
+  defer s.lock.Unlock()
+
+  return s.requested[path]
+}
+
+func (s *testSite) reset() {
+  s.lock.Lock()
+  defer s.lock.Unlock()
+
+  s.requested = make(map[string]int)
+}
+
+func runTestCrawler(t *testing.T, s *testSite, dst string, fresh bool) {
+  c, err := NewCrawler(s.server.URL + "/site/", dst, 2, "wt-crawl-test", 0, s.server.Client(), fresh)
+  if err != nil {
+    t.Fatal(err)
+  }
+
+  if err := c.Run(); err != nil {
+    t.Fatal(err)
+  }
+}
+
+func assertSaved(t *testing.T, dst string, rel string, expected bool) {
+  _, err := os.Stat(filepath.Join(dst, filepath.FromSlash(rel)))
+  if expected && err != nil {
+    t.Errorf("expected %s to be saved", rel)
+  } else if !expected && err == nil {
+    t.Errorf("expected %s not to be saved", rel)
+  }
+}
+
+func TestCrawler(t *testing.T) {
+  s := newTestSite()
+  defer s.server.Close()
+
+  dst, err := ioutil.TempDir("", "wt-crawl")
+  if err != nil {
+    t.Fatal(err)
+  }
+  defer os.RemoveAll(dst)
+
+  runTestCrawler(t, s, dst, true)
+
+  assertSaved(t, dst, "index.html", true)
+  assertSaved(t, dst, "a.html", true)
+
+  // resolved against <base href>
+  assertSaved(t, dst, "sub/b.html", true)
+  assertSaved(t, dst, "b.html", false)
+
+  // only reachable via the sitemap listed in robots.txt
+  assertSaved(t, dst, "orphan.html", true)
+
+  // rel=nofollow
+  assertSaved(t, dst, "nofollow.html", false)
+  if s.count("/site/nofollow.html") != 0 {
+    t.Errorf("nofollow link was requested")
+  }
+
+  // disallowed by robots.txt
+  assertSaved(t, dst, "private/secret.html", false)
+  if s.count("/site/private/secret.html") != 0 {
+    t.Errorf("page disallowed by robots.txt was requested")
+  }
+
+  // outside the root
+  if s.count("/outside.html") != 0 {
+    t.Errorf("page outside the root was requested")
+  }
+
+  if n := s.count("/robots.txt"); n != 1 {
+    t.Errorf("expected robots.txt to be requested once, got %d", n)
+  }
+}
+
+func TestCrawlerResume(t *testing.T) {
+  s := newTestSite()
+  defer s.server.Close()
+
+  dst, err := ioutil.TempDir("", "wt-crawl")
+  if err != nil {
+    t.Fatal(err)
+  }
+  defer os.RemoveAll(dst)
+
+  // an interrupted crawl: the root is done, b.html is still pending
+  state := NewCrawlState(filepath.Join(dst, CRAWL_STATE_FILE))
+  state.AddPending(s.server.URL + "/site/")
+  state.SetDone(s.server.URL + "/site/", 200)
+  state.AddPending(s.server.URL + "/site/sub/b.html")
+  if err := state.Save(); err != nil {
+    t.Fatal(err)
+  }
+
+  runTestCrawler(t, s, dst, false)
+
+  assertSaved(t, dst, "sub/b.html", true)
+
+  // not seeded again
+  if s.count("/site/") != 0 || s.count("/map.xml") != 0 {
+    t.Errorf("resumed crawl shouldn't start from the root again")
+  }
+
+  assertSaved(t, dst, "index.html", false)
+
+  resumed, ok, err := LoadCrawlState(filepath.Join(dst, CRAWL_STATE_FILE))
+  if err != nil || !ok {
+    t.Fatal("crawl state not saved")
+  }
+
+  if resumed.HasPending() {
+    t.Errorf("expected no pending pages, got %v", resumed.GetAllPending())
+  }
+
+  // a fresh crawl starts over, and doesn't refetch the pages that have already been saved
+  s.reset()
+
+  runTestCrawler(t, s, dst, true)
+
+  assertSaved(t, dst, "index.html", true)
+  if s.count("/site/sub/b.html") != 0 {
+    t.Errorf("saved page was requested again")
+  }
+}
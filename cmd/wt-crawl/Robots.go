@@ -0,0 +1,187 @@
+package main
+
+import (
+  "strconv"
+  "strings"
+  "time"
+)
+
+// robots.txt according to RFC 9309, plus the non-standard Crawl-delay
+type RobotsRule struct {
+  pattern string // can contain * and a trailing $
+  allow   bool
+}
+
+type Robots struct {
+  rules      []RobotsRule
+  crawlDelay time.Duration
+  sitemaps   []string
+}
+
+type robotsGroup struct {
+  agents     []string
+  rules      []RobotsRule
+  crawlDelay time.Duration
+}
+
+// -1 if the group doesn't apply, 0 for *, otherwise the length of the matching user-agent
+func (g *robotsGroup) score(userAgent string) int {
+  score := -1
+  for _, agent := range g.agents {
+    if agent == "*" {
+      if score < 0 {
+        score = 0
+      }
+    } else if strings.HasPrefix(userAgent, agent) && len(agent) > score {
+      score = len(agent)
+    }
+  }
+
+  return score
+}
+
+// everything is allowed, used when robots.txt doesn't exist
+func NewPermissiveRobots() *Robots {
+  return &Robots{[]RobotsRule{}, 0, []string{}}
+}
+
+// used when robots.txt is unreachable
+func NewRestrictiveRobots() *Robots {
+  return &Robots{[]RobotsRule{RobotsRule{"/", false}}, 0, []string{}}
+}
+
+// userAgent is the product token of the crawler (eg. "wt-crawl")
+func ParseRobots(content string, userAgent string) *Robots {
+  groups := make([]*robotsGroup, 0)
+  sitemaps := make([]string, 0)
+
+  var group *robotsGroup = nil
+  inAgents := false // consecutive user-agent lines belong to the same group
+
+  for _, line := range strings.Split(content, "\n") {
+    if i := strings.Index(line, "#"); i != -1 {
+      line = line[0:i]
+    }
+
+    i := strings.Index(line, ":")
+    if i == -1 {
+      continue
+    }
+
+    key := strings.ToLower(strings.TrimSpace(line[0:i]))
+    value := strings.TrimSpace(line[i+1:])
+
+    switch key {
+    case "user-agent":
+      if !inAgents {
+        group = &robotsGroup{[]string{}, []RobotsRule{}, 0}
+        groups = append(groups, group)
+        inAgents = true
+      }
+
+      group.agents = append(group.agents, strings.ToLower(value))
+    case "allow", "disallow":
+      inAgents = false
+      if group != nil && value != "" { // empty disallow means everything is allowed
+        group.rules = append(group.rules, RobotsRule{value, key == "allow"})
+      }
+    case "crawl-delay":
+      inAgents = false
+      if group != nil {
+        if seconds, err := strconv.ParseFloat(value, 64); err == nil && seconds > 0 {
+          group.crawlDelay = time.Duration(seconds*float64(time.Second))
+        }
+      }
+    case "sitemap":
+      // not part of any group
+      sitemaps = append(sitemaps, value)
+    default:
+      inAgents = false
+    }
+  }
+
+  // the most specific matching user-agent wins, groups with the same user-agent are merged
+  userAgent = strings.ToLower(userAgent)
+
+  scores := make([]int, len(groups))
+  best := -1
+  for i, g := range groups {
+    scores[i] = g.score(userAgent)
+    if scores[i] > best {
+      best = scores[i]
+    }
+  }
+
+  r := &Robots{[]RobotsRule{}, 0, sitemaps}
+  for i, g := range groups {
+    if best != -1 && scores[i] == best {
+      r.rules = append(r.rules, g.rules...)
+      if g.crawlDelay > r.crawlDelay {
+        r.crawlDelay = g.crawlDelay
+      }
+    }
+  }
+
+  return r
+}
+
+func robotsMatch(pattern string, path string) bool {
+  anchored := strings.HasSuffix(pattern, "$")
+  if anchored {
+    pattern = pattern[0:len(pattern)-1]
+  }
+
+  parts := strings.Split(pattern, "*")
+
+  if !strings.HasPrefix(path, parts[0]) {
+    return false
+  }
+
+  rest := path[len(parts[0]):]
+  if len(parts) == 1 {
+    return !anchored || rest == ""
+  }
+
+  for i, part := range parts[1:] {
+    if anchored && i == len(parts) - 2 {
+      return strings.HasSuffix(rest, part)
+    }
+
+    j := strings.Index(rest, part)
+    if j == -1 {
+      return false
+    }
+
+    rest = rest[j+len(part):]
+  }
+
+  return true
+}
+
+// path is the escaped path of the url, the longest matching rule wins, allow wins ties
+func (r *Robots) Allowed(path string) bool {
+  if path == "/robots.txt" {
+    return true
+  }
+
+  allowed := true
+  longest := -1
+  for _, rule := range r.rules {
+    if robotsMatch(rule.pattern, path) {
+      if n := len(rule.pattern); n > longest || (n == longest && rule.allow) {
+        allowed = rule.allow
+        longest = n
+      }
+    }
+  }
+
+  return allowed
+}
+
+func (r *Robots) CrawlDelay() time.Duration {
+  return r.crawlDelay
+}
+
+func (r *Robots) Sitemaps() []string {
+  return r.sitemaps
+}
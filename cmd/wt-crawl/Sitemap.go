@@ -0,0 +1,49 @@
+package main
+
+import (
+  "encoding/xml"
+  "strings"
+)
+
+const (
+  MAX_SITEMAP_DEPTH = 3 // sitemap indices can refer to other sitemaps
+)
+
+type sitemapLoc struct {
+  Loc string `xml:"loc"`
+}
+
+// both <urlset> and <sitemapindex>
+type Sitemap struct {
+  URLs     []sitemapLoc `xml:"url"`
+  Sitemaps []sitemapLoc `xml:"sitemap"`
+}
+
+func ParseSitemap(content []byte) (*Sitemap, error) {
+  sm := &Sitemap{}
+
+  if err := xml.Unmarshal(content, sm); err != nil {
+    return nil, err
+  }
+
+  return sm, nil
+}
+
+func trimLocs(locs []sitemapLoc) []string {
+  res := make([]string, 0)
+  for _, loc := range locs {
+    if l := strings.TrimSpace(loc.Loc); l != "" {
+      res = append(res, l)
+    }
+  }
+
+  return res
+}
+
+func (sm *Sitemap) PageURLs() []string {
+  return trimLocs(sm.URLs)
+}
+
+func (sm *Sitemap) SitemapURLs() []string {
+  return trimLocs(sm.Sitemaps)
+}
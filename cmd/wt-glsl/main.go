@@ -25,6 +25,7 @@ var cmdParser *parsers.CLIParser = nil
 type CmdArgs struct {
   inputFile string
   outputFile string // defaults to a.shader in current dir
+  pairFile string // shader of the opposite target, checked together with the input, but not written
 
   target string
   compactOutput bool
@@ -48,6 +49,7 @@ func parseArgs() CmdArgs {
 	cmdArgs := CmdArgs{
 		inputFile:     "",
 		outputFile:    DEFAULT_OUTPUTFILE,
+    pairFile:      "",
     target:        "vertex",
 		compactOutput: false,
     autoDownload:  false,
@@ -59,6 +61,7 @@ func parseArgs() CmdArgs {
     "",
     []parsers.CLIOption{
       parsers.NewCLIUniqueFile("o", "output" , "-o, --output    <output-file> Defaults to \"" + DEFAULT_OUTPUTFILE + "\" if not set", false, &(cmdArgs.outputFile)),
+      parsers.NewCLIUniqueFile("p", "pair"   , "-p, --pair      <shader-file> Fragment shader if target is vertex, or vertex shader if target is fragment, checked together with the input shader (eg. varyings)", true, &(cmdArgs.pairFile)),
       parsers.NewCLIUniqueFlag("c", "compact", "-c, --compact   Compact output with minimal whitespace and short names", &(cmdArgs.compactOutput)),
       parsers.NewCLIUniqueFlag("", "auto-download"         , "--auto-download                   Automatically download missing packages (use wt-pkg-sync if you want to do this manually). Doesn't update packages!", &(cmdArgs.autoDownload)), 
       parsers.NewCLIUniqueEnum("t", "target" , "-t, --target    \"vertex\" or \"fragment\", defaults to \"vertex\"", []string{"vertex", "fragment"}, &(cmdArgs.target)),
@@ -97,11 +100,10 @@ func setUpEnv(cmdArgs CmdArgs) error {
   return files.ResolvePackages(cmdArgs.inputFile)
 }
 
-func buildShader(cmdArgs CmdArgs) error {
-  // dont bother caching, because shaders are expected to be relatively small
-  entryShader, err := shaders.NewInitShaderFile(cmdArgs.inputFile)
+func finalizeShader(path string) (*shaders.ShaderBundle, error) {
+  entryShader, err := shaders.NewInitShaderFile(path)
   if err != nil {
-    return err
+    return nil, err
   }
 
   bundle := shaders.NewShaderBundle()
@@ -109,9 +111,52 @@ func buildShader(cmdArgs CmdArgs) error {
   bundle.Append(entryShader)
 
   if err := bundle.Finalize(); err != nil {
+    return nil, err
+  }
+
+  return bundle, nil
+}
+
+// errors that concern both shaders are reported at the main function of the input shader
+func checkPair(bundle *shaders.ShaderBundle, cmdArgs CmdArgs) error {
+  target := glsl.TARGET
+  if target == "vertex" {
+    glsl.TARGET = "fragment"
+  } else {
+    glsl.TARGET = "vertex"
+  }
+
+  defer func() {
+    glsl.TARGET = target
+  }()
+
+  pairBundle, err := finalizeShader(cmdArgs.pairFile)
+  if err != nil {
     return err
   }
 
+  errCtx := bundle.EntryContext()
+
+  if target == "vertex" {
+    return shaders.CheckShaderPair(bundle, pairBundle, errCtx)
+  } else {
+    return shaders.CheckShaderPair(pairBundle, bundle, errCtx)
+  }
+}
+
+func buildShader(cmdArgs CmdArgs) error {
+  // dont bother caching, because shaders are expected to be relatively small
+  bundle, err := finalizeShader(cmdArgs.inputFile)
+  if err != nil {
+    return err
+  }
+
+  if cmdArgs.pairFile != "" {
+    if err := checkPair(bundle, cmdArgs); err != nil {
+      return err
+    }
+  }
+
   content, err := bundle.Write(patterns.NL, patterns.TAB)
   if err != nil {
     return err
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"io/ioutil"
 	"os"
+	"path/filepath"
 
 	"github.com/computeportal/wtsuite/pkg/cache"
 	"github.com/computeportal/wtsuite/pkg/directives"
@@ -34,9 +35,13 @@ type CmdArgs struct {
 	target      string
 
 	compactOutput bool
+	mangleMembers bool
 	forceBuild    bool // delete cache and start fresh
   executable    bool // create an executable
   autoDownload  bool
+  strictNull    bool
+  jsonSchema    []string // names of types exported as json schema
+  openAPI       []string // names of rpc interfaces exported as openapi document
 
 	verbosity int
 }
@@ -62,9 +67,13 @@ func parseArgs() CmdArgs {
 		outputFile:    DEFAULT_OUTPUTFILE,
 		target:        DEFAULT_TARGET,
 		compactOutput: false,
+		mangleMembers: false,
 		forceBuild:    false,
     executable:    false,
     autoDownload:  false,
+    strictNull:    false,
+    jsonSchema:    make([]string, 0),
+    openAPI:       make([]string, 0),
 		verbosity:     0,
 	}
 
@@ -74,8 +83,12 @@ func parseArgs() CmdArgs {
     []parsers.CLIOption{
       parsers.NewCLIUniqueFile("o", "output"    , "-o, --output <output-file>  Defaults to \"" + DEFAULT_OUTPUTFILE + "\" if not set", false, &(cmdArgs.outputFile)),
       parsers.NewCLIUniqueFlag("c", "compact"   , "-c, --compact               Compact output with minimal whitespace and short names", &(cmdArgs.compactOutput)),
+      parsers.NewCLIUniqueFlag("", "mangle-members", "--mangle-members            Also give short names to non-private class members that aren't accessed via interfaces (requires --compact)", &(cmdArgs.mangleMembers)),
       parsers.NewCLIUniqueFlag("f", "force"     , "-f, --force                 Force a complete project rebuild", &(cmdArgs.forceBuild)),
       parsers.NewCLIUniqueEnum("t", "target"    , "-t, --target <js-target>    Defaults to \"" + DEFAULT_TARGET + "\", other possibilities are \"browser\" or \"worker\"", []string{"nodejs", "browser", "worker"}, &(cmdArgs.target)),
+      parsers.NewCLIUniqueFlag("", "strict-null", "--strict-null               Null isn't compatible with other types, nullable values (T? or T|null) must be checked before use", &(cmdArgs.strictNull)),
+      parsers.NewCLIAppendString("", "json-schema", "--json-schema <type-name>    Write JSON Schema of a class, interface or enum to <output-file-base>.schema.json (can be repeated)", &(cmdArgs.jsonSchema)),
+      parsers.NewCLIAppendString("", "openapi", "--openapi <rpc-interface>    Write OpenAPI document of an rpc interface served by HTTPRPC to <output-file-base>.openapi.json (can be repeated)", &(cmdArgs.openAPI)),
       parsers.NewCLIUniqueFlag("x", "executable", "-x, --executable            Create an executable with a node hashbang (target must be nodejs)", &(cmdArgs.executable)),
       parsers.NewCLIUniqueFlag("", "auto-download"         , "--auto-download                   Automatically download missing packages (use wt-pkg-sync if you want to do this manually). Doesn't update packages!", &(cmdArgs.autoDownload)), 
       parsers.NewCLIUniqueFlag("l", "latest"    , "-l, --latest                Ignore max semver, use latest tagged versions of dependencies", &(files.LATEST)),
@@ -92,6 +105,10 @@ func parseArgs() CmdArgs {
     printMessageAndExit("Error: --executable can only be used if target is nodejs")
   }
 
+  if cmdArgs.mangleMembers && !cmdArgs.compactOutput {
+    printMessageAndExit("Error: --mangle-members can only be used in combination with --compact")
+  }
+
 	return cmdArgs
 }
 
@@ -102,6 +119,7 @@ func setUpEnv(cmdArgs CmdArgs) error {
     patterns.NL = ""
 		patterns.TAB = ""
 		patterns.COMPACT_NAMING = true
+		patterns.MANGLE_ALL_MEMBERS = cmdArgs.mangleMembers
 		macros.COMPACT = true
 	}
 
@@ -110,6 +128,7 @@ func setUpEnv(cmdArgs CmdArgs) error {
   }
 
 	js.TARGET = cmdArgs.target
+	values.STRICT_NULL = cmdArgs.strictNull
 	directives.ForceNewViewFileScriptRegistration(directives.NewFileCache())
 
 	VERBOSITY = cmdArgs.verbosity
@@ -124,7 +143,9 @@ func setUpEnv(cmdArgs CmdArgs) error {
 }
 
 func buildProject(cmdArgs CmdArgs) error {
-	cache.LoadJSCache(cmdArgs.outputFile, cmdArgs.forceBuild)
+	// schemas aren't cached
+	exportSchemas := len(cmdArgs.jsonSchema) > 0 || len(cmdArgs.openAPI) > 0
+	cache.LoadJSCache(cmdArgs.outputFile, cmdArgs.forceBuild || cmdArgs.strictNull || exportSchemas)
 
 	if cache.RequiresUpdate(cmdArgs.inputFile) {
 		entryScript, err := scripts.NewInitFileScript(cmdArgs.inputFile)
@@ -132,7 +153,10 @@ func buildProject(cmdArgs CmdArgs) error {
 			return err
 		}
 
-		bundle := scripts.NewFileBundle(map[string]string{})
+		// worker bundles get the same defines as the main bundle
+		defines := map[string]string{}
+
+		bundle := scripts.NewFileBundle(defines)
 
 		bundle.Append(entryScript)
 
@@ -155,12 +179,42 @@ func buildProject(cmdArgs CmdArgs) error {
       }
     }
 
+		if err := buildWorkerBundles(cmdArgs, defines); err != nil {
+			return err
+		}
+
+		if err := buildSchemas(cmdArgs, bundle); err != nil {
+			return err
+		}
+
 		cache.SaveCache(cmdArgs.outputFile)
 	}
 
 	return nil
 }
 
+// worker bundles are written next to the output file
+func buildWorkerBundles(cmdArgs CmdArgs, defines map[string]string) error {
+	workerBundles, err := scripts.BuildWorkerBundles(defines)
+	if err != nil {
+		return err
+	}
+
+	for name, content := range workerBundles {
+		dst := filepath.Join(filepath.Dir(cmdArgs.outputFile), name)
+
+		if VERBOSITY >= 1 {
+			fmt.Fprintf(os.Stdout, "writing worker bundle %s\n", dst)
+		}
+
+		if err := ioutil.WriteFile(dst, []byte(content), 0644); err != nil {
+			return errors.New("Error: " + err.Error())
+		}
+	}
+
+	return nil
+}
+
 func main() {
 	cmdArgs := parseArgs()
 
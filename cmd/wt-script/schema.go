@@ -0,0 +1,121 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/computeportal/wtsuite/pkg/tokens/js"
+	"github.com/computeportal/wtsuite/pkg/tree/scripts"
+)
+
+// types can be defined in any module of the bundle, but names must be unambiguous
+func findSchemaTypes(bundle *scripts.FileBundle, names []string) ([]js.SchemaType, error) {
+	found := make(map[string]js.SchemaType)
+
+	for _, name := range names {
+		found[name] = nil
+	}
+
+	if err := bundle.Walk(func(scriptPath string, obj_ interface{}) error {
+		obj, ok := obj_.(js.SchemaType)
+		if !ok {
+			return nil
+		}
+
+		prev, ok := found[obj.Name()]
+		if !ok {
+			return nil
+		} else if prev != nil && prev != obj {
+			errCtx := obj.Context()
+			err := errCtx.NewError("Error: " + obj.Name() + " is ambiguous")
+			err.AppendContextString("Info: also defined here", prev.Context())
+			return err
+		}
+
+		found[obj.Name()] = obj
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	result := make([]js.SchemaType, 0)
+	for _, name := range names {
+		if found[name] == nil {
+			return nil, errors.New("Error: type " + name + " not found")
+		}
+
+		result = append(result, found[name])
+	}
+
+	return result, nil
+}
+
+func schemaOutputFile(cmdArgs CmdArgs, ext string) string {
+	return strings.TrimSuffix(cmdArgs.outputFile, filepath.Ext(cmdArgs.outputFile)) + ext
+}
+
+func writeSchemaFile(dst string, content string) error {
+	if VERBOSITY >= 1 {
+		fmt.Fprintf(os.Stdout, "writing schema %s\n", dst)
+	}
+
+	if err := ioutil.WriteFile(dst, []byte(content), 0644); err != nil {
+		return errors.New("Error: " + err.Error())
+	}
+
+	return nil
+}
+
+// schemas are written next to the output file
+func buildSchemas(cmdArgs CmdArgs, bundle *scripts.FileBundle) error {
+	if len(cmdArgs.jsonSchema) > 0 {
+		ts, err := findSchemaTypes(bundle, cmdArgs.jsonSchema)
+		if err != nil {
+			return err
+		}
+
+		content, err := js.WriteJSONSchema(ts)
+		if err != nil {
+			return err
+		}
+
+		if err := writeSchemaFile(schemaOutputFile(cmdArgs, ".schema.json"), content); err != nil {
+			return err
+		}
+	}
+
+	if len(cmdArgs.openAPI) > 0 {
+		ts, err := findSchemaTypes(bundle, cmdArgs.openAPI)
+		if err != nil {
+			return err
+		}
+
+		interfs := make([]*js.Interface, 0)
+		for _, t := range ts {
+			interf, ok := t.(*js.Interface)
+			if !ok || !interf.IsRPC() {
+				errCtx := t.Context()
+				return errCtx.NewError("Error: " + t.Name() + " isn't an rpc interface")
+			}
+
+			interfs = append(interfs, interf)
+		}
+
+		title := strings.TrimSuffix(filepath.Base(cmdArgs.inputFile), filepath.Ext(cmdArgs.inputFile))
+
+		content, err := js.WriteOpenAPI(title, interfs)
+		if err != nil {
+			return err
+		}
+
+		if err := writeSchemaFile(schemaOutputFile(cmdArgs, ".openapi.json"), content); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
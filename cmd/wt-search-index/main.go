@@ -16,6 +16,7 @@ import (
 	"github.com/computeportal/wtsuite/pkg/directives"
 	"github.com/computeportal/wtsuite/pkg/files"
 	"github.com/computeportal/wtsuite/pkg/parsers"
+	"github.com/computeportal/wtsuite/pkg/search"
 	"github.com/computeportal/wtsuite/pkg/styles"
 	"github.com/computeportal/wtsuite/pkg/tokens/context"
 	tokens "github.com/computeportal/wtsuite/pkg/tokens/html"
@@ -24,10 +25,6 @@ import (
 	"github.com/computeportal/wtsuite/pkg/tree/scripts"
 )
 
-const (
-  CONTENT_LIMIT = 100 // number of chars
-)
-
 var (
   VERBOSITY = 0
   cmdParser *parsers.CLIParser = nil
@@ -49,6 +46,10 @@ type SearchConfig struct {
 	ContentQuery   string `json:"content-query"`
 	contentQueries []styles.Selector
 	Ignore         []string `json:"ignore"`
+  Language       string   `json:"language"` // for pages without <html lang>
+  ShardSize      int      `json:"shard-size"` // approximate max number of bytes per shard file, 0 to write a single file
+  Weights        map[string]float64 `json:"weights"` // keys: "title", "description", "content"
+  weights        [search.N_FIELDS]float64
 }
 
 func printMessageAndExit(msg string) {
@@ -140,83 +141,8 @@ func setUpEnv(cmdArgs CmdArgs, cfg *SearchConfig) error {
   return nil
 }
 
-type SearchIndexPage struct {
-	Url     string   `json:"url"`     // used as key
-	Title   string   `json:"title"`   // should be unique for each indexed page
-	Content []string `json:"content"` // each string is a paragraph, but this quickly bloats the index file, so better just put description here
-}
-
-type SearchIndex struct {
-	Pages   []SearchIndexPage      `json:"pages"`   // sorted
-	Ignore  map[string]string      `json:"ignore"`  // key is same as value
-	Index   map[string]interface{} `json:"index"`   // nested character tree, leaves are indices into pages array
-	Partial map[string]interface{} `json:"partial"` // nested character tree which doesn't start at beginning of word
-}
-
-func NewSearchIndex() *SearchIndex {
-	return &SearchIndex{
-		Pages:   make([]SearchIndexPage, 0),
-		Ignore:  make(map[string]string),
-		Index:   make(map[string]interface{}), // XXX: can be left empty for initial test
-		Partial: make(map[string]interface{}), // XXX: can be left empty for initial test
-	}
-}
-
-func (si *SearchIndex) AddPage(url string, title string, content []string) {
-	si.Pages = append(si.Pages, SearchIndexPage{url, title, content})
-}
-
-func findRootParagraph(xpath []tree.Tag) tree.Tag {
-	for _, t := range xpath {
-		if t.Name() == "p" {
-			return t
-		}
-	}
-
-	return nil
-}
-
-func extractTagText(tags []tree.Tag) []string {
-  str := make([]string, 0)
-
-  for _, t := range tags {
-    if err := tree.WalkText(t, []tree.Tag{}, func(_ []tree.Tag, s string) error {
-      str = append(str, s)
-
-      return nil
-    }); err != nil {
-      panic("unexpected")
-    }
-  }
-
-  return str
-}
-
-func extractHeadTitle(root *tree.Root) string {
-  for _, rootChild := range root.Children() {
-    if rootChild.Name() == "html" {
-      for _, htmlChild := range rootChild.Children() {
-        if htmlChild.Name() == "head" {
-          for _, headChild := range htmlChild.Children() {
-            if headChild.Name() == "title" {
-              titleTag, ok := headChild.(*tree.Title)
-              if !ok {
-                panic("unexpeced")
-              }
-
-              return titleTag.Content()
-            }
-          }
-        }
-      }
-    }
-  }
-
-  return ""
-}
-
 // this function can only add pending
-func parseHTMLFile(cmdArgs CmdArgs, cfg *SearchConfig, path string, si *SearchIndex) error {
+func parseHTMLFile(cmdArgs CmdArgs, cfg *SearchConfig, path string, si *search.Index) error {
   url := path[len(cmdArgs.root):]
 
   rawBytes, err := ioutil.ReadFile(path)
@@ -235,23 +161,7 @@ func parseHTMLFile(cmdArgs CmdArgs, cfg *SearchConfig, path string, si *SearchIn
 		return err
 	}
 
-  // TODO: fast build that circumvents directives
-	/*root := tree.NewRoot(p.NewContext(0, 1))
-	node := directives.NewRootNode(root, directives.HTML)
-  // the source isn't really used, because the html file doesnt contain import statements
-	fileScope := directives.NewFileScope(false, directives.NewFileCache())
-
-	for _, tag := range rawTags {
-		if err := directives.BuildTag(fileScope, node, tag); err != nil {
-			return err
-		}
-	}
-
-  tree.RegisterParents(root)
-  if err := root.Validate(); err != nil {
-    return err
-  }*/
-
+  // wt-site indexes its views directly from the trees it builds, this is for html that wasn't generated by wt-site
   root, err := tree.BuildPermissive(rawTags)
   if err != nil {
     return err
@@ -262,21 +172,17 @@ func parseHTMLFile(cmdArgs CmdArgs, cfg *SearchConfig, path string, si *SearchIn
   if cfg.titleQuery != nil {
     titleTags := cfg.titleQuery.Match(root)
     if len(titleTags) > 0 {
-      titleParts := extractTagText(titleTags)
+      titleParts := search.TagText(titleTags)
       title = strings.Join(titleParts, " ")
     }
   } 
 
   if title == "" {
     // html -> head -> title
-    title = extractHeadTitle(root)
-  }
-
-  if strings.Contains(title, "|") {
-    title = strings.Split(title, "|")[0]
+    title = search.HeadTitle(root)
   }
 
-  title = strings.TrimSpace(title)
+  title = search.PageTitle(title)
 
   contentTags := make([]tree.Tag, 0)
 
@@ -284,40 +190,29 @@ func parseHTMLFile(cmdArgs CmdArgs, cfg *SearchConfig, path string, si *SearchIn
     contentTags = append(contentTags, sel.Match(root)...)
   }
 
-  content := extractTagText(contentTags)
+  content := search.TagText(contentTags)
 
-  // prepend the description
+  description := ""
   if cfg.IncludeDescription {
-    sel, err := parseSelector("head > meta[name=\"description\"]", cmdArgs.configFile)
-    if err != nil {
-      // must be valid!
-      panic(err)
-    }
-
-    tags := sel.Match(root)
-    if len(tags) > 0 {
-      if contentToken_, ok := tags[0].Attributes().Get("content"); ok {
-        contentToken, err := tokens.AssertString(contentToken_)
-        if err == nil {
-          content = append([]string{contentToken.Value()}, content...)
-        }
-      }
-    }
+    description = search.HeadDescription(root)
   }
 
-  if strings.HasSuffix(url, "/index.html") {
-    url = strings.TrimSuffix(url, "/index.html")
-  }
+  url = search.PageURL(url)
 
   fmt.Fprintf(os.Stdout, "Adding to index %s (title=%s)\n", url, title)
 
-  si.AddPage(url, title, content)
+  lang := search.HTMLLang(root)
+  if lang == "" {
+    lang = cfg.Language
+  }
+
+  si.AddPage(search.NewPage(url, title, description, content, lang))
 
   return nil
 }
 
-func registerSearchableContent(cmdArgs CmdArgs, cfg *SearchConfig) (*SearchIndex, error) {
-	searchIndex := NewSearchIndex()
+func registerSearchableContent(cmdArgs CmdArgs, cfg *SearchConfig) (*search.Index, error) {
+	searchIndex := search.NewIndex()
 
   // collect files first
   htmlFiles := []string{}
@@ -355,137 +250,6 @@ func registerSearchableContent(cmdArgs CmdArgs, cfg *SearchConfig) (*SearchIndex
 	return searchIndex, nil
 }
 
-func (si *SearchIndex) indexWord(m map[string]interface{}, pageID int, f string) error {
-	chars := strings.Split(f, "")
-
-	for _, char := range chars {
-		if mInner, ok := m[char]; ok {
-			m = mInner.(map[string]interface{})
-		} else {
-			mInner := make(map[string]interface{})
-			m[char] = mInner
-			m = mInner
-		}
-	}
-
-	if pages_, ok := m["pages"]; ok {
-		pages := pages_.([]float64)
-		unique := true
-		for _, page := range pages {
-			if int(page) == pageID {
-				unique = false
-				break
-			}
-		}
-
-		if unique {
-			pages = append(pages, float64(pageID))
-			m["pages"] = pages
-		}
-	} else {
-		m["pages"] = []float64{float64(pageID)}
-	}
-
-	return nil
-}
-
-func (si *SearchIndex) IndexWord(pageID int, f string) error {
-	if err := si.indexWord(si.Index, pageID, f); err != nil {
-		return err
-	}
-
-	// partial versions of the word are also indexed (include the full word itself)
-	for i := 0; i < len(f); i++ {
-		fPart := f[i:]
-
-		if err := si.indexWord(si.Partial, pageID, fPart); err != nil {
-			return err
-		}
-	}
-
-	return nil
-}
-
-func isIgnoredWord(cfg *SearchConfig, w string) bool {
-	i := sort.SearchStrings(cfg.Ignore, w)
-
-	if i > -1 && i < len(cfg.Ignore) {
-		return cfg.Ignore[i] == w
-	} else {
-		return false
-	}
-}
-
-func indexSentence(cfg *SearchConfig, si *SearchIndex, pageID int, sentence string) error {
-	fields := strings.FieldsFunc(strings.Trim(sentence, "."), func(r rune) bool {
-		return r < 46 || // keep period as decimal separator
-			r == 47 || // forward slash
-			r == 58 || // :
-			r == 59 || // ;
-			r == 63 || // ?
-			r == 95 // _
-	})
-
-	for _, field := range fields {
-		f := strings.ToLower(strings.TrimSpace(field))
-		if f != "" {
-			if !isIgnoredWord(cfg, f) {
-				if err := si.IndexWord(pageID, f); err != nil {
-					return err
-				}
-			}
-		}
-	}
-
-	return nil
-}
-
-// actually fill the index/partial nested trees
-func buildSearchIndex(cfg *SearchConfig, searchIndex *SearchIndex) error {
-  fmt.Println("processing ", len(searchIndex.Pages), " pages...")
-
-	// loop each word of each page
-	for i, page := range searchIndex.Pages {
-		if err := indexSentence(cfg, searchIndex, i, page.Title); err != nil {
-			return err
-		}
-		for _, paragraph := range page.Content {
-			if err := indexSentence(cfg, searchIndex, i, paragraph); err != nil {
-				return err
-			}
-		}
-	}
-
-	// add the ignored values
-	for _, w := range cfg.Ignore {
-		searchIndex.Ignore[w] = w
-	}
-
-	return nil
-}
-
-func limitSearchIndexContent(searchIndex *SearchIndex) error {
-  for i, page := range searchIndex.Pages {
-    content := make([]string, 0)
-    count := 0
-    for _, part := range page.Content {
-      count += len(part)
-
-      if count < CONTENT_LIMIT {
-        content = append(content, part)
-      } else {
-        content = append(content, part[0:len(part) - (count - CONTENT_LIMIT)] + "...")
-        break
-      }
-    }
-
-    page.Content = content
-    searchIndex.Pages[i] = page
-  }
-
-  return nil
-}
-
 func parseSelectors(str string, refPath string) ([]styles.Selector, error) {
   return styles.ParseSelectorList(
     tokens.NewValueString(str, context.NewContext(context.NewSource(str), refPath)),
@@ -513,6 +277,7 @@ func ReadConfigFile(cmdArgs *CmdArgs) (*SearchConfig, error) {
     ContentQuery: "",
     contentQueries: []styles.Selector{},
     Ignore:         []string{},
+    Weights:        map[string]float64{},
   }
 
 	b, err := ioutil.ReadFile(cmdArgs.configFile)
@@ -536,6 +301,17 @@ func ReadConfigFile(cmdArgs *CmdArgs) (*SearchConfig, error) {
     return nil, err
   }
 
+  cfg.Ignore = search.NormalizeIgnore(cfg.Ignore)
+
+  if cfg.ShardSize < 0 {
+    return nil, errors.New("Error: shard-size can't be negative")
+  }
+
+  cfg.weights, err = search.FieldWeights(cfg.Weights)
+  if err != nil {
+    return nil, err
+  }
+
   return cfg, nil
 }
 
@@ -600,20 +376,13 @@ func main() {
 		printMessageAndExit(err.Error()+"\n")
 	}
 
-	if err := buildSearchIndex(cfg, searchIndex); err != nil {
-		printMessageAndExit(err.Error()+"\n")
-	}
-
-  if err := limitSearchIndexContent(searchIndex); err != nil {
-		printMessageAndExit(err.Error()+"\n")
-  }
+  fmt.Println("processing ", len(searchIndex.Pages), " pages...")
 
-	b, err := json.Marshal(searchIndex)
-	if err != nil {
+	if err := searchIndex.Build(cfg.Ignore, cfg.Language, cfg.weights); err != nil {
 		printMessageAndExit(err.Error()+"\n")
 	}
 
-	if err := ioutil.WriteFile(cmdArgs.searchIndexOutput, b, 0644); err != nil {
+	if err := searchIndex.Write(cmdArgs.searchIndexOutput, cfg.ShardSize); err != nil {
 		printMessageAndExit(err.Error()+"\n")
 	}
 
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"encoding/json"
+	"sort"
+
+	"github.com/computeportal/wtsuite/pkg/cache"
+	"github.com/computeportal/wtsuite/pkg/tree"
+
+	"github.com/computeportal/wtsuite/cmd/wt-site/config"
+)
+
+// the css of the templates used by a view is kept in the view cache,
+// so the bundle can be written without rebuilding unchanged views
+
+// nil if the view hasn't been built with component styles yet
+func cachedComponentStyles(src string) []string {
+	data := cache.GetViewData(src, "styles")
+	if data == nil {
+		return nil
+	}
+
+	styles := make([]string, 0)
+	if err := json.Unmarshal(data, &styles); err != nil {
+		return nil
+	}
+
+	return styles
+}
+
+// without a bundle the component styles are included in the views themselves
+func requiresStylesUpdate(cfg *config.Config, src string) bool {
+	if cfg.StylePath == "" {
+		return false
+	}
+
+	return cachedComponentStyles(src) == nil
+}
+
+// also registered if empty, so the view isn't rebuilt next time
+func registerComponentStyles(src string, root *tree.Root) error {
+	data, err := json.Marshal(root.ComponentStyles())
+	if err != nil {
+		return err
+	}
+
+	cache.SetViewData(src, "styles", data)
+
+	return nil
+}
+
+// each component only once, in order of the sorted views
+func collectComponentStyles(cfg *config.Config) []string {
+	srcs := make([]string, 0)
+	for src, _ := range cfg.GetViews() {
+		srcs = append(srcs, src)
+	}
+
+	sort.Strings(srcs)
+
+	done := make(map[string]bool)
+	res := make([]string, 0)
+	for _, src := range srcs {
+		for _, css := range cachedComponentStyles(src) {
+			if _, ok := done[css]; !ok {
+				done[css] = true
+				res = append(res, css)
+			}
+		}
+	}
+
+	return res
+}
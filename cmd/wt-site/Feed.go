@@ -0,0 +1,359 @@
+package main
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/computeportal/wtsuite/pkg/cache"
+	"github.com/computeportal/wtsuite/pkg/files"
+	"github.com/computeportal/wtsuite/pkg/search"
+	tokens "github.com/computeportal/wtsuite/pkg/tokens/html"
+	"github.com/computeportal/wtsuite/pkg/tree"
+
+	"github.com/computeportal/wtsuite/cmd/wt-site/config"
+)
+
+// layouts accepted for the dates of feed items
+var feedDateLayouts = []string{time.RFC3339, "2006-01-02T15:04", "2006-01-02"}
+
+type FeedItem struct {
+	Url     string    `json:"url"` // relative to the site url
+	Title   string    `json:"title"`
+	Summary string    `json:"summary"`
+	Date    time.Time `json:"date"`
+	Author  string    `json:"author"` // empty to use the author of the feed
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+}
+
+type atomAuthor struct {
+	Name string `xml:"name"`
+}
+
+type atomEntry struct {
+	Title   string      `xml:"title"`
+	Link    atomLink    `xml:"link"`
+	Id      string      `xml:"id"`
+	Updated string      `xml:"updated"`
+	Author  *atomAuthor `xml:"author,omitempty"`
+	Summary string      `xml:"summary,omitempty"`
+}
+
+type atomFeed struct {
+	XMLName  xml.Name    `xml:"feed"`
+	Xmlns    string      `xml:"xmlns,attr"`
+	Title    string      `xml:"title"`
+	Subtitle string      `xml:"subtitle,omitempty"`
+	Links    []atomLink  `xml:"link"`
+	Id       string      `xml:"id"`
+	Updated  string      `xml:"updated"`
+	Author   *atomAuthor `xml:"author,omitempty"`
+	Entries  []atomEntry `xml:"entry"`
+}
+
+type rssItem struct {
+	Title       string `xml:"title"`
+	Link        string `xml:"link"`
+	Guid        string `xml:"guid"`
+	PubDate     string `xml:"pubDate"`
+	Description string `xml:"description,omitempty"`
+}
+
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Description   string    `xml:"description"`
+	LastBuildDate string    `xml:"lastBuildDate"`
+	Items         []rssItem `xml:"item"`
+}
+
+type rssFeed struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+// "/dir/index.html" -> "/dir/"
+func sitePageURL(url string) string {
+	if strings.HasSuffix(url, "/index.html") {
+		return strings.TrimSuffix(url, "index.html")
+	}
+
+	return url
+}
+
+func sourceModTime(src string) time.Time {
+	info, err := os.Stat(src)
+	if err != nil {
+		return time.Now()
+	}
+
+	return info.ModTime()
+}
+
+// sorted keys of the feeds that contain the view
+func viewFeeds(cfg *config.Config, src string) []string {
+	keys := make([]string, 0)
+
+	for key, feedConfig := range cfg.Feeds {
+		for _, view := range feedConfig.Views {
+			if view == src {
+				keys = append(keys, key)
+				break
+			}
+		}
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
+
+// nil if the view hasn't been extracted yet
+func cachedFeedItems(src string) map[string]FeedItem {
+	data := cache.GetViewData(src, "feeds")
+	if data == nil {
+		return nil
+	}
+
+	items := make(map[string]FeedItem)
+	if err := json.Unmarshal(data, &items); err != nil {
+		return nil
+	}
+
+	return items
+}
+
+// views that were added to a feed need to be rebuilt, even if they didn't change
+func requiresFeedUpdate(cfg *config.Config, src string) bool {
+	keys := viewFeeds(cfg, src)
+	if len(keys) == 0 {
+		return false
+	}
+
+	items := cachedFeedItems(src)
+
+	for _, key := range keys {
+		if _, ok := items[key]; !ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// zero time if there is no date query, or if it doesn't match
+func extractFeedDate(feedConfig *config.FeedConfig, root *tree.Root) (time.Time, error) {
+	tags := feedConfig.DateMatch(root)
+	if len(tags) == 0 {
+		return time.Time{}, nil
+	}
+
+	tag := tags[0]
+
+	value := ""
+	if attr := tag.Attributes(); attr != nil {
+		if datetimeToken_, ok := attr.Get("datetime"); ok {
+			if datetimeToken, err := tokens.AssertString(datetimeToken_); err == nil {
+				value = datetimeToken.Value()
+			}
+		}
+	}
+
+	if value == "" {
+		value = strings.Join(search.TagText([]tree.Tag{tag}), "")
+	}
+
+	value = strings.TrimSpace(value)
+
+	for _, layout := range feedDateLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, nil
+		}
+	}
+
+	errCtx := tag.Context()
+	return time.Time{}, errCtx.NewError("Error: unable to parse date \"" + value + "\" (expected eg. 2006-01-02 or 2006-01-02T15:04:05Z)")
+}
+
+func extractFeedItem(feedConfig *config.FeedConfig, src string, url string, root *tree.Root) (FeedItem, error) {
+	title := strings.Join(search.TagText(feedConfig.TitleMatch(root)), " ")
+	if title == "" {
+		title = search.HeadTitle(root)
+	}
+
+	summary := strings.Join(search.TagText(feedConfig.ContentMatch(root)), " ")
+
+	date, err := extractFeedDate(feedConfig, root)
+	if err != nil {
+		return FeedItem{}, err
+	}
+
+	if date.IsZero() {
+		date = sourceModTime(src)
+	}
+
+	author := ""
+	if tags := feedConfig.AuthorMatch(root); len(tags) > 0 {
+		author = strings.TrimSpace(strings.Join(search.TagText(tags[0:1]), " "))
+	}
+
+	return FeedItem{sitePageURL(url), search.PageTitle(title), strings.TrimSpace(summary), date, author}, nil
+}
+
+// the extracted items are kept in the view cache, so that unchanged views can be skipped next time
+func registerFeedItems(cfg *config.Config, src string, url string, root *tree.Root) error {
+	keys := viewFeeds(cfg, src)
+	if len(keys) == 0 {
+		return nil
+	}
+
+	items := make(map[string]FeedItem)
+	for _, key := range keys {
+		feedConfig := cfg.Feeds[key]
+
+		item, err := extractFeedItem(&feedConfig, src, url, root)
+		if err != nil {
+			return err
+		}
+
+		items[key] = item
+	}
+
+	data, err := json.Marshal(items)
+	if err != nil {
+		return err
+	}
+
+	cache.SetViewData(src, "feeds", data)
+
+	return nil
+}
+
+func newAtomFeed(cfg *config.Config, key string, feedConfig *config.FeedConfig, items []FeedItem, updated time.Time) interface{} {
+	feed := &atomFeed{
+		Xmlns:    "http://www.w3.org/2005/Atom",
+		Title:    feedConfig.Title,
+		Subtitle: feedConfig.Description,
+		Links: []atomLink{
+			atomLink{cfg.AbsURL(feedConfig.Link), "alternate"},
+			atomLink{cfg.AbsURL(key), "self"},
+		},
+		Id:      cfg.AbsURL(key),
+		Updated: updated.Format(time.RFC3339),
+		Entries: make([]atomEntry, 0),
+	}
+
+	if feedConfig.Author != "" {
+		feed.Author = &atomAuthor{feedConfig.Author}
+	}
+
+	for _, item := range items {
+		u := cfg.AbsURL(item.Url)
+
+		// entries without their own author inherit the author of the feed
+		var author *atomAuthor = nil
+		if item.Author != "" && item.Author != feedConfig.Author {
+			author = &atomAuthor{item.Author}
+		}
+
+		feed.Entries = append(feed.Entries, atomEntry{item.Title, atomLink{u, ""}, u, item.Date.Format(time.RFC3339), author, item.Summary})
+	}
+
+	return feed
+}
+
+func newRSSFeed(cfg *config.Config, feedConfig *config.FeedConfig, items []FeedItem, updated time.Time) interface{} {
+	feed := &rssFeed{
+		Version: "2.0",
+		Channel: rssChannel{
+			Title:         feedConfig.Title,
+			Link:          cfg.AbsURL(feedConfig.Link),
+			Description:   feedConfig.Description,
+			LastBuildDate: updated.Format(time.RFC1123Z),
+			Items:         make([]rssItem, 0),
+		},
+	}
+
+	for _, item := range items {
+		u := cfg.AbsURL(item.Url)
+		feed.Channel.Items = append(feed.Channel.Items, rssItem{item.Title, u, u, item.Date.Format(time.RFC1123Z), item.Summary})
+	}
+
+	return feed
+}
+
+// feeds are rebuilt from the cached items, most recent first
+func buildFeeds(cfg *config.Config, anyUpdated bool) error {
+	keys := make([]string, 0)
+	for key, _ := range cfg.Feeds {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		feedConfig := cfg.Feeds[key]
+		dst := feedConfig.GetDst()
+
+		if !anyUpdated && files.IsFile(dst) {
+			continue
+		}
+
+		items := make([]FeedItem, 0)
+		for _, src := range feedConfig.Views {
+			if item, ok := cachedFeedItems(src)[key]; ok {
+				items = append(items, item)
+			}
+		}
+
+		sort.Slice(items, func(i, j int) bool {
+			if items[i].Date.Equal(items[j].Date) {
+				return items[i].Url < items[j].Url
+			}
+
+			return items[i].Date.After(items[j].Date)
+		})
+
+		if feedConfig.Limit > 0 && len(items) > feedConfig.Limit {
+			items = items[0:feedConfig.Limit]
+		}
+
+		updated := time.Now()
+		if len(items) > 0 {
+			updated = items[0].Date
+		}
+
+		var feed interface{} = nil
+		if feedConfig.Format == "rss" {
+			feed = newRSSFeed(cfg, &feedConfig, items, updated)
+		} else {
+			feed = newAtomFeed(cfg, key, &feedConfig, items, updated)
+		}
+
+		b, err := xml.MarshalIndent(feed, "", "  ")
+		if err != nil {
+			return err
+		}
+
+		if VERBOSITY >= 2 {
+			fmt.Fprintf(os.Stdout, "writing feed %s (%d items)\n", dst, len(items))
+		}
+
+		if err := ioutil.WriteFile(dst, append(append([]byte(xml.Header), b...), '\n'), 0644); err != nil {
+			return errors.New("Error: " + err.Error())
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,243 @@
+package main
+
+import (
+	"errors"
+	"net/url"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/computeportal/wtsuite/pkg/tokens/context"
+	tokens "github.com/computeportal/wtsuite/pkg/tokens/html"
+	"github.com/computeportal/wtsuite/pkg/tree"
+
+	"github.com/computeportal/wtsuite/cmd/wt-site/config"
+)
+
+// attributes that refer to other pages or to assets
+var linkAttributes = []string{"href", "src", "srcset", "poster", "data"}
+
+type linkRef struct {
+	view     string // url of the view containing the link
+	raw      string
+	target   string // resolved path, without query or fragment
+	fragment string
+	isAsset  bool
+	ctx      context.Context
+}
+
+// checks the links of the built views against the views and files of the config, without any network access
+type LinkChecker struct {
+	known map[string]bool       // urls of all views, files and generated bundles
+	ids   map[string]tree.IDMap // only for views that were built
+	links []linkRef
+}
+
+func siteURL(outputDir string, dst string) string {
+	return filepath.ToSlash(strings.TrimPrefix(dst, outputDir))
+}
+
+func NewLinkChecker(cfg *config.Config, outputDir string) *LinkChecker {
+	known := make(map[string]bool)
+
+	addURL := func(u string) {
+		if u != "" {
+			known[path.Join("/", u)] = true
+		}
+	}
+
+	// also views that are excluded from this build
+	for _, group := range cfg.Views {
+		for _, u := range group {
+			addURL(u)
+		}
+	}
+
+	for _, dst := range cfg.Files {
+		addURL(siteURL(outputDir, dst))
+	}
+
+	addURL(cfg.CssUrl)
+	addURL(cfg.JsUrl)
+	addURL(cfg.MathFontUrl)
+
+	for key, _ := range cfg.Search.Indices {
+		addURL(key)
+	}
+
+	for key, _ := range cfg.Feeds {
+		addURL(key)
+	}
+
+	addURL(cfg.Sitemap.File)
+	addURL(cfg.Robots.File)
+
+	return &LinkChecker{known, make(map[string]tree.IDMap), make([]linkRef, 0)}
+}
+
+func isExternalURL(u *url.URL) bool {
+	return u.Scheme != "" || u.Host != ""
+}
+
+func baseHref(root *tree.Root) string {
+	for _, rootChild := range root.Children() {
+		if rootChild.Name() == "html" {
+			for _, htmlChild := range rootChild.Children() {
+				if htmlChild.Name() == "head" {
+					for _, headChild := range htmlChild.Children() {
+						if headChild.Name() == "base" {
+							if hrefToken_, ok := headChild.Attributes().Get("href"); ok {
+								if hrefToken, err := tokens.AssertString(hrefToken_); err == nil {
+									return hrefToken.Value()
+								}
+							}
+						}
+					}
+				}
+			}
+		}
+	}
+
+	return ""
+}
+
+func (lc *LinkChecker) addLink(view string, base *url.URL, tagName string, attrName string, raw string, ctx context.Context) error {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return nil
+	}
+
+	ref, err := url.Parse(raw)
+	if err != nil {
+		return ctx.NewError("Error: malformed url " + raw)
+	}
+
+	if isExternalURL(ref) {
+		return nil
+	}
+
+	resolved := base.ResolveReference(ref)
+	if isExternalURL(resolved) {
+		// eg. due to an absolute <base href>
+		return nil
+	}
+
+	isAsset := !(attrName == "href" && (tagName == "a" || tagName == "area"))
+
+	lc.links = append(lc.links, linkRef{view, raw, resolved.Path, ref.Fragment, isAsset, ctx})
+
+	return nil
+}
+
+func (lc *LinkChecker) collectLinks(view string, base *url.URL, t tree.Tag) error {
+	if attr := t.Attributes(); attr != nil {
+		for _, attrName := range linkAttributes {
+			valueToken_, ok := attr.Get(attrName)
+			if !ok {
+				continue
+			}
+
+			valueToken, err := tokens.AssertString(valueToken_)
+			if err != nil {
+				continue
+			}
+
+			if attrName == "srcset" {
+				// comma separated candidates, each followed by an optional descriptor
+				for _, candidate := range strings.Split(valueToken.Value(), ",") {
+					if fields := strings.Fields(candidate); len(fields) > 0 {
+						if err := lc.addLink(view, base, t.Name(), attrName, fields[0], valueToken.Context()); err != nil {
+							return err
+						}
+					}
+				}
+			} else if err := lc.addLink(view, base, t.Name(), attrName, valueToken.Value(), valueToken.Context()); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, child := range t.Children() {
+		if err := lc.collectLinks(view, base, child); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// view is the url of the view
+func (lc *LinkChecker) AddView(view string, root *tree.Root) error {
+	idMap := tree.NewIDMap()
+	if err := root.CollectIDs(idMap); err != nil {
+		return err
+	}
+
+	lc.ids[view] = idMap
+
+	base := &url.URL{Path: view}
+	if href := baseHref(root); href != "" {
+		if b, err := base.Parse(href); err == nil {
+			base = b
+		}
+	}
+
+	return lc.collectLinks(view, base, root)
+}
+
+// eg. "/docs/" and "/docs" can both refer to "/docs/index.html"
+func (lc *LinkChecker) resolveTarget(target string) (string, bool) {
+	candidates := []string{target}
+	if strings.HasSuffix(target, "/") {
+		candidates = append(candidates, target+"index.html")
+	} else {
+		candidates = append(candidates, target+"/index.html")
+	}
+
+	for _, c := range candidates {
+		if lc.known[c] {
+			return c, true
+		}
+	}
+
+	return "", false
+}
+
+// returns nil if all links are ok
+func (lc *LinkChecker) Check() error {
+	sort.SliceStable(lc.links, func(i, j int) bool {
+		return lc.links[i].view < lc.links[j].view
+	})
+
+	msgs := make([]string, 0)
+
+	for _, link := range lc.links {
+		target, ok := lc.resolveTarget(link.target)
+
+		if !ok {
+			if link.isAsset {
+				msgs = append(msgs, link.ctx.NewError("Error: missing asset "+link.raw).Error())
+			} else {
+				msgs = append(msgs, link.ctx.NewError("Error: dangling link "+link.raw).Error())
+			}
+
+			continue
+		}
+
+		if link.fragment == "" {
+			continue
+		}
+
+		// fragments of views that weren't built, and of files, can't be checked
+		if idMap, ok := lc.ids[target]; ok && !idMap.Has(link.fragment) {
+			msgs = append(msgs, link.ctx.NewError("Error: missing fragment target #"+link.fragment+" in "+target).Error())
+		}
+	}
+
+	if len(msgs) == 0 {
+		return nil
+	}
+
+	return errors.New(strings.Join(msgs, "\n"))
+}
@@ -0,0 +1,137 @@
+package main
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/computeportal/wtsuite/pkg/files"
+	"github.com/computeportal/wtsuite/pkg/git"
+
+	"github.com/computeportal/wtsuite/cmd/wt-site/config"
+)
+
+type sitemapURL struct {
+	Loc     string `xml:"loc"`
+	LastMod string `xml:"lastmod,omitempty"`
+}
+
+type sitemapURLSet struct {
+	XMLName xml.Name     `xml:"urlset"`
+	Xmlns   string       `xml:"xmlns,attr"`
+	URLs    []sitemapURL `xml:"url"`
+}
+
+// sources that aren't committed fall back to their modification time
+func sitemapLastMods(cfg *config.Config) (map[string]time.Time, error) {
+	result := make(map[string]time.Time)
+
+	switch cfg.Sitemap.LastMod {
+	case "":
+		return result, nil
+	case "git":
+		var err error
+		result, err = git.LastCommitTimes(cfg.Sitemap.Views)
+		if err != nil {
+			return nil, errors.New("Error: unable to get the commit times of the views (" + err.Error() + ")")
+		}
+	}
+
+	for _, src := range cfg.Sitemap.Views {
+		if _, ok := result[src]; !ok {
+			result[src] = sourceModTime(src)
+		}
+	}
+
+	return result, nil
+}
+
+func buildSitemap(cfg *config.Config, outputDir string, anyUpdated bool) error {
+	dst := cfg.Sitemap.GetDst()
+	if dst == "" || (!anyUpdated && files.IsFile(dst)) {
+		return nil
+	}
+
+	lastMods, err := sitemapLastMods(cfg)
+	if err != nil {
+		return err
+	}
+
+	urlSet := &sitemapURLSet{
+		Xmlns: "http://www.sitemaps.org/schemas/sitemap/0.9",
+		URLs:  make([]sitemapURL, 0),
+	}
+
+	for _, src := range cfg.Sitemap.Views {
+		viewDst, ok := cfg.GetViews()[src]
+		if !ok {
+			continue
+		}
+
+		u := sitemapURL{cfg.AbsURL(sitePageURL(siteURL(outputDir, viewDst))), ""}
+		if t, ok := lastMods[src]; ok {
+			u.LastMod = t.UTC().Format(time.RFC3339)
+		}
+
+		urlSet.URLs = append(urlSet.URLs, u)
+	}
+
+	sort.Slice(urlSet.URLs, func(i, j int) bool {
+		return urlSet.URLs[i].Loc < urlSet.URLs[j].Loc
+	})
+
+	b, err := xml.MarshalIndent(urlSet, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if VERBOSITY >= 2 {
+		fmt.Fprintf(os.Stdout, "writing sitemap %s (%d urls)\n", dst, len(urlSet.URLs))
+	}
+
+	if err := ioutil.WriteFile(dst, append(append([]byte(xml.Header), b...), '\n'), 0644); err != nil {
+		return errors.New("Error: " + err.Error())
+	}
+
+	return nil
+}
+
+// always written, it is cheap
+func buildRobots(cfg *config.Config) error {
+	dst := cfg.Robots.GetDst()
+	if dst == "" {
+		return nil
+	}
+
+	var b strings.Builder
+
+	b.WriteString("User-agent: *\n")
+
+	for _, allow := range cfg.Robots.Allow {
+		b.WriteString("Allow: " + allow + "\n")
+	}
+
+	if len(cfg.Robots.Disallow) == 0 {
+		// an empty disallow allows everything
+		b.WriteString("Disallow:\n")
+	} else {
+		for _, disallow := range cfg.Robots.Disallow {
+			b.WriteString("Disallow: " + disallow + "\n")
+		}
+	}
+
+	if cfg.Sitemap.File != "" {
+		b.WriteString("\nSitemap: " + cfg.AbsURL(cfg.Sitemap.File) + "\n")
+	}
+
+	if err := ioutil.WriteFile(dst, []byte(b.String()), 0644); err != nil {
+		return errors.New("Error: " + err.Error())
+	}
+
+	return nil
+}
@@ -7,10 +7,14 @@ import (
 	"io/ioutil"
 	"os"
 	"path/filepath"
-	"sort"
+	"strings"
 
 	"github.com/computeportal/wtsuite/pkg/directives"
 	"github.com/computeportal/wtsuite/pkg/files"
+	"github.com/computeportal/wtsuite/pkg/search"
+	"github.com/computeportal/wtsuite/pkg/styles"
+	"github.com/computeportal/wtsuite/pkg/tokens/context"
+	tokens "github.com/computeportal/wtsuite/pkg/tokens/html"
 	"github.com/computeportal/wtsuite/pkg/tree"
 )
 
@@ -30,18 +34,57 @@ type CmdArgs struct {
 }
 
 type SearchIndexConfig struct {
-	TitleQuery     string `json:"title-query"`
+	TitleQuery     string `json:"title-query"` // optional, falls back to the <title> in <head>
 	titleQuery     XQuery
 	ContentQueries []string `json:"content-queries"`
 	contentQueries []XQuery
 	Pages          []string `json:"pages"` // XXX: is "views" a better name?
+	Description    bool     `json:"description"` // also index <meta name="description">
+	Language       string   `json:"language"`    // for views without <html lang>
+	ShardSize      int      `json:"shard-size"`  // approximate max number of bytes per shard file, 0 to write a single file
+	Weights        map[string]float64 `json:"weights"` // keys: "title", "description", "content"
+	weights        [search.N_FIELDS]float64
+	dst            string
 }
 
 type SearchConfig struct {
-	Indices map[string]SearchIndexConfig `json:"indices"`
+	Indices map[string]SearchIndexConfig `json:"indices"` // key is the url of the index file
 	Ignore  []string                     `json:"ignore"`
 }
 
+type SitemapConfig struct {
+	File    string   `json:"file"`    // eg. "sitemap.xml", empty for no sitemap
+	Views   []string `json:"views"`   // view groups or view files, defaults to all views
+	LastMod string   `json:"lastmod"` // "file" (modification time of the source), "git" (last commit of the source), or empty
+	dst     string
+}
+
+type RobotsConfig struct {
+	File     string   `json:"file"` // eg. "robots.txt", empty for no robots.txt
+	Allow    []string `json:"allow"`
+	Disallow []string `json:"disallow"`
+	dst      string
+}
+
+type FeedConfig struct {
+	Format         string   `json:"format"` // "atom" (default) or "rss"
+	Title          string   `json:"title"`
+	Description    string   `json:"description"`
+	Link           string   `json:"link"` // url of the html page corresponding to the feed, defaults to the site root
+	Author         string   `json:"author"` // name of the author of the feed, optional
+	Views          []string `json:"views"`
+	TitleQuery     string   `json:"title-query"` // css selector, optional, falls back to the <title> in <head>
+	titleQuery     styles.Selector
+	ContentQueries []string `json:"content-queries"` // css selectors, the text of the matches is the summary of an item
+	contentQueries []styles.Selector
+	DateQuery      string `json:"date-query"` // css selector, datetime attribute or text of the first match, falls back to the modification time of the source
+	dateQuery      styles.Selector
+	AuthorQuery    string `json:"author-query"` // css selector, text of the first match is the author of an item, falls back to the author of the feed
+	authorQuery    styles.Selector
+	Limit          int `json:"limit"` // max number of items (most recent first), 0 for all
+	dst            string
+}
+
 type Config struct {
 	Views    map[string]map[string]string `json:"views"` // first key is group name
 	views    map[string]string
@@ -59,6 +102,11 @@ type Config struct {
 	mathFontDst string
 
 	Search SearchConfig `json:"search"`
+
+	SiteUrl string                `json:"site-url"` // absolute url of the root of the output dir, needed for the sitemap and the feeds
+	Sitemap SitemapConfig         `json:"sitemap"`
+	Robots  RobotsConfig          `json:"robots"`
+	Feeds   map[string]FeedConfig `json:"feeds"` // key is the url of the feed file
 }
 
 func NewDefaultCmdArgs() CmdArgs {
@@ -248,7 +296,7 @@ func relAndGlobToAbsControlsFileMap(cmdArgs *CmdArgs, controlGroups map[string]m
 }
 
 func expandSearchViews(cmdArgs *CmdArgs, viewsRelMap map[string]map[string]string, cfg *Config) error {
-	// check of max one per view is done later
+	// a view can be part of several indices
 	for key, indexConfig := range cfg.Search.Indices {
 		var hasViews bool = false
 		var err error = nil
@@ -262,9 +310,11 @@ func expandSearchViews(cmdArgs *CmdArgs, viewsRelMap map[string]map[string]strin
 		}
 
 		// also compile the queries
-		indexConfig.titleQuery, err = ParseXQuery(indexConfig.TitleQuery)
-		if err != nil {
-			return err
+		if indexConfig.TitleQuery != "" {
+			indexConfig.titleQuery, err = ParseXQuery(indexConfig.TitleQuery)
+			if err != nil {
+				return err
+			}
 		}
 
 		indexConfig.contentQueries = make([]XQuery, len(indexConfig.ContentQueries))
@@ -275,12 +325,159 @@ func expandSearchViews(cmdArgs *CmdArgs, viewsRelMap map[string]map[string]strin
 			}
 		}
 
+		if indexConfig.ShardSize < 0 {
+			return errors.New("Error: shard-size of search index " + key + " can't be negative")
+		}
+
+		indexConfig.weights, err = search.FieldWeights(indexConfig.Weights)
+		if err != nil {
+			return err
+		}
+
+		indexConfig.dst, err = filepath.Abs(filepath.Join(cmdArgs.OutputDir, key))
+		if err != nil {
+			return errors.New("Error: bad search index dst path (" + key + ")")
+		}
+
 		// save strategy struct back into list
 		cfg.Search.Indices[key] = indexConfig
 	}
 
-	// sort the ignore words, (just to be sure, needed for BinarySearch)
-	sort.Strings(cfg.Search.Ignore)
+	// fold and sort the ignore words (needed for BinarySearch)
+	cfg.Search.Ignore = search.NormalizeIgnore(cfg.Search.Ignore)
+
+	return nil
+}
+
+func parseSelectors(str string, refPath string) ([]styles.Selector, error) {
+	return styles.ParseSelectorList(
+		tokens.NewValueString(str, context.NewContext(context.NewSource(str), refPath)),
+	)
+}
+
+// nil for an empty query
+func parseSelector(str string, refPath string) (styles.Selector, error) {
+	if str == "" {
+		return nil, nil
+	}
+
+	sels, err := parseSelectors(str, refPath)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(sels) != 1 {
+		return nil, errors.New("Error: expected only one selector in \"" + str + "\"")
+	}
+
+	return sels[0], nil
+}
+
+func outputPath(cmdArgs *CmdArgs, url string, what string) (string, error) {
+	dst, err := filepath.Abs(filepath.Join(cmdArgs.OutputDir, url))
+	if err != nil {
+		return "", errors.New("Error: bad " + what + " dst path (" + url + ")")
+	}
+
+	return dst, nil
+}
+
+// sitemap, robots.txt and feeds
+func expandMetadata(cmdArgs *CmdArgs, viewsRelMap map[string]map[string]string, cfg *Config) error {
+	var err error
+
+	if (cfg.Sitemap.File != "" || len(cfg.Feeds) > 0) && cfg.SiteUrl == "" {
+		return errors.New("Error: site-url is needed for the sitemap and the feeds")
+	}
+
+	if cfg.SiteUrl != "" && !files.IsURL(cfg.SiteUrl) {
+		return errors.New("Error: site-url must be absolute (got " + cfg.SiteUrl + ")")
+	}
+
+	if cfg.Sitemap.File != "" {
+		if len(cfg.Sitemap.Views) == 0 {
+			cfg.Sitemap.Views = []string{"*"}
+		}
+
+		cfg.Sitemap.Views, _, err = expandViewGlobs(cfg.Sitemap.Views, cmdArgs, viewsRelMap)
+		if err != nil {
+			return err
+		}
+
+		switch cfg.Sitemap.LastMod {
+		case "", "file", "git":
+		default:
+			return errors.New("Error: sitemap lastmod must be \"file\", \"git\" or empty (got " + cfg.Sitemap.LastMod + ")")
+		}
+
+		cfg.Sitemap.dst, err = outputPath(cmdArgs, cfg.Sitemap.File, "sitemap")
+		if err != nil {
+			return err
+		}
+	}
+
+	if cfg.Robots.File != "" {
+		cfg.Robots.dst, err = outputPath(cmdArgs, cfg.Robots.File, "robots")
+		if err != nil {
+			return err
+		}
+	}
+
+	for key, feedConfig := range cfg.Feeds {
+		var hasViews bool = false
+		feedConfig.Views, hasViews, err = expandViewGlobs(feedConfig.Views, cmdArgs, viewsRelMap)
+		if err != nil {
+			return err
+		}
+
+		if !hasViews {
+			PrintMessage("Warning: no views found for feed " + key + "'\n'")
+		}
+
+		switch feedConfig.Format {
+		case "":
+			feedConfig.Format = "atom"
+		case "atom", "rss":
+		default:
+			return errors.New("Error: format of feed " + key + " must be \"atom\" or \"rss\" (got " + feedConfig.Format + ")")
+		}
+
+		if feedConfig.Limit < 0 {
+			return errors.New("Error: limit of feed " + key + " can't be negative")
+		}
+
+		feedConfig.titleQuery, err = parseSelector(feedConfig.TitleQuery, cmdArgs.ConfigFile)
+		if err != nil {
+			return err
+		}
+
+		feedConfig.contentQueries = make([]styles.Selector, 0)
+		for _, querySource := range feedConfig.ContentQueries {
+			sels, err := parseSelectors(querySource, cmdArgs.ConfigFile)
+			if err != nil {
+				return err
+			}
+
+			feedConfig.contentQueries = append(feedConfig.contentQueries, sels...)
+		}
+
+		feedConfig.dateQuery, err = parseSelector(feedConfig.DateQuery, cmdArgs.ConfigFile)
+		if err != nil {
+			return err
+		}
+
+		feedConfig.authorQuery, err = parseSelector(feedConfig.AuthorQuery, cmdArgs.ConfigFile)
+		if err != nil {
+			return err
+		}
+
+		feedConfig.dst, err = outputPath(cmdArgs, key, "feed")
+		if err != nil {
+			return err
+		}
+
+		cfg.Feeds[key] = feedConfig
+	}
 
 	return nil
 }
@@ -304,6 +501,10 @@ func ReadConfigFile(cmdArgs *CmdArgs) (*Config, error) {
 			Indices: make(map[string]SearchIndexConfig),
 			Ignore:  make([]string, 0),
 		},
+		SiteUrl: "",
+		Sitemap: SitemapConfig{},
+		Robots:  RobotsConfig{},
+		Feeds:   make(map[string]FeedConfig),
 	}
 
 	b, err := ioutil.ReadFile(cmdArgs.ConfigFile)
@@ -334,6 +535,10 @@ func ReadConfigFile(cmdArgs *CmdArgs) (*Config, error) {
 		return cfg, err
 	}
 
+	if err := expandMetadata(cmdArgs, cfg.Views, cfg); err != nil {
+		return cfg, err
+	}
+
 	if cmdArgs.CssUrl != "" {
 		cfg.CssUrl = cmdArgs.CssUrl
 	}
@@ -408,7 +613,72 @@ func (c *Config) GetMathFontDst() string {
 	return c.mathFontDst
 }
 
+// absolute url of a path relative to the output dir
+func (c *Config) AbsURL(u string) string {
+	return strings.TrimRight(c.SiteUrl, "/") + "/" + strings.TrimLeft(u, "/")
+}
+
+func (s *SitemapConfig) GetDst() string {
+	return s.dst
+}
+
+func (r *RobotsConfig) GetDst() string {
+	return r.dst
+}
+
+func (f *FeedConfig) GetDst() string {
+	return f.dst
+}
+
+// empty if there is no title query
+func (f *FeedConfig) TitleMatch(root *tree.Root) []tree.Tag {
+	if f.titleQuery == nil {
+		return []tree.Tag{}
+	}
+
+	return f.titleQuery.Match(root)
+}
+
+func (f *FeedConfig) ContentMatch(root *tree.Root) []tree.Tag {
+	res := make([]tree.Tag, 0)
+	for _, cq := range f.contentQueries {
+		res = append(res, cq.Match(root)...)
+	}
+
+	return res
+}
+
+// empty if there is no date query
+func (f *FeedConfig) DateMatch(root *tree.Root) []tree.Tag {
+	if f.dateQuery == nil {
+		return []tree.Tag{}
+	}
+
+	return f.dateQuery.Match(root)
+}
+
+// empty if there is no author query
+func (f *FeedConfig) AuthorMatch(root *tree.Root) []tree.Tag {
+	if f.authorQuery == nil {
+		return []tree.Tag{}
+	}
+
+	return f.authorQuery.Match(root)
+}
+
+func (s *SearchIndexConfig) GetDst() string {
+	return s.dst
+}
+
+func (s *SearchIndexConfig) GetWeights() [search.N_FIELDS]float64 {
+	return s.weights
+}
+
 func (s *SearchIndexConfig) TitleMatch(xpath []tree.Tag) bool {
+	if s.titleQuery == nil {
+		return false
+	}
+
 	return s.titleQuery.Match(xpath)
 }
 
@@ -1,26 +1,31 @@
 package main
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io/ioutil"
 	"os"
 	"os/signal"
+	"path"
 	"path/filepath"
 	"runtime/pprof"
   "sort"
+  "strings"
 
 	"github.com/computeportal/wtsuite/pkg/cache"
 	"github.com/computeportal/wtsuite/pkg/directives"
 	"github.com/computeportal/wtsuite/pkg/files"
 	"github.com/computeportal/wtsuite/pkg/git"
 	"github.com/computeportal/wtsuite/pkg/parsers"
+	"github.com/computeportal/wtsuite/pkg/search"
 	"github.com/computeportal/wtsuite/pkg/tokens/context"
 	tokens "github.com/computeportal/wtsuite/pkg/tokens/html"
 	"github.com/computeportal/wtsuite/pkg/tokens/js"
 	"github.com/computeportal/wtsuite/pkg/tokens/js/macros"
 	"github.com/computeportal/wtsuite/pkg/tokens/js/values"
 	"github.com/computeportal/wtsuite/pkg/tokens/patterns"
+	"github.com/computeportal/wtsuite/pkg/tokens/raw"
 	"github.com/computeportal/wtsuite/pkg/tree"
 	"github.com/computeportal/wtsuite/pkg/tree/scripts"
 	"github.com/computeportal/wtsuite/pkg/styles"
@@ -38,10 +43,14 @@ type CmdArgs struct {
 	config.CmdArgs // common for this transpiler and wt-search-index
 
 	compactOutput bool
+	mangleMembers bool
 	forceBuild    bool
 	noAliasing    bool
 	autoLink      bool
   autoDownload  bool 
+	checkLinks    bool
+	strict        bool
+	strictNull    bool
 	profFile      string
 
 	verbosity int // defaults to zero, every -v[v[v]] adds a level
@@ -63,10 +72,14 @@ func parseArgs() CmdArgs {
 		CmdArgs: config.NewDefaultCmdArgs(),
 
 		compactOutput: false,
+		mangleMembers: false,
 		forceBuild:    false,
 		noAliasing:    false,
 		autoLink:      false,
     autoDownload:  false,
+		checkLinks:    false,
+		strict:        false,
+		strictNull:    false,
 		profFile:      "",
 		verbosity:     0,
 	}
@@ -78,9 +91,13 @@ func parseArgs() CmdArgs {
     "",
     []parsers.CLIOption{
       parsers.NewCLIUniqueFlag("c", "compact"          , "-c, --compact                 Compact output with minimal whitespace, newline etc.", &(cmdArgs.compactOutput)),
+      parsers.NewCLIUniqueFlag("", "mangle-members"    , "--mangle-members              Also give short names to non-private class members that aren't accessed via interfaces (requires --compact)", &(cmdArgs.mangleMembers)),
       parsers.NewCLIUniqueFlag("f", "force"            , "-f, --force                   Force a complete project build", &(cmdArgs.forceBuild)),
       parsers.NewCLIUniqueFlag("", "auto-link"         , "--auto-link                   Convert tags to <a> automatically if they have the 'href' attribute", &(cmdArgs.autoLink)), 
       parsers.NewCLIUniqueFlag("", "auto-download"         , "--auto-download                   Automatically download missing packages (use wt-pkg-sync if you want to do this manually). Doesn't update packages!", &(cmdArgs.autoDownload)), 
+      parsers.NewCLIUniqueFlag("", "check-links"       , "--check-links                 Report dangling links, missing #fragment targets and missing assets (rebuilds all views)", &(cmdArgs.checkLinks)),
+      parsers.NewCLIUniqueFlag("", "strict"            , "--strict                      Check html content models and accessibility (alt, labels, heading levels, lang) (rebuilds all views)", &(cmdArgs.strict)),
+      parsers.NewCLIUniqueFlag("", "strict-null"       , "--strict-null                 Null isn't compatible with other types, nullable values (T? or T|null) must be checked before use (rebuilds everything)", &(cmdArgs.strictNull)),
       parsers.NewCLIUniqueFlag("", "no-aliasing"       , "--no-aliasing                 Don't allow standard html tags to be aliased", &(cmdArgs.noAliasing)),
       parsers.NewCLIUniqueKeyValue("D"                 , "-D<name> <value>              Define a global variable with a value", cmdArgs.GlobalVars),
       parsers.NewCLIUniqueKey("B"                      , "-B<name>                      Define a global flag (its value is an empty string)", cmdArgs.GlobalVars),
@@ -102,6 +119,10 @@ func parseArgs() CmdArgs {
     printMessageAndExit(err.Error())
   }
 
+  if cmdArgs.mangleMembers && !cmdArgs.compactOutput {
+    printMessageAndExit("Error: --mangle-members can only be used in combination with --compact")
+  }
+
   if len(cmdArgs.IncludeViews) != 0 && len(cmdArgs.ExcludeViews) != 0 {
     printMessageAndExit("Error: --include-view can't be combined with --exclude-view")
   }
@@ -163,6 +184,7 @@ func setUpEnv(cmdArgs CmdArgs, cfg *config.Config) error {
 		patterns.TAB = ""
 		patterns.LAST_SEMICOLON = ""
     patterns.COMPACT_NAMING = true
+    patterns.MANGLE_ALL_MEMBERS = cmdArgs.mangleMembers
     macros.COMPACT = true
 		tree.COMPRESS_NUMBERS = true
 	}
@@ -175,10 +197,21 @@ func setUpEnv(cmdArgs CmdArgs, cfg *config.Config) error {
 		tree.AUTO_LINK = true
 	}
 
+	if cmdArgs.strict {
+		tree.STRICT = true
+	}
+
+	values.STRICT_NULL = cmdArgs.strictNull
+
   if cmdArgs.autoDownload {
     git.RegisterFetchPublicOrPrivate()
   }
 
+	// worker bundles are placed next to the main js bundle
+	macros.WorkerURL = func(absPath string) string {
+		return path.Join(path.Dir(cfg.JsUrl), macros.WorkerBundleName(absPath))
+	}
+
 	if cfg.PxPerRem != 0 {
 		tokens.PX_PER_REM = cfg.PxPerRem
 	}
@@ -204,7 +237,8 @@ func setUpEnv(cmdArgs CmdArgs, cfg *config.Config) error {
   return files.ResolvePackages(cmdArgs.ConfigFile)
 }
 
-func buildHTMLFile(c *directives.FileCache, src, url, dst string, control string, cssUrl string, jsUrl string, sheet directives.StyleSheet) error {
+// the returned root is used for the search indices
+func buildHTMLFile(c *directives.FileCache, src, url, dst string, control string, cssUrl string, jsUrl string, sheet directives.StyleSheet) (*tree.Root, error) {
 	cache.StartRootUpdate(src)
 
 	directives.SetActiveURL(url)
@@ -215,16 +249,226 @@ func buildHTMLFile(c *directives.FileCache, src, url, dst string, control string
 	directives.UnsetActiveURL()
 
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	output := r.Write("", patterns.NL, patterns.TAB)
 
 	// src is just for info
 	if err := files.WriteFile(src, dst, []byte(output)); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// sorted keys of the search indices that contain the view
+func viewSearchIndices(cfg *config.Config, src string) []string {
+	keys := make([]string, 0)
+
+	for key, indexConfig := range cfg.Search.Indices {
+		for _, view := range indexConfig.Pages {
+			if view == src {
+				keys = append(keys, key)
+				break
+			}
+		}
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
+
+// nil if the view hasn't been indexed yet
+func cachedSearchPages(src string) map[string]search.Page {
+	data := cache.GetViewData(src, "search")
+	if data == nil {
+		return nil
+	}
+
+	pages := make(map[string]search.Page)
+	if err := json.Unmarshal(data, &pages); err != nil {
+		return nil
+	}
+
+	return pages
+}
+
+// views that were added to an index need to be rebuilt, even if they didn't change
+func requiresSearchUpdate(cfg *config.Config, src string) bool {
+	keys := viewSearchIndices(cfg, src)
+	if len(keys) == 0 {
+		return false
+	}
+
+	pages := cachedSearchPages(src)
+
+	for _, key := range keys {
+		if _, ok := pages[key]; !ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// text is matched if it is anywhere inside a matching tag
+func matchesAncestor(match func([]tree.Tag) bool, xpath []tree.Tag) bool {
+	for i := len(xpath); i > 0; i-- {
+		if match(xpath[0:i]) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func extractSearchPage(indexConfig *config.SearchIndexConfig, url string, root *tree.Root) (search.Page, error) {
+	titleParts := make([]string, 0)
+	content := make([]string, 0)
+
+	if err := tree.WalkText(root, []tree.Tag{}, func(xpath []tree.Tag, s string) error {
+		if matchesAncestor(indexConfig.TitleMatch, xpath) {
+			titleParts = append(titleParts, s)
+		} else if matchesAncestor(indexConfig.ContentMatch, xpath) {
+			content = append(content, s)
+		}
+
+		return nil
+	}); err != nil {
+		return search.Page{}, err
+	}
+
+	title := strings.Join(titleParts, " ")
+	if title == "" {
+		title = search.HeadTitle(root)
+	}
+
+	description := ""
+	if indexConfig.Description {
+		description = search.HeadDescription(root)
+	}
+
+	lang := search.HTMLLang(root)
+	if lang == "" {
+		lang = indexConfig.Language
+	}
+
+	return search.NewPage(search.PageURL(url), search.PageTitle(title), description, content, lang), nil
+}
+
+// the extracted pages are kept in the view cache, so that unchanged views can be skipped next time
+func registerSearchPages(cfg *config.Config, src string, url string, root *tree.Root) error {
+	keys := viewSearchIndices(cfg, src)
+	if len(keys) == 0 {
+		return nil
+	}
+
+	pages := make(map[string]search.Page)
+	for _, key := range keys {
+		indexConfig := cfg.Search.Indices[key]
+
+		page, err := extractSearchPage(&indexConfig, url, root)
+		if err != nil {
+			return err
+		}
+
+		pages[key] = page
+	}
+
+	data, err := json.Marshal(pages)
+	if err != nil {
 		return err
 	}
 
+	cache.SetViewData(src, "search", data)
+
+	return nil
+}
+
+// the hash of the index settings is written next to the index, so changes to the config itself also trigger a rebuild
+func searchIndexHash(cfg *config.Config, indexConfig config.SearchIndexConfig) (string, error) {
+	// pages of view groups are expanded in random order
+	indexConfig.Pages = append([]string{}, indexConfig.Pages...)
+	sort.Strings(indexConfig.Pages)
+
+	b, err := json.Marshal(struct {
+		Index  config.SearchIndexConfig
+		Ignore []string
+	}{indexConfig, cfg.Search.Ignore})
+	if err != nil {
+		return "", err
+	}
+
+	return raw.ShortHash(string(b)), nil
+}
+
+func searchIndexHashFile(dst string) string {
+	return dst + ".hash"
+}
+
+// indices are rebuilt from the cached pages, so no html needs to be parsed
+func buildSearchIndices(cfg *config.Config, anyUpdated bool) error {
+	keys := make([]string, 0)
+	for key, _ := range cfg.Search.Indices {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		indexConfig := cfg.Search.Indices[key]
+		dst := indexConfig.GetDst()
+
+		hash, err := searchIndexHash(cfg, indexConfig)
+		if err != nil {
+			return err
+		}
+
+		if !anyUpdated && files.IsFile(dst) {
+			if prev, err := ioutil.ReadFile(searchIndexHashFile(dst)); err == nil && string(prev) == hash {
+				continue
+			}
+		}
+
+		pages := make([]search.Page, 0)
+		for _, src := range indexConfig.Pages {
+			page, ok := cachedSearchPages(src)[key]
+			if !ok {
+				// eg. view had an error
+				continue
+			}
+
+			pages = append(pages, page)
+		}
+
+		sort.Slice(pages, func(i, j int) bool {
+			return pages[i].Url < pages[j].Url
+		})
+
+		si := search.NewIndex()
+		for _, page := range pages {
+			si.AddPage(page)
+		}
+
+		if VERBOSITY >= 2 {
+			fmt.Fprintf(os.Stdout, "writing search index %s (%d pages)\n", dst, len(pages))
+		}
+
+		if err := si.Build(cfg.Search.Ignore, indexConfig.Language, indexConfig.GetWeights()); err != nil {
+			return err
+		}
+
+		if err := si.Write(dst, indexConfig.ShardSize); err != nil {
+			return errors.New("Error: " + err.Error())
+		}
+
+		if err := ioutil.WriteFile(searchIndexHashFile(dst), []byte(hash), 0644); err != nil {
+			return errors.New("Error: " + err.Error())
+		}
+	}
+
 	return nil
 }
 
@@ -287,7 +531,7 @@ func buildProjectViews(cfg *config.Config, cmdArgs CmdArgs) error {
 
 	cache.LoadHTMLCache(cfg.GetViews(), viewControls,
 		cfg.CssUrl, cfg.JsUrl, cfg.PxPerRem, cmdArgs.OutputDir, GitCommit,
-		cmdArgs.compactOutput, cmdArgs.GlobalVars, cmdArgs.forceBuild)
+		cmdArgs.compactOutput, cmdArgs.GlobalVars, cmdArgs.forceBuild || cmdArgs.checkLinks || cmdArgs.strict || cmdArgs.strictNull)
 
 	if cfg.MathFontUrl != "" {
 		directives.MATH_FONT = "FreeSerifMath"
@@ -306,11 +550,6 @@ func buildProjectViews(cfg *config.Config, cmdArgs CmdArgs) error {
     if err != nil {
       return err
     }
-
-    // XXX: or should we write after all extensions have been applied?
-    if err = styles.WriteSheetToFile(sheet, cfg.GetCssDst()); err != nil {
-      return err
-    }
   }
 
   // sort views for consistent behaviour
@@ -320,7 +559,7 @@ func buildProjectViews(cfg *config.Config, cmdArgs CmdArgs) error {
       files.AddCacheDependency(src, cfg.StylePath)
     }
 
-		if cache.RequiresUpdate(src) {
+		if cache.RequiresUpdate(src) || requiresSearchUpdate(cfg, src) || requiresFeedUpdate(cfg, src) || requiresStylesUpdate(cfg, src) {
 			updatedViews = append(updatedViews, src)
 		}
 	}
@@ -329,6 +568,11 @@ func buildProjectViews(cfg *config.Config, cmdArgs CmdArgs) error {
 
   c := directives.NewFileCache()
 
+	var linkChecker *LinkChecker = nil
+	if cmdArgs.checkLinks {
+		linkChecker = NewLinkChecker(cfg, cmdArgs.OutputDir)
+	}
+
 	for _, src := range updatedViews {
 		dst := cfg.GetViews()[src]
 
@@ -339,7 +583,23 @@ func buildProjectViews(cfg *config.Config, cmdArgs CmdArgs) error {
 
 		url := dst[len(cmdArgs.OutputDir):]
 
-		err := buildHTMLFile(c, src, url, dst, control, cfg.CssUrl, cfg.JsUrl, sheet)
+		r, err := buildHTMLFile(c, src, url, dst, control, cfg.CssUrl, cfg.JsUrl, sheet)
+		if err == nil {
+			err = registerSearchPages(cfg, src, url, r)
+		}
+
+		if err == nil {
+			err = registerFeedItems(cfg, src, url, r)
+		}
+
+		if err == nil && sheet != nil {
+			err = registerComponentStyles(src, r)
+		}
+
+		if err == nil && linkChecker != nil {
+			err = linkChecker.AddView(url, r)
+		}
+
 		if err != nil {
 			context.AppendString(err, "Info: error encountered in \""+src+"\"")
 
@@ -366,6 +626,33 @@ func buildProjectViews(cfg *config.Config, cmdArgs CmdArgs) error {
 		cache.SaveHTMLCache(cmdArgs.OutputDir) // also cleans
 	}
 
+	// written after the views, so that only the styles of templates that are actually used are bundled
+	if sheet != nil {
+		if err := styles.WriteBundleToFile(sheet, collectComponentStyles(cfg), cfg.GetCssDst()); err != nil {
+			return err
+		}
+	}
+
+	if err := buildSearchIndices(cfg, len(updatedViews) > 0); err != nil {
+		return err
+	}
+
+	if err := buildFeeds(cfg, len(updatedViews) > 0); err != nil {
+		return err
+	}
+
+	if err := buildSitemap(cfg, cmdArgs.OutputDir, len(updatedViews) > 0); err != nil {
+		return err
+	}
+
+	if err := buildRobots(cfg); err != nil {
+		return err
+	}
+
+	if linkChecker != nil {
+		return linkChecker.Check()
+	}
+
 	return nil
 }
 
@@ -377,7 +664,7 @@ func buildProjectControls(cfg *config.Config, cmdArgs CmdArgs) error {
 
   sort.Strings(allControls)
 
-	cache.LoadControlCache(allControls, cfg.GetJsDst(), cmdArgs.compactOutput, cmdArgs.forceBuild)
+	cache.LoadControlCache(allControls, cfg.GetJsDst(), cmdArgs.compactOutput, cmdArgs.forceBuild || cmdArgs.strictNull)
 
   // sort controls for consistent behaviour
   anyUpdated := false
@@ -424,6 +711,23 @@ func buildProjectControls(cfg *config.Config, cmdArgs CmdArgs) error {
 			return errors.New("Error: " + err.Error())
 		}
 
+		workerBundles, err := scripts.BuildWorkerBundles(cmdArgs.GlobalVars)
+		if err != nil {
+			return err
+		}
+
+		for name, workerContent := range workerBundles {
+			dst := filepath.Join(filepath.Dir(cfg.GetJsDst()), name)
+
+			if VERBOSITY >= 2 {
+				fmt.Fprintf(os.Stdout, "writing worker bundle %s\n", dst)
+			}
+
+			if err := ioutil.WriteFile(dst, []byte(workerContent), 0644); err != nil {
+				return errors.New("Error: " + err.Error())
+			}
+		}
+
 		cache.SaveCache(cfg.GetJsDst())
 
 		return nil
@@ -10,6 +10,7 @@ import (
 	"github.com/computeportal/wtsuite/pkg/files"
 	"github.com/computeportal/wtsuite/pkg/git"
 	"github.com/computeportal/wtsuite/pkg/parsers"
+	_ "github.com/computeportal/wtsuite/pkg/styles" // registers the style builder
   tokens "github.com/computeportal/wtsuite/pkg/tokens/html"
 	"github.com/computeportal/wtsuite/pkg/tokens/js"
 	"github.com/computeportal/wtsuite/pkg/tokens/js/macros"
@@ -40,6 +41,8 @@ type CmdArgs struct {
   pxPerRem int
   autoLink bool
   autoDownload bool
+  strict bool
+  strictNull bool
 
   // stylesheets and js is included inline
 
@@ -66,6 +69,8 @@ func parseArgs() CmdArgs {
     pxPerRem: DEFAULT_PX_PER_REM,
     autoLink: false,
     autoDownload: false,
+    strict: false,
+    strictNull: false,
 		compactOutput: false,
 		verbosity:     0,
 	}
@@ -76,6 +81,8 @@ func parseArgs() CmdArgs {
     []parsers.CLIOption{
       parsers.NewCLIUniqueFlag("c", "compact"       , "-c, --compact          Compact output with minimal whitespace and short names", &(cmdArgs.compactOutput)),
       parsers.NewCLIUniqueFlag("", "auto-link"      , "--auto-link            Convert tags to <a> automatically if they have href attribute", &(cmdArgs.autoLink)),
+      parsers.NewCLIUniqueFlag("", "strict"         , "--strict               Check html content models and accessibility (alt, labels, heading levels, lang)", &(cmdArgs.strict)),
+      parsers.NewCLIUniqueFlag("", "strict-null"    , "--strict-null          Null isn't compatible with other types, nullable values (T? or T|null) must be checked before use", &(cmdArgs.strictNull)),
       parsers.NewCLIUniqueFlag("", "auto-download"         , "--auto-download                   Automatically download missing packages (use wt-pkg-sync if you want to do this manually). Doesn't update packages!", &(cmdArgs.autoDownload)), 
       parsers.NewCLIUniqueFile("o", "output"        , "-o, --output <file>    Defaults to \"" + DEFAULT_OUTPUTFILE + "\" if not set", false, &(cmdArgs.outputFile)),
       parsers.NewCLIUniqueFile("", "control"        , "--control <file>       Optional control file", true, &(cmdArgs.control)),
@@ -112,6 +119,12 @@ func setUpEnv(cmdArgs CmdArgs) error {
 		tree.AUTO_LINK = true
 	}
 
+  if cmdArgs.strict {
+    tree.STRICT = true
+  }
+
+  values.STRICT_NULL = cmdArgs.strictNull
+
   if cmdArgs.autoDownload {
     git.RegisterFetchPublicOrPrivate()
   }
@@ -14,6 +14,7 @@ import (
 type HTMLCacheEntry struct {
 	Deps         []string
 	Control      string
+	Extracted    map[string][]byte // eg. search index entries extracted from the view, so unchanged views don't need to be rebuilt
 	touched      bool
 	lastModified time.Time
 }
@@ -190,12 +191,12 @@ func (c *HTMLCache) StartUpdate(fname string) {
 	//fmt.Println("starting update of ", fname)
 	//}
 
-	c.Data[fname] = HTMLCacheEntry{make([]string, 0), "", true, time.Time{}}
+	c.Data[fname] = HTMLCacheEntry{make([]string, 0), "", nil, true, time.Time{}}
 }
 
 func (c *HTMLCache) StartRootUpdate(fname string) {
 	fmt.Println("starting root update of ", fname)
-	c.Data[fname] = HTMLCacheEntry{make([]string, 0), "", true, time.Time{}}
+	c.Data[fname] = HTMLCacheEntry{make([]string, 0), "", nil, true, time.Time{}}
 }
 
 func StartRootUpdate(fname string) {
@@ -271,6 +272,43 @@ func SetViewControl(fname string, control string) {
 	c.Data[fname] = entry
 }
 
+// key identifies the purpose of the data (eg. "search")
+func SetViewData(fname string, key string, data []byte) {
+	c, ok := _cache.(*HTMLCache)
+	if !ok {
+		panic("unexpected")
+	}
+
+	entry, ok := c.Data[fname]
+	if !ok {
+    c.StartUpdate(fname)
+    entry = c.Data[fname]
+	}
+
+	if entry.Extracted == nil {
+		entry.Extracted = make(map[string][]byte)
+	}
+
+	entry.Extracted[key] = data
+
+	c.Data[fname] = entry
+}
+
+// nil if nothing was extracted from the view
+func GetViewData(fname string, key string) []byte {
+	c, ok := _cache.(*HTMLCache)
+	if !ok {
+		panic("unexpected")
+	}
+
+	entry, ok := c.Data[fname]
+	if !ok {
+		return nil
+	}
+
+	return entry.Extracted[key]
+}
+
 func (c *HTMLCache) requiresUpdate(fname string, age time.Time, m map[string]bool) bool {
 	if prevVal, ok := m[fname]; ok {
 		return prevVal
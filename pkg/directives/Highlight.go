@@ -0,0 +1,191 @@
+package directives
+
+import (
+  "html"
+  "strings"
+
+	"github.com/computeportal/wtsuite/pkg/parsers"
+	"github.com/computeportal/wtsuite/pkg/tokens/context"
+	tokens "github.com/computeportal/wtsuite/pkg/tokens/html"
+	"github.com/computeportal/wtsuite/pkg/tree"
+)
+
+// the pre or code tag gets the classes "hl hl-<lang>", the spans inside get "hl-<class>"
+func addHighlightClass(attr *tokens.StringDict, lang string, ctx context.Context) error {
+  class := "hl hl-" + lang
+
+  if classToken_, ok := attr.Get("class"); ok && !tokens.IsNull(classToken_) {
+    classToken, err := tokens.AssertString(classToken_)
+    if err != nil {
+      return err
+    }
+
+    if classToken.Value() != "" {
+      class = class + " " + classToken.Value()
+    }
+  }
+
+  attr.Set("class", tokens.NewValueString(class, ctx))
+
+  return nil
+}
+
+func appendHighlighted(tag tree.Tag, lang string, code string, codeCtx context.Context) error {
+  spans, err := parsers.Highlight(lang, code, codeCtx)
+  if err != nil {
+    return err
+  }
+
+  for _, span := range spans {
+    text := tree.NewVerbatimText(html.EscapeString(span.Text), codeCtx)
+
+    if span.Class == parsers.HL_NONE {
+      tag.AppendChild(text)
+      continue
+    }
+
+    spanAttr := tokens.NewEmptyStringDict(codeCtx)
+    spanAttr.Set("class", tokens.NewValueString("hl-"+span.Class, codeCtx))
+
+    spanTag, err := tree.BuildTag("span", spanAttr, codeCtx)
+    if err != nil {
+      return err
+    }
+
+    spanTag.AppendChild(text)
+    tag.AppendChild(spanTag)
+  }
+
+  return nil
+}
+
+// lang attribute of pre and code tags, "" if not set
+// other values of the global lang attribute (eg. lang="fr") are left alone
+func popHighlightLang(tagName string, attr *tokens.StringDict) (string, error) {
+  if tagName != "pre" && tagName != "code" {
+    return "", nil
+  }
+
+  langToken_, ok := attr.Get("lang")
+  if !ok || tokens.IsNull(langToken_) {
+    return "", nil
+  }
+
+  langToken, err := tokens.AssertString(langToken_)
+  if err != nil {
+    return "", err
+  }
+
+  lang := langToken.Value()
+  if !parsers.IsHighlightLang(lang) {
+    return "", nil
+  }
+
+  attr.Delete("lang")
+
+  if err := addHighlightClass(attr, lang, langToken.Context()); err != nil {
+    return "", err
+  }
+
+  return lang, nil
+}
+
+// the children of a pre or code tag with a lang attribute must be text
+func buildHighlightedChildren(tag tree.Tag, lang string, tagToken *tokens.Tag) error {
+  var b strings.Builder
+  ctx := tagToken.Context()
+
+  for i, child := range tagToken.Children() {
+    if !child.IsText() {
+      errCtx := child.Context()
+      return errCtx.NewError("Error: expected only text inside highlighted " + tagToken.Name())
+    }
+
+    if i == 0 {
+      ctx = tokens.NewValueString(child.Text(), child.Context()).InnerContext()
+    }
+
+    b.WriteString(child.Text())
+  }
+
+  return appendHighlighted(tag, lang, b.String(), ctx)
+}
+
+// highlight("code", "lang") is a pre tag, highlight("code", "lang", inline=true) a code tag
+func Highlight(scope Scope, node Node, tag *tokens.Tag) error {
+  ctx := tag.Context()
+
+  attrScope := NewSubScope(scope)
+
+  if err := tag.AssertEmpty(); err != nil {
+    return err
+  }
+
+  attr, err := tag.Attributes([]string{"value", "lang"})
+  if err != nil {
+    return err
+  }
+
+	attr, err = attr.EvalStringDict(attrScope)
+	if err != nil {
+		return err
+	}
+
+  attr, err = removeForcedSuffix(attr)
+  if err != nil {
+    return err
+  }
+
+	valueToken, err := tokens.DictString(attr, "value")
+	if err != nil {
+		return err
+	}
+
+  attr.Delete("value")
+
+  name := "pre"
+  if inlineToken_, ok := attr.Get("inline"); ok {
+		inlineToken, err := tokens.AssertBool(inlineToken_)
+		if err != nil {
+			return err
+		}
+
+    if inlineToken.Value() {
+      name = "code"
+    }
+
+    attr.Delete("inline")
+  }
+
+  if _, ok := attr.Get("lang"); !ok {
+    return ctx.NewError("Error: lang not specified")
+  }
+
+  langToken, err := tokens.DictString(attr, "lang")
+  if err != nil {
+    return err
+  }
+
+  if !parsers.IsHighlightLang(langToken.Value()) {
+    errCtx := langToken.Context()
+    return errCtx.NewError("Error: highlighting of \"" + langToken.Value() + "\" not supported")
+  }
+
+  lang, err := popHighlightLang(name, attr)
+  if err != nil {
+    return err
+  }
+
+  hlTag, err := tree.BuildTag(name, attr, ctx)
+  if err != nil {
+    return err
+  }
+
+  if err := appendHighlighted(hlTag, lang, valueToken.Value(), valueToken.InnerContext()); err != nil {
+    return err
+  }
+
+  return node.AppendChild(hlTag)
+}
+
+var _highlightOk = registerDirective("highlight", Highlight)
@@ -1,6 +1,7 @@
 package directives
 
 import (
+  "path/filepath"
   "strings"
 
 	"github.com/computeportal/wtsuite/pkg/files"
@@ -18,6 +19,16 @@ type CachedScope struct {
 }
 
 func parseFile(path string) ([]*tokens.Tag, context.Context, error) {
+  if filepath.Ext(path) == ".md" {
+    p, err := parsers.NewMarkdownParser(path)
+    if err != nil {
+      return nil, context.Context{}, err
+    }
+
+    tags, err := p.BuildTags()
+    return tags, p.NewContext(0, 1), err
+  }
+
   p, err := parsers.NewTemplateParser(path)
   if err != nil {
     return nil, context.Context{}, err
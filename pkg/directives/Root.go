@@ -1,6 +1,8 @@
 package directives
 
 import (
+	"strings"
+
 	"github.com/computeportal/wtsuite/pkg/tree"
 	//"github.com/computeportal/wtsuite/pkg/tree/scripts"
 )
@@ -59,6 +61,23 @@ func FinalizeRoot(node *RootNode, control string, cssUrl string, jsUrl string, s
 		return nil, err
 	}
 
+  if tree.STRICT {
+    if err := root.ValidateStrict(idMap); err != nil {
+      return nil, err
+    }
+  }
+
+  for _, css := range node.componentStyles {
+    root.AddComponentStyle(css)
+  }
+
+  // without a bundle the component styles must be included in the document itself
+  if sheet == nil && len(node.componentStyles) > 0 {
+    if err := root.IncludeStyle(strings.Join(node.componentStyles, "")); err != nil {
+      return nil, err
+    }
+  }
+
   // also apply any registered stylesheets
   sheets := node.sheets
   if sheet != nil {
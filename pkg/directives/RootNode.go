@@ -9,6 +9,8 @@ import (
 type RootNode struct {
 	t NodeType
   sheets []StyleSheet
+  componentClasses map[string]bool
+  componentStyles []string // in order of first use
 	NodeData
 }
 
@@ -17,7 +19,7 @@ func NewRootNode(tag tree.Tag, t NodeType) *RootNode {
 		panic("expected Root or SVGRoot (tags with empty names)")
 	}
 
-	return &RootNode{t, make([]StyleSheet, 0), newNodeData(tag, nil)}
+	return &RootNode{t, make([]StyleSheet, 0), make(map[string]bool), make([]string, 0), newNodeData(tag, nil)}
 }
 
 func (n *RootNode) Type() NodeType {
@@ -50,3 +52,26 @@ func (n *RootNode) getNode() Node {
 func (n *RootNode) RegisterStyleSheet(sheet StyleSheet) {
   n.sheets = append(n.sheets, sheet)
 }
+
+func (n *RootNode) HasComponentStyle(class string) bool {
+  _, ok := n.componentClasses[class]
+  return ok
+}
+
+// each template contributes its css only once, regardless of the number of instances
+func (n *RootNode) AddComponentStyle(class string, css string) {
+  n.componentClasses[class] = true
+  n.componentStyles = append(n.componentStyles, css)
+}
+
+func GetRootNode(node Node) *RootNode {
+  if rNode, ok := node.(*RootNode); ok {
+    return rNode
+  }
+
+  if parent := node.Parent(); parent != nil {
+    return GetRootNode(parent)
+  }
+
+  return nil
+}
@@ -103,6 +103,14 @@ func buildTree(parent Scope, parentNode Node, nt NodeType,
     return err
   }
 
+  lang := ""
+  if parentNode.Type() == HTML {
+    lang, err = popHighlightLang(tagToken.Name(), attr)
+    if err != nil {
+      return err
+    }
+  }
+
 	var tag tree.Tag
 	switch parentNode.Type() {
 	case SVG:
@@ -149,10 +157,19 @@ func buildTree(parent Scope, parentNode Node, nt NodeType,
   }
 
 	if op != nil {
+    if lang != "" {
+      errCtx := tagToken.Context()
+      return errCtx.NewError("Error: lang can't be used for a tag that is extended by a template")
+    }
+
 		if err := op.Apply(scope, newNode, tagToken.Children()); err != nil {
 			return err
 		}
-	} else {
+	} else if lang != "" {
+    if err := buildHighlightedChildren(tag, lang, tagToken); err != nil {
+      return err
+    }
+  } else {
     // there is no way to estimate the number of children before hand?
 		for _, child := range tagToken.Children() {
 			if err := BuildTag(scope, newNode, child); err != nil {
@@ -180,6 +197,8 @@ func buildDeferred(scope Scope, node *TemplateNode, tag *tokens.Tag) error {
   case key == "replace":
     // replace directive is not directly registered
     return ReplaceBlockChildren(scope, node, tag)
+  case key == "fill" && tag.IsDirective():
+    return FillSlot(scope, node, tag)
   //case IsDirective(key) && tag.IsDirective():
 		//return BuildDirective(scope, node, tag)
   default:
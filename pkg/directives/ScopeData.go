@@ -100,8 +100,11 @@ func (scope *ScopeData) Sync(dst Scope, keepAutoVars, keepImports, asImports boo
 				c.scope,
 				c.args,
 				c.argDefaults,
+				c.argTypes,
 				c.superAttr,
 				c.children,
+				c.styles,
+				c.class,
 				true,
 				c.exported,
         c.final,
@@ -154,8 +157,11 @@ func (scope *ScopeData) SyncPackage(dst Scope, keepAutoVars, keepImports, asImpo
           c.scope,
           c.args,
           c.argDefaults,
+          c.argTypes,
           c.superAttr,
           c.children,
+          c.styles,
+          c.class,
           true,
           false,
           c.final,
@@ -250,8 +256,11 @@ func (scope *ScopeData) SyncFiltered(dst Scope, keepAutoVars, keepImports, asImp
 				c.scope,
 				c.args,
 				c.argDefaults,
+				c.argTypes,
 				c.superAttr,
 				c.children,
+				c.styles,
+				c.class,
 				true,
 				false,
         c.final,
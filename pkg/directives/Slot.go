@@ -0,0 +1,79 @@
+package directives
+
+import (
+	tokens "github.com/computeportal/wtsuite/pkg/tokens/html"
+)
+
+// slots are blocks that are filled by sections of the caller (eg. header: ...)
+//  * the children of a slot are its default content, required slots must be filled
+//  * sections are evaluated in the scope of the caller, just like replace
+//  * a template that extends another template can fill the slots of that template in its body,
+//    and can pass slots on to its own callers by declaring a slot inside such a section
+//  * super attributes only apply to the extended tag, they can't be used to fill slots
+//  * .final templates can declare slots, they just can't be extended
+
+func getSlotName(tag *tokens.Tag) (string, bool, error) {
+	attr, err := tag.Attributes([]string{"name"})
+	if err != nil {
+		return "", false, err
+	}
+
+	if err := attr.AssertOnlyValidKeys([]string{"name", "required"}); err != nil {
+		return "", false, err
+	}
+
+	nameToken, err := tokens.DictString(attr, "name")
+	if err != nil {
+		return "", false, err
+	}
+
+	required, err := tokens.DictHasFlag(attr, "required")
+	if err != nil {
+		return "", false, err
+	}
+
+	name := nameToken.Value()
+	if name == "default" {
+		errCtx := nameToken.Context()
+		return "", false, errCtx.NewError("Error: invalid slot name (default is reserved for the children)")
+	}
+
+	return name, required, nil
+}
+
+func FillSlot(scope Scope, node *TemplateNode, tag *tokens.Tag) error {
+	name, err := getOpNameTarget("slot", tag)
+	if err != nil {
+		return err
+	}
+
+	if name == "default" {
+		errCtx := tag.Context()
+		return errCtx.NewError("Error: invalid slot name (default is reserved for the children)")
+	}
+
+	for _, prev := range node.operations {
+		if prev.Target() == name {
+			errCtx := tag.Context()
+			err := errCtx.NewError("Error: slot " + name + " already filled")
+			err.AppendContextString("Info: filled here", prev.Context())
+			return err
+		}
+	}
+
+	subScope := NewSubScope(scope)
+
+	op := &ReplaceChildrenOp{[][]*tokens.Tag{tag.Children()}, []Scope{subScope},
+		newOperationData(name, tag.Context())}
+
+	return node.PushOp(op)
+}
+
+// sections are handled by buildDeferred, so if we get here the section isn't a direct child of a template call
+func evalFill(scope Scope, node Node, tag *tokens.Tag) error {
+	errCtx := tag.Context()
+	return errCtx.NewError("Error: slot section not directly inside template call")
+}
+
+var _evalSlotOk = registerDirective("slot", EvalBlock)
+var _evalFillOk = registerDirective("fill", evalFill)
@@ -13,6 +13,13 @@ func RegisterBuildStyle(fn BuildStyleFunc) bool {
   return true
 }
 
+type BuildComponentStyleFunc func(d *tokens.StringDict) (string, error)
+var BuildComponentStyle BuildComponentStyleFunc = nil
+func RegisterBuildComponentStyle(fn BuildComponentStyleFunc) bool {
+  BuildComponentStyle = fn
+  return true
+}
+
 func buildInlineStyle(node Node, attr *tokens.StringDict, content string,
 	ctx context.Context) error {
 	if style, err := tree.NewStyle(attr, content, ctx); err != nil {
@@ -16,8 +16,11 @@ type Template struct {
 	scope       Scope
 	args        *tokens.List // works a little different from Parens
 	argDefaults *tokens.List // works a little different from Parens
+	argTypes    []*tokens.Type // entries are nil for untyped args
 	superAttr   *tokens.RawDict // passed on to super
 	children    []*tokens.Tag
+	styles      []*tokens.Tag // scoped to the instances of this template
+	class       string // attached to the root tag of each instance, if there are styles
 	imported    bool
 	exported    bool
   final       bool
@@ -25,8 +28,10 @@ type Template struct {
 }
 
 func newTemplate(name string, extends string, scope Scope, args *tokens.List, argDefaults *tokens.List,
+	argTypes []*tokens.Type,
 	superAttr *tokens.RawDict,
 	children []*tokens.Tag,
+	styles []*tokens.Tag,
 	exported bool, final bool, ctx context.Context) Template {
 
 	// copy the scope, in order to take a snapshot of its state
@@ -38,8 +43,11 @@ func newTemplate(name string, extends string, scope Scope, args *tokens.List, ar
 		subScope,
 		args,
 		argDefaults,
+		argTypes,
 		superAttr,
 		children,
+		styles,
+		tree.NewStableClass(ctx.Path() + ":" + name),
 		false,
 		exported,
     final,
@@ -57,7 +65,8 @@ func assertValidTag(nameToken *tokens.String) error {
 		name == "import" || name == "print" || name == "script" || name == "style" ||
 		name == "switch" || name == "var" || name == "else" || name == "elseif" ||
 		name == "case" || name == "default" ||
-		name == "replace" || name == "append" || name == "prepend" || name == "block" {
+		name == "replace" || name == "append" || name == "prepend" || name == "block" ||
+		name == "slot" || name == "fill" {
 		return errCtx.NewError("Error: invalid tag name, is already a directive")
 	} else if tree.IsTag(name) && NO_ALIASING {
 		err := errCtx.NewError("Error: invalid tag name, is already a tag")
@@ -130,6 +139,7 @@ func AddTemplate(scope Scope, node Node, tag *tokens.Tag) error {
 
 	var args *tokens.List = nil
 	var argDefaults *tokens.List = nil
+	var argTypes []*tokens.Type = nil
 	if args_, ok := attr.Get("args"); ok {
 		if tokens.IsList(args_) {
 			// dont evaluate!, but make sure we have only strings
@@ -139,6 +149,7 @@ func AddTemplate(scope Scope, node Node, tag *tokens.Tag) error {
 			}
 
 			argDefaults = tokens.NewNilList(args.Len(), attr.Context())
+			argTypes = make([]*tokens.Type, args.Len())
 		} else if tokens.IsParens(args_) {
 			argParens, err := tokens.AssertParens(args_)
 			if err != nil {
@@ -147,6 +158,7 @@ func AddTemplate(scope Scope, node Node, tag *tokens.Tag) error {
 
 			args = tokens.NewValuesList(argParens.Values(), argParens.Context())
 			argDefaults = tokens.NewValuesList(argParens.Alts(), argParens.Context())
+			argTypes = argParens.Types()
 
       if err := assertArgDefaultsLast(argDefaults); err != nil {
         return err
@@ -158,6 +170,7 @@ func AddTemplate(scope Scope, node Node, tag *tokens.Tag) error {
 	} else {
 		args = tokens.NewEmptyList(attr.Context())
 		argDefaults = tokens.NewNilList(args.Len(), attr.Context())
+		argTypes = make([]*tokens.Type, 0)
 	}
 
 	exported, err := tokens.DictHasFlag(attr, "export")
@@ -185,6 +198,11 @@ func AddTemplate(scope Scope, node Node, tag *tokens.Tag) error {
     return err
   }
 
+	children, styles, err := splitTemplateStyles(tag.Children())
+	if err != nil {
+		return err
+	}
+
 	key := nameToken.Value()
 
 	switch {
@@ -194,7 +212,7 @@ func AddTemplate(scope Scope, node Node, tag *tokens.Tag) error {
 		err.AppendContextString("Info: defined here", scope.GetTemplate(key).ctx)
 		return err
 	default:
-    if err := scope.SetTemplate(key, newTemplate(key, extends, scope, args, argDefaults, superAttr, tag.Children(), exported, final, tag.Context())); err != nil {
+    if err := scope.SetTemplate(key, newTemplate(key, extends, scope, args, argDefaults, argTypes, superAttr, children, styles, exported, final, tag.Context())); err != nil {
       return err
     }
 	}
@@ -202,6 +220,32 @@ func AddTemplate(scope Scope, node Node, tag *tokens.Tag) error {
 	return nil
 }
 
+// style blocks directly inside the template body are scoped to its instances,
+// other style tags (eg. nested, or plain css text) are built as usual
+func splitTemplateStyles(tags []*tokens.Tag) ([]*tokens.Tag, []*tokens.Tag, error) {
+	children := make([]*tokens.Tag, 0)
+	styles := make([]*tokens.Tag, 0)
+
+	for _, tag := range tags {
+		if tag.Name() == "style" && !tag.IsDirective() && !tag.IsScript() {
+			attr := tag.RawAttributes()
+			if _, ok := attr.Get(".content"); ok {
+				if attr.Len() != 1 {
+					errCtx := tag.Context()
+					return nil, nil, errCtx.NewError("Error: template style can't have attributes")
+				}
+
+				styles = append(styles, tag)
+				continue
+			}
+		}
+
+		children = append(children, tag)
+	}
+
+	return children, styles, nil
+}
+
 // first return value: ok
 // second return value: can be passed to parent
 func (c Template) hasArg(key string) bool {
@@ -223,6 +267,22 @@ func (c Template) hasArg(key string) bool {
 	return false
 }
 
+// nil if arg is untyped
+func (c Template) argType(key string) *tokens.Type {
+	for i, arg_ := range c.args.GetTokens() {
+		arg, err := tokens.AssertString(arg_)
+		if err != nil {
+			panic("should've been caught before")
+		}
+
+		if arg.Value() == key {
+			return c.argTypes[i]
+		}
+	}
+
+	return nil
+}
+
 func (c Template) argsStringList() ([]string, error) {
 	res := make([]string, 0)
 
@@ -263,13 +323,17 @@ func (c Template) argsWithoutDefaults() []string {
 func (c Template) listValidArgNames() string {
 	var b strings.Builder
 
-	for _, v := range c.args.GetTokens() {
+	for i, v := range c.args.GetTokens() {
 		arg, err := tokens.AssertString(v)
 		if err != nil {
 			panic(err)
 		}
 
 		b.WriteString(arg.Value())
+		if c.argTypes[i] != nil {
+			b.WriteString(": ")
+			b.WriteString(c.argTypes[i].Write())
+		}
 		b.WriteString("\n")
 	}
 
@@ -299,6 +363,13 @@ func (c Template) instantiate(node *TemplateNode, args *tokens.StringDict,
 				"\n"+c.listValidArgNames())
 			return err
 		} else if ok {
+      // errors point to the caller
+      if typ := c.argType(kVal); typ != nil {
+        if err := typ.Check(v, k.Context()); err != nil {
+          return err
+        }
+      }
+
       // dont set if forced but not actually available
       vVar := functions.Var{v, true, true, false, false, v.Context()}
       if err := subScope.SetVar(kVal, vVar); err != nil {
@@ -340,6 +411,12 @@ func (c Template) instantiate(node *TemplateNode, args *tokens.StringDict,
           return err
         }
 
+        if typ := c.argTypes[i]; typ != nil {
+          if err := typ.Check(v, t.Context()); err != nil {
+            return err
+          }
+        }
+
         vVar := functions.Var{v, true, true, false, false, v.Context()}
         if err := subScope.SetVar(argName, vVar); err != nil {
           return err
@@ -376,7 +453,7 @@ func (c Template) instantiate(node *TemplateNode, args *tokens.StringDict,
 			nType = SVG
 		}
 
-    tNode, err := prepareOperations(subScope, node, c.children, c.ctx)
+    tNode, err := prepareOperations(subScope, node, c.children, ctx)
     if err != nil {
       return err
     }
@@ -412,6 +489,109 @@ func (c Template) instantiate(node *TemplateNode, args *tokens.StringDict,
     panic(err)
   }
 
+  if len(c.styles) > 0 {
+    if err := c.applyStyles(node, child); err != nil {
+      return err
+    }
+  }
+
+	return nil
+}
+
+// the css is built once per document, in the scope of the template definition (so without args)
+func (c Template) buildStyles() (string, error) {
+	subScope := NewSubScope(c.scope)
+
+	var b strings.Builder
+	for _, tag := range c.styles {
+		attr, err := tag.Attributes([]string{})
+		if err != nil {
+			return "", err
+		}
+
+		contentToken, ok := attr.Get(".content")
+		if !ok {
+			panic("expected .content")
+		}
+
+		contentToken, err = contentToken.Eval(subScope)
+		if err != nil {
+			return "", err
+		}
+
+		d, err := tokens.AssertStringDict(contentToken)
+		if err != nil {
+			return "", err
+		}
+
+		// selectors are relative to the root tag of the instance, :scope is the root tag itself
+		inner := tokens.NewEmptyStringDict(d.Context())
+		if err := d.Loop(func(key *tokens.String, value tokens.Token, last bool) error {
+			if key.Value() == ":scope" {
+				rootProps, err := tokens.AssertStringDict(value)
+				if err != nil {
+					return err
+				}
+
+				return rootProps.Loop(func(k *tokens.String, v tokens.Token, last bool) error {
+					inner.Set(k, v)
+					return nil
+				})
+			}
+
+			inner.Set(key, value)
+			return nil
+		}); err != nil {
+			return "", err
+		}
+
+		scoped := tokens.NewEmptyStringDict(d.Context())
+		scoped.Set("."+c.class, inner)
+
+		css, err := BuildComponentStyle(scoped)
+		if err != nil {
+			return "", err
+		}
+
+		b.WriteString(css)
+	}
+
+	return b.String(), nil
+}
+
+func (c Template) applyStyles(node *TemplateNode, child tree.Tag) error {
+	visible, ok := child.(tree.VisibleTag)
+	if !ok {
+		errCtx := c.ctx
+		return errCtx.NewError("Error: template with style must extend a visible tag")
+	}
+
+	classes := visible.GetClasses()
+
+	// a forced class attribute would otherwise be overwritten by the classes when writing
+	childAttr := child.Attributes()
+	if classToken_, ok := childAttr.Get("class"); ok {
+		if classToken, err := tokens.AssertString(classToken_); err == nil {
+			classes = append(classes, strings.Fields(classToken.Value())...)
+			childAttr.Delete("class")
+		}
+	}
+
+	visible.SetClasses(append(classes, c.class))
+
+	// data URIs (eg. svg images) don't have a root, the css couldn't be used there anyway
+	root := GetRootNode(node)
+	if root == nil || root.HasComponentStyle(c.class) {
+		return nil
+	}
+
+	css, err := c.buildStyles()
+	if err != nil {
+		return err
+	}
+
+	root.AddComponentStyle(c.class, css)
+
 	return nil
 }
 
@@ -424,9 +604,16 @@ func prepareBlocks(node *TemplateNode, tags []*tokens.Tag, insideBranch bool, ne
       if err := prepareBlocks(node, tag.Children(), true, newOpNames); err != nil {
         return err
       }
-    case "block":
+    case "block", "slot":
       if node.GetBlockTarget(tag) == "" {
-        name, err := getOpNameTarget("name", tag)
+        var name string
+        required := false
+        var err error
+        if tag.Name() == "slot" {
+          name, required, err = getSlotName(tag)
+        } else {
+          name, err = getOpNameTarget("name", tag)
+        }
         if err != nil {
           return err
         }
@@ -449,6 +636,11 @@ func prepareBlocks(node *TemplateNode, tags []*tokens.Tag, insideBranch bool, ne
           node.SetBlockTarget(tag, newName)
         } else if newName, ok := newOpNames[name]; ok {
           node.SetBlockTarget(tag, newName)
+        } else if required {
+          errCtx := node.ctx
+          err := errCtx.NewError("Error: required slot " + name + " not filled")
+          err.AppendContextString("Info: slot declared here", tag.Context())
+          return err
         } else {
           node.SetBlockTarget(tag, name)
         }
@@ -508,7 +700,7 @@ func BuildTemplate(scope Scope, node Node, tag *tokens.Tag) error {
 		return err
 	}
 
-	tNode, err := prepareOperations(attrScope, node, tag.Children(), template.ctx)
+	tNode, err := prepareOperations(attrScope, node, tag.Children(), tag.Context())
 	if err != nil {
 		return err
 	}
@@ -14,7 +14,7 @@ type TemplateNode struct {
 	operations []Operation
   collectDeferred bool
   blockTargets map[*tokens.Tag]string
-  ctx context.Context
+  ctx context.Context // of the template call
 }
 
 func NewTemplateNode(parent Node, ctx context.Context) *TemplateNode {
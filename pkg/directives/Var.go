@@ -36,6 +36,17 @@ func AddVar(scope Scope, node Node, tag *tokens.Tag) error {
 		return err
 	}
 
+	// optional type annotation
+	var typ *tokens.Type = nil
+	if typ_, ok := attr.Get(".type"); ok {
+		typ, err = tokens.AssertType(typ_)
+		if err != nil {
+			return err
+		}
+
+		attr.Delete(".type")
+	}
+
 	n := attr.Len()
 	if n != 1 && n != 2 {
 		errCtx := attr.Context()
@@ -72,6 +83,12 @@ func AddVar(scope Scope, node Node, tag *tokens.Tag) error {
 		return err
 	}
 
+	if typ != nil {
+		if err := typ.Check(valueToken, nameToken.InnerContext()); err != nil {
+			return err
+		}
+	}
+
 	constant, err := tokens.DictHasFlag(attr, "constant")
 	if err != nil {
 		return err
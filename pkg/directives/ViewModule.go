@@ -292,6 +292,17 @@ func (v *ViewVariableData) SetValue(_ values.Value) {
   panic("cant be set")
 }
 
+// view variables are never narrowed
+func (v *ViewVariableData) GetNarrowed(member string) values.Value {
+  return nil
+}
+
+func (v *ViewVariableData) SetNarrowed(member string, _ values.Value) {
+}
+
+func (v *ViewVariableData) ClearNarrowed() {
+}
+
 func (v *ViewNull) Dump(indent string) string {
 	return indent + "ViewNull"
 }
@@ -22,6 +22,7 @@ type OperationData struct {
 	target string
   popped bool // operations can be used multiple times, but must be popped once
   // note that operations that have not been renamed using a 'white-space' prefix, can only be popped once
+  ctx context.Context // of the directive, used if there are no child tags
 }
 
 type ReplaceChildrenOp struct {
@@ -40,8 +41,8 @@ type AppendOp struct {
 	OperationData
 }*/
 
-func newOperationData(target string) OperationData {
-  return OperationData{target, false}
+func newOperationData(target string, ctx context.Context) OperationData {
+  return OperationData{target, false, ctx}
 }
 
 func (op *OperationData) Popped() bool {
@@ -60,17 +61,27 @@ func (op *OperationData) SetTarget(t string) {
 	op.target = t
 }
 
+func (op *OperationData) contextOf(tags [][]*tokens.Tag) context.Context {
+  for _, ts := range tags {
+    if len(ts) > 0 {
+      return ts[0].Context()
+    }
+  }
+
+  return op.ctx
+}
+
 func (op *ReplaceChildrenOp) Context() context.Context {
-	return op.tags[0][0].Context()
+	return op.contextOf(op.tags)
 }
 
 func (op *AppendOp) Context() context.Context {
-	return op.tags[0][0].Context()
+	return op.contextOf(op.tags)
 }
 
 func NewAppendToDefaultOp(scope Scope, tags []*tokens.Tag) (*AppendOp, error) {
   // can't use subscope!
-	return &AppendOp{[][]*tokens.Tag{tags}, []Scope{scope}, newOperationData("default")}, nil
+	return &AppendOp{[][]*tokens.Tag{tags}, []Scope{scope}, newOperationData("default", tags[0].Context())}, nil
 }
 
 func (op *AppendOp) Merge(other_ Operation) (Operation, error) {
@@ -102,7 +113,7 @@ func (op *ReplaceChildrenOp) Merge(other_ Operation) (Operation, error) {
     return &ReplaceChildrenOp{
       append(op.tags, other.tags...),
       append(op.scopes, other.scopes...),
-      newOperationData(op.target),
+      newOperationData(op.target, op.ctx),
     }, nil
 	default:
 		panic("unrecognized")
@@ -193,7 +204,7 @@ func AppendToBlock(scope Scope, node *TemplateNode, tag *tokens.Tag) error {
 
   subScope := NewSubScope(scope)
 
-  op := &AppendOp{[][]*tokens.Tag{tag.Children()}, []Scope{subScope}, newOperationData(name)}
+  op := &AppendOp{[][]*tokens.Tag{tag.Children()}, []Scope{subScope}, newOperationData(name, tag.Context())}
 
   return node.PushOp(op)
 }
@@ -207,7 +218,7 @@ func ReplaceBlockChildren(scope Scope, node *TemplateNode, tag *tokens.Tag) erro
   subScope := NewSubScope(scope)
 
   op := &ReplaceChildrenOp{[][]*tokens.Tag{tag.Children()}, []Scope{subScope}, 
-    newOperationData(name)}
+    newOperationData(name, tag.Context())}
 
   return node.PushOp(op)
 }
@@ -0,0 +1,91 @@
+package git
+
+import (
+  "path/filepath"
+  "time"
+
+  gitcore      "gopkg.in/src-d/go-git.v4"
+  gitplumbing  "gopkg.in/src-d/go-git.v4/plumbing"
+  gitobject    "gopkg.in/src-d/go-git.v4/plumbing/object"
+)
+
+func fileHash(tree *gitobject.Tree, path string) gitplumbing.Hash {
+  entry, err := tree.FindEntry(path)
+  if err != nil {
+    return gitplumbing.ZeroHash
+  }
+
+  return entry.Hash
+}
+
+// time of the last commit that changed each of the files, files that were never committed are left out
+// all paths must be absolute and inside the same repository
+// the history is walked only once, which is much faster than a log per file
+func LastCommitTimes(paths []string) (map[string]time.Time, error) {
+  result := make(map[string]time.Time)
+  if len(paths) == 0 {
+    return result, nil
+  }
+
+  repo, err := gitcore.PlainOpenWithOptions(filepath.Dir(paths[0]), &gitcore.PlainOpenOptions{DetectDotGit: true})
+  if err != nil {
+    return nil, err
+  }
+
+  wt, err := repo.Worktree()
+  if err != nil {
+    return nil, err
+  }
+
+  // key is path relative to worktree root, with forward slashes
+  pending := make(map[string]string)
+  for _, path := range paths {
+    rel, err := filepath.Rel(wt.Filesystem.Root(), path)
+    if err != nil {
+      return nil, err
+    }
+
+    pending[filepath.ToSlash(rel)] = path
+  }
+
+  iter, err := repo.Log(&gitcore.LogOptions{Order: gitcore.LogOrderCommitterTime})
+  if err != nil {
+    return nil, err
+  }
+
+  defer iter.Close()
+
+  for len(pending) > 0 {
+    commit, err := iter.Next()
+    if err != nil {
+      break // end of history
+    }
+
+    tree, err := commit.Tree()
+    if err != nil {
+      return nil, err
+    }
+
+    var parentTree *gitobject.Tree = nil
+    if parent, err := commit.Parent(0); err == nil {
+      parentTree, err = parent.Tree()
+      if err != nil {
+        return nil, err
+      }
+    }
+
+    for rel, path := range pending {
+      hash := fileHash(tree, rel)
+      if hash.IsZero() {
+        continue
+      }
+
+      if parentTree == nil || fileHash(parentTree, rel) != hash {
+        result[path] = commit.Committer.When
+        delete(pending, rel)
+      }
+    }
+  }
+
+  return result, nil
+}
@@ -113,9 +113,16 @@ func (p *CLIParser) Error(msg string) error {
 }
 
 // returns nil if not found, or if ambiguous
+// an exact match is never ambiguous (eg. --strict vs --strict-null)
 func (p *CLIParser) findLongOption(key string) CLIOption {
   var res CLIOption = nil
 
+  for _, opt := range p.options {
+    if opt.Long() == key {
+      return opt
+    }
+  }
+
   for _, opt := range p.options {
     if strings.HasPrefix(opt.Long(), key) {
       if res != nil {
@@ -0,0 +1,51 @@
+package parsers
+
+import (
+  "testing"
+)
+
+func TestFindLongOptionPrefersExactMatch(t *testing.T) {
+  strict := false
+  strictNull := false
+
+  p := NewCLIParser("", "", []CLIOption{
+    NewCLIUniqueFlag("", "strict", "", &strict),
+    NewCLIUniqueFlag("", "strict-null", "", &strictNull),
+  }, nil)
+
+  if err := p.Parse([]string{"--strict"}); err != nil {
+    t.Fatal(err)
+  }
+
+  if !strict || strictNull {
+    t.Errorf("--strict: got strict=%v, strict-null=%v", strict, strictNull)
+  }
+
+  strict = false
+
+  if err := p.Parse([]string{"--strict-null"}); err != nil {
+    t.Fatal(err)
+  }
+
+  if strict || !strictNull {
+    t.Errorf("--strict-null: got strict=%v, strict-null=%v", strict, strictNull)
+  }
+}
+
+func TestFindLongOptionAmbiguousPrefix(t *testing.T) {
+  strict := false
+  strictNull := false
+
+  p := NewCLIParser("", "", []CLIOption{
+    NewCLIUniqueFlag("", "strict", "", &strict),
+    NewCLIUniqueFlag("", "strict-null", "", &strictNull),
+  }, nil)
+
+  if p.findLongOption("str") != nil {
+    t.Errorf("expected --str to be ambiguous")
+  }
+
+  if opt := p.findLongOption("strict-n"); opt == nil || opt.Long() != "strict-null" {
+    t.Errorf("expected --strict-n to match --strict-null")
+  }
+}
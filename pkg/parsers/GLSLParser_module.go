@@ -276,6 +276,10 @@ func (p *GLSLParser) buildModuleStatement(ts []raw.Token, isExport bool) ([]raw.
       return p.buildVarying(ts, isExport)
     case "uniform":
       return p.buildUniform(ts, isExport)
+    case "in", "out", "flat", "smooth":
+      return p.buildInOut(ts, nil, isExport)
+    case "layout":
+      return p.buildLayoutQualified(ts, isExport)
     case "const":
       return p.buildConst(ts, isExport)
     case "struct":
@@ -78,6 +78,11 @@ func (p *GLSLParser) buildVersion(ts []raw.Token) ([]raw.Token, error) {
     return nil, err
   }
 
+  if numberToken.Value() != 100 && numberToken.Value() != 300 {
+    errCtx := numberToken.Context()
+    return nil, errCtx.NewError("Error: only versions 100 and 300 are supported")
+  }
+
   esToken, err := raw.AssertWord(ts[3])
   if err != nil {
     return nil, err
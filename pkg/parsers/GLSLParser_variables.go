@@ -156,6 +156,10 @@ func (p *GLSLParser) buildArraySize(t raw.Token) (int, error) {
 }
 
 func (p *GLSLParser) buildUniform(ts []raw.Token, isExport bool) ([]raw.Token, error) {
+  if len(ts) > 2 && raw.IsBracesGroup(ts[2]) {
+    return p.buildUniformBlock(ts, nil, isExport)
+  }
+
   ts, remainingTokens := splitByNextSeparator(ts, patterns.SEMICOLON)
 
   n := len(ts)
@@ -197,6 +201,212 @@ func (p *GLSLParser) buildUniform(ts []raw.Token, isExport bool) ([]raw.Token, e
   return remainingTokens, nil
 }
 
+// uniform Name {...} [instance];
+func (p *GLSLParser) buildUniformBlock(ts []raw.Token, layout *glsl.Layout, isExport bool) ([]raw.Token, error) {
+  ts, remainingTokens := splitByNextSeparator(ts, patterns.SEMICOLON)
+
+  if len(ts) != 3 && len(ts) != 4 {
+    errCtx := raw.MergeContexts(ts...)
+    return nil, errCtx.NewError("Error: bad uniform block")
+  }
+
+  name, err := raw.AssertWord(ts[1])
+  if err != nil {
+    return nil, err
+  }
+
+  brace, err := raw.AssertBracesGroup(ts[2])
+  if err != nil {
+    return nil, err
+  }
+
+  if brace.IsComma() {
+    errCtx := brace.Context()
+    return nil, errCtx.NewError("Error: expected semicolon separators")
+  }
+
+  entries := make([]*glsl.StructEntry, 0)
+  for _, field := range brace.Fields {
+    entry, err := p.buildStructEntry(field)
+    if err != nil {
+      return nil, err
+    }
+
+    entries = append(entries, entry)
+  }
+
+  var instanceExpr *glsl.VarExpression = nil
+  if len(ts) == 4 {
+    instanceExpr, err = p.buildVarExpression(ts[3])
+    if err != nil {
+      return nil, err
+    }
+  }
+
+  st, err := glsl.NewUniformBlock(layout, name.Value(), entries, instanceExpr, raw.MergeContexts(ts...))
+  if err != nil {
+    return nil, err
+  }
+
+  p.module.AddStatement(st)
+
+  if isExport {
+    if instanceExpr != nil {
+      if err := p.buildSimpleExport(instanceExpr.Name(), instanceExpr.Context()); err != nil {
+        return nil, err
+      }
+    } else {
+      for _, entry := range entries {
+        if err := p.buildSimpleExport(entry.Name(), entry.Context()); err != nil {
+          return nil, err
+        }
+      }
+    }
+  }
+
+  return remainingTokens, nil
+}
+
+// [flat|smooth] in|out [precision] type name;
+func (p *GLSLParser) buildInOut(ts []raw.Token, layout *glsl.Layout, isExport bool) ([]raw.Token, error) {
+  ts, remainingTokens := splitByNextSeparator(ts, patterns.SEMICOLON)
+
+  interp := ""
+  if raw.IsWord(ts[0], "flat") || raw.IsWord(ts[0], "smooth") {
+    interpToken, err := raw.AssertWord(ts[0])
+    if err != nil {
+      panic(err)
+    }
+
+    interp = interpToken.Value()
+    ts = ts[1:]
+  }
+
+  n := len(ts)
+  if n < 3 {
+    errCtx := raw.MergeContexts(ts...)
+    return nil, errCtx.NewError("Error: expected at least 3 tokens")
+  }
+
+  isOut := false
+  if raw.IsWord(ts[0], "out") {
+    isOut = true
+  } else if !raw.IsWord(ts[0], "in") {
+    errCtx := ts[0].Context()
+    return nil, errCtx.NewError("Error: expected in or out")
+  }
+
+  var precType glsl.PrecisionType = glsl.DEFAULTP
+  if raw.IsWord(ts[1], "highp") || raw.IsWord(ts[1], "mediump") || raw.IsWord(ts[1], "lowp") {
+    var err error
+    precType, err = p.buildPrecisionType(ts[1])
+    if err != nil {
+      return nil, err
+    }
+
+    n = n - 1
+    ts = ts[1:]
+  }
+
+  typeExpr, err := p.buildTypeExpression(ts[1:n-1])
+  if err != nil {
+    return nil, err
+  }
+
+  name, err := raw.AssertWord(ts[n-1])
+  if err != nil {
+    return nil, err
+  }
+
+  if isOut {
+    p.module.AddStatement(glsl.NewOut(layout, interp, precType, typeExpr, name.Value(), name.Context()))
+  } else {
+    p.module.AddStatement(glsl.NewIn(layout, interp, precType, typeExpr, name.Value(), name.Context()))
+  }
+
+  if isExport {
+    if err := p.buildSimpleExport(name.Value(), name.Context()); err != nil {
+      return nil, err
+    }
+  }
+
+  return remainingTokens, nil
+}
+
+func (p *GLSLParser) buildLayout(t raw.Token) (*glsl.Layout, error) {
+  parens, err := raw.AssertParensGroup(t)
+  if err != nil {
+    return nil, err
+  }
+
+  if !(parens.IsSingle() || parens.IsComma()) {
+    errCtx := parens.Context()
+    return nil, errCtx.NewError("Error: expected comma separated layout qualifiers")
+  }
+
+  entries := make([]*glsl.LayoutEntry, 0)
+
+  for _, field := range parens.Fields {
+    ctx := raw.MergeContexts(field...)
+
+    name, err := raw.AssertWord(field[0])
+    if err != nil {
+      return nil, err
+    }
+
+    switch {
+    case len(field) == 1:
+      entries = append(entries, glsl.NewLayoutEntry(name.Value(), -1, ctx))
+    case len(field) == 3 && raw.IsSymbol(field[1], patterns.EQUAL):
+      value, err := raw.AssertLiteralInt(field[2])
+      if err != nil {
+        return nil, err
+      }
+
+      if value.Value() < 0 {
+        errCtx := field[2].Context()
+        return nil, errCtx.NewError("Error: expected positive layout qualifier value")
+      }
+
+      entries = append(entries, glsl.NewLayoutEntry(name.Value(), value.Value(), ctx))
+    default:
+      return nil, ctx.NewError("Error: bad layout qualifier")
+    }
+  }
+
+  return glsl.NewLayout(entries, parens.Context()), nil
+}
+
+// layout(...) in|out|uniform ...
+func (p *GLSLParser) buildLayoutQualified(ts []raw.Token, isExport bool) ([]raw.Token, error) {
+  if len(ts) < 3 || !raw.IsParensGroup(ts[1]) {
+    errCtx := ts[0].Context()
+    return nil, errCtx.NewError("Error: expected layout(...)")
+  }
+
+  layout, err := p.buildLayout(ts[1])
+  if err != nil {
+    return nil, err
+  }
+
+  ts = ts[2:]
+
+  switch {
+  case raw.IsWord(ts[0], "uniform"):
+    if len(ts) < 3 || !raw.IsBracesGroup(ts[2]) {
+      errCtx := ts[0].Context()
+      return nil, errCtx.NewError("Error: layout only applies to uniform blocks")
+    }
+
+    return p.buildUniformBlock(ts, layout, isExport)
+  case raw.IsWord(ts[0], "in"), raw.IsWord(ts[0], "out"), raw.IsWord(ts[0], "flat"), raw.IsWord(ts[0], "smooth"):
+    return p.buildInOut(ts, layout, isExport)
+  default:
+    errCtx := ts[0].Context()
+    return nil, errCtx.NewError("Error: expected in, out or uniform after layout(...)")
+  }
+}
+
 func (p *GLSLParser) buildConst(ts []raw.Token, isExport bool) ([]raw.Token, error) {
   ts, remainingTokens := splitByNextSeparator(ts, patterns.SEMICOLON)
 
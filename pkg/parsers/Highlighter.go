@@ -0,0 +1,442 @@
+package parsers
+
+import (
+  "regexp"
+  "strings"
+  "unicode"
+  "unicode/utf8"
+
+  "github.com/computeportal/wtsuite/pkg/tokens/context"
+  "github.com/computeportal/wtsuite/pkg/tokens/patterns"
+)
+
+// highlight classes, written as "hl-<class>" so that themes can style them
+const (
+  HL_NONE        = ""
+  HL_COMMENT     = "comment"
+  HL_STRING      = "string"
+  HL_NUMBER      = "number"
+  HL_LITERAL     = "literal" // true, false, null, entities
+  HL_KEYWORD     = "keyword"
+  HL_TYPE        = "type"
+  HL_FUNCTION    = "function"
+  HL_OPERATOR    = "operator"
+  HL_PUNCTUATION = "punctuation"
+  HL_TAG         = "tag"
+  HL_ATTRIBUTE   = "attr"
+  HL_PROPERTY    = "property"
+  HL_VARIABLE    = "variable"
+  HL_PREPROC     = "preproc"
+)
+
+type HighlightSpan struct {
+  Class string
+  Text  string
+}
+
+func newWordSet(words string) map[string]bool {
+  res := make(map[string]bool)
+  for _, w := range strings.Fields(words) {
+    res[w] = true
+  }
+
+  return res
+}
+
+var (
+  hlJSKeywords = newWordSet(`abstract as async await break case catch class const continue default delete do else enum
+    export extends final finally for from function get if implements import in instanceof interface let new of return
+    rpc set static super switch this throw try typeof var void while yield`)
+  hlTemplateKeywords = newWordSet(`append as block case class default else elseif export extends for from function if
+    import in of parameters permissive prepend replace style super switch template var`)
+  hlGLSLKeywords = newWordSet(`as attribute break centroid const continue discard do else export flat for from highp if
+    import in inout invariant layout lowp mediump out precision return smooth struct uniform varying void while`)
+  hlGLSLTypes = newWordSet(`bool int uint float double vec2 vec3 vec4 bvec2 bvec3 bvec4 ivec2 ivec3 ivec4 uvec2 uvec3
+    uvec4 mat2 mat3 mat4 mat2x2 mat2x3 mat2x4 mat3x2 mat3x3 mat3x4 mat4x2 mat4x3 mat4x4 sampler2D sampler3D samplerCube
+    sampler2DShadow sampler2DArray isampler2D usampler2D`)
+
+  hlPunctuation = newWordSet(`( ) [ ] { } , ; . :`)
+
+  hlReCSS = regexp.MustCompile(`^(?:(/\*[\s\S]*?(?:\*/|$))|("(?:\\.|[^"\\\n])*"|'(?:\\.|[^'\\\n])*')|(@[A-Za-z-]+)|(![A-Za-z]+)|(#[0-9A-Fa-f]{3,8}\b)|(-?[0-9]*\.?[0-9]+(?:%|[A-Za-z]+)?)|([A-Za-z_-][A-Za-z0-9_-]*)|([.#:][A-Za-z_-][A-Za-z0-9_-]*|::[A-Za-z-]+)|([{}()\[\],;:>+~*=])|(\s+)|(.))`)
+  hlReHTMLComment = regexp.MustCompile(`^<!--[\s\S]*?(?:-->|$)`)
+  hlReHTMLDeclaration = regexp.MustCompile(`^<![^>]*>?`)
+  hlReHTMLTagStart = regexp.MustCompile(`^(</?)([A-Za-z][A-Za-z0-9-]*)`)
+  hlReHTMLAttr = regexp.MustCompile(`^(?:(\s+)|([^\s"'<>/=]+)|(=)|("[^"]*"?|'[^']*'?)|(/?>))`)
+  hlReHTMLEntity = regexp.MustCompile(`^&(?:#x[a-fA-F0-9]+|#[0-9]+|[A-Za-z][A-Za-z0-9]*);`)
+  hlReHTMLText = regexp.MustCompile(`^[^<&]+`)
+)
+
+var hlLangAliases = map[string]string{
+  "wts":        "wts",
+  "wtscript":   "wts",
+  "js":         "js",
+  "javascript": "js",
+  "wtt":        "wtt",
+  "wttemplate": "wtt",
+  "glsl":       "glsl",
+  "css":        "css",
+  "html":       "html",
+  "json":       "json",
+}
+
+func IsHighlightLang(lang string) bool {
+  _, ok := hlLangAliases[lang]
+  return ok
+}
+
+// ctx should start at the first character of code, it is used for errors
+func Highlight(lang string, code string, ctx context.Context) ([]HighlightSpan, error) {
+  h := &highlighter{make([]HighlightSpan, 0)}
+
+  var err error = nil
+  switch hlLangAliases[lang] {
+  case "wts", "js", "json", "wtt", "glsl":
+    h.highlightMasked(code, hlLangAliases[lang], ctx)
+  case "css":
+    h.highlightCSS(code)
+  case "html":
+    err = h.highlightHTML(code, ctx)
+  default:
+    return nil, ctx.NewError("Error: highlighting of \"" + lang + "\" not supported")
+  }
+
+  if err != nil {
+    return nil, err
+  }
+
+  return h.spans, nil
+}
+
+type highlighter struct {
+  spans []HighlightSpan
+}
+
+// consecutive text with the same class is merged
+func (h *highlighter) add(class string, text string) {
+  if text == "" {
+    return
+  }
+
+  if n := len(h.spans); n > 0 && h.spans[n-1].Class == class {
+    h.spans[n-1].Text += text
+  } else {
+    h.spans = append(h.spans, HighlightSpan{class, text})
+  }
+}
+
+// strings and comments are masked by the regular parser settings, words and symbols are matched by their patterns
+//  (but not tokenized, so incomplete snippets can still be highlighted)
+// snippets that can't be masked (eg. an unterminated string) are kept unhighlighted
+func (h *highlighter) highlightMasked(code string, lang string, ctx context.Context) {
+  var settings ParserSettings
+  keywords := map[string]bool{}
+
+  switch lang {
+  case "wts", "js":
+    settings = jsParserSettings
+    keywords = hlJSKeywords
+  case "json":
+    settings = jsParserSettings
+  case "wtt":
+    settings = uiParserSettings
+    keywords = hlTemplateKeywords
+  case "glsl":
+    settings = glslParserSettings
+    keywords = hlGLSLKeywords
+  default:
+    panic("unhandled lang")
+  }
+
+  // a single-line comment on the last line must still be terminated
+  p := newParser(code+"\n", settings, ctx)
+
+  if err := p.maskQuoted(); err != nil {
+    h.add(HL_NONE, code)
+    return
+  }
+
+  // start -> stop of every word and symbol
+  stops := make(map[int]int)
+
+  for _, item := range []struct {
+    re   *regexp.Regexp
+    mask RuneMask
+  }{
+    {settings.wordsAndLiterals.pattern, WORD_OR_LITERAL},
+    {settings.symbols.pattern, SYMBOL},
+  } {
+    for {
+      r, _, ok := p.nextMatch(item.re, false)
+      if !ok {
+        break
+      }
+
+      p.SetMask(r[0], r[1], item.mask)
+      stops[r[0]] = r[1]
+    }
+
+    p.SeekToStart()
+  }
+
+  // index of the next non-whitespace rune at or after i
+  nextNonSpaceIndex := func(i int) int {
+    for ; i < len(p.raw); i++ {
+      if !unicode.IsSpace(p.raw[i]) {
+        break
+      }
+    }
+
+    return i
+  }
+
+  nextNonSpace := func(i int) rune {
+    if i = nextNonSpaceIndex(i); i < len(p.raw) {
+      return p.raw[i]
+    }
+
+    return 0
+  }
+
+  // only whitespace before i on the same line
+  lineStart := func(i int) bool {
+    for i = i - 1; i >= 0 && p.raw[i] != '\n'; i-- {
+      if !unicode.IsSpace(p.raw[i]) {
+        return false
+      }
+    }
+
+    return true
+  }
+
+  prevText := ""
+
+  for i := 0; i < len(p.raw); {
+    m := p.mask[i]
+
+    j := i + 1
+    if stop, ok := stops[i]; ok && (m == SYMBOL || m == WORD_OR_LITERAL) {
+      j = stop
+    } else {
+      for j < len(p.raw) && p.mask[j] == m {
+        j++
+      }
+    }
+
+    text := string(p.raw[i:j])
+    class := HL_NONE
+
+    switch m {
+    case SL_COMMENT, ML_COMMENT:
+      class = HL_COMMENT
+    case STRING, FORMULA:
+      class = HL_STRING
+      if lang == "json" && nextNonSpace(j) == ':' {
+        class = HL_PROPERTY
+      }
+    case SYMBOL:
+      if hlPunctuation[text] {
+        class = HL_PUNCTUATION
+      } else {
+        class = HL_OPERATOR
+      }
+
+      if lang == "glsl" && text == "#" && lineStart(i) {
+        // the rest of the line is a preprocessor directive
+        for j < len(p.raw) && p.raw[j] != '\n' {
+          j++
+        }
+
+        text = string(p.raw[i:j])
+        class = HL_PREPROC
+      }
+    case WORD_OR_LITERAL:
+      switch {
+      case patterns.IsBool(text) || patterns.IsNull(text) || text == "undefined":
+        class = HL_LITERAL
+      case unicode.IsDigit(p.raw[i]) || (lang == "wtt" && patterns.IsColor(text)):
+        class = HL_NUMBER
+      case lang == "wtt" && prevText == "$":
+        class = HL_VARIABLE
+      case lang == "wtt" && stops[nextNonSpaceIndex(j)] == nextNonSpaceIndex(j)+1 && nextNonSpace(j) == '=':
+        class = HL_ATTRIBUTE
+      case keywords[text]:
+        class = HL_KEYWORD
+      case lang == "glsl" && hlGLSLTypes[text]:
+        class = HL_TYPE
+      case lang == "wtt" && lineStart(i):
+        class = HL_TAG
+      case nextNonSpace(j) == '(':
+        class = HL_FUNCTION
+      case lang != "wtt" && unicode.IsUpper(p.raw[i]):
+        class = HL_TYPE
+      }
+    }
+
+    h.add(class, text)
+
+    if strings.TrimSpace(text) != "" {
+      prevText = text
+    }
+
+    i = j
+  }
+
+  h.trimLast()
+}
+
+// removes the last rune, which was added to terminate the snippet
+func (h *highlighter) trimLast() {
+  n := len(h.spans)
+  last := h.spans[n-1].Text[0:len(h.spans[n-1].Text)-1]
+
+  if last == "" {
+    h.spans = h.spans[0:n-1]
+  } else {
+    h.spans[n-1].Text = last
+  }
+}
+
+func (h *highlighter) highlightCSS(code string) {
+  // a statement is a selector (or at-rule prelude) if it is terminated by an opening brace
+  isSelector := func(rest string) bool {
+    i := strings.IndexAny(rest, "{;}")
+    return i != -1 && rest[i] == '{'
+  }
+
+  inSelector := isSelector(code)
+  parens := 0
+
+  for len(code) > 0 {
+    m := hlReCSS.FindStringSubmatch(code)
+
+    text := m[0]
+    rest := strings.TrimLeft(code[len(text):], " \t\n")
+    class := HL_NONE
+    selector := inSelector && parens == 0
+
+    switch {
+    case m[1] != "":
+      class = HL_COMMENT
+    case m[2] != "":
+      class = HL_STRING
+    case m[3] != "" || m[4] != "":
+      class = HL_KEYWORD
+    case m[5] != "" && selector:
+      class = HL_ATTRIBUTE
+    case m[5] != "" || (m[6] != "" && !selector):
+      class = HL_NUMBER
+    case m[7] != "":
+      if strings.HasPrefix(rest, "(") {
+        class = HL_FUNCTION
+      } else if selector {
+        class = HL_TAG
+      } else if strings.HasPrefix(rest, ":") {
+        class = HL_PROPERTY
+      }
+    case m[8] != "":
+      if selector {
+        class = HL_ATTRIBUTE
+      } else if text[0] == ':' {
+        // separator of a property and its value
+        h.add(HL_PUNCTUATION, ":")
+        code = code[1:]
+        continue
+      }
+    case m[9] != "":
+      class = HL_PUNCTUATION
+
+      switch text {
+      case "(":
+        parens++
+      case ")":
+        if parens > 0 {
+          parens--
+        }
+      case "{", ";", "}":
+        inSelector = isSelector(code[1:])
+      }
+    }
+
+    h.add(class, text)
+    code = code[len(text):]
+  }
+}
+
+func (h *highlighter) highlightHTML(code string, ctx context.Context) error {
+  runePos := 0 // for the contexts of nested scripts
+
+  advance := func(n int) {
+    runePos += utf8.RuneCountInString(code[0:n])
+    code = code[n:]
+  }
+
+  for len(code) > 0 {
+    if m := hlReHTMLComment.FindString(code); m != "" {
+      h.add(HL_COMMENT, m)
+      advance(len(m))
+    } else if m := hlReHTMLDeclaration.FindString(code); m != "" {
+      h.add(HL_PREPROC, m)
+      advance(len(m))
+    } else if m := hlReHTMLTagStart.FindStringSubmatch(code); m != nil {
+      h.add(HL_PUNCTUATION, m[1])
+      h.add(HL_TAG, m[2])
+      advance(len(m[0]))
+
+      for len(code) > 0 {
+        a := hlReHTMLAttr.FindStringSubmatch(code)
+        if a == nil {
+          break
+        }
+
+        switch {
+        case a[1] != "":
+          h.add(HL_NONE, a[0])
+        case a[2] != "":
+          h.add(HL_ATTRIBUTE, a[0])
+        case a[3] != "":
+          h.add(HL_OPERATOR, a[0])
+        case a[4] != "":
+          h.add(HL_STRING, a[0])
+        case a[5] != "":
+          h.add(HL_PUNCTUATION, a[0])
+        }
+
+        advance(len(a[0]))
+        if a[5] != "" {
+          break
+        }
+      }
+
+      // script and style content
+      name := strings.ToLower(m[2])
+      if m[1] == "<" && (name == "script" || name == "style") {
+        end := strings.Index(strings.ToLower(code), "</"+name)
+        if end == -1 {
+          end = len(code)
+        }
+
+        inner := code[0:end]
+        if name == "style" {
+          h.highlightCSS(inner)
+        } else {
+          innerCtx := ctx.NewContext(runePos, runePos+utf8.RuneCountInString(inner))
+          h.highlightMasked(inner, "js", innerCtx)
+        }
+
+        advance(end)
+      }
+    } else if m := hlReHTMLEntity.FindString(code); m != "" {
+      h.add(HL_LITERAL, m)
+      advance(len(m))
+    } else if m := hlReHTMLText.FindString(code); m != "" {
+      h.add(HL_NONE, m)
+      advance(len(m))
+    } else {
+      _, n := utf8.DecodeRuneInString(code)
+      h.add(HL_NONE, code[0:n])
+      advance(n)
+    }
+  }
+
+  return nil
+}
@@ -182,6 +182,7 @@ var jsParserSettings = ParserSettings{
 		operatorSettings{8, "|", BIN | L2R},
 		operatorSettings{6, "&&", BIN | L2R},
 		operatorSettings{5, "||", BIN | L2R},
+		operatorSettings{5, "??", BIN | L2R},
 		operatorSettings{4, "? :", TER | L2R},
 		operatorSettings{3, "=", BIN},
 		operatorSettings{3, "+=", BIN},
@@ -196,6 +197,9 @@ var jsParserSettings = ParserSettings{
 		operatorSettings{3, "&=", BIN},
 		operatorSettings{3, "|=", BIN},
 		operatorSettings{3, "^=", BIN},
+		operatorSettings{3, "||=", BIN},
+		operatorSettings{3, "&&=", BIN},
+		operatorSettings{3, "??=", BIN},
 	}),
 	tmpGroupWords:            true,
 	tmpGroupPeriods:          true,
@@ -238,6 +242,7 @@ var jsOperatorMap = map[string]string{
 	"bininstanceof": "instanceof",
 	"bin||":         "||",
 	"bin&&":         "&&",
+	"bin??":         "??",
 	"bin==":         "==",
 	"bin!=":         "!=",
 	"bin!==":        "!==",
@@ -255,6 +260,9 @@ var jsOperatorMap = map[string]string{
 	"bin&=":         "&=",
 	"bin|=":         "|=",
 	"bin^=":         "^=",
+	"bin||=":        "||=",
+	"bin&&=":        "&&=",
+	"bin??=":        "??=",
 	"post++":        "++",
 	"post--":        "--",
 	"pre--":         "--",
@@ -18,7 +18,7 @@ func (p *JSParser) buildCallArgs(t raw.Token) ([]js.Expression, error) {
 	}
 
 	for _, field := range group.Fields {
-		arg, err := p.buildExpression(field)
+		arg, err := p.buildSpreadableExpression(field)
 		if err != nil {
 			return nil, err
 		}
@@ -124,6 +124,8 @@ func (p *JSParser) buildCallStatement(ts_ []raw.Token) (js.Statement, []raw.Toke
 		switch call := call_.(type) {
 		case *js.Call:
 			return call, remainingTokens, nil
+		case *js.OptionalCall:
+			return call, remainingTokens, nil
 		case *js.Await:
 			return call, remainingTokens, nil
 		default:
@@ -2,6 +2,7 @@ package parsers
 
 import (
 	"github.com/computeportal/wtsuite/pkg/tokens/js"
+	"github.com/computeportal/wtsuite/pkg/tokens/js/macros"
 	"github.com/computeportal/wtsuite/pkg/tokens/patterns"
 	"github.com/computeportal/wtsuite/pkg/tokens/raw"
 )
@@ -81,9 +82,63 @@ func (p *JSParser) buildClassUniversalName(ts []raw.Token) (string, []raw.Token,
 	return "", ts, nil
 }
 
+// @name or @name(args...), builtin decorators take precedence over user functions
+func (p *JSParser) buildDecorators(ts []raw.Token) ([]js.Decorator, []raw.Token, error) {
+	decorators := make([]js.Decorator, 0)
+
+	for len(ts) > 0 && raw.IsSymbol(ts[0], patterns.AT) {
+		if len(ts) < 2 {
+			errCtx := ts[0].Context()
+			return nil, nil, errCtx.NewError("Error: bad decorator")
+		}
+
+		nameToken, remaining, err := condensePackagePeriods(ts[1:])
+		if err != nil {
+			return nil, nil, err
+		}
+
+		ctx := raw.MergeContexts(ts[0], nameToken)
+
+		args := make([]js.Expression, 0)
+		if len(remaining) > 0 && raw.IsParensGroup(remaining[0]) {
+			args, err = p.buildCallArgs(remaining[0])
+			if err != nil {
+				return nil, nil, err
+			}
+
+			remaining = remaining[1:]
+		}
+
+		var d js.Decorator
+		if macros.IsDecoratorMacro(nameToken.Value()) {
+			d, err = macros.NewDecoratorMacro(nameToken.Value(), args, ctx)
+			if err != nil {
+				return nil, nil, err
+			}
+		} else {
+			lhs, err := p.buildVarExpression(nameToken)
+			if err != nil {
+				return nil, nil, err
+			}
+
+			d = js.NewFunctionDecorator(lhs, args, ctx)
+		}
+
+		decorators = append(decorators, d)
+		ts = remaining
+	}
+
+	return decorators, ts, nil
+}
+
 func (p *JSParser) buildClass(ts []raw.Token) (*js.Class, error) {
 	clCtx := raw.MergeContexts(ts...)
 
+	decorators, ts, err := p.buildDecorators(ts)
+	if err != nil {
+		return nil, err
+	}
+
 	if len(ts) < 2 {
 		errCtx := clCtx
 		return nil, errCtx.NewError("Error: bad class definition")
@@ -107,7 +162,6 @@ func (p *JSParser) buildClass(ts []raw.Token) (*js.Class, error) {
 
 	// special, because classes dont necessarily have a name
 	var clType *js.TypeExpression
-	var err error = nil
 	if raw.IsAnyWord(ts[1]) && len(ts) > 2 && raw.IsAngledGroup(ts[2]) {
 		clType, err = p.buildTypeExpression(ts[1:3])
 		ts = ts[3:]
@@ -141,7 +195,7 @@ func (p *JSParser) buildClass(ts []raw.Token) (*js.Class, error) {
 		return nil, err
 	}
 
-  class, err := js.NewClass(clType, extends, implements, isAbstract, isFinal, universalName, clCtx)
+  class, err := js.NewClass(clType, extends, implements, isAbstract, isFinal, universalName, decorators, clCtx)
 	if err != nil {
 		return nil, err
 	}
@@ -167,6 +221,18 @@ func (p *JSParser) buildClass(ts []raw.Token) (*js.Class, error) {
 
 	Outer:
 		for len(remaining) > 0 {
+      memberDecorators, remaining_, err := p.buildDecorators(remaining)
+      if err != nil {
+        return nil, err
+      }
+
+      if len(remaining_) == 0 {
+        errCtx := memberDecorators[len(memberDecorators)-1].Context()
+        return nil, errCtx.NewError("Error: decorator without member")
+      }
+
+      remaining = remaining_
+
       encounteredAbstract := false
 			for i, t := range remaining {
         if raw.IsWord(t, "abstract") {
@@ -192,7 +258,7 @@ func (p *JSParser) buildClass(ts []raw.Token) (*js.Class, error) {
 							return nil, errCtx.NewError("Error: unexpected tokens after member function")
 						}
 
-						if err := class.AddFunction(function); err != nil {
+						if err := class.AddFunction(function, memberDecorators); err != nil {
 							return nil, err
 						}
 
@@ -211,7 +277,7 @@ func (p *JSParser) buildClass(ts []raw.Token) (*js.Class, error) {
             return nil, errCtx.NewError("Error: unexpected tokens after abstract member function")
           }
 
-          if err := class.AddFunction(function); err != nil {
+          if err := class.AddFunction(function, memberDecorators); err != nil {
             return nil, err
           }
 
@@ -240,7 +306,7 @@ func (p *JSParser) buildClass(ts []raw.Token) (*js.Class, error) {
           }
         }
 
-        if err := class.AddProperty(propName, typeExpr); err != nil {
+        if err := class.AddProperty(propName, typeExpr, memberDecorators); err != nil {
           return nil, err
         }
 
@@ -257,7 +323,17 @@ func (p *JSParser) buildClass(ts []raw.Token) (*js.Class, error) {
 }
 
 func (p *JSParser) buildClassExpression(ts []raw.Token) (js.Expression, error) {
-	return p.buildClass(ts)
+	cl, err := p.buildClass(ts)
+	if err != nil {
+		return nil, err
+	}
+
+	if cl.HasDecorators() {
+		errCtx := cl.Context()
+		return nil, errCtx.NewError("Error: only class statements can be decorated")
+	}
+
+	return cl, nil
 }
 
 func (p *JSParser) buildClassStatement(ts []raw.Token) (*js.Class, []raw.Token, error) {
@@ -159,10 +159,135 @@ func (p *JSParser) buildEnum(ts []raw.Token) (*js.Enum, error) {
 	return js.NewEnum(clType, extends, keys, values, enCtx)
 }
 
-func (p *JSParser) buildEnumStatement(ts []raw.Token) (*js.Enum, []raw.Token, error) {
+// an enum with at least one payload variant (eg. Circle(radius Number)) is a tagged union
+func isTaggedUnionBody(bracesGroup *raw.Group) bool {
+	for _, field := range bracesGroup.Fields {
+		if len(field) > 1 && raw.IsParensGroup(field[1]) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (p *JSParser) buildTaggedUnion(ts []raw.Token) (*js.TaggedUnion, error) {
+	tuCtx := raw.MergeContexts(ts...)
+
+	if len(ts) < 3 {
+		errCtx := tuCtx
+		return nil, errCtx.NewError("Error: bad enum definition")
+	}
+
+	clType, ts, err := p.buildClassOrExtendsTypeExpression(ts[1:])
+	if err != nil {
+		return nil, err
+	}
+
+	if len(ts) != 1 {
+		errCtx := raw.MergeContexts(ts...)
+		return nil, errCtx.NewError("Error: tagged union can't extend anything")
+	}
+
+	bracesGroup, err := raw.AssertBracesGroup(ts[0])
+	if err != nil {
+		return nil, err
+	}
+
+	if bracesGroup.IsSemiColon() {
+		errCtx := bracesGroup.Context()
+		return nil, errCtx.NewError("Error: enums use comma separator")
+	}
+
+	tu := js.NewTaggedUnion(clType, tuCtx)
+
+	uniqueKeys := make(map[string]*raw.Word)
+
+	for _, field := range bracesGroup.Fields {
+		if len(field) == 0 {
+			continue
+		}
+
+		key, err := raw.AssertWord(field[0])
+		if err != nil {
+			return nil, err
+		}
+
+		if old, ok := uniqueKeys[key.Value()]; ok {
+			errCtx := key.Context()
+			err := errCtx.NewError("Error: key not unique")
+			err.AppendContextString("Info: also defined here", old.Context())
+			return nil, err
+		}
+
+		uniqueKeys[key.Value()] = key
+
+		fieldNames := make([]*js.Word, 0)
+		fieldTypes := make([]*js.TypeExpression, 0)
+
+		switch {
+		case len(field) == 1:
+		case len(field) == 2 && raw.IsParensGroup(field[1]):
+			parens, err := raw.AssertParensGroup(field[1])
+			if err != nil {
+				panic(err)
+			}
+
+			if parens.IsSemiColon() {
+				errCtx := parens.Context()
+				return nil, errCtx.NewError("Error: expected comma separated fields")
+			}
+
+			for _, payloadField := range parens.Fields {
+				if len(payloadField) == 0 {
+					continue
+				}
+
+				if len(payloadField) < 2 {
+					errCtx := raw.MergeContexts(payloadField...)
+					return nil, errCtx.NewError("Error: expected field name and type")
+				}
+
+				fieldName, err := raw.AssertWord(payloadField[0])
+				if err != nil {
+					return nil, err
+				}
+
+				fieldType, err := p.buildTypeExpression(payloadField[1:])
+				if err != nil {
+					return nil, err
+				}
+
+				fieldNames = append(fieldNames, js.NewWord(fieldName.Value(), fieldName.Context()))
+				fieldTypes = append(fieldTypes, fieldType)
+			}
+		default:
+			errCtx := raw.MergeContexts(field...)
+			return nil, errCtx.NewError("Error: bad tagged union variant (expected Name or Name(field Type, ...))")
+		}
+
+		if err := tu.AddVariant(js.NewWord(key.Value(), key.Context()), fieldNames, fieldTypes); err != nil {
+			return nil, err
+		}
+	}
+
+	return tu, nil
+}
+
+func (p *JSParser) buildEnumStatement(ts []raw.Token) (js.EnumStatement, []raw.Token, error) {
 	for i, t := range ts {
 		if raw.IsBracesGroup(t) {
-			statement, err := p.buildEnum(ts[0 : i+1])
+			var statement js.EnumStatement
+
+			bracesGroup, err := raw.AssertBracesGroup(t)
+			if err != nil {
+				panic(err)
+			}
+
+			if isTaggedUnionBody(bracesGroup) {
+				statement, err = p.buildTaggedUnion(ts[0 : i+1])
+			} else {
+				statement, err = p.buildEnum(ts[0 : i+1])
+			}
 			if err != nil {
 				return nil, nil, err
 			}
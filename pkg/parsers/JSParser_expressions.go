@@ -86,7 +86,7 @@ func (p *JSParser) buildLiteralArrayExpression(t raw.Token) (js.Expression, erro
 
 	items := make([]js.Expression, 0)
 	for _, field := range group.Fields {
-		item, err := p.buildExpression(field)
+		item, err := p.buildSpreadableExpression(field)
 		if err != nil {
 			return nil, err
 		}
@@ -113,6 +113,17 @@ func (p *JSParser) buildLiteralObjectExpression(t raw.Token) (js.Expression, err
 	values := make([]js.Expression, 0)
 
 	for _, field := range group.Fields {
+		if len(field) > 0 && raw.IsSymbol(field[0], patterns.SPLAT) {
+			value, err := p.buildSpreadableExpression(field)
+			if err != nil {
+				return nil, err
+			}
+
+			keys = append(keys, nil)
+			values = append(values, value)
+			continue
+		}
+
 		components := splitBySeparator(field, patterns.COLON)
 		if len(components) != 2 {
 			errCtx := raw.MergeContexts(field...)
@@ -149,6 +160,25 @@ func (p *JSParser) buildLiteralObjectExpression(t raw.Token) (js.Expression, err
 	return obj, nil
 }
 
+// ...x is only allowed in call arguments, literal arrays and literal objects
+func (p *JSParser) buildSpreadableExpression(ts []raw.Token) (js.Expression, error) {
+	if len(ts) == 0 || !raw.IsSymbol(ts[0], patterns.SPLAT) {
+		return p.buildExpression(ts)
+	}
+
+	if len(ts) == 1 {
+		errCtx := ts[0].Context()
+		return nil, errCtx.NewError("Error: expected expression after ...")
+	}
+
+	expr, err := p.buildExpression(ts[1:])
+	if err != nil {
+		return nil, err
+	}
+
+	return js.NewSpread(expr, raw.MergeContexts(ts...)), nil
+}
+
 func (p *JSParser) buildParensExpression(t raw.Token) (js.Expression, error) {
 	group, err := raw.AssertParensGroup(t)
 	if err != nil {
@@ -324,7 +354,97 @@ func (p *JSParser) buildMemberExpression(ts []raw.Token) (js.Expression, error)
 	return js.NewMember(lhs, js.NewWord(w.Value(), w.Context()), ts[n-2].Context()), nil
 }
 
+// a?.b, a?.[i] or a?.(...)
+func (p *JSParser) buildOptionalChainExpression(ts []raw.Token) (js.Expression, error) {
+	n := len(ts)
+
+	lhs, err := p.buildExpression(ts[0 : n-2])
+	if err != nil {
+		return nil, err
+	}
+
+	ctx := ts[n-2].Context()
+
+	switch {
+	case raw.IsAnyWord(ts[n-1]):
+		w, err := raw.AssertWord(ts[n-1])
+		if err != nil {
+			panic(err)
+		}
+
+		return js.NewOptionalMember(lhs, js.NewWord(w.Value(), w.Context()), ctx), nil
+	case raw.IsBracketsGroup(ts[n-1]):
+		group, err := raw.AssertBracketsGroup(ts[n-1])
+		if err != nil {
+			panic(err)
+		}
+
+		if !group.IsSingle() {
+			errCtx := group.Context()
+			return nil, errCtx.NewError("Error: expected single index")
+		}
+
+		index, err := p.buildExpression(group.Fields[0])
+		if err != nil {
+			return nil, err
+		}
+
+		return js.NewOptionalIndex(lhs, index, group.Context()), nil
+	default:
+		if err := js.AssertCallable(lhs); err != nil {
+			return nil, err
+		}
+
+		args, err := p.buildCallArgs(ts[n-1])
+		if err != nil {
+			return nil, err
+		}
+
+		return js.NewOptionalCall(lhs, args, lhs.Context()), nil
+	}
+}
+
+// A | B is turned into Union<A, B>, and T? into Union<T, null>
+func (p *JSParser) buildUnionTypeExpression(ts []raw.Token) (*js.TypeExpression, error) {
+	alts := make([]*js.TypeExpression, 0)
+
+	if raw.ContainsSymbol(ts, patterns.PIPE) {
+		for _, field := range splitBySeparator(ts, patterns.PIPE) {
+			if len(field) == 0 {
+				errCtx := raw.MergeContexts(ts...)
+				return nil, errCtx.NewError("Error: empty union type alternative")
+			}
+
+			alt, err := p.buildTypeExpression(field)
+			if err != nil {
+				return nil, err
+			}
+
+			alts = append(alts, alt)
+		}
+	} else {
+		alt, err := p.buildTypeExpression(ts[0 : len(ts)-1])
+		if err != nil {
+			return nil, err
+		}
+
+		alts = append(alts, alt)
+		alts = append(alts, js.NewNullTypeExpression(ts[len(ts)-1].Context()))
+	}
+
+	return js.NewTypeExpression("Union", nil, alts, raw.MergeContexts(ts...))
+}
+
 func (p *JSParser) buildTypeExpression(ts []raw.Token) (*js.TypeExpression, error) {
+	if raw.ContainsSymbol(ts, patterns.PIPE) ||
+		(len(ts) > 1 && raw.IsSymbol(ts[len(ts)-1], patterns.QUESTION)) {
+		return p.buildUnionTypeExpression(ts)
+	}
+
+	if len(ts) == 1 && raw.IsLiteralNull(ts[0]) {
+		return js.NewNullTypeExpression(ts[0].Context()), nil
+	}
+
 	nameToken, ts, err := condensePackagePeriods(ts)
 	if err != nil {
 		return nil, err
@@ -487,6 +607,9 @@ func (p *JSParser) buildExpression(ts []raw.Token) (js.Expression, error) {
 				return nil, err
 			}
 		}
+	case n > 2 && raw.IsSymbol(ts[n-2], patterns.QUESTION_PERIOD) &&
+		(raw.IsAnyWord(ts[n-1]) || raw.IsBracketsGroup(ts[n-1]) || raw.IsParensGroup(ts[n-1])):
+		return p.buildOptionalChainExpression(ts)
 	case raw.IsParensGroup(ts[n-1]):
 		return p.buildCallExpression(ts)
 	case raw.IsBracketsGroup(ts[n-1]):
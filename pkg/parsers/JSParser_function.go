@@ -100,6 +100,9 @@ func (p *JSParser) buildFunctionArgument(ts []raw.Token,
 					defTokens = ts[i+1:]
 					break
 				} else if !(raw.IsSymbol(ts[i], patterns.PERIOD) ||
+					raw.IsSymbol(ts[i], patterns.PIPE) ||
+					raw.IsSymbol(ts[i], patterns.QUESTION) ||
+					raw.IsLiteralNull(ts[i]) ||
 					raw.IsAngledGroup(ts[i]) ||
 					raw.IsAnyWord(ts[i])) {
 					errCtx := ts[i].Context()
@@ -472,6 +472,8 @@ func (p *JSParser) buildExportStatement(ts []raw.Token) ([]raw.Token, error) {
 		}
   case raw.IsBracesGroup(ts[1]):
     return p.buildExportList(ts)
+  case raw.IsSymbol(ts[1], patterns.AT):
+    return p.buildExportClassStatement(ts)
 	default:
 		errCtx := ts[0].Context()
 		return nil, errCtx.NewError("Error: not yet handled")
@@ -479,6 +481,18 @@ func (p *JSParser) buildExportStatement(ts []raw.Token) ([]raw.Token, error) {
 }
 
 func (p *JSParser) buildModuleStatement(ts []raw.Token) ([]raw.Token, error) {
+	if raw.IsSymbol(ts[0], patterns.AT) {
+		// decorators can also precede the export keyword
+		for i, t := range ts {
+			if raw.IsWord(t, "export") {
+				ts = append([]raw.Token{t}, append(ts[0:i:i], ts[i+1:]...)...)
+				break
+			} else if raw.IsBracesGroup(t) {
+				break
+			}
+		}
+	}
+
 	if raw.IsAnyWord(ts[0]) {
 		firstWord, err := raw.AssertWord(ts[0])
 		if err != nil {
@@ -165,12 +165,22 @@ func (p *JSParser) buildThrowStatement(ts []raw.Token) (*js.Throw, []raw.Token,
 
 func (p *JSParser) buildBreakStatement(ts []raw.Token) (*js.Break, []raw.Token, error) {
 	exprTokens, remainingTokens := splitByNextSeparator(ts, patterns.SEMICOLON)
-	if len(exprTokens) != 1 {
+	if len(exprTokens) > 2 || (len(exprTokens) == 2 && !raw.IsAnyWord(exprTokens[1])) {
 		errCtx := raw.MergeContexts(ts...)
 		return nil, nil, errCtx.NewError("Error: bad break statement")
 	}
 
-	breakStatement, err := js.NewBreak(exprTokens[0].Context())
+	label := ""
+	if len(exprTokens) == 2 {
+		labelWord, err := raw.AssertWord(exprTokens[1])
+		if err != nil {
+			return nil, nil, err
+		}
+
+		label = labelWord.Value()
+	}
+
+	breakStatement, err := js.NewBreak(label, raw.MergeContexts(exprTokens...))
 	if err != nil {
 		return nil, nil, err
 	}
@@ -180,12 +190,22 @@ func (p *JSParser) buildBreakStatement(ts []raw.Token) (*js.Break, []raw.Token,
 
 func (p *JSParser) buildContinueStatement(ts []raw.Token) (*js.Continue, []raw.Token, error) {
 	exprTokens, remainingTokens := splitByNextSeparator(ts, patterns.SEMICOLON)
-	if len(exprTokens) != 1 {
+	if len(exprTokens) > 2 || (len(exprTokens) == 2 && !raw.IsAnyWord(exprTokens[1])) {
 		errCtx := raw.MergeContexts(ts...)
 		return nil, nil, errCtx.NewError("Error: bad continue statement")
 	}
 
-	continueStatement, err := js.NewContinue(exprTokens[0].Context())
+	label := ""
+	if len(exprTokens) == 2 {
+		labelWord, err := raw.AssertWord(exprTokens[1])
+		if err != nil {
+			return nil, nil, err
+		}
+
+		label = labelWord.Value()
+	}
+
+	continueStatement, err := js.NewContinue(label, raw.MergeContexts(exprTokens...))
 	if err != nil {
 		return nil, nil, err
 	}
@@ -345,7 +365,10 @@ func (p *JSParser) buildDeleteStatement(ts []raw.Token) (*js.DeleteOp,
 func (p *JSParser) buildStatement(ts []raw.Token) (js.Statement, []raw.Token, error) {
 	ts = p.expandTmpGroups(ts)
 
-	if raw.IsAnyWord(ts[0]) {
+	if raw.IsSymbol(ts[0], patterns.AT) {
+		// only classes can be decorated
+		return p.buildClassStatement(ts)
+	} else if raw.IsAnyWord(ts[0]) {
 		firstWord, err := raw.AssertWord(ts[0])
 		if err != nil {
 			panic(err)
@@ -395,6 +418,8 @@ func (p *JSParser) buildStatement(ts []raw.Token) (js.Statement, []raw.Token, er
 			return p.buildSwitchStatement(ts)
 		case "while":
 			return p.buildWhileStatement(ts)
+		case "do":
+			return p.buildDoWhileStatement(ts)
 		case "for":
 			return p.buildForStatement(ts)
 		case "void":
@@ -412,6 +437,8 @@ func (p *JSParser) buildStatement(ts []raw.Token) (js.Statement, []raw.Token, er
 			switch {
       case raw.IsWord(ts[0], "rpc") && raw.IsWord(ts[1], "interface"):
         return p.buildInterfaceStatement(ts)
+			case raw.IsSymbol(ts[1], patterns.COLON):
+				return p.buildLabelStatement(ts)
 			case raw.IsSymbolThatEndsWith(ts[1], patterns.EQUAL) &&
 				!raw.IsSymbol(ts[1], patterns.COLON_EQUAL):
 				return p.buildAssignStatement(ts)
@@ -422,7 +449,8 @@ func (p *JSParser) buildStatement(ts []raw.Token) (js.Statement, []raw.Token, er
 			case raw.IsSymbol(ts[1], patterns.MINUS_MINUS):
 				return p.buildPostDecrOpStatement(ts)
 			case !raw.ContainsSymbol(ts[0:ilast], patterns.EQUAL) &&
-				(raw.IsAnyGroup(ts[ilast-2]) || raw.IsAnyWord(ts[ilast-2])) &&
+				(raw.IsAnyGroup(ts[ilast-2]) || raw.IsAnyWord(ts[ilast-2]) ||
+					raw.IsSymbol(ts[ilast-2], patterns.QUESTION_PERIOD)) &&
 				raw.IsParensGroup(ts[ilast-1]):
 				return p.buildCallStatement(ts)
 			default:
@@ -479,3 +507,28 @@ func (p *JSParser) buildStatement(ts []raw.Token) (js.Statement, []raw.Token, er
 		}
 	}
 }
+
+// label: for/while/do loop
+func (p *JSParser) buildLabelStatement(ts []raw.Token) (*js.Label, []raw.Token, error) {
+	labelWord, err := raw.AssertWord(ts[0])
+	if err != nil {
+		return nil, nil, err
+	}
+
+	labelCtx := raw.MergeContexts(ts[0:2]...)
+	if len(ts) < 3 {
+		return nil, nil, labelCtx.NewError("Error: expected loop after label")
+	}
+
+	loop, remainingTokens, err := p.buildStatement(ts[2:])
+	if err != nil {
+		return nil, nil, err
+	}
+
+	labelStatement, err := js.NewLabel(labelWord.Value(), loop, labelCtx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return labelStatement, remainingTokens, nil
+}
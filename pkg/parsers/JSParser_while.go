@@ -56,3 +56,53 @@ func (p *JSParser) buildWhileStatement(ts []raw.Token) (*js.While, []raw.Token,
 
 	return whileStatement, ts, nil
 }
+
+func (p *JSParser) buildDoWhileStatement(ts []raw.Token) (*js.DoWhile, []raw.Token, error) {
+	first := ts[0]
+
+	if len(ts) < 4 || !raw.IsWord(ts[2], "while") {
+		errCtx := first.Context()
+		return nil, nil, errCtx.NewError("Error: expected do{...}while(...)")
+	}
+
+	bracesGroup, err := raw.AssertBracesGroup(ts[1])
+	if err != nil {
+		return nil, nil, err
+	}
+
+	parensGroup, err := raw.AssertParensGroup(ts[3])
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if !parensGroup.IsSingle() {
+		errCtx := parensGroup.Context()
+		return nil, nil, errCtx.NewError("Error: expected single condition")
+	}
+
+	cond, err := p.buildExpression(parensGroup.Fields[0])
+	if err != nil {
+		return nil, nil, err
+	}
+
+	doWhileCtx := context.MergeContexts(first.Context(),
+		bracesGroup.Context(), parensGroup.Context())
+
+	doWhileStatement, err := js.NewDoWhile(cond, doWhileCtx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	statements, err := p.buildBlockStatements(bracesGroup)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for _, st := range statements {
+		doWhileStatement.AddStatement(st)
+	}
+
+	ts = stripSeparators(4, ts, patterns.SEMICOLON)
+
+	return doWhileStatement, ts, nil
+}
@@ -0,0 +1,739 @@
+package parsers
+
+import (
+  "errors"
+  "io/ioutil"
+  "regexp"
+  "strconv"
+  "strings"
+  "unicode"
+  "unicode/utf8"
+
+  "github.com/computeportal/wtsuite/pkg/tokens/context"
+  "github.com/computeportal/wtsuite/pkg/tokens/html"
+  "github.com/computeportal/wtsuite/pkg/tokens/patterns"
+)
+
+// the body of a markdown module is exported as a template with this name
+const MARKDOWN_TEMPLATE_NAME = "Markdown"
+
+var (
+  mdReFrontMatterKey = regexp.MustCompile(`^([A-Za-z_][A-Za-z0-9_]*)[ \t]*:(?:[ \t]+(.*))?$`)
+  mdReSlugStrip = regexp.MustCompile(`[^\p{L}\p{N}_ -]`)
+)
+
+var mdTextEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", "\"", "&quot;")
+
+// markdown files (CommonMark + tables), optionally with yaml-like front matter:
+//  ---
+//  template: Layout
+//  from: ./layout.wtt
+//  block: content
+//  title: "Some title"
+//  ---
+// the front matter keys become exported vars and are passed as args to the template
+type MarkdownParser struct {
+  raw       string
+  runeIndex []int // byte offset -> rune offset
+  refs      map[string]mdLinkRef
+  ids       map[string]int
+  ctx       context.Context
+}
+
+type mdFrontMatterEntry struct {
+  key   string
+  value interface{}
+  ctx   context.Context
+}
+
+func NewMarkdownParser(path string) (*MarkdownParser, error) {
+	rawBytes, err := ioutil.ReadFile(path)
+	if err != nil {
+    return nil, errors.New("Error: problem reading \"" + path + "\" (" + err.Error() + ")")
+	}
+
+	raw := string(rawBytes)
+	src := context.NewSource(raw)
+
+  runeIndex := make([]int, len(raw)+1)
+  iRune := 0
+  for i, _ := range raw {
+    for j := i; j < len(raw) && (j == i || !utf8.RuneStart(raw[j])); j++ {
+      runeIndex[j] = iRune
+    }
+    iRune++
+  }
+  runeIndex[len(raw)] = iRune
+
+  return &MarkdownParser{
+    raw,
+    runeIndex,
+    make(map[string]mdLinkRef),
+    make(map[string]int),
+    context.NewContext(src, path),
+  }, nil
+}
+
+// rune offsets, like Parser.NewContext
+func (p *MarkdownParser) NewContext(start, stop int) context.Context {
+  if stop == -1 {
+    stop = p.runeIndex[len(p.raw)]
+  }
+
+  return p.ctx.NewContext(start, stop)
+}
+
+// byte offsets
+func (p *MarkdownParser) newContext(start, stop int) context.Context {
+  if stop > len(p.raw) {
+    stop = len(p.raw)
+  }
+
+  if start > stop {
+    start = stop
+  }
+
+  return p.NewContext(p.runeIndex[start], p.runeIndex[stop])
+}
+
+func (p *MarkdownParser) BuildTags() ([]*html.Tag, error) {
+  entries, bodyOffset, err := p.parseFrontMatter()
+  if err != nil {
+    return nil, err
+  }
+
+  bp := newMDBlockParser(p)
+  doc := bp.parse(p.raw[bodyOffset:], bodyOffset)
+
+  body, err := p.convertBlocks(doc.children, false)
+  if err != nil {
+    return nil, err
+  }
+
+  return p.buildModule(entries, body)
+}
+
+// import of the template, exported vars, the Markdown template and its instantiation
+func (p *MarkdownParser) buildModule(entries []mdFrontMatterEntry, body []*html.Tag) ([]*html.Tag, error) {
+  result := make([]*html.Tag, 0)
+
+  ctx := p.newContext(0, 1)
+
+  var templateEntry, fromEntry, blockEntry *mdFrontMatterEntry
+  vars := make([]mdFrontMatterEntry, 0)
+
+  for i, entry := range entries {
+    switch entry.key {
+    case "template":
+      templateEntry = &entries[i]
+    case "from":
+      fromEntry = &entries[i]
+    case "block":
+      blockEntry = &entries[i]
+    default:
+      vars = append(vars, entry)
+    }
+  }
+
+  assertStringEntry := func(entry *mdFrontMatterEntry) (string, error) {
+    s, ok := entry.value.(string)
+    if !ok || s == "" {
+      errCtx := entry.ctx
+      return "", errCtx.NewError("Error: expected a string")
+    }
+
+    return s, nil
+  }
+
+  templateName := ""
+  if templateEntry != nil {
+    var err error
+    templateName, err = assertStringEntry(templateEntry)
+    if err != nil {
+      return nil, err
+    }
+
+    if !patterns.IsValidVar(templateName) {
+      errCtx := templateEntry.ctx
+      return nil, errCtx.NewError("Error: invalid template name")
+    }
+
+    if fromEntry == nil {
+      errCtx := templateEntry.ctx
+      return nil, errCtx.NewError("Error: template specified without \"from\"")
+    }
+
+    from, err := assertStringEntry(fromEntry)
+    if err != nil {
+      return nil, err
+    }
+
+    importAttr := html.NewEmptyRawDict(fromEntry.ctx)
+    importAttr.Set(html.NewValueString(".dynamic", fromEntry.ctx), html.NewValueBool(false, fromEntry.ctx))
+
+    names := html.NewEmptyRawDict(templateEntry.ctx)
+    names.Set(html.NewValueString(templateName, templateEntry.ctx), html.NewValueString(templateName, templateEntry.ctx))
+
+    importAttr.Set(html.NewValueString("names", templateEntry.ctx), names)
+    importAttr.Set(html.NewValueString("from", fromEntry.ctx), html.NewValueString(from, fromEntry.ctx))
+
+    result = append(result, html.NewDirectiveTag("import", importAttr, []*html.Tag{}, fromEntry.ctx))
+  } else if fromEntry != nil {
+    errCtx := fromEntry.ctx
+    return nil, errCtx.NewError("Error: \"from\" specified without template")
+  } else if blockEntry != nil {
+    errCtx := blockEntry.ctx
+    return nil, errCtx.NewError("Error: block specified without template")
+  }
+
+  args := html.NewEmptyRawDict(ctx)
+
+  for _, entry := range vars {
+    value, err := html.GolangToToken(entry.value, entry.ctx)
+    if err != nil {
+      errCtx := entry.ctx
+      return nil, errCtx.NewError("Error: " + err.Error())
+    }
+
+    varAttr := html.NewEmptyRawDict(entry.ctx)
+    varAttr.Set(html.NewValueString(entry.key, entry.ctx), value)
+    varAttr.Set(html.NewValueString("export", entry.ctx), html.NewValueString("", entry.ctx))
+
+    result = append(result, html.NewDirectiveTag("var", varAttr, []*html.Tag{}, entry.ctx))
+
+    args.Set(html.NewValueString(entry.key, entry.ctx), value)
+  }
+
+  templateAttr := html.NewEmptyRawDict(ctx)
+  templateAttr.Set(html.NewValueString("name", ctx), html.NewValueString(MARKDOWN_TEMPLATE_NAME, ctx))
+  templateAttr.Set(html.NewValueString("extends", ctx), html.NewValueString("dummy", ctx))
+  templateAttr.Set(html.NewValueString("super", ctx), html.NewEmptyRawDict(ctx))
+  templateAttr.Set(html.NewValueString("export", ctx), html.NewValueString("", ctx))
+
+  result = append(result, html.NewDirectiveTag("template", templateAttr, body, ctx))
+
+  bodyTag := html.NewTag(MARKDOWN_TEMPLATE_NAME, html.NewEmptyRawDict(ctx), []*html.Tag{}, ctx)
+
+  if templateName == "" {
+    result = append(result, bodyTag)
+  } else if blockEntry != nil {
+    block, err := assertStringEntry(blockEntry)
+    if err != nil {
+      return nil, err
+    }
+
+    appendAttr := html.NewEmptyRawDict(blockEntry.ctx)
+    appendAttr.Set(html.NewValueInt(0, blockEntry.ctx), html.NewValueString(block, blockEntry.ctx))
+    appendTag := html.NewDirectiveTag("append", appendAttr, []*html.Tag{bodyTag}, blockEntry.ctx)
+
+    result = append(result, html.NewTag(templateName, args, []*html.Tag{appendTag}, templateEntry.ctx))
+  } else {
+    result = append(result, html.NewTag(templateName, args, []*html.Tag{bodyTag}, templateEntry.ctx))
+  }
+
+  return result, nil
+}
+
+// returns the byte offset of the body
+func (p *MarkdownParser) parseFrontMatter() ([]mdFrontMatterEntry, int, error) {
+  entries := make([]mdFrontMatterEntry, 0)
+
+  lines := strings.SplitAfter(p.raw, "\n")
+  if len(lines) == 0 || strings.TrimRight(lines[0], " \t\r\n") != "---" {
+    return entries, 0, nil
+  }
+
+  offset := len(lines[0])
+  startCtx := p.newContext(0, 3)
+
+  var listEntry *mdFrontMatterEntry = nil
+
+  for _, line := range lines[1:] {
+    lineOffset := offset
+    offset += len(line)
+
+    content := strings.TrimRight(line, " \t\r\n")
+    lineCtx := p.newContext(lineOffset, lineOffset+len(content))
+
+    if content == "---" || content == "..." {
+      return entries, offset, nil
+    }
+
+    trimmed := strings.TrimSpace(content)
+    if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+      continue
+    }
+
+    if strings.HasPrefix(trimmed, "- ") || trimmed == "-" {
+      if listEntry == nil {
+        return nil, 0, lineCtx.NewError("Error: unexpected list item")
+      }
+
+      value, err := parseMDFrontMatterValue(strings.TrimSpace(trimmed[1:]), lineCtx)
+      if err != nil {
+        return nil, 0, err
+      }
+
+      listEntry.value = append(listEntry.value.([]interface{}), value)
+      continue
+    }
+
+    if content[0] == ' ' || content[0] == '\t' {
+      return nil, 0, lineCtx.NewError("Error: nested front matter values not supported")
+    }
+
+    m := mdReFrontMatterKey.FindStringSubmatch(content)
+    if m == nil {
+      return nil, 0, lineCtx.NewError("Error: expected key: value")
+    }
+
+    key := m[1]
+    for _, entry := range entries {
+      if entry.key == key {
+        return nil, 0, lineCtx.NewError("Error: duplicate key " + key)
+      }
+    }
+
+    if !patterns.IsValidVar(key) {
+      return nil, 0, lineCtx.NewError("Error: invalid var name " + key)
+    }
+
+    entries = append(entries, mdFrontMatterEntry{key, nil, lineCtx})
+
+    if m[2] == "" || strings.HasPrefix(m[2], "#") {
+      // block list can follow
+      entries[len(entries)-1].value = []interface{}{}
+      listEntry = &entries[len(entries)-1]
+    } else {
+      value, err := parseMDFrontMatterValue(m[2], lineCtx)
+      if err != nil {
+        return nil, 0, err
+      }
+
+      entries[len(entries)-1].value = value
+      listEntry = nil
+    }
+  }
+
+  return nil, 0, startCtx.NewError("Error: front matter not closed")
+}
+
+func parseMDFrontMatterValue(s string, ctx context.Context) (interface{}, error) {
+  s = strings.TrimSpace(s)
+
+  // strip trailing comments of unquoted values
+  if s != "" && s[0] != '"' && s[0] != '\'' {
+    if i := strings.Index(s, " #"); i != -1 {
+      s = strings.TrimSpace(s[0:i])
+    }
+  }
+
+  switch {
+  case s == "" || s == "~" || s == "null":
+    return nil, nil
+  case s == "true":
+    return true, nil
+  case s == "false":
+    return false, nil
+  case strings.HasPrefix(s, "\""):
+    if !strings.HasSuffix(s, "\"") || len(s) < 2 {
+      return nil, ctx.NewError("Error: unterminated string")
+    }
+
+    v, err := strconv.Unquote(s)
+    if err != nil {
+      return nil, ctx.NewError("Error: bad string (" + err.Error() + ")")
+    }
+
+    return v, nil
+  case strings.HasPrefix(s, "'"):
+    if !strings.HasSuffix(s, "'") || len(s) < 2 {
+      return nil, ctx.NewError("Error: unterminated string")
+    }
+
+    return strings.ReplaceAll(s[1:len(s)-1], "''", "'"), nil
+  case strings.HasPrefix(s, "["):
+    if !strings.HasSuffix(s, "]") {
+      return nil, ctx.NewError("Error: unterminated list")
+    }
+
+    res := make([]interface{}, 0)
+
+    inner := strings.TrimSpace(s[1 : len(s)-1])
+    if inner == "" {
+      return res, nil
+    }
+
+    for _, item := range splitMDFrontMatterList(inner) {
+      v, err := parseMDFrontMatterValue(item, ctx)
+      if err != nil {
+        return nil, err
+      }
+
+      res = append(res, v)
+    }
+
+    return res, nil
+  case strings.HasPrefix(s, "{"):
+    return nil, ctx.NewError("Error: front matter dicts not supported")
+  }
+
+  if i, err := strconv.Atoi(s); err == nil {
+    return i, nil
+  }
+
+  if f, err := strconv.ParseFloat(s, 64); err == nil {
+    return f, nil
+  }
+
+  return s, nil
+}
+
+// commas inside quotes don't split
+func splitMDFrontMatterList(s string) []string {
+  res := make([]string, 0)
+
+  var quote rune = 0
+  start := 0
+  for i, c := range s {
+    switch {
+    case quote != 0:
+      if c == quote {
+        quote = 0
+      }
+    case c == '"' || c == '\'':
+      quote = c
+    case c == ',':
+      res = append(res, s[start:i])
+      start = i + 1
+    }
+  }
+
+  return append(res, s[start:])
+}
+
+func (p *MarkdownParser) convertBlocks(blocks []*mdBlock, tight bool) ([]*html.Tag, error) {
+  result := make([]*html.Tag, 0)
+
+  for _, block := range blocks {
+    if tight && block.t == MD_PARAGRAPH {
+      // paragraphs in tight lists aren't wrapped
+      children, err := p.convertInlines(block.content)
+      if err != nil {
+        return nil, err
+      }
+
+      result = append(result, children...)
+      continue
+    }
+
+    tag, err := p.convertBlock(block)
+    if err != nil {
+      return nil, err
+    }
+
+    result = append(result, tag)
+  }
+
+  return result, nil
+}
+
+func (p *MarkdownParser) convertBlock(block *mdBlock) (*html.Tag, error) {
+  ctx := block.ctx
+  attr := html.NewEmptyRawDict(ctx)
+
+  setAttr := func(key string, value string) {
+    attr.Set(html.NewValueString(key, ctx), html.NewValueString(value, ctx))
+  }
+
+  switch block.t {
+  case MD_BLOCKQUOTE:
+    children, err := p.convertBlocks(block.children, false)
+    if err != nil {
+      return nil, err
+    }
+
+    return html.NewTag("blockquote", attr, children, ctx), nil
+  case MD_LIST:
+    name := "ul"
+    if block.list.ordered {
+      name = "ol"
+      if block.list.start != 1 {
+        attr.Set(html.NewValueString("start", ctx), html.NewValueInt(block.list.start, ctx))
+      }
+    }
+
+    items := make([]*html.Tag, 0)
+    for _, item := range block.children {
+      children, err := p.convertBlocks(item.children, block.list.tight)
+      if err != nil {
+        return nil, err
+      }
+
+      items = append(items, html.NewTag("li", html.NewEmptyRawDict(item.ctx), children, item.ctx))
+    }
+
+    return html.NewTag(name, attr, items, ctx), nil
+  case MD_PARAGRAPH:
+    children, err := p.convertInlines(block.content)
+    if err != nil {
+      return nil, err
+    }
+
+    return html.NewTag("p", attr, children, ctx), nil
+  case MD_HEADING:
+    children, err := p.convertInlines(block.content)
+    if err != nil {
+      return nil, err
+    }
+
+    setAttr("id", p.uniqueHeadingID(block.content))
+
+    return html.NewTag("h"+strconv.Itoa(block.level), attr, children, ctx), nil
+  case MD_THEMATIC_BREAK:
+    return html.NewTag("hr", attr, []*html.Tag{}, ctx), nil
+  case MD_CODE_BLOCK:
+    lines := make([]string, len(block.lines))
+    for i, line := range block.lines {
+      lines[i] = line.s
+    }
+
+    code := strings.Join(lines, "\n")
+
+    lang := ""
+    if fields := strings.Fields(block.info); len(fields) > 0 {
+      lang = fields[0]
+    }
+
+    if lang == "math" {
+      mathAttr := html.NewEmptyRawDict(ctx)
+      mathAttr.Set(html.NewValueString("value", ctx), html.NewValueString(strings.TrimSpace(code), ctx))
+      mathAttr.Set(html.NewValueString("inline", ctx), html.NewValueBool(false, ctx))
+
+      return html.NewDirectiveTag("math", mathAttr, []*html.Tag{}, ctx), nil
+    }
+
+    codeAttr := html.NewEmptyRawDict(ctx)
+    if lang != "" {
+      codeAttr.Set(html.NewValueString("class", ctx), html.NewValueString("language-"+mdAttrEscape(lang), ctx))
+    }
+
+    // highlighted code is escaped after tokenization
+    if IsHighlightLang(lang) {
+      codeAttr.Set(html.NewValueString("lang", ctx), html.NewValueString(lang, ctx))
+    } else {
+      code = mdTextEscaper.Replace(code)
+    }
+
+    codeTag := html.NewTag("code", codeAttr, []*html.Tag{html.NewTextTag(code, ctx)}, ctx)
+
+    return html.NewTag("pre", attr, []*html.Tag{codeTag}, ctx), nil
+  case MD_HTML_BLOCK:
+    lines := make([]string, len(block.lines))
+    for i, line := range block.lines {
+      lines[i] = line.s
+    }
+
+    // raw html is kept verbatim
+    return html.NewTextTag(strings.Join(lines, "\n"), ctx), nil
+  case MD_TABLE:
+    return p.convertTable(block)
+  default:
+    errCtx := ctx
+    return nil, errCtx.NewError("Internal Error: unexpected markdown block")
+  }
+}
+
+func (p *MarkdownParser) convertTable(block *mdBlock) (*html.Tag, error) {
+  ctx := block.ctx
+
+  convertRow := func(row []mdText, cellName string) (*html.Tag, error) {
+    cells := make([]*html.Tag, 0)
+
+    for i, align := range block.aligns {
+      cellCtx := ctx
+      var children []*html.Tag
+      if i < len(row) {
+        cellCtx = p.newContext(row[i].offsets0(), row[i].endOffset())
+
+        var err error
+        children, err = p.convertInlines(row[i])
+        if err != nil {
+          return nil, err
+        }
+      } else {
+        children = []*html.Tag{}
+      }
+
+      cellAttr := html.NewEmptyRawDict(cellCtx)
+      if align != "" {
+        cellAttr.Set(html.NewValueString("style", cellCtx), html.NewValueString("text-align:"+align, cellCtx))
+      }
+
+      cells = append(cells, html.NewTag(cellName, cellAttr, children, cellCtx))
+    }
+
+    return html.NewTag("tr", html.NewEmptyRawDict(ctx), cells, ctx), nil
+  }
+
+  header, err := convertRow(block.rows[0], "th")
+  if err != nil {
+    return nil, err
+  }
+
+  children := []*html.Tag{html.NewTag("thead", html.NewEmptyRawDict(ctx), []*html.Tag{header}, ctx)}
+
+  if len(block.rows) > 1 {
+    rows := make([]*html.Tag, 0)
+    for _, row := range block.rows[1:] {
+      rowTag, err := convertRow(row, "td")
+      if err != nil {
+        return nil, err
+      }
+
+      rows = append(rows, rowTag)
+    }
+
+    children = append(children, html.NewTag("tbody", html.NewEmptyRawDict(ctx), rows, ctx))
+  }
+
+  return html.NewTag("table", html.NewEmptyRawDict(ctx), children, ctx), nil
+}
+
+func mdAttrEscape(s string) string {
+  return mdTextEscaper.Replace(s)
+}
+
+// github style: lowercase, punctuation removed, spaces replaced by dashes, duplicates get a numeric suffix
+func (p *MarkdownParser) uniqueHeadingID(content mdText) string {
+  ip := newMDInlineParser(p, content)
+  root := ip.parse()
+
+  slug := strings.Map(unicode.ToLower, mdInlinePlainText(root))
+  slug = mdReSlugStrip.ReplaceAllString(slug, "")
+  slug = strings.ReplaceAll(strings.TrimSpace(slug), " ", "-")
+
+  if slug == "" {
+    slug = "section"
+  }
+
+  id := slug
+  if n, ok := p.ids[slug]; ok {
+    id = slug + "-" + strconv.Itoa(n)
+    p.ids[slug] = n + 1
+  } else {
+    p.ids[slug] = 1
+  }
+
+  return id
+}
+
+func mdInlinePlainText(node *mdInline) string {
+  var b strings.Builder
+
+  for child := node.firstChild; child != nil; child = child.next {
+    switch child.t {
+    case MD_TEXT, MD_CODE, MD_MATH:
+      b.WriteString(child.literal)
+    case MD_SOFTBREAK, MD_HARDBREAK:
+      b.WriteString(" ")
+    case MD_EMPH, MD_STRONG, MD_LINK, MD_IMAGE:
+      b.WriteString(mdInlinePlainText(child))
+    }
+  }
+
+  return b.String()
+}
+
+func (p *MarkdownParser) convertInlines(content mdText) ([]*html.Tag, error) {
+  ip := newMDInlineParser(p, content)
+  root := ip.parse()
+
+  return p.convertInlineChildren(ip, root)
+}
+
+func (p *MarkdownParser) convertInlineChildren(ip *mdInlineParser, node *mdInline) ([]*html.Tag, error) {
+  result := make([]*html.Tag, 0)
+
+  // consecutive text is merged into a single text tag
+  var b strings.Builder
+  textStart := -1
+  textStop := -1
+
+  flushText := func() {
+    if textStart != -1 {
+      result = append(result, html.NewTextTag(b.String(), ip.context(textStart, textStop)))
+      b.Reset()
+      textStart = -1
+    }
+  }
+
+  addText := func(s string, n *mdInline) {
+    if textStart == -1 {
+      textStart = n.start
+    }
+
+    textStop = n.stop
+    b.WriteString(s)
+  }
+
+  for child := node.firstChild; child != nil; child = child.next {
+    ctx := ip.context(child.start, child.stop)
+
+    switch child.t {
+    case MD_TEXT:
+      addText(mdTextEscaper.Replace(child.literal), child)
+    case MD_SOFTBREAK:
+      addText(" ", child)
+    case MD_ENTITY, MD_HTML_INLINE:
+      addText(child.literal, child)
+    case MD_HARDBREAK:
+      flushText()
+      result = append(result, html.NewTag("br", html.NewEmptyRawDict(ctx), []*html.Tag{}, ctx))
+    case MD_CODE:
+      flushText()
+      textTag := html.NewTextTag(mdTextEscaper.Replace(child.literal), ctx)
+      result = append(result, html.NewTag("code", html.NewEmptyRawDict(ctx), []*html.Tag{textTag}, ctx))
+    case MD_MATH:
+      flushText()
+      attr := html.NewEmptyRawDict(ctx)
+      attr.Set(html.NewValueString("value", ctx), html.NewValueString(child.literal, ctx))
+      attr.Set(html.NewValueString("inline", ctx), html.NewValueBool(true, ctx))
+      result = append(result, html.NewDirectiveTag("math", attr, []*html.Tag{}, ctx))
+    case MD_EMPH, MD_STRONG, MD_LINK:
+      flushText()
+      children, err := p.convertInlineChildren(ip, child)
+      if err != nil {
+        return nil, err
+      }
+
+      attr := html.NewEmptyRawDict(ctx)
+      name := "em"
+      if child.t == MD_STRONG {
+        name = "strong"
+      } else if child.t == MD_LINK {
+        name = "a"
+        attr.Set(html.NewValueString("href", ctx), html.NewValueString(mdAttrEscape(child.dest), ctx))
+        if child.title != "" {
+          attr.Set(html.NewValueString("title", ctx), html.NewValueString(mdAttrEscape(child.title), ctx))
+        }
+      }
+
+      result = append(result, html.NewTag(name, attr, children, ctx))
+    case MD_IMAGE:
+      flushText()
+      attr := html.NewEmptyRawDict(ctx)
+      attr.Set(html.NewValueString("src", ctx), html.NewValueString(mdAttrEscape(child.dest), ctx))
+      attr.Set(html.NewValueString("alt", ctx), html.NewValueString(mdAttrEscape(mdInlinePlainText(child)), ctx))
+      if child.title != "" {
+        attr.Set(html.NewValueString("title", ctx), html.NewValueString(mdAttrEscape(child.title), ctx))
+      }
+
+      result = append(result, html.NewTag("img", attr, []*html.Tag{}, ctx))
+    }
+  }
+
+  flushText()
+
+  return result, nil
+}
@@ -0,0 +1,984 @@
+package parsers
+
+import (
+  "regexp"
+  "strings"
+
+  "github.com/computeportal/wtsuite/pkg/tokens/context"
+)
+
+// block structure follows the CommonMark reference implementation (commonmark.js), plus GFM tables
+
+type mdBlockType int
+
+const (
+  MD_DOCUMENT mdBlockType = iota
+  MD_BLOCKQUOTE
+  MD_LIST
+  MD_ITEM
+  MD_PARAGRAPH
+  MD_HEADING
+  MD_THEMATIC_BREAK
+  MD_CODE_BLOCK
+  MD_HTML_BLOCK
+  MD_TABLE
+)
+
+const MD_CODE_INDENT = 4
+
+var (
+  mdReMaybeSpecial = regexp.MustCompile(`^[#` + "`" + `~*+_=<>0-9|:-]`)
+  mdReATXHeadingMarker = regexp.MustCompile(`^#{1,6}(?:[ \t]+|$)`)
+  mdReCodeFence = regexp.MustCompile("^`{3,}|^~{3,}")
+  mdReClosingCodeFence = regexp.MustCompile("^(?:`{3,}|~{3,})[ \t]*$")
+  mdReSetextHeadingLine = regexp.MustCompile(`^(?:=+|-+)[ \t]*$`)
+  mdReThematicBreak = regexp.MustCompile(`^(?:\*[ \t]*){3,}$|^(?:_[ \t]*){3,}$|^(?:-[ \t]*){3,}$`)
+  mdReBulletListMarker = regexp.MustCompile(`^[*+-]`)
+  mdReOrderedListMarker = regexp.MustCompile(`^(\d{1,9})([.)])`)
+  mdReATXClosingEmpty = regexp.MustCompile(`^[ \t]*#+[ \t]*$`)
+  mdReATXClosing = regexp.MustCompile(`[ \t]+#+[ \t]*$`)
+  mdReTableDelimiterCell = regexp.MustCompile(`^:?-+:?$`)
+
+  mdReHTMLBlockOpen = []*regexp.Regexp{
+    nil,
+    regexp.MustCompile(`(?i)^<(?:script|pre|textarea|style)(?:\s|>|$)`),
+    regexp.MustCompile(`^<!--`),
+    regexp.MustCompile(`^<[?]`),
+    regexp.MustCompile(`^<![A-Za-z]`),
+    regexp.MustCompile(`^<!\[CDATA\[`),
+    regexp.MustCompile(`(?i)^<[/]?(?:address|article|aside|base|basefont|blockquote|body|caption|center|col|colgroup|dd|details|dialog|dir|div|dl|dt|fieldset|figcaption|figure|footer|form|frame|frameset|h[123456]|head|header|hr|html|iframe|legend|li|link|main|menu|menuitem|nav|noframes|ol|optgroup|option|p|param|search|section|summary|table|tbody|td|tfoot|th|thead|title|tr|track|ul)(?:\s|[/]?[>]|$)`),
+    regexp.MustCompile(`(?i)^(?:` + MD_OPEN_TAG + `|` + MD_CLOSE_TAG + `)\s*$`),
+  }
+
+  mdReHTMLBlockClose = []*regexp.Regexp{
+    nil,
+    regexp.MustCompile(`(?i)</(?:script|pre|textarea|style)>`),
+    regexp.MustCompile(`-->`),
+    regexp.MustCompile(`\?>`),
+    regexp.MustCompile(`>`),
+    regexp.MustCompile(`\]\]>`),
+  }
+)
+
+// text with the byte offset in the source of every byte, so that inline tokens can get precise contexts
+type mdText struct {
+  s       string
+  offsets []int
+}
+
+func newMDText(s string, offset int) mdText {
+  offsets := make([]int, len(s))
+  for i, _ := range offsets {
+    offsets[i] = offset + i
+  }
+
+  return mdText{s, offsets}
+}
+
+func (t mdText) slice(start, stop int) mdText {
+  return mdText{t.s[start:stop], t.offsets[start:stop]}
+}
+
+func (t mdText) append(other mdText) mdText {
+  return mdText{t.s + other.s, append(append([]int{}, t.offsets...), other.offsets...)}
+}
+
+func (t mdText) trimSpace() mdText {
+  start := 0
+  for start < len(t.s) && isMDWhitespace(t.s[start]) {
+    start++
+  }
+
+  stop := len(t.s)
+  for stop > start && isMDWhitespace(t.s[stop-1]) {
+    stop--
+  }
+
+  return t.slice(start, stop)
+}
+
+// lines joined by newlines, each line with its leading whitespace removed (used for paragraphs and headings)
+func joinMDLines(lines []mdText) mdText {
+  res := mdText{"", []int{}}
+  for i, line := range lines {
+    if i > 0 {
+      res = res.append(mdText{"\n", []int{res.endOffset()}})
+    }
+
+    start := 0
+    for start < len(line.s) && (line.s[start] == ' ' || line.s[start] == '\t') {
+      start++
+    }
+
+    res = res.append(line.slice(start, len(line.s)))
+  }
+
+  return res
+}
+
+// byte offset in the source just after the text
+func (t mdText) endOffset() int {
+  if len(t.offsets) == 0 {
+    return 0
+  }
+
+  return t.offsets[len(t.offsets)-1] + 1
+}
+
+func (t mdText) offsets0() int {
+  if len(t.offsets) == 0 {
+    return 0
+  }
+
+  return t.offsets[0]
+}
+
+type mdListData struct {
+  ordered      bool
+  bulletChar   byte
+  start        int
+  delimiter    byte
+  padding      int
+  markerOffset int
+  tight        bool
+}
+
+type mdBlock struct {
+  t               mdBlockType
+  parent          *mdBlock
+  children        []*mdBlock
+  open            bool
+  lastLineBlank   bool
+  lastLineChecked bool
+  startLine       int
+  lines           []mdText
+
+  // headings
+  level   int
+  content mdText
+
+  // code blocks
+  fenced      bool
+  fenceChar   byte
+  fenceLength int
+  fenceOffset int
+  info        string
+
+  htmlType int
+
+  list mdListData
+
+  // tables, first row is the header
+  aligns []string
+  rows   [][]mdText
+
+  ctx context.Context
+}
+
+func (b *mdBlock) lastChild() *mdBlock {
+  if len(b.children) == 0 {
+    return nil
+  }
+
+  return b.children[len(b.children)-1]
+}
+
+func (b *mdBlock) removeChild(child *mdBlock) {
+  for i, c := range b.children {
+    if c == child {
+      b.children = append(b.children[0:i], b.children[i+1:]...)
+      return
+    }
+  }
+}
+
+func (b *mdBlock) replaceChild(child *mdBlock, replacement *mdBlock) {
+  for i, c := range b.children {
+    if c == child {
+      b.children[i] = replacement
+      replacement.parent = b
+      return
+    }
+  }
+}
+
+func isMDWhitespace(c byte) bool {
+  return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f' || c == '\v'
+}
+
+func isMDSpaceOrTab(c byte) bool {
+  return c == ' ' || c == '\t'
+}
+
+func mdPeek(s string, i int) byte {
+  if i < len(s) {
+    return s[i]
+  }
+
+  return 0
+}
+
+func mdCanContain(parent mdBlockType, child mdBlockType) bool {
+  switch parent {
+  case MD_DOCUMENT, MD_BLOCKQUOTE, MD_ITEM:
+    return child != MD_ITEM
+  case MD_LIST:
+    return child == MD_ITEM
+  default:
+    return false
+  }
+}
+
+func mdAcceptsLines(t mdBlockType) bool {
+  return t == MD_PARAGRAPH || t == MD_CODE_BLOCK || t == MD_HTML_BLOCK || t == MD_TABLE
+}
+
+type mdBlockParser struct {
+  p *MarkdownParser
+
+  doc                  *mdBlock
+  tip                  *mdBlock
+  oldtip               *mdBlock
+  lastMatchedContainer *mdBlock
+  allClosed            bool
+
+  line       string
+  lineOffset int // byte offset of the line in the source
+  lineNumber int
+
+  offset               int
+  column               int
+  nextNonspace         int
+  nextNonspaceColumn   int
+  indent               int
+  indented             bool
+  blank                bool
+  partiallyConsumedTab bool
+}
+
+func newMDBlockParser(p *MarkdownParser) *mdBlockParser {
+  doc := &mdBlock{t: MD_DOCUMENT, open: true, children: make([]*mdBlock, 0)}
+
+  return &mdBlockParser{p: p, doc: doc, tip: doc, oldtip: doc, lastMatchedContainer: doc, allClosed: true}
+}
+
+func (bp *mdBlockParser) lineContext() context.Context {
+  return bp.p.newContext(bp.lineOffset, bp.lineOffset+len(bp.line))
+}
+
+func (bp *mdBlockParser) findNextNonspace() {
+  i := bp.offset
+  cols := bp.column
+
+  for i < len(bp.line) {
+    c := bp.line[i]
+    if c == ' ' {
+      i++
+      cols++
+    } else if c == '\t' {
+      i++
+      cols += 4 - (cols % 4)
+    } else {
+      break
+    }
+  }
+
+  bp.blank = i >= len(bp.line)
+  bp.nextNonspace = i
+  bp.nextNonspaceColumn = cols
+  bp.indent = bp.nextNonspaceColumn - bp.column
+  bp.indented = bp.indent >= MD_CODE_INDENT
+}
+
+func (bp *mdBlockParser) advanceOffset(count int, columns bool) {
+  for count > 0 && bp.offset < len(bp.line) {
+    if bp.line[bp.offset] == '\t' {
+      charsToTab := 4 - (bp.column % 4)
+      if columns {
+        bp.partiallyConsumedTab = charsToTab > count
+        charsToAdvance := charsToTab
+        if count < charsToAdvance {
+          charsToAdvance = count
+        }
+
+        bp.column += charsToAdvance
+        if !bp.partiallyConsumedTab {
+          bp.offset++
+        }
+
+        count -= charsToAdvance
+      } else {
+        bp.partiallyConsumedTab = false
+        bp.column += charsToTab
+        bp.offset++
+        count--
+      }
+    } else {
+      bp.partiallyConsumedTab = false
+      bp.offset++
+      bp.column++
+      count--
+    }
+  }
+}
+
+func (bp *mdBlockParser) advanceNextNonspace() {
+  bp.offset = bp.nextNonspace
+  bp.column = bp.nextNonspaceColumn
+  bp.partiallyConsumedTab = false
+}
+
+func (bp *mdBlockParser) addLine() {
+  prefix := ""
+  if bp.partiallyConsumedTab {
+    bp.offset++ // skip over the tab
+    prefix = strings.Repeat(" ", 4-(bp.column%4))
+  }
+
+  text := newMDText(prefix+bp.line[bp.offset:], bp.lineOffset+bp.offset-len(prefix))
+
+  if bp.tip.t == MD_TABLE {
+    bp.addTableRow(text)
+  } else {
+    bp.tip.lines = append(bp.tip.lines, text)
+  }
+}
+
+func (bp *mdBlockParser) addChild(t mdBlockType, offset int) *mdBlock {
+  for !mdCanContain(bp.tip.t, t) {
+    bp.finalize(bp.tip)
+  }
+
+  child := &mdBlock{
+    t: t,
+    parent: bp.tip,
+    open: true,
+    children: make([]*mdBlock, 0),
+    startLine: bp.lineNumber,
+    lines: make([]mdText, 0),
+    ctx: bp.p.newContext(bp.lineOffset+offset, bp.lineOffset+len(bp.line)),
+  }
+
+  bp.tip.children = append(bp.tip.children, child)
+  bp.tip = child
+
+  return child
+}
+
+func (bp *mdBlockParser) closeUnmatchedBlocks() {
+  if !bp.allClosed {
+    for bp.oldtip != bp.lastMatchedContainer {
+      parent := bp.oldtip.parent
+      bp.finalize(bp.oldtip)
+      bp.oldtip = parent
+    }
+
+    bp.allClosed = true
+  }
+}
+
+// 0: matched, 1: not matched, 2: line was consumed entirely (closing code fence)
+func (bp *mdBlockParser) continueBlock(container *mdBlock) int {
+  switch container.t {
+  case MD_LIST:
+    return 0
+  case MD_BLOCKQUOTE:
+    if !bp.indented && mdPeek(bp.line, bp.nextNonspace) == '>' {
+      bp.advanceNextNonspace()
+      bp.advanceOffset(1, false)
+      if isMDSpaceOrTab(mdPeek(bp.line, bp.offset)) {
+        bp.advanceOffset(1, true)
+      }
+
+      return 0
+    }
+
+    return 1
+  case MD_ITEM:
+    if bp.blank {
+      if len(container.children) == 0 {
+        // a blank line after an empty list item
+        return 1
+      }
+
+      bp.advanceNextNonspace()
+    } else if bp.indent >= container.list.markerOffset+container.list.padding {
+      bp.advanceOffset(container.list.markerOffset+container.list.padding, true)
+    } else {
+      return 1
+    }
+
+    return 0
+  case MD_CODE_BLOCK:
+    if container.fenced {
+      rest := bp.line[bp.nextNonspace:]
+      if bp.indent <= 3 && mdPeek(bp.line, bp.nextNonspace) == container.fenceChar {
+        if m := mdReClosingCodeFence.FindString(rest); m != "" && len(strings.TrimRight(m, " \t")) >= container.fenceLength {
+          bp.finalize(container)
+          return 2
+        }
+      }
+
+      // skip optional spaces of the fence offset
+      for i := container.fenceOffset; i > 0 && isMDSpaceOrTab(mdPeek(bp.line, bp.offset)); i-- {
+        bp.advanceOffset(1, true)
+      }
+    } else {
+      if bp.indent >= MD_CODE_INDENT {
+        bp.advanceOffset(MD_CODE_INDENT, true)
+      } else if bp.blank {
+        bp.advanceNextNonspace()
+      } else {
+        return 1
+      }
+    }
+
+    return 0
+  case MD_HTML_BLOCK:
+    if bp.blank && (container.htmlType == 6 || container.htmlType == 7) {
+      return 1
+    }
+
+    return 0
+  case MD_PARAGRAPH, MD_TABLE:
+    if bp.blank {
+      return 1
+    }
+
+    return 0
+  default:
+    // headings and thematic breaks can't contain more than one line
+    return 1
+  }
+}
+
+func (bp *mdBlockParser) finalize(block *mdBlock) {
+  above := block.parent
+  block.open = false
+
+  switch block.t {
+  case MD_PARAGRAPH:
+    content := bp.p.parseReferences(joinMDLines(block.lines))
+    if strings.TrimSpace(content.s) == "" {
+      above.removeChild(block)
+    } else {
+      block.content = content
+    }
+  case MD_HEADING:
+    if block.content.s == "" && len(block.lines) > 0 {
+      block.content = joinMDLines(block.lines)
+    }
+  case MD_CODE_BLOCK:
+    if block.fenced {
+      if len(block.lines) > 0 {
+        block.info = mdUnescapeString(strings.TrimSpace(block.lines[0].s))
+        block.lines = block.lines[1:]
+      }
+    } else {
+      // trailing blank lines aren't part of indented code
+      n := len(block.lines)
+      for n > 0 && strings.TrimSpace(block.lines[n-1].s) == "" {
+        n--
+      }
+
+      block.lines = block.lines[0:n]
+    }
+  case MD_LIST:
+    block.list.tight = true
+
+    for i, item := range block.children {
+      isLastItem := i == len(block.children)-1
+      if mdEndsWithBlankLine(item) && !isLastItem {
+        block.list.tight = false
+        break
+      }
+
+      for j, subItem := range item.children {
+        isLastSubItem := j == len(item.children)-1
+        if mdEndsWithBlankLine(subItem) && !(isLastItem && isLastSubItem) {
+          block.list.tight = false
+          break
+        }
+      }
+
+      if !block.list.tight {
+        break
+      }
+    }
+  }
+
+  bp.tip = above
+}
+
+func mdEndsWithBlankLine(block *mdBlock) bool {
+  for block != nil {
+    if block.lastLineBlank {
+      return true
+    }
+
+    if !block.lastLineChecked && (block.t == MD_LIST || block.t == MD_ITEM) {
+      block.lastLineChecked = true
+      block = block.lastChild()
+    } else {
+      block.lastLineChecked = true
+      break
+    }
+  }
+
+  return false
+}
+
+// GFM table rows, escaped pipes don't split cells
+func splitMDTableRow(row mdText) []mdText {
+  row = row.trimSpace()
+  if strings.HasPrefix(row.s, "|") {
+    row = row.slice(1, len(row.s))
+  }
+
+  if strings.HasSuffix(row.s, "|") && !strings.HasSuffix(row.s, "\\|") {
+    row = row.slice(0, len(row.s)-1)
+  }
+
+  cells := make([]mdText, 0)
+  start := 0
+  inCode := false
+  for i := 0; i < len(row.s); i++ {
+    switch row.s[i] {
+    case '\\':
+      i++
+    case '`':
+      inCode = !inCode
+    case '|':
+      if !inCode {
+        cells = append(cells, row.slice(start, i).trimSpace())
+        start = i + 1
+      }
+    }
+  }
+
+  cells = append(cells, row.slice(start, len(row.s)).trimSpace())
+
+  // escaped pipes inside cells become regular pipes
+  for i, cell := range cells {
+    for j := 0; j+1 < len(cell.s); j++ {
+      if cell.s[j] == '\\' && cell.s[j+1] == '|' {
+        cell = cell.slice(0, j).append(cell.slice(j+1, len(cell.s)))
+      }
+    }
+
+    cells[i] = cell
+  }
+
+  return cells
+}
+
+// returns nil if not a delimiter row
+func parseMDTableDelimiterRow(line string) []string {
+  if !strings.Contains(line, "|") {
+    return nil
+  }
+
+  aligns := make([]string, 0)
+  for _, cell := range splitMDTableRow(newMDText(line, 0)) {
+    s := strings.TrimSpace(cell.s)
+    if !mdReTableDelimiterCell.MatchString(s) {
+      return nil
+    }
+
+    left := strings.HasPrefix(s, ":")
+    right := strings.HasSuffix(s, ":")
+
+    switch {
+    case left && right:
+      aligns = append(aligns, "center")
+    case left:
+      aligns = append(aligns, "left")
+    case right:
+      aligns = append(aligns, "right")
+    default:
+      aligns = append(aligns, "")
+    }
+  }
+
+  return aligns
+}
+
+func (bp *mdBlockParser) addTableRow(text mdText) {
+  // the delimiter row itself
+  if strings.TrimSpace(text.s) == "" {
+    return
+  }
+
+  cells := splitMDTableRow(text)
+
+  // rows are padded or truncated to the number of header cells
+  n := len(bp.tip.aligns)
+  for len(cells) < n {
+    cells = append(cells, mdText{"", []int{}})
+  }
+
+  bp.tip.rows = append(bp.tip.rows, cells[0:n])
+}
+
+// 0: no match, 1: container block started, 2: leaf block started
+func (bp *mdBlockParser) startBlock(container *mdBlock) int {
+  rest := bp.line[bp.nextNonspace:]
+
+  // block quote
+  if !bp.indented && mdPeek(bp.line, bp.nextNonspace) == '>' {
+    bp.advanceNextNonspace()
+    bp.advanceOffset(1, false)
+    if isMDSpaceOrTab(mdPeek(bp.line, bp.offset)) {
+      bp.advanceOffset(1, true)
+    }
+
+    bp.closeUnmatchedBlocks()
+    bp.addChild(MD_BLOCKQUOTE, bp.nextNonspace)
+    return 1
+  }
+
+  // ATX heading
+  if !bp.indented {
+    if m := mdReATXHeadingMarker.FindString(rest); m != "" {
+      bp.advanceNextNonspace()
+      bp.advanceOffset(len(m), false)
+      bp.closeUnmatchedBlocks()
+
+      heading := bp.addChild(MD_HEADING, bp.nextNonspace)
+      heading.level = len(strings.TrimSpace(m))
+
+      content := newMDText(bp.line[bp.offset:], bp.lineOffset+bp.offset)
+      if mdReATXClosingEmpty.MatchString(content.s) {
+        content = content.slice(0, 0)
+      } else if loc := mdReATXClosing.FindStringIndex(content.s); loc != nil {
+        content = content.slice(0, loc[0])
+      }
+
+      heading.content = content.trimSpace()
+      bp.advanceOffset(len(bp.line)-bp.offset, false)
+      return 2
+    }
+  }
+
+  // fenced code block
+  if !bp.indented {
+    if m := mdReCodeFence.FindString(rest); m != "" && !(m[0] == '`' && strings.Contains(rest[len(m):], "`")) {
+      bp.closeUnmatchedBlocks()
+
+      code := bp.addChild(MD_CODE_BLOCK, bp.nextNonspace)
+      code.fenced = true
+      code.fenceLength = len(m)
+      code.fenceChar = m[0]
+      code.fenceOffset = bp.indent
+
+      bp.advanceNextNonspace()
+      bp.advanceOffset(len(m), false)
+      return 2
+    }
+  }
+
+  // html block
+  if !bp.indented && mdPeek(bp.line, bp.nextNonspace) == '<' {
+    for htmlType := 1; htmlType <= 7; htmlType++ {
+      if mdReHTMLBlockOpen[htmlType].MatchString(rest) && (htmlType < 7 || (container.t != MD_PARAGRAPH && !(!bp.allClosed && !bp.blank && bp.tip.t == MD_PARAGRAPH))) {
+        bp.closeUnmatchedBlocks()
+
+        // spaces are part of the html block
+        html := bp.addChild(MD_HTML_BLOCK, bp.offset)
+        html.htmlType = htmlType
+        return 2
+      }
+    }
+  }
+
+  // table, the header is the last line of the paragraph
+  if !bp.indented && container.t == MD_PARAGRAPH && len(container.lines) > 0 {
+    if aligns := parseMDTableDelimiterRow(rest); aligns != nil {
+      header := container.lines[len(container.lines)-1]
+      headerCells := splitMDTableRow(header)
+
+      if len(headerCells) == len(aligns) {
+        bp.closeUnmatchedBlocks()
+
+        container.lines = container.lines[0 : len(container.lines)-1]
+        parent := container.parent
+
+        table := &mdBlock{
+          t: MD_TABLE,
+          open: true,
+          children: make([]*mdBlock, 0),
+          startLine: bp.lineNumber - 1,
+          aligns: aligns,
+          rows: [][]mdText{headerCells},
+          ctx: bp.p.newContext(header.offsets0(), bp.lineOffset+len(bp.line)),
+        }
+
+        if len(container.lines) == 0 {
+          parent.replaceChild(container, table)
+        } else {
+          bp.finalize(container)
+          table.parent = parent
+          parent.children = append(parent.children, table)
+        }
+
+        bp.tip = table
+        bp.advanceOffset(len(bp.line)-bp.offset, false)
+        return 2
+      }
+    }
+  }
+
+  // setext heading
+  if !bp.indented && container.t == MD_PARAGRAPH && mdReSetextHeadingLine.MatchString(rest) {
+    bp.closeUnmatchedBlocks()
+
+    content := bp.p.parseReferences(joinMDLines(container.lines))
+    if strings.TrimSpace(content.s) != "" {
+      heading := &mdBlock{
+        t: MD_HEADING,
+        open: true,
+        children: make([]*mdBlock, 0),
+        startLine: container.startLine,
+        content: content.trimSpace(),
+        ctx: container.ctx,
+      }
+
+      if rest[0] == '=' {
+        heading.level = 1
+      } else {
+        heading.level = 2
+      }
+
+      container.parent.replaceChild(container, heading)
+      bp.tip = heading
+      bp.advanceOffset(len(bp.line)-bp.offset, false)
+      return 2
+    }
+  }
+
+  // thematic break
+  if !bp.indented && mdReThematicBreak.MatchString(rest) {
+    bp.closeUnmatchedBlocks()
+    bp.addChild(MD_THEMATIC_BREAK, bp.nextNonspace)
+    bp.advanceOffset(len(bp.line)-bp.offset, false)
+    return 2
+  }
+
+  // list item
+  if data, ok := bp.parseListMarker(container); ok {
+    bp.closeUnmatchedBlocks()
+
+    if bp.tip.t != MD_LIST || !mdListsMatch(container.list, data) {
+      list := bp.addChild(MD_LIST, bp.nextNonspace)
+      list.list = data
+    }
+
+    item := bp.addChild(MD_ITEM, bp.nextNonspace)
+    item.list = data
+    return 1
+  }
+
+  // indented code block
+  if bp.indented && bp.tip.t != MD_PARAGRAPH && !bp.blank {
+    bp.advanceOffset(MD_CODE_INDENT, true)
+    bp.closeUnmatchedBlocks()
+    bp.addChild(MD_CODE_BLOCK, bp.offset)
+    return 2
+  }
+
+  return 0
+}
+
+func mdListsMatch(a mdListData, b mdListData) bool {
+  return a.ordered == b.ordered && a.delimiter == b.delimiter && a.bulletChar == b.bulletChar
+}
+
+func (bp *mdBlockParser) parseListMarker(container *mdBlock) (mdListData, bool) {
+  data := mdListData{}
+
+  if bp.indent >= MD_CODE_INDENT {
+    return data, false
+  }
+
+  rest := bp.line[bp.nextNonspace:]
+  markerLength := 0
+
+  if m := mdReBulletListMarker.FindString(rest); m != "" {
+    data.bulletChar = m[0]
+    markerLength = len(m)
+  } else if m := mdReOrderedListMarker.FindStringSubmatch(rest); m != nil && (container.t != MD_PARAGRAPH || m[1] == "1") {
+    data.ordered = true
+    data.start = 0
+    for _, c := range m[1] {
+      data.start = data.start*10 + int(c-'0')
+    }
+    data.delimiter = m[2][0]
+    markerLength = len(m[0])
+  } else {
+    return data, false
+  }
+
+  // the marker must be followed by whitespace
+  nextc := mdPeek(bp.line, bp.nextNonspace+markerLength)
+  if !(nextc == 0 || isMDSpaceOrTab(nextc)) {
+    return data, false
+  }
+
+  // an empty list item can't interrupt a paragraph
+  if container.t == MD_PARAGRAPH && strings.TrimSpace(bp.line[bp.nextNonspace+markerLength:]) == "" {
+    return data, false
+  }
+
+  bp.advanceNextNonspace()
+  bp.advanceOffset(markerLength, true)
+
+  spacesStartCol := bp.column
+  spacesStartOffset := bp.offset
+
+  for {
+    bp.advanceOffset(1, true)
+    nextc = mdPeek(bp.line, bp.offset)
+    if !(bp.column-spacesStartCol < 5 && isMDSpaceOrTab(nextc)) {
+      break
+    }
+  }
+
+  blankItem := bp.offset >= len(bp.line)
+  spacesAfterMarker := bp.column - spacesStartCol
+
+  if spacesAfterMarker >= 5 || spacesAfterMarker < 1 || blankItem {
+    data.padding = markerLength + 1
+    bp.column = spacesStartCol
+    bp.offset = spacesStartOffset
+    if isMDSpaceOrTab(mdPeek(bp.line, bp.offset)) {
+      bp.advanceOffset(1, true)
+    }
+  } else {
+    data.padding = markerLength + spacesAfterMarker
+  }
+
+  data.markerOffset = bp.indent
+
+  return data, true
+}
+
+func (bp *mdBlockParser) incorporateLine(line string, lineOffset int) {
+  container := bp.doc
+  bp.oldtip = bp.tip
+  bp.offset = 0
+  bp.column = 0
+  bp.blank = false
+  bp.partiallyConsumedTab = false
+  bp.lineNumber++
+  bp.line = line
+  bp.lineOffset = lineOffset
+
+  // try to match the open blocks
+  for {
+    lastChild := container.lastChild()
+    if lastChild == nil || !lastChild.open {
+      break
+    }
+
+    container = lastChild
+
+    bp.findNextNonspace()
+
+    res := bp.continueBlock(container)
+    if res == 1 {
+      container = container.parent
+      break
+    } else if res == 2 {
+      return
+    }
+  }
+
+  bp.allClosed = container == bp.oldtip
+  bp.lastMatchedContainer = container
+
+  matchedLeaf := container.t != MD_PARAGRAPH && container.t != MD_TABLE && mdAcceptsLines(container.t)
+
+  // try new block starts
+  for !matchedLeaf {
+    bp.findNextNonspace()
+
+    if !bp.indented && !mdReMaybeSpecial.MatchString(bp.line[bp.nextNonspace:]) {
+      bp.advanceNextNonspace()
+      break
+    }
+
+    res := bp.startBlock(container)
+    if res == 0 {
+      bp.advanceNextNonspace()
+      break
+    }
+
+    container = bp.tip
+    if res == 2 {
+      matchedLeaf = true
+    }
+  }
+
+  if !bp.allClosed && !bp.blank && bp.tip.t == MD_PARAGRAPH {
+    // lazy paragraph continuation
+    bp.addLine()
+    return
+  }
+
+  bp.closeUnmatchedBlocks()
+
+  if bp.blank && container.lastChild() != nil {
+    container.lastChild().lastLineBlank = true
+  }
+
+  t := container.t
+
+  lastLineBlank := bp.blank &&
+    !(t == MD_BLOCKQUOTE ||
+      (t == MD_CODE_BLOCK && container.fenced) ||
+      (t == MD_ITEM && len(container.children) == 0 && container.startLine == bp.lineNumber))
+
+  for cont := container; cont != nil; cont = cont.parent {
+    cont.lastLineBlank = lastLineBlank
+  }
+
+  if mdAcceptsLines(t) {
+    bp.addLine()
+
+    if t == MD_HTML_BLOCK && container.htmlType >= 1 && container.htmlType <= 5 &&
+      mdReHTMLBlockClose[container.htmlType].MatchString(bp.line[bp.offset:]) {
+      bp.finalize(container)
+    }
+  } else if bp.offset < len(bp.line) && !bp.blank {
+    bp.addChild(MD_PARAGRAPH, bp.offset)
+    bp.advanceNextNonspace()
+    bp.addLine()
+  }
+}
+
+// offset is the byte offset of src in the source file (after the front matter)
+func (bp *mdBlockParser) parse(src string, offset int) *mdBlock {
+  for len(src) > 0 {
+    n := strings.IndexAny(src, "\r\n")
+    line := src
+    next := len(src)
+    if n != -1 {
+      line = src[0:n]
+      next = n + 1
+      if src[n] == '\r' && n+1 < len(src) && src[n+1] == '\n' {
+        next = n + 2
+      }
+    }
+
+    bp.incorporateLine(line, offset)
+
+    src = src[next:]
+    offset += next
+  }
+
+  for bp.tip != nil {
+    bp.finalize(bp.tip)
+  }
+
+  return bp.doc
+}
@@ -0,0 +1,922 @@
+package parsers
+
+import (
+  "regexp"
+  "strings"
+  "unicode"
+  "unicode/utf8"
+
+  "github.com/computeportal/wtsuite/pkg/tokens/context"
+)
+
+// inline parsing follows the CommonMark reference implementation (commonmark.js)
+
+type mdInlineType int
+
+const (
+  MD_TEXT mdInlineType = iota
+  MD_SOFTBREAK
+  MD_HARDBREAK
+  MD_CODE
+  MD_MATH
+  MD_HTML_INLINE
+  MD_ENTITY
+  MD_EMPH
+  MD_STRONG
+  MD_LINK
+  MD_IMAGE
+  MD_CONTAINER // root of the inlines of a block
+)
+
+const (
+  MD_TAG_NAME = `[A-Za-z][A-Za-z0-9-]*`
+  MD_ATTRIBUTE = `(?:\s+[a-zA-Z_:][a-zA-Z0-9:._-]*(?:\s*=\s*(?:[^"'=<>` + "`" + `\x00-\x20]+|'[^']*'|"[^"]*"))?)`
+  MD_OPEN_TAG = `<` + MD_TAG_NAME + MD_ATTRIBUTE + `*\s*/?>`
+  MD_CLOSE_TAG = `</` + MD_TAG_NAME + `\s*[>]`
+  MD_HTML_COMMENT = `<!-->|<!--->|<!--[\s\S]*?-->`
+  MD_PROCESSING_INSTRUCTION = `[<][?][\s\S]*?[?][>]`
+  MD_DECLARATION = `<![A-Za-z]+[^>]*>`
+  MD_CDATA = `<!\[CDATA\[[\s\S]*?\]\]>`
+)
+
+var (
+  mdReHTMLTag = regexp.MustCompile(`^(?:` + MD_OPEN_TAG + `|` + MD_CLOSE_TAG + `|` + MD_HTML_COMMENT + `|` +
+    MD_PROCESSING_INSTRUCTION + `|` + MD_DECLARATION + `|` + MD_CDATA + `)`)
+  mdReEntity = regexp.MustCompile(`(?i)^&(?:#x[a-f0-9]{1,6}|#[0-9]{1,7}|[a-z][a-z0-9]{1,31});`)
+  mdReEmailAutolink = regexp.MustCompile(`^<([a-zA-Z0-9.!#$%&'*+/=?^_` + "`" + `{|}~-]+@[a-zA-Z0-9](?:[a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?(?:\.[a-zA-Z0-9](?:[a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?)*)>`)
+  mdReAutolink = regexp.MustCompile(`^<[A-Za-z][A-Za-z0-9.+-]{1,31}:[^<>\x00-\x20]*>`)
+  mdReLinkTitle = regexp.MustCompile(`^(?:"(?:\\[\s\S]|[^\\"])*"|'(?:\\[\s\S]|[^\\'])*'|\((?:\\[\s\S]|[^\\()])*\))`)
+  mdReLinkDestinationBraces = regexp.MustCompile(`^<(?:[^<>\n\\]|\\.)*>`)
+  mdReLinkLabel = regexp.MustCompile(`^\[(?:[^\\\[\]]|\\.){0,999}\]`)
+  mdReSpnl = regexp.MustCompile(`^ *(?:\n *)?`)
+  mdReWhitespace = regexp.MustCompile(`[ \t\n\r\f\v]+`)
+  mdReFinalSpace = regexp.MustCompile(` *$`)
+  mdReInitialSpace = regexp.MustCompile(`^ *`)
+  mdReTicks = regexp.MustCompile("^`+")
+  mdReMain = regexp.MustCompile("^[^\n`\\[\\]\\\\!<&*_$]+")
+)
+
+type mdInline struct {
+  t          mdInlineType
+  literal    string
+  dest       string
+  title      string
+  start      int // offsets in the subject
+  stop       int
+  parent     *mdInline
+  prev       *mdInline
+  next       *mdInline
+  firstChild *mdInline
+  lastChild  *mdInline
+}
+
+func (n *mdInline) appendChild(child *mdInline) {
+  child.unlink()
+  child.parent = n
+  if n.lastChild != nil {
+    n.lastChild.next = child
+    child.prev = n.lastChild
+    n.lastChild = child
+  } else {
+    n.firstChild = child
+    n.lastChild = child
+  }
+}
+
+func (n *mdInline) unlink() {
+  if n.prev != nil {
+    n.prev.next = n.next
+  } else if n.parent != nil {
+    n.parent.firstChild = n.next
+  }
+
+  if n.next != nil {
+    n.next.prev = n.prev
+  } else if n.parent != nil {
+    n.parent.lastChild = n.prev
+  }
+
+  n.parent = nil
+  n.next = nil
+  n.prev = nil
+}
+
+func (n *mdInline) insertAfter(sibling *mdInline) {
+  sibling.unlink()
+  sibling.next = n.next
+  if sibling.next != nil {
+    sibling.next.prev = sibling
+  }
+
+  sibling.prev = n
+  n.next = sibling
+  sibling.parent = n.parent
+  if sibling.next == nil && sibling.parent != nil {
+    sibling.parent.lastChild = sibling
+  }
+}
+
+type mdDelimiter struct {
+  cc         byte
+  numdelims  int
+  origdelims int
+  node       *mdInline
+  prev       *mdDelimiter
+  next       *mdDelimiter
+  canOpen    bool
+  canClose   bool
+}
+
+type mdBracket struct {
+  node              *mdInline
+  prev              *mdBracket
+  previousDelimiter *mdDelimiter
+  index             int
+  image             bool
+  active            bool
+  bracketAfter      bool
+}
+
+type mdLinkRef struct {
+  dest  string
+  title string
+}
+
+type mdInlineParser struct {
+  p          *MarkdownParser
+  subject    mdText
+  pos        int
+  delimiters *mdDelimiter
+  brackets   *mdBracket
+}
+
+func newMDInlineParser(p *MarkdownParser, subject mdText) *mdInlineParser {
+  return &mdInlineParser{p, subject, 0, nil, nil}
+}
+
+// context of a range of the subject
+func (ip *mdInlineParser) context(start, stop int) context.Context {
+  if start >= len(ip.subject.offsets) {
+    end := ip.subject.endOffset()
+    return ip.p.newContext(end, end)
+  }
+
+  if stop <= start {
+    stop = start + 1
+  }
+
+  return ip.p.newContext(ip.subject.offsets[start], ip.subject.offsets[stop-1]+1)
+}
+
+func (ip *mdInlineParser) peek() byte {
+  return mdPeek(ip.subject.s, ip.pos)
+}
+
+func (ip *mdInlineParser) match(re *regexp.Regexp) string {
+  m := re.FindString(ip.subject.s[ip.pos:])
+  ip.pos += len(m)
+  return m
+}
+
+// skip spaces and at most one newline
+func (ip *mdInlineParser) spnl() bool {
+  ip.match(mdReSpnl)
+  return true
+}
+
+func newMDTextNode(s string, start, stop int) *mdInline {
+  return &mdInline{t: MD_TEXT, literal: s, start: start, stop: stop}
+}
+
+func isMDASCIIPunctuation(c byte) bool {
+  return strings.IndexByte("!\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~", c) != -1
+}
+
+// backslash escapes are removed
+func mdUnescapeString(s string) string {
+  var b strings.Builder
+
+  for i := 0; i < len(s); i++ {
+    if s[i] == '\\' && i+1 < len(s) && isMDASCIIPunctuation(s[i+1]) {
+      i++
+    }
+
+    b.WriteByte(s[i])
+  }
+
+  return b.String()
+}
+
+// case fold and collapse whitespace
+func normalizeMDReference(s string) string {
+  s = strings.TrimSpace(s[1 : len(s)-1])
+  s = mdReWhitespace.ReplaceAllString(s, " ")
+  return strings.ToUpper(strings.ToLower(s))
+}
+
+func (ip *mdInlineParser) parseBackticks(block *mdInline) bool {
+  start := ip.pos
+  ticks := ip.match(mdReTicks)
+  afterOpenTicks := ip.pos
+
+  for {
+    loc := strings.Index(ip.subject.s[ip.pos:], "`")
+    if loc == -1 {
+      break
+    }
+
+    ip.pos += loc
+    closing := ip.match(mdReTicks)
+    if closing == ticks {
+      contents := strings.ReplaceAll(ip.subject.s[afterOpenTicks:ip.pos-len(ticks)], "\n", " ")
+      if len(contents) > 0 && strings.Trim(contents, " ") != "" && contents[0] == ' ' && contents[len(contents)-1] == ' ' {
+        contents = contents[1 : len(contents)-1]
+      }
+
+      node := &mdInline{t: MD_CODE, literal: contents, start: start, stop: ip.pos}
+
+      // $`...`$ is math
+      if start > 0 && ip.subject.s[start-1] == '$' && ip.peek() == '$' && block.lastChild != nil && block.lastChild.t == MD_TEXT &&
+        strings.HasSuffix(block.lastChild.literal, "$") && len(ticks) == 1 {
+        prev := block.lastChild
+        prev.literal = prev.literal[0 : len(prev.literal)-1]
+        prev.stop--
+        if prev.literal == "" {
+          prev.unlink()
+        }
+
+        node.t = MD_MATH
+        ip.pos++
+      }
+
+      block.appendChild(node)
+      return true
+    }
+  }
+
+  // no matching backtick string
+  ip.pos = afterOpenTicks
+  block.appendChild(newMDTextNode(ticks, start, afterOpenTicks))
+  return true
+}
+
+func (ip *mdInlineParser) parseBackslash(block *mdInline) bool {
+  start := ip.pos
+  ip.pos++
+
+  c := ip.peek()
+  if c == '\n' {
+    ip.pos++
+    block.appendChild(&mdInline{t: MD_HARDBREAK, start: start, stop: ip.pos})
+  } else if c != 0 && isMDASCIIPunctuation(c) {
+    ip.pos++
+    block.appendChild(newMDTextNode(string(c), start, ip.pos))
+  } else {
+    block.appendChild(newMDTextNode("\\", start, ip.pos))
+  }
+
+  return true
+}
+
+func (ip *mdInlineParser) parseAutolink(block *mdInline) bool {
+  start := ip.pos
+
+  if m := ip.match(mdReEmailAutolink); m != "" {
+    dest := m[1 : len(m)-1]
+    node := &mdInline{t: MD_LINK, dest: "mailto:" + dest, start: start, stop: ip.pos}
+    node.appendChild(newMDTextNode(dest, start+1, ip.pos-1))
+    block.appendChild(node)
+    return true
+  } else if m := ip.match(mdReAutolink); m != "" {
+    dest := m[1 : len(m)-1]
+    node := &mdInline{t: MD_LINK, dest: dest, start: start, stop: ip.pos}
+    node.appendChild(newMDTextNode(dest, start+1, ip.pos-1))
+    block.appendChild(node)
+    return true
+  }
+
+  return false
+}
+
+func (ip *mdInlineParser) parseHTMLTag(block *mdInline) bool {
+  start := ip.pos
+
+  if m := ip.match(mdReHTMLTag); m != "" {
+    block.appendChild(&mdInline{t: MD_HTML_INLINE, literal: m, start: start, stop: ip.pos})
+    return true
+  }
+
+  return false
+}
+
+func mdRuneBefore(s string, pos int) rune {
+  if pos == 0 {
+    return '\n'
+  }
+
+  r, _ := utf8.DecodeLastRuneInString(s[0:pos])
+  return r
+}
+
+func mdRuneAfter(s string, pos int) rune {
+  if pos >= len(s) {
+    return '\n'
+  }
+
+  r, _ := utf8.DecodeRuneInString(s[pos:])
+  return r
+}
+
+func isMDPunctuation(r rune) bool {
+  return unicode.IsPunct(r) || unicode.IsSymbol(r)
+}
+
+func (ip *mdInlineParser) scanDelims(c byte) (int, bool, bool) {
+  startPos := ip.pos
+
+  numdelims := 0
+  for ip.peek() == c {
+    numdelims++
+    ip.pos++
+  }
+
+  if numdelims == 0 {
+    return 0, false, false
+  }
+
+  charBefore := mdRuneBefore(ip.subject.s, startPos)
+  charAfter := mdRuneAfter(ip.subject.s, ip.pos)
+
+  afterIsWhitespace := unicode.IsSpace(charAfter)
+  afterIsPunctuation := isMDPunctuation(charAfter)
+  beforeIsWhitespace := unicode.IsSpace(charBefore)
+  beforeIsPunctuation := isMDPunctuation(charBefore)
+
+  leftFlanking := !afterIsWhitespace && (!afterIsPunctuation || beforeIsWhitespace || beforeIsPunctuation)
+  rightFlanking := !beforeIsWhitespace && (!beforeIsPunctuation || afterIsWhitespace || afterIsPunctuation)
+
+  canOpen := leftFlanking
+  canClose := rightFlanking
+  if c == '_' {
+    canOpen = leftFlanking && (!rightFlanking || beforeIsPunctuation)
+    canClose = rightFlanking && (!leftFlanking || afterIsPunctuation)
+  }
+
+  ip.pos = startPos
+  return numdelims, canOpen, canClose
+}
+
+func (ip *mdInlineParser) handleDelim(c byte, block *mdInline) bool {
+  numdelims, canOpen, canClose := ip.scanDelims(c)
+  if numdelims == 0 {
+    return false
+  }
+
+  start := ip.pos
+  ip.pos += numdelims
+
+  node := newMDTextNode(ip.subject.s[start:ip.pos], start, ip.pos)
+  block.appendChild(node)
+
+  ip.delimiters = &mdDelimiter{
+    cc: c,
+    numdelims: numdelims,
+    origdelims: numdelims,
+    node: node,
+    prev: ip.delimiters,
+    canOpen: canOpen,
+    canClose: canClose,
+  }
+
+  if ip.delimiters.prev != nil {
+    ip.delimiters.prev.next = ip.delimiters
+  }
+
+  return true
+}
+
+func (ip *mdInlineParser) removeDelimiter(d *mdDelimiter) {
+  if d.prev != nil {
+    d.prev.next = d.next
+  }
+
+  if d.next == nil {
+    // top of stack
+    ip.delimiters = d.prev
+  } else {
+    d.next.prev = d.prev
+  }
+}
+
+func removeMDDelimitersBetween(bottom *mdDelimiter, top *mdDelimiter) {
+  if bottom.next != top {
+    bottom.next = top
+    top.prev = bottom
+  }
+}
+
+func (ip *mdInlineParser) processEmphasis(stackBottom *mdDelimiter) {
+  type bottomKey struct {
+    cc  byte
+    idx int
+  }
+
+  openersBottom := make(map[bottomKey]*mdDelimiter)
+
+  // find first closer above stackBottom
+  closer := ip.delimiters
+  for closer != nil && closer.prev != stackBottom {
+    closer = closer.prev
+  }
+
+  for closer != nil {
+    if !closer.canClose {
+      closer = closer.next
+      continue
+    }
+
+    key := bottomKey{closer.cc, closer.origdelims % 3}
+    if closer.canOpen {
+      key.idx += 3
+    }
+
+    // look back for the first matching opener
+    opener := closer.prev
+    openerFound := false
+    for opener != nil && opener != stackBottom && opener != openersBottom[key] {
+      oddMatch := (closer.canOpen || opener.canClose) && closer.origdelims%3 != 0 &&
+        (opener.origdelims+closer.origdelims)%3 == 0
+      if opener.cc == closer.cc && opener.canOpen && !oddMatch {
+        openerFound = true
+        break
+      }
+
+      opener = opener.prev
+    }
+
+    oldCloser := closer
+
+    if !openerFound {
+      closer = closer.next
+    } else {
+      useDelims := 1
+      if closer.numdelims >= 2 && opener.numdelims >= 2 {
+        useDelims = 2
+      }
+
+      openerInl := opener.node
+      closerInl := closer.node
+
+      opener.numdelims -= useDelims
+      closer.numdelims -= useDelims
+      openerInl.literal = openerInl.literal[0 : len(openerInl.literal)-useDelims]
+      closerInl.literal = closerInl.literal[0 : len(closerInl.literal)-useDelims]
+
+      t := MD_EMPH
+      if useDelims == 2 {
+        t = MD_STRONG
+      }
+
+      emph := &mdInline{t: t, start: openerInl.stop - useDelims, stop: closerInl.start + useDelims}
+
+      tmp := openerInl.next
+      for tmp != nil && tmp != closerInl {
+        next := tmp.next
+        emph.appendChild(tmp)
+        tmp = next
+      }
+
+      openerInl.insertAfter(emph)
+
+      removeMDDelimitersBetween(opener, closer)
+
+      if opener.numdelims == 0 {
+        openerInl.unlink()
+        ip.removeDelimiter(opener)
+      }
+
+      if closer.numdelims == 0 {
+        closerInl.unlink()
+        next := closer.next
+        ip.removeDelimiter(closer)
+        closer = next
+      }
+    }
+
+    if !openerFound {
+      // set lower bound for future searches for openers
+      openersBottom[key] = oldCloser.prev
+      if !oldCloser.canOpen {
+        ip.removeDelimiter(oldCloser)
+      }
+    }
+  }
+
+  for ip.delimiters != nil && ip.delimiters != stackBottom {
+    ip.removeDelimiter(ip.delimiters)
+  }
+}
+
+func (ip *mdInlineParser) addBracket(node *mdInline, index int, image bool) {
+  if ip.brackets != nil {
+    ip.brackets.bracketAfter = true
+  }
+
+  ip.brackets = &mdBracket{
+    node: node,
+    prev: ip.brackets,
+    previousDelimiter: ip.delimiters,
+    index: index,
+    image: image,
+    active: true,
+  }
+}
+
+func (ip *mdInlineParser) removeBracket() {
+  ip.brackets = ip.brackets.prev
+}
+
+func (ip *mdInlineParser) parseOpenBracket(block *mdInline) bool {
+  start := ip.pos
+  ip.pos++
+
+  node := newMDTextNode("[", start, ip.pos)
+  block.appendChild(node)
+
+  ip.addBracket(node, ip.pos, false)
+  return true
+}
+
+func (ip *mdInlineParser) parseBang(block *mdInline) bool {
+  start := ip.pos
+  ip.pos++
+
+  if ip.peek() == '[' {
+    ip.pos++
+
+    node := newMDTextNode("![", start, ip.pos)
+    block.appendChild(node)
+
+    ip.addBracket(node, ip.pos, true)
+  } else {
+    block.appendChild(newMDTextNode("!", start, ip.pos))
+  }
+
+  return true
+}
+
+// returns false if no valid destination was found
+func (ip *mdInlineParser) parseLinkDestination() (string, bool) {
+  if m := ip.match(mdReLinkDestinationBraces); m != "" {
+    return mdUnescapeString(m[1 : len(m)-1]), true
+  }
+
+  if ip.peek() == '<' {
+    return "", false
+  }
+
+  // raw destination, balanced parentheses
+  savePos := ip.pos
+  openParens := 0
+  for {
+    c := ip.peek()
+    if c == 0 {
+      break
+    }
+
+    if c == '\\' && ip.pos+1 < len(ip.subject.s) && isMDASCIIPunctuation(ip.subject.s[ip.pos+1]) {
+      ip.pos += 2
+    } else if c == '(' {
+      ip.pos++
+      openParens++
+    } else if c == ')' {
+      if openParens < 1 {
+        break
+      }
+
+      ip.pos++
+      openParens--
+    } else if c <= 0x20 || c == 0x7f {
+      break
+    } else {
+      ip.pos++
+    }
+  }
+
+  if ip.pos == savePos && ip.peek() != ')' {
+    return "", false
+  }
+
+  if openParens != 0 {
+    return "", false
+  }
+
+  return mdUnescapeString(ip.subject.s[savePos:ip.pos]), true
+}
+
+func (ip *mdInlineParser) parseLinkTitle() (string, bool) {
+  if m := ip.match(mdReLinkTitle); m != "" {
+    return mdUnescapeString(m[1 : len(m)-1]), true
+  }
+
+  return "", false
+}
+
+// returns the length of the label, 0 if not found
+func (ip *mdInlineParser) parseLinkLabel() int {
+  m := mdReLinkLabel.FindString(ip.subject.s[ip.pos:])
+  if m == "" || len(m) > 1001 {
+    return 0
+  }
+
+  ip.pos += len(m)
+  return len(m)
+}
+
+func (ip *mdInlineParser) parseCloseBracket(block *mdInline) bool {
+  startPos := ip.pos
+  ip.pos++
+
+  opener := ip.brackets
+  if opener == nil {
+    block.appendChild(newMDTextNode("]", startPos, ip.pos))
+    return true
+  }
+
+  if !opener.active {
+    block.appendChild(newMDTextNode("]", startPos, ip.pos))
+    ip.removeBracket()
+    return true
+  }
+
+  isImage := opener.image
+  savePos := ip.pos
+
+  dest := ""
+  title := ""
+  matched := false
+
+  // inline link
+  if ip.peek() == '(' {
+    ip.pos++
+    ip.spnl()
+    if d, ok := ip.parseLinkDestination(); ok {
+      dest = d
+      beforeTitle := ip.pos
+      ip.spnl()
+      if ip.pos > beforeTitle && isMDWhitespace(ip.subject.s[ip.pos-1]) {
+        if t, ok := ip.parseLinkTitle(); ok {
+          title = t
+        }
+      }
+
+      ip.spnl()
+      if ip.peek() == ')' {
+        ip.pos++
+        matched = true
+      }
+    }
+
+    if !matched {
+      ip.pos = savePos
+    }
+  }
+
+  // reference link
+  if !matched {
+    beforeLabel := ip.pos
+    n := ip.parseLinkLabel()
+
+    reflabel := ""
+    if n > 2 {
+      reflabel = ip.subject.s[beforeLabel : beforeLabel+n]
+    } else if !opener.bracketAfter {
+      // empty or missing second label, the first label is the reference
+      reflabel = "[" + ip.subject.s[opener.index:startPos] + "]"
+    }
+
+    if n == 0 {
+      ip.pos = savePos
+    }
+
+    if reflabel != "" {
+      if ref, ok := ip.p.refs[normalizeMDReference(reflabel)]; ok {
+        dest = ref.dest
+        title = ref.title
+        matched = true
+      }
+    }
+  }
+
+  if !matched {
+    ip.removeBracket()
+    ip.pos = startPos + 1
+    block.appendChild(newMDTextNode("]", startPos, ip.pos))
+    return true
+  }
+
+  t := MD_LINK
+  if isImage {
+    t = MD_IMAGE
+  }
+
+  node := &mdInline{t: t, dest: dest, title: title, start: opener.node.start, stop: ip.pos}
+
+  tmp := opener.node.next
+  for tmp != nil {
+    next := tmp.next
+    node.appendChild(tmp)
+    tmp = next
+  }
+
+  block.appendChild(node)
+  ip.processEmphasis(opener.previousDelimiter)
+  ip.removeBracket()
+  opener.node.unlink()
+
+  // links can't contain other links
+  if !isImage {
+    for o := ip.brackets; o != nil; o = o.prev {
+      if !o.image {
+        o.active = false
+      }
+    }
+  }
+
+  return true
+}
+
+func (ip *mdInlineParser) parseEntity(block *mdInline) bool {
+  start := ip.pos
+
+  if m := ip.match(mdReEntity); m != "" {
+    block.appendChild(&mdInline{t: MD_ENTITY, literal: m, start: start, stop: ip.pos})
+    return true
+  }
+
+  return false
+}
+
+func (ip *mdInlineParser) parseString(block *mdInline) bool {
+  start := ip.pos
+
+  if m := ip.match(mdReMain); m != "" {
+    block.appendChild(newMDTextNode(m, start, ip.pos))
+    return true
+  }
+
+  return false
+}
+
+func (ip *mdInlineParser) parseNewline(block *mdInline) bool {
+  start := ip.pos
+  ip.pos++
+
+  lastc := block.lastChild
+  if lastc != nil && lastc.t == MD_TEXT && strings.HasSuffix(lastc.literal, " ") {
+    hardbreak := strings.HasSuffix(lastc.literal, "  ")
+    lastc.literal = mdReFinalSpace.ReplaceAllString(lastc.literal, "")
+    if hardbreak {
+      block.appendChild(&mdInline{t: MD_HARDBREAK, start: start, stop: ip.pos})
+    } else {
+      block.appendChild(&mdInline{t: MD_SOFTBREAK, start: start, stop: ip.pos})
+    }
+  } else {
+    block.appendChild(&mdInline{t: MD_SOFTBREAK, start: start, stop: ip.pos})
+  }
+
+  // gobble leading spaces of the next line
+  ip.match(mdReInitialSpace)
+  return true
+}
+
+func (ip *mdInlineParser) parseInline(block *mdInline) {
+  c := ip.peek()
+
+  res := false
+  switch c {
+  case '\n':
+    res = ip.parseNewline(block)
+  case '\\':
+    res = ip.parseBackslash(block)
+  case '`':
+    res = ip.parseBackticks(block)
+  case '*', '_':
+    res = ip.handleDelim(c, block)
+  case '[':
+    res = ip.parseOpenBracket(block)
+  case '!':
+    res = ip.parseBang(block)
+  case ']':
+    res = ip.parseCloseBracket(block)
+  case '<':
+    res = ip.parseAutolink(block) || ip.parseHTMLTag(block)
+  case '&':
+    res = ip.parseEntity(block)
+  default:
+    res = ip.parseString(block)
+  }
+
+  if !res {
+    start := ip.pos
+    _, n := utf8.DecodeRuneInString(ip.subject.s[ip.pos:])
+    ip.pos += n
+    block.appendChild(newMDTextNode(ip.subject.s[start:ip.pos], start, ip.pos))
+  }
+}
+
+func (ip *mdInlineParser) parse() *mdInline {
+  root := &mdInline{t: MD_CONTAINER, start: 0, stop: len(ip.subject.s)}
+
+  for ip.pos < len(ip.subject.s) {
+    ip.parseInline(root)
+  }
+
+  ip.processEmphasis(nil)
+
+  return root
+}
+
+// link reference definitions at the start of a paragraph are removed and stored in the parser
+func (p *MarkdownParser) parseReferences(content mdText) mdText {
+  for {
+    ip := newMDInlineParser(p, content)
+    if !ip.parseReference() {
+      break
+    }
+
+    content = content.slice(ip.pos, len(content.s))
+  }
+
+  return content
+}
+
+func (ip *mdInlineParser) parseReference() bool {
+  n := ip.parseLinkLabel()
+  if n <= 2 || ip.peek() != ':' {
+    return false
+  }
+
+  rawLabel := ip.subject.s[0:n]
+  ip.pos++
+
+  ip.spnl()
+  dest, ok := ip.parseLinkDestination()
+  if !ok {
+    return false
+  }
+
+  beforeTitle := ip.pos
+  ip.spnl()
+
+  title := ""
+  if ip.pos != beforeTitle {
+    if title, ok = ip.parseLinkTitle(); !ok {
+      ip.pos = beforeTitle
+    }
+  }
+
+  // the title must be followed by the end of the line
+  atLineEnd := func() bool {
+    rest := ip.subject.s[ip.pos:]
+    i := strings.IndexByte(rest, '\n')
+    if i == -1 {
+      i = len(rest)
+    }
+
+    if strings.TrimLeft(rest[0:i], " \t") == "" {
+      ip.pos += i
+      if ip.pos < len(ip.subject.s) {
+        ip.pos++
+      }
+      return true
+    }
+
+    return false
+  }
+
+  if !atLineEnd() {
+    if title == "" {
+      return false
+    }
+
+    // title must be on its own line then
+    title = ""
+    ip.pos = beforeTitle
+    if !atLineEnd() {
+      return false
+    }
+  }
+
+  normLabel := normalizeMDReference(rawLabel)
+  if normLabel == "" {
+    return false
+  }
+
+  // the first definition wins
+  if _, ok := ip.p.refs[normLabel]; !ok {
+    ip.p.refs[normLabel] = mdLinkRef{dest, title}
+  }
+
+  return true
+}
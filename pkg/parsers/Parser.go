@@ -779,7 +779,8 @@ func (p *Parser) nestOperatorLeftToRight(ts []tokens.Token, osm *operatorSetting
 
 func (p *Parser) isNestableToken(i int, t tokens.Token) bool {
 	if tokens.IsAnySymbol(t) { // some symbols can also be words, so check before IsAnyWord
-		if p.settings.tmpGroupPeriods && tokens.IsSymbol(t, patterns.PERIOD) {
+		if p.settings.tmpGroupPeriods && (tokens.IsSymbol(t, patterns.PERIOD) ||
+			tokens.IsSymbol(t, patterns.QUESTION_PERIOD)) {
 			return false
 		}
 
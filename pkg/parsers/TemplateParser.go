@@ -557,6 +557,27 @@ func (p *TemplateParser) buildVarDirective(ts []raw.Token) (*html.Tag, []raw.Tok
 
   ts, _ = p.eatWhitespace(ts[2:])
 
+  // optional type annotation
+  var typ *html.Type = nil
+  if raw.IsSymbol(ts[0], ":") {
+    if len(ts) < 2 {
+      errCtx := ts[0].Context()
+      return nil, nil, errCtx.NewError("Error: expected type")
+    }
+
+    typ, ts, err = p.buildType(ts[1:])
+    if err != nil {
+      return nil, nil, err
+    }
+
+    ts, _ = p.eatWhitespace(ts)
+
+    if len(ts) == 0 {
+      errCtx := typ.Context()
+      return nil, nil, errCtx.NewError("Error: expected =")
+    }
+  }
+
   if !raw.IsSymbol(ts[0], "=") {
     errCtx := ts[0].Context()
     return nil, nil, errCtx.NewError("Error: expected =")
@@ -574,6 +595,10 @@ func (p *TemplateParser) buildVarDirective(ts []raw.Token) (*html.Tag, []raw.Tok
 	nameHtmlToken := html.NewValueString(nameToken.Value(), nameToken.Context())
 	attr.Set(nameHtmlToken, rhsExpr)
 
+  if typ != nil {
+    attr.Set(html.NewValueString(".type", typ.Context()), typ)
+  }
+
 	return html.NewDirectiveTag("var", attr, []*html.Tag{}, ctx), rem, nil
 }
 
@@ -609,6 +634,15 @@ func (p *TemplateParser) buildPermissiveDirective(ts []raw.Token) (*html.Tag, []
 }
 
 func (p *TemplateParser) buildParens(ts []raw.Token) (*html.Parens, []raw.Token, error) {
+  return p.buildParensWithTypes(ts, false)
+}
+
+// template args can have type annotations (eg. (title: string, n: int = 3))
+func (p *TemplateParser) buildArgParens(ts []raw.Token) (*html.Parens, []raw.Token, error) {
+  return p.buildParensWithTypes(ts, true)
+}
+
+func (p *TemplateParser) buildParensWithTypes(ts []raw.Token, typed bool) (*html.Parens, []raw.Token, error) {
   if r, ok := raw.FindGroupStop(ts, 1, ts[0]); ok {
     groups, err := p.nestGroups(raw.RemoveWhitespace(ts[0:r[1]+1]))
     if err != nil {
@@ -635,8 +669,23 @@ func (p *TemplateParser) buildParens(ts []raw.Token) (*html.Parens, []raw.Token,
     n := len(group.Fields)
     values := make([]html.Token, n)
     alts := make([]html.Token, n)
+    types := make([]*html.Type, n)
+    anyTypes := false
 
     for i, field := range group.Fields {
+      if typed && len(field) > 2 && raw.IsAnyWord(field[0]) && raw.IsSymbol(field[1], ":") {
+        typ, typeRem, err := p.buildType(field[2:])
+        if err != nil {
+          return nil, nil, err
+        }
+
+        types[i] = typ
+        anyTypes = true
+
+        // continue as if there was no type annotation
+        field = append([]raw.Token{field[0]}, typeRem...)
+      }
+
       if len(field) == 1 {
         valExpr, rem, err := p.buildEndOfLineExpression(field)
         if err != nil {
@@ -704,10 +753,17 @@ func (p *TemplateParser) buildParens(ts []raw.Token) (*html.Parens, []raw.Token,
       }
     }
 
+    var parens *html.Parens
+    if anyTypes {
+      parens = html.NewTypedParens(values, types, alts, ctx)
+    } else {
+      parens = html.NewParens(values, alts, ctx)
+    }
+
     if r[1] + 1 < len(ts) {
-      return html.NewParens(values, alts, ctx), ts[r[1]+1:], nil
+      return parens, ts[r[1]+1:], nil
     } else {
-      return html.NewParens(values, alts, ctx), []raw.Token{}, nil
+      return parens, []raw.Token{}, nil
     }
   } else {
     errCtx := ts[0].Context()
@@ -715,6 +771,59 @@ func (p *TemplateParser) buildParens(ts []raw.Token) (*html.Parens, []raw.Token,
   }
 }
 
+// eg. list<dict>, int?
+func (p *TemplateParser) buildType(ts []raw.Token) (*html.Type, []raw.Token, error) {
+  nameToken, err := raw.AssertWord(ts[0])
+  if err != nil {
+    return nil, nil, err
+  }
+
+  ctx := nameToken.Context()
+  parameters := make([]*html.Type, 0)
+  ts = ts[1:]
+
+  if len(ts) > 0 && raw.IsSymbol(ts[0], "<") {
+    ts = ts[1:]
+    for {
+      if len(ts) == 0 {
+        return nil, nil, ctx.NewError("Error: closing > not found")
+      }
+
+      parameter, rem, err := p.buildType(ts)
+      if err != nil {
+        return nil, nil, err
+      }
+
+      parameters = append(parameters, parameter)
+
+      if len(rem) == 0 {
+        return nil, nil, ctx.NewError("Error: closing > not found")
+      } else if raw.IsSymbol(rem[0], ">") {
+        ts = rem[1:]
+        break
+      } else if raw.IsSymbol(rem[0], patterns.COMMA) {
+        ts = rem[1:]
+      } else {
+        errCtx := rem[0].Context()
+        return nil, nil, errCtx.NewError("Error: unexpected token in type")
+      }
+    }
+  }
+
+  nullable := false
+  if len(ts) > 0 && raw.IsSymbol(ts[0], "?") {
+    nullable = true
+    ts = ts[1:]
+  }
+
+  typ, err := html.NewType(nameToken.Value(), parameters, nullable, ctx)
+  if err != nil {
+    return nil, nil, err
+  }
+
+  return typ, ts, nil
+}
+
 func (p *TemplateParser) buildParametersDirective(ts []raw.Token) (*html.Tag, []raw.Token, error) {
   parens, rem, err := p.buildParens(ts[1:])
   if err != nil {
@@ -831,7 +940,7 @@ func (p *TemplateParser) buildTemplateDirective(ts []raw.Token) (*html.Tag, []ra
   ts = ts[0:iSuper]
   if raw.IsSymbol(ts[0], patterns.PARENS_START) {
     // args
-    argParens, argRem, err := p.buildParens(ts)
+    argParens, argRem, err := p.buildArgParens(ts)
     if err != nil {
       return nil, nil, err
     }
@@ -906,6 +1015,53 @@ func (p *TemplateParser) buildForDirective(ts []raw.Token) (*html.Tag, []raw.Tok
 	return html.NewDirectiveTag("for", attr, []*html.Tag{}, ctx), rem, nil
 }
 
+// slot name [required]
+func (p *TemplateParser) buildSlotDirective(ts []raw.Token) (*html.Tag, []raw.Token, error) {
+  ctx := ts[0].Context()
+
+  if len(ts) < 2 {
+    return nil, nil, ctx.NewError("Error: expected slot name")
+  }
+
+  nameToken, err := raw.AssertWord(ts[1])
+  if err != nil {
+    return nil, nil, err
+  }
+
+  attr := html.NewEmptyRawDict(ctx)
+  attr.Set(html.NewValueString("name", nameToken.Context()), html.NewValueString(nameToken.Value(), nameToken.Context()))
+
+  ts = ts[2:]
+  if len(ts) > 0 && raw.IsWord(ts[0], "required") {
+    flagCtx := ts[0].Context()
+    attr.Set(html.NewValueString("required", flagCtx), html.NewValueString("", flagCtx))
+    ts = ts[1:]
+  }
+
+  if len(ts) > 0 && !raw.IsNL(ts[0]) {
+    errCtx := ts[0].Context()
+    return nil, nil, errCtx.NewError("Error: unexpected tokens after slot name")
+  }
+
+	return html.NewDirectiveTag("slot", attr, []*html.Tag{}, ctx), ts, nil
+}
+
+// name: ..., fills a slot of the surrounding template call, content can be inline or indented
+func (p *TemplateParser) buildSlotSection(ts []raw.Token) (*html.Tag, []raw.Token, error) {
+  nameToken, err := raw.AssertWord(ts[0])
+  if err != nil {
+    return nil, nil, err
+  }
+
+  ctx := nameToken.Context()
+  attr := html.NewEmptyRawDict(ctx)
+  attr.Set(html.NewValueString("slot", ctx), html.NewValueString(nameToken.Value(), ctx))
+
+  tag := html.NewDirectiveTag("fill", attr, []*html.Tag{}, ctx)
+
+  return p.buildInlineChildren(tag, ts[2:])
+}
+
 func (p *TemplateParser) buildSingleOrNoValueDirective(ts []raw.Token) (*html.Tag, []raw.Token, error) {
   firstToken, err := raw.AssertWord(ts[0])
   if err != nil {
@@ -1045,6 +1201,10 @@ func (p *TemplateParser) buildGenericTag(inline bool, ts []raw.Token) (*html.Tag
 		return tag, ts, nil
 	}
 
+  return p.buildInlineChildren(tag, ts)
+}
+
+func (p *TemplateParser) buildInlineChildren(tag *html.Tag, ts []raw.Token) (*html.Tag, []raw.Token, error) {
 	// while line is not empty find the children
 	lineIsEmpty := func() bool {
     for _, t := range ts {
@@ -1138,8 +1298,12 @@ func (p *TemplateParser) buildTag(indent int, ts []raw.Token) (*html.Tag, []raw.
           return p.buildStyleDirective(indent, ts)
         case key == "for":
           return p.buildForDirective(ts)
+        case key == "slot":
+          return p.buildSlotDirective(ts)
         case key == "if" || key == "elseif" || key == "else" || key == "append" || key == "replace" || key == "prepend" || key == "block" || key == "switch" || key == "case" || key == "default":
           return p.buildSingleOrNoValueDirective(ts)
+        case len(ts) > 1 && raw.IsSymbol(ts[1], ":"):
+          return p.buildSlotSection(ts)
         default:
           return p.buildGenericTag(false, ts)
       }
@@ -0,0 +1,141 @@
+package search
+
+import (
+  "sort"
+  "strings"
+)
+
+// turns the words of a page, or of a query, into index terms
+// the SearchIndex runtime (see js/macros/SearchIndexHeader.go) must do exactly the same
+type Analyser struct {
+  lang      string // empty if not a supported language
+  stopWords map[string]bool
+  stemmer   Stemmer
+}
+
+type Stemmer interface {
+  // word is lowercase and folded
+  Stem(word string) string
+}
+
+type language struct {
+  stemmer   Stemmer
+  stopWords []string
+}
+
+// every stemmer has a port in the STEM table of js/macros/SearchAnalyserHeader.go,
+//  so languages can't be added without also adding them there
+var _languages = map[string]language{
+  "de": language{&GermanStemmer{}, germanStopWords},
+  "en": language{&EnglishStemmer{}, englishStopWords},
+  "nl": language{&DutchStemmer{}, dutchStopWords},
+}
+
+// sorted
+func Languages() []string {
+  result := make([]string, 0)
+  for lang, _ := range _languages {
+    result = append(result, lang)
+  }
+
+  sort.Strings(result)
+
+  return result
+}
+
+// eg. "en-US" -> "en"
+func PrimaryLanguage(lang string) string {
+  lang = strings.ToLower(strings.TrimSpace(lang))
+
+  if i := strings.IndexAny(lang, "-_"); i != -1 {
+    lang = lang[0:i]
+  }
+
+  return lang
+}
+
+// unsupported languages are only folded, without stop words or stemming
+func NewAnalyser(lang string) *Analyser {
+  lang = PrimaryLanguage(lang)
+
+  l, ok := _languages[lang]
+  if !ok {
+    return &Analyser{"", map[string]bool{}, nil}
+  }
+
+  stopWords := make(map[string]bool)
+  for _, w := range l.stopWords {
+    stopWords[Fold(w)] = true
+  }
+
+  return &Analyser{lang, stopWords, l.stemmer}
+}
+
+func (a *Analyser) Language() string {
+  return a.lang
+}
+
+// sorted and folded
+func (a *Analyser) StopWords() []string {
+  result := make([]string, 0)
+  for w, _ := range a.stopWords {
+    result = append(result, w)
+  }
+
+  sort.Strings(result)
+
+  return result
+}
+
+func isSeparator(r rune) bool {
+  return r < 46 || // keep period as decimal separator
+    r == 47 || // forward slash
+    r == 58 || // :
+    r == 59 || // ;
+    r == 63 || // ?
+    r == 95 // _
+}
+
+// periods at the start or end of a word are dropped
+func Tokenize(text string) []string {
+  result := make([]string, 0)
+
+  for _, w := range strings.FieldsFunc(text, isSeparator) {
+    w = strings.Trim(w, ".")
+    if w != "" {
+      result = append(result, w)
+    }
+  }
+
+  return result
+}
+
+// lowercase and folded, but not yet stemmed
+func (a *Analyser) Normalize(word string) string {
+  return Fold(strings.ToLower(word))
+}
+
+// returns empty string for stop words
+func (a *Analyser) Term(word string) string {
+  w := a.Normalize(word)
+
+  if a.stopWords[w] {
+    return ""
+  } else if a.stemmer != nil {
+    return a.stemmer.Stem(w)
+  } else {
+    return w
+  }
+}
+
+func (a *Analyser) Terms(text string) []string {
+  result := make([]string, 0)
+
+  for _, w := range Tokenize(text) {
+    if t := a.Term(w); t != "" {
+      result = append(result, t)
+    }
+  }
+
+  return result
+}
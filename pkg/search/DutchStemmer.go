@@ -0,0 +1,157 @@
+package search
+
+import (
+  "strings"
+)
+
+// snowball dutch stemmer
+// diacritics have already been folded, which is the first step of the original algorithm
+type DutchStemmer struct {
+}
+
+func isDutchVowel(r rune) bool {
+  return isOneOf(r, "aeiouyè")
+}
+
+func (s *DutchStemmer) Stem(word string) string {
+  w := []rune(word)
+
+  // initial y, y after a vowel, and i between vowels are consonants
+  if len(w) > 0 && w[0] == 'y' {
+    w[0] = 'Y'
+  }
+
+  for i := 1; i < len(w); i++ {
+    if !isDutchVowel(w[i-1]) {
+      continue
+    }
+
+    if w[i] == 'y' {
+      w[i] = 'Y'
+    } else if w[i] == 'i' && i < len(w)-1 && isDutchVowel(w[i+1]) {
+      w[i] = 'I'
+    }
+  }
+
+  r1 := regionStart(w, 0, isDutchVowel)
+  r2 := regionStart(w, r1, isDutchVowel)
+  if r1 < 3 {
+    r1 = 3
+  }
+
+  w = s.step1(w, r1)
+  w, eFound := s.step2(w, r1)
+  w = s.step3a(w, r1, r2)
+  w = s.step3b(w, r1, r2, eFound)
+  w = s.step4(w)
+
+  return strings.Replace(strings.Replace(string(w), "I", "i", -1), "Y", "y", -1)
+}
+
+func dutchUndouble(w []rune) []rune {
+  if longestSuffix(w, "kk", "dd", "tt") != "" {
+    return w[0 : len(w)-1]
+  }
+
+  return w
+}
+
+// non-vowel, and not preceded by gem
+func dutchValidEnEnding(w []rune, suffix string) bool {
+  stem := w[0:suffixStart(w, suffix)]
+
+  return len(stem) > 0 && !isDutchVowel(stem[len(stem)-1]) && !hasSuffix(stem, "gem")
+}
+
+func (s *DutchStemmer) removeEn(w []rune, suffix string, r1 int) []rune {
+  if suffixStart(w, suffix) >= r1 && dutchValidEnEnding(w, suffix) {
+    return dutchUndouble(replaceSuffix(w, suffix, ""))
+  }
+
+  return w
+}
+
+func (s *DutchStemmer) step1(w []rune, r1 int) []rune {
+  suffix := longestSuffix(w, "heden", "ene", "en", "se", "s")
+
+  switch suffix {
+  case "heden":
+    if suffixStart(w, suffix) >= r1 {
+      return replaceSuffix(w, suffix, "heid")
+    }
+  case "en", "ene":
+    return s.removeEn(w, suffix, r1)
+  case "s", "se":
+    // non-vowel other than j
+    if start := suffixStart(w, suffix); start >= r1 && start > 0 && !isDutchVowel(w[start-1]) && w[start-1] != 'j' {
+      return replaceSuffix(w, suffix, "")
+    }
+  }
+
+  return w
+}
+
+func (s *DutchStemmer) step2(w []rune, r1 int) ([]rune, bool) {
+  if hasSuffix(w, "e") {
+    if start := suffixStart(w, "e"); start >= r1 && start > 0 && !isDutchVowel(w[start-1]) {
+      return dutchUndouble(w[0:start]), true
+    }
+  }
+
+  return w, false
+}
+
+func (s *DutchStemmer) step3a(w []rune, r1 int, r2 int) []rune {
+  if hasSuffix(w, "heid") && suffixStart(w, "heid") >= r2 && !hasSuffix(w, "cheid") {
+    w = replaceSuffix(w, "heid", "")
+
+    if hasSuffix(w, "en") {
+      w = s.removeEn(w, "en", r1)
+    }
+  }
+
+  return w
+}
+
+func (s *DutchStemmer) step3b(w []rune, r1 int, r2 int, eFound bool) []rune {
+  suffix := longestSuffix(w, "end", "ing", "ig", "lijk", "baar", "bar")
+  if suffix == "" || suffixStart(w, suffix) < r2 {
+    return w
+  }
+
+  switch suffix {
+  case "end", "ing":
+    w = replaceSuffix(w, suffix, "")
+    if hasSuffix(w, "ig") && suffixStart(w, "ig") >= r2 && !hasSuffix(w, "eig") {
+      w = replaceSuffix(w, "ig", "")
+    } else {
+      w = dutchUndouble(w)
+    }
+  case "ig":
+    if !hasSuffix(w, "eig") {
+      w = replaceSuffix(w, suffix, "")
+    }
+  case "lijk":
+    w, _ = s.step2(replaceSuffix(w, suffix, ""), r1)
+  case "baar":
+    w = replaceSuffix(w, suffix, "")
+  case "bar":
+    if eFound {
+      w = replaceSuffix(w, suffix, "")
+    }
+  }
+
+  return w
+}
+
+// undouble a vowel: eg. maan -> man
+func (s *DutchStemmer) step4(w []rune) []rune {
+  n := len(w)
+
+  if n >= 4 && !isDutchVowel(w[n-4]) && w[n-3] == w[n-2] && isOneOf(w[n-2], "aeou") &&
+    !isDutchVowel(w[n-1]) && w[n-1] != 'I' {
+    return append(w[0:n-2:n-2], w[n-1])
+  }
+
+  return w
+}
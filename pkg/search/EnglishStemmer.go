@@ -0,0 +1,274 @@
+package search
+
+import (
+  "strings"
+)
+
+// snowball english (porter2) stemmer
+// apostrophes are separators, so the possessive step isn't needed
+type EnglishStemmer struct {
+}
+
+var englishExceptions = map[string]string{
+  "skis": "ski",
+  "skies": "sky",
+  "dying": "die",
+  "lying": "lie",
+  "tying": "tie",
+  "idly": "idl",
+  "gently": "gentl",
+  "ugly": "ugli",
+  "early": "earli",
+  "only": "onli",
+  "singly": "singl",
+  "sky": "sky",
+  "news": "news",
+  "howe": "howe",
+  "atlas": "atlas",
+  "cosmos": "cosmos",
+  "bias": "bias",
+  "andes": "andes",
+}
+
+// left alone after step 1a
+var englishInvariants = map[string]bool{
+  "inning": true,
+  "outing": true,
+  "canning": true,
+  "herring": true,
+  "earring": true,
+  "proceed": true,
+  "exceed": true,
+  "succeed": true,
+}
+
+func isEnglishVowel(r rune) bool {
+  return isOneOf(r, "aeiouy")
+}
+
+func englishEndsInShortSyllable(w []rune) bool {
+  n := len(w)
+
+  if n == 2 {
+    return isEnglishVowel(w[0]) && !isEnglishVowel(w[1])
+  } else if n > 2 {
+    return !isEnglishVowel(w[n-3]) && isEnglishVowel(w[n-2]) && !isEnglishVowel(w[n-1]) && !isOneOf(w[n-1], "wxY")
+  } else {
+    return false
+  }
+}
+
+func (s *EnglishStemmer) Stem(word string) string {
+  if exception, ok := englishExceptions[word]; ok {
+    return exception
+  }
+
+  w := []rune(word)
+  if len(w) <= 2 {
+    return word
+  }
+
+  // consonant y's
+  if w[0] == 'y' {
+    w[0] = 'Y'
+  }
+
+  for i := 1; i < len(w); i++ {
+    if w[i] == 'y' && isEnglishVowel(w[i-1]) {
+      w[i] = 'Y'
+    }
+  }
+
+  r1 := regionStart(w, 0, isEnglishVowel)
+  for _, prefix := range []string{"gener", "commun", "arsen"} {
+    if strings.HasPrefix(word, prefix) {
+      r1 = len(prefix)
+    }
+  }
+
+  r2 := regionStart(w, r1, isEnglishVowel)
+
+  w = s.step1a(w)
+
+  if englishInvariants[string(w)] {
+    return string(w)
+  }
+
+  w = s.step1b(w, r1)
+  w = s.step1c(w)
+  w = s.step2(w, r1)
+  w = s.step3(w, r1, r2)
+  w = s.step4(w, r2)
+  w = s.step5(w, r1, r2)
+
+  return strings.Replace(string(w), "Y", "y", -1)
+}
+
+func (s *EnglishStemmer) step1a(w []rune) []rune {
+  switch suffix := longestSuffix(w, "sses", "ied", "ies", "us", "ss", "s"); suffix {
+  case "sses":
+    return replaceSuffix(w, suffix, "ss")
+  case "ied", "ies":
+    if len(w) > 4 {
+      return replaceSuffix(w, suffix, "i")
+    } else {
+      return replaceSuffix(w, suffix, "ie")
+    }
+  case "s":
+    // vowel not immediately before the s
+    if containsVowel(w[0:len(w)-2], isEnglishVowel) {
+      return replaceSuffix(w, suffix, "")
+    }
+  }
+
+  return w
+}
+
+func (s *EnglishStemmer) step1b(w []rune, r1 int) []rune {
+  switch suffix := longestSuffix(w, "eed", "eedly", "ed", "edly", "ing", "ingly"); suffix {
+  case "eed", "eedly":
+    if suffixStart(w, suffix) >= r1 {
+      return replaceSuffix(w, suffix, "ee")
+    }
+  case "ed", "edly", "ing", "ingly":
+    stem := w[0:suffixStart(w, suffix)]
+    if !containsVowel(stem, isEnglishVowel) {
+      return w
+    }
+
+    w = replaceSuffix(w, suffix, "")
+
+    if longestSuffix(w, "at", "bl", "iz") != "" {
+      return append(w, 'e')
+    } else if longestSuffix(w, "bb", "dd", "ff", "gg", "mm", "nn", "pp", "rr", "tt") != "" {
+      return w[0 : len(w)-1]
+    } else if r1 >= len(w) && englishEndsInShortSyllable(w) {
+      return append(w, 'e')
+    }
+  }
+
+  return w
+}
+
+func (s *EnglishStemmer) step1c(w []rune) []rune {
+  n := len(w)
+
+  if n > 2 && (w[n-1] == 'y' || w[n-1] == 'Y') && !isEnglishVowel(w[n-2]) {
+    return replaceSuffix(w, "y", "i")
+  }
+
+  return w
+}
+
+var englishStep2 = map[string]string{
+  "tional": "tion",
+  "enci": "ence",
+  "anci": "ance",
+  "abli": "able",
+  "entli": "ent",
+  "izer": "ize",
+  "ization": "ize",
+  "ational": "ate",
+  "ation": "ate",
+  "ator": "ate",
+  "alism": "al",
+  "aliti": "al",
+  "alli": "al",
+  "fulness": "ful",
+  "ousli": "ous",
+  "ousness": "ous",
+  "iveness": "ive",
+  "iviti": "ive",
+  "biliti": "ble",
+  "bli": "ble",
+  "ogi": "og",
+  "fulli": "ful",
+  "lessli": "less",
+  "li": "",
+}
+
+func (s *EnglishStemmer) step2(w []rune, r1 int) []rune {
+  suffix := ""
+  for candidate, _ := range englishStep2 {
+    if len(candidate) > len(suffix) && hasSuffix(w, candidate) {
+      suffix = candidate
+    }
+  }
+
+  if suffix == "" || suffixStart(w, suffix) < r1 {
+    return w
+  }
+
+  switch suffix {
+  case "ogi":
+    if !hasSuffix(w, "logi") {
+      return w
+    }
+  case "li":
+    if len(w) < 3 || !isOneOf(w[len(w)-3], "cdeghkmnrt") {
+      return w
+    }
+  }
+
+  return replaceSuffix(w, suffix, englishStep2[suffix])
+}
+
+var englishStep3 = map[string]string{
+  "tional": "tion",
+  "ational": "ate",
+  "alize": "al",
+  "icate": "ic",
+  "iciti": "ic",
+  "ical": "ic",
+  "ful": "",
+  "ness": "",
+  "ative": "",
+}
+
+func (s *EnglishStemmer) step3(w []rune, r1 int, r2 int) []rune {
+  suffix := ""
+  for candidate, _ := range englishStep3 {
+    if len(candidate) > len(suffix) && hasSuffix(w, candidate) {
+      suffix = candidate
+    }
+  }
+
+  if suffix == "" || suffixStart(w, suffix) < r1 {
+    return w
+  }
+
+  if suffix == "ative" && suffixStart(w, suffix) < r2 {
+    return w
+  }
+
+  return replaceSuffix(w, suffix, englishStep3[suffix])
+}
+
+func (s *EnglishStemmer) step4(w []rune, r2 int) []rune {
+  suffix := longestSuffix(w, "al", "ance", "ence", "er", "ic", "able", "ible", "ant", "ement",
+    "ment", "ent", "ism", "ate", "iti", "ous", "ive", "ize", "ion")
+
+  if suffix == "" || suffixStart(w, suffix) < r2 {
+    return w
+  }
+
+  if suffix == "ion" && !(hasSuffix(w, "sion") || hasSuffix(w, "tion")) {
+    return w
+  }
+
+  return replaceSuffix(w, suffix, "")
+}
+
+func (s *EnglishStemmer) step5(w []rune, r1 int, r2 int) []rune {
+  n := len(w)
+
+  if hasSuffix(w, "e") {
+    if n-1 >= r2 || (n-1 >= r1 && !englishEndsInShortSyllable(w[0:n-1])) {
+      return w[0 : n-1]
+    }
+  } else if hasSuffix(w, "ll") && n-1 >= r2 {
+    return w[0 : n-1]
+  }
+
+  return w
+}
@@ -0,0 +1,118 @@
+package search
+
+import (
+  "strings"
+)
+
+// snowball german stemmer
+// umlauts and ß have already been folded, which the last step of the original algorithm does anyway
+type GermanStemmer struct {
+}
+
+func isGermanVowel(r rune) bool {
+  return isOneOf(r, "aeiouyäöü")
+}
+
+func (s *GermanStemmer) Stem(word string) string {
+  w := []rune(strings.Replace(word, "ß", "ss", -1))
+
+  // u and y between vowels are consonants
+  for i := 1; i < len(w)-1; i++ {
+    if isGermanVowel(w[i-1]) && isGermanVowel(w[i+1]) {
+      if w[i] == 'u' {
+        w[i] = 'U'
+      } else if w[i] == 'y' {
+        w[i] = 'Y'
+      }
+    }
+  }
+
+  r1 := regionStart(w, 0, isGermanVowel)
+  r2 := regionStart(w, r1, isGermanVowel)
+  if r1 < 3 {
+    r1 = 3
+  }
+
+  w = s.step1(w, r1)
+  w = s.step2(w, r1)
+  w = s.step3(w, r1, r2)
+
+  result := string(w)
+  for _, pair := range [][2]string{{"U", "u"}, {"Y", "y"}, {"ä", "a"}, {"ö", "o"}, {"ü", "u"}} {
+    result = strings.Replace(result, pair[0], pair[1], -1)
+  }
+
+  return result
+}
+
+func (s *GermanStemmer) step1(w []rune, r1 int) []rune {
+  suffix := longestSuffix(w, "em", "ern", "er", "e", "en", "es", "s")
+  if suffix == "" || suffixStart(w, suffix) < r1 {
+    return w
+  }
+
+  switch suffix {
+  case "em", "ern", "er":
+    return replaceSuffix(w, suffix, "")
+  case "e", "en", "es":
+    w = replaceSuffix(w, suffix, "")
+    if hasSuffix(w, "niss") {
+      w = w[0 : len(w)-1]
+    }
+
+    return w
+  default: // s
+    if len(w) > 1 && isOneOf(w[len(w)-2], "bdfghklmnrt") {
+      return replaceSuffix(w, suffix, "")
+    }
+
+    return w
+  }
+}
+
+func (s *GermanStemmer) step2(w []rune, r1 int) []rune {
+  suffix := longestSuffix(w, "en", "er", "est", "st")
+  if suffix == "" || suffixStart(w, suffix) < r1 {
+    return w
+  }
+
+  if suffix == "st" {
+    // valid st-ending, itself preceded by at least 3 letters
+    if len(w) < 6 || !isOneOf(w[len(w)-3], "bdfghklmnt") {
+      return w
+    }
+  }
+
+  return replaceSuffix(w, suffix, "")
+}
+
+func (s *GermanStemmer) step3(w []rune, r1 int, r2 int) []rune {
+  suffix := longestSuffix(w, "end", "ung", "ig", "ik", "isch", "lich", "heit", "keit")
+  if suffix == "" || suffixStart(w, suffix) < r2 {
+    return w
+  }
+
+  switch suffix {
+  case "end", "ung":
+    w = replaceSuffix(w, suffix, "")
+    if hasSuffix(w, "ig") && !hasSuffix(w, "eig") && suffixStart(w, "ig") >= r2 {
+      w = replaceSuffix(w, "ig", "")
+    }
+  case "ig", "ik", "isch":
+    if !hasSuffix(w, "e"+suffix) {
+      w = replaceSuffix(w, suffix, "")
+    }
+  case "lich", "heit":
+    w = replaceSuffix(w, suffix, "")
+    if prev := longestSuffix(w, "er", "en"); prev != "" && suffixStart(w, prev) >= r1 {
+      w = replaceSuffix(w, prev, "")
+    }
+  case "keit":
+    w = replaceSuffix(w, suffix, "")
+    if prev := longestSuffix(w, "lich", "ig"); prev != "" && suffixStart(w, prev) >= r2 {
+      w = replaceSuffix(w, prev, "")
+    }
+  }
+
+  return w
+}
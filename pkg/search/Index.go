@@ -0,0 +1,382 @@
+package search
+
+import (
+  "encoding/json"
+  "errors"
+  "io/ioutil"
+  "os"
+  "path/filepath"
+  "sort"
+  "strings"
+
+  "github.com/computeportal/wtsuite/pkg/tokens/raw"
+)
+
+const (
+  CONTENT_LIMIT = 100 // number of chars
+)
+
+// fields of a page that are scored separately
+const (
+  TITLE_FIELD = iota
+  DESCRIPTION_FIELD
+  CONTENT_FIELD
+  N_FIELDS
+)
+
+// the json layout must match the SearchIndex runtime (see js/macros/SearchIndexHeader.go)
+type Page struct {
+	Url         string   `json:"url"`     // used as key
+	Title       string   `json:"title"`   // should be unique for each indexed page
+  Description string   `json:"description"`
+	Content     []string `json:"content"` // each string is a paragraph, truncated to CONTENT_LIMIT so that it can be used as a snippet
+  Lang        string   `json:"lang"`    // primary language subtag, determines the analyser
+  Lengths     [N_FIELDS]int `json:"-"`       // number of indexed words in title, description and content
+}
+
+type Index struct {
+	Pages      []Page                 `json:"pages,omitempty"` // sorted, nil if sharded
+  PageShards []PageShard            `json:"pageShards,omitempty"`
+  Lengths    [][N_FIELDS]int        `json:"lengths"` // field lengths of each page, always in the manifest because they are needed for the ranking
+	Ignore     map[string]string      `json:"ignore"`  // key is same as value
+  Language   string                 `json:"language"` // default language of queries
+  Weights    [N_FIELDS]float64      `json:"weights"` // field weights used by the BM25 ranking
+  AvgLengths [N_FIELDS]float64      `json:"avgLengths"`
+	Index      map[string]interface{} `json:"index"`   // nested character tree, leaves are indices into pages array, and term frequencies per field
+	Partial    map[string]interface{} `json:"partial"` // nested character tree which doesn't start at beginning of word
+}
+
+func NewIndex() *Index {
+	return &Index{
+		Pages:   make([]Page, 0),
+		Ignore:  make(map[string]string),
+		Index:   make(map[string]interface{}),
+		Partial: make(map[string]interface{}),
+	}
+}
+
+// the content is truncated to CONTENT_LIMIT when building
+func NewPage(url string, title string, description string, content []string, lang string) Page {
+  return Page{url, title, description, content, PrimaryLanguage(lang), [N_FIELDS]int{}}
+}
+
+func (si *Index) AddPage(page Page) {
+	si.Pages = append(si.Pages, page)
+}
+
+// "dir/index.html" -> "dir"
+func PageURL(url string) string {
+  if strings.HasSuffix(url, "/index.html") {
+    url = strings.TrimSuffix(url, "/index.html")
+  }
+
+  return url
+}
+
+// "Title | Site name" -> "Title"
+func PageTitle(title string) string {
+  if strings.Contains(title, "|") {
+    title = strings.Split(title, "|")[0]
+  }
+
+  return strings.TrimSpace(title)
+}
+
+// normalized in the same way as the words of the pages, and sorted for isIgnored
+func NormalizeIgnore(words []string) []string {
+  result := make([]string, len(words))
+  for i, w := range words {
+    result[i] = Fold(strings.ToLower(w))
+  }
+
+  sort.Strings(result)
+
+  return result
+}
+
+// keys: "title", "description", "content", missing keys get the default weights
+func FieldWeights(weights map[string]float64) ([N_FIELDS]float64, error) {
+  result := [N_FIELDS]float64{3.0, 2.0, 1.0}
+
+  for name, w := range weights {
+    if w < 0.0 {
+      return result, errors.New("Error: weight of " + name + " can't be negative")
+    }
+
+    switch name {
+    case "title":
+      result[TITLE_FIELD] = w
+    case "description":
+      result[DESCRIPTION_FIELD] = w
+    case "content":
+      result[CONTENT_FIELD] = w
+    default:
+      return result, errors.New("Error: unrecognized weight field " + name + " (expected title, description or content)")
+    }
+  }
+
+  return result, nil
+}
+
+// if field is -1 the term frequencies aren't tracked
+func (si *Index) indexWord(m map[string]interface{}, pageID int, field int, f string) error {
+	chars := strings.Split(f, "")
+
+	for _, char := range chars {
+		if mInner, ok := m[char]; ok {
+			m = mInner.(map[string]interface{})
+		} else {
+			mInner := make(map[string]interface{})
+			m[char] = mInner
+			m = mInner
+		}
+	}
+
+	if pages_, ok := m["pages"]; ok {
+		pages := pages_.([]float64)
+		j := -1
+		for k, page := range pages {
+			if int(page) == pageID {
+				j = k
+				break
+			}
+		}
+
+		if j == -1 {
+			pages = append(pages, float64(pageID))
+			m["pages"] = pages
+
+      if field != -1 {
+        m["tf"] = append(m["tf"].([][N_FIELDS]float64), [N_FIELDS]float64{})
+      }
+
+      j = len(pages) - 1
+		}
+
+    if field != -1 {
+      m["tf"].([][N_FIELDS]float64)[j][field] += 1
+    }
+	} else {
+		m["pages"] = []float64{float64(pageID)}
+
+    if field != -1 {
+      tf := [N_FIELDS]float64{}
+      tf[field] = 1
+      m["tf"] = [][N_FIELDS]float64{tf}
+    }
+	}
+
+	return nil
+}
+
+func (si *Index) IndexWord(pageID int, field int, f string) error {
+	if err := si.indexWord(si.Index, pageID, field, f); err != nil {
+		return err
+	}
+
+  si.Pages[pageID].Lengths[field] += 1
+
+	// partial versions of the word are also indexed (include the full word itself)
+	for i := 0; i < len(f); i++ {
+		fPart := f[i:]
+
+		if err := si.indexWord(si.Partial, pageID, -1, fPart); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ignore must be sorted
+func isIgnored(ignore []string, w string) bool {
+	i := sort.SearchStrings(ignore, w)
+
+	if i > -1 && i < len(ignore) {
+		return ignore[i] == w
+	} else {
+		return false
+	}
+}
+
+func (si *Index) indexSentence(ignore []string, analyser *Analyser, pageID int, field int, sentence string) error {
+	for _, word := range Tokenize(sentence) {
+    if isIgnored(ignore, analyser.Normalize(word)) {
+      continue
+    }
+
+    if f := analyser.Term(word); f != "" {
+      if err := si.IndexWord(pageID, field, f); err != nil {
+        return err
+      }
+		}
+	}
+
+	return nil
+}
+
+func (si *Index) limitContent() {
+  for i, page := range si.Pages {
+    content := make([]string, 0)
+    count := 0
+    for _, part := range page.Content {
+      count += len(part)
+
+      if count < CONTENT_LIMIT {
+        content = append(content, part)
+      } else {
+        content = append(content, part[0:len(part) - (count - CONTENT_LIMIT)] + "...")
+        break
+      }
+    }
+
+    page.Content = content
+    si.Pages[i] = page
+  }
+}
+
+// actually fill the index/partial nested trees, ignore must be normalized (see NormalizeIgnore)
+// language is the default language of queries
+func (si *Index) Build(ignore []string, language string, weights [N_FIELDS]float64) error {
+  analysers := make(map[string]*Analyser)
+
+	// loop each word of each page
+	for i, page := range si.Pages {
+    analyser, ok := analysers[page.Lang]
+    if !ok {
+      analyser = NewAnalyser(page.Lang)
+      analysers[page.Lang] = analyser
+    }
+
+		if err := si.indexSentence(ignore, analyser, i, TITLE_FIELD, page.Title); err != nil {
+			return err
+		}
+		if err := si.indexSentence(ignore, analyser, i, DESCRIPTION_FIELD, page.Description); err != nil {
+			return err
+		}
+		for _, paragraph := range page.Content {
+			if err := si.indexSentence(ignore, analyser, i, CONTENT_FIELD, paragraph); err != nil {
+				return err
+			}
+		}
+	}
+
+  // average field lengths are needed for the BM25 length normalization
+  si.Lengths = make([][N_FIELDS]int, len(si.Pages))
+  if n := len(si.Pages); n > 0 {
+    for i, page := range si.Pages {
+      si.Lengths[i] = page.Lengths
+
+      for field, l := range page.Lengths {
+        si.AvgLengths[field] += float64(l)/float64(n)
+      }
+    }
+  }
+
+  si.Weights = weights
+  si.Language = PrimaryLanguage(language)
+
+	// add the ignored values
+	for _, w := range ignore {
+		si.Ignore[w] = w
+	}
+
+  // only the snippets are kept, the full content has been indexed
+  si.limitContent()
+
+	return nil
+}
+
+// shards are written next to the manifest, the content hash in the name makes them safe to cache
+func writeShard(manifest string, content interface{}) (string, error) {
+  b, err := json.Marshal(content)
+  if err != nil {
+    return "", err
+  }
+
+  base := strings.TrimSuffix(filepath.Base(manifest), filepath.Ext(manifest))
+  name := base + "." + raw.ShortHash(string(b)) + ".json"
+
+  if err := ioutil.WriteFile(filepath.Join(filepath.Dir(manifest), name), b, 0644); err != nil {
+    return "", err
+  }
+
+  return name, nil
+}
+
+// shard files referred to by a previously written manifest, nil if there is no (readable) manifest
+func readShardFiles(manifest string) map[string]bool {
+  b, err := ioutil.ReadFile(manifest)
+  if err != nil {
+    return nil
+  }
+
+  var prev struct {
+    PageShards []PageShard            `json:"pageShards"`
+    Index      map[string]interface{} `json:"index"`
+    Partial    map[string]interface{} `json:"partial"`
+  }
+
+  if err := json.Unmarshal(b, &prev); err != nil {
+    return nil
+  }
+
+  files := make(map[string]bool)
+
+  var walk func(node map[string]interface{})
+  walk = func(node map[string]interface{}) {
+    for k, v := range node {
+      if file, ok := v.(string); ok && k == "shard" {
+        files[file] = true
+      } else if child, ok := v.(map[string]interface{}); ok {
+        walk(child)
+      }
+    }
+  }
+
+  walk(prev.Index)
+  walk(prev.Partial)
+
+  for _, ps := range prev.PageShards {
+    files[ps.File] = true
+  }
+
+  return files
+}
+
+// shardSize is the approximate max number of bytes per shard file, 0 to write a single file
+// shards of the previous manifest that are no longer used are removed afterwards
+func (si *Index) Write(dst string, shardSize int) error {
+  prevFiles := readShardFiles(dst)
+  files := make(map[string]bool)
+
+  if shardSize > 0 {
+    if err := si.Shard(shardSize, func(content interface{}) (string, error) {
+      file, err := writeShard(dst, content)
+      files[file] = true
+      return file, err
+    }); err != nil {
+      return err
+    }
+  }
+
+	b, err := json.Marshal(si)
+	if err != nil {
+		return err
+	}
+
+	if err := ioutil.WriteFile(dst, b, 0644); err != nil {
+    return err
+  }
+
+  for file, _ := range prevFiles {
+    // only shard files next to the manifest
+    if !files[file] && file == filepath.Base(file) && strings.HasSuffix(file, ".json") && file != filepath.Base(dst) {
+      if err := os.Remove(filepath.Join(filepath.Dir(dst), file)); err != nil && !os.IsNotExist(err) {
+        return err
+      }
+    }
+  }
+
+  return nil
+}
@@ -0,0 +1,91 @@
+package search
+
+import (
+  "io/ioutil"
+  "os"
+  "path/filepath"
+  "sort"
+  "testing"
+)
+
+func buildTestIndex(t *testing.T, dst string, content string) {
+  si := NewIndex()
+  si.AddPage(NewPage("a.html", "Apples", "", []string{"apples and pears " + content}, "en"))
+  si.AddPage(NewPage("b.html", "Bananas", "", []string{"bananas and cherries " + content}, "en"))
+  si.AddPage(NewPage("c.html", "Cherries", "", []string{"cherries and plums"}, "en"))
+
+  if err := si.Build([]string{}, "en", [N_FIELDS]float64{1.0, 1.0, 1.0}); err != nil {
+    t.Fatal(err)
+  }
+
+  if err := si.Write(dst, 100); err != nil {
+    t.Fatal(err)
+  }
+}
+
+func listDir(t *testing.T, dir string) []string {
+  infos, err := ioutil.ReadDir(dir)
+  if err != nil {
+    t.Fatal(err)
+  }
+
+  names := make([]string, 0)
+  for _, info := range infos {
+    names = append(names, info.Name())
+  }
+
+  sort.Strings(names)
+
+  return names
+}
+
+func TestWriteRemovesUnusedShards(t *testing.T) {
+  dir, err := ioutil.TempDir("", "search")
+  if err != nil {
+    t.Fatal(err)
+  }
+  defer os.RemoveAll(dir)
+
+  dst := filepath.Join(dir, "index.json")
+
+  // unrelated files must be left alone
+  other := filepath.Join(dir, "other.json")
+  if err := ioutil.WriteFile(other, []byte("{}"), 0644); err != nil {
+    t.Fatal(err)
+  }
+
+  buildTestIndex(t, dst, "oranges")
+  before := listDir(t, dir)
+
+  buildTestIndex(t, dst, "grapes and melons")
+  after := listDir(t, dir)
+
+  used := readShardFiles(dst)
+  if len(used) < 2 {
+    t.Fatalf("expected the index to be sharded, got %v", after)
+  }
+
+  for _, name := range after {
+    if name != "index.json" && name != "other.json" && !used[name] {
+      t.Errorf("unused shard %s wasn't removed (before: %v, after: %v)", name, before, after)
+    }
+  }
+
+  for name, _ := range used {
+    if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+      t.Errorf("shard %s is missing", name)
+    }
+  }
+
+  if _, err := os.Stat(other); err != nil {
+    t.Errorf("other.json was removed")
+  }
+
+  // rebuilding the same content doesn't change anything
+  buildTestIndex(t, dst, "grapes and melons")
+  again := listDir(t, dir)
+
+  if len(again) != len(after) {
+    t.Errorf("expected %v, got %v", after, again)
+  }
+}
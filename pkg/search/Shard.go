@@ -0,0 +1,177 @@
+package search
+
+import (
+  "encoding/json"
+  "sort"
+)
+
+// subtrees of the index and partial tries, and chunks of pages, are moved into separate files, so that the browser only needs to fetch what a query uses
+// the remaining manifest refers to the shards via {"shard": <file>} placeholders
+
+type PageShard struct {
+  Start int    `json:"start"`
+  Stop  int    `json:"stop"` // exclusive
+  File  string `json:"file"`
+}
+
+// returns the file name, relative to the manifest
+type ShardWriter func(content interface{}) (string, error)
+
+type trieSharder struct {
+  maxSize int
+  write   ShardWriter
+
+  // siblings are grouped until maxSize is reached
+  group     map[string]interface{} // key is the prefix of the subtree
+  groupSize int
+  holders   []map[string]interface{}
+}
+
+func jsonSize(v interface{}) int {
+  b, err := json.Marshal(v)
+  if err != nil {
+    panic(err)
+  }
+
+  return len(b)
+}
+
+func (s *trieSharder) flush() error {
+  if len(s.group) == 0 {
+    return nil
+  }
+
+  file, err := s.write(s.group)
+  if err != nil {
+    return err
+  }
+
+  for _, holder := range s.holders {
+    holder["shard"] = file
+  }
+
+  s.group = make(map[string]interface{})
+  s.groupSize = 0
+  s.holders = make([]map[string]interface{}, 0)
+
+  return nil
+}
+
+func (s *trieSharder) add(prefix string, node map[string]interface{}, size int) (map[string]interface{}, error) {
+  if len(s.group) > 0 && s.groupSize + size > s.maxSize {
+    if err := s.flush(); err != nil {
+      return nil, err
+    }
+  }
+
+  holder := make(map[string]interface{})
+
+  s.group[prefix] = node
+  s.groupSize += size + len(prefix) + 4
+  s.holders = append(s.holders, holder)
+
+  return holder, nil
+}
+
+// returns the skeleton that replaces node
+func (s *trieSharder) shard(prefix string, node map[string]interface{}) (map[string]interface{}, error) {
+  if size := jsonSize(node); size <= s.maxSize {
+    return s.add(prefix, node, size)
+  }
+
+  // sorted for reproducible shards
+  keys := make([]string, 0)
+  for k, _ := range node {
+    keys = append(keys, k)
+  }
+
+  sort.Strings(keys)
+
+  skeleton := make(map[string]interface{})
+  for _, k := range keys {
+    if k == "pages" || k == "tf" {
+      skeleton[k] = node[k]
+      continue
+    }
+
+    child, err := s.shard(prefix + k, node[k].(map[string]interface{}))
+    if err != nil {
+      return nil, err
+    }
+
+    skeleton[k] = child
+  }
+
+  return skeleton, nil
+}
+
+func shardTrie(trie map[string]interface{}, maxSize int, write ShardWriter) (map[string]interface{}, error) {
+  s := &trieSharder{maxSize, write, make(map[string]interface{}), 0, make([]map[string]interface{}, 0)}
+
+  skeleton, err := s.shard("", trie)
+  if err != nil {
+    return nil, err
+  }
+
+  if err := s.flush(); err != nil {
+    return nil, err
+  }
+
+  return skeleton, nil
+}
+
+func shardPages(pages []Page, maxSize int, write ShardWriter) ([]PageShard, error) {
+  shards := make([]PageShard, 0)
+
+  start := 0
+  size := 0
+  for i, page := range pages {
+    pageSize := jsonSize(page)
+
+    if i > start && size + pageSize > maxSize {
+      file, err := write(pages[start:i])
+      if err != nil {
+        return nil, err
+      }
+
+      shards = append(shards, PageShard{start, i, file})
+      start = i
+      size = 0
+    }
+
+    size += pageSize + 1
+  }
+
+  if start < len(pages) {
+    file, err := write(pages[start:])
+    if err != nil {
+      return nil, err
+    }
+
+    shards = append(shards, PageShard{start, len(pages), file})
+  }
+
+  return shards, nil
+}
+
+func (si *Index) Shard(maxSize int, write ShardWriter) error {
+  var err error
+  si.Index, err = shardTrie(si.Index, maxSize, write)
+  if err != nil {
+    return err
+  }
+
+  si.Partial, err = shardTrie(si.Partial, maxSize, write)
+  if err != nil {
+    return err
+  }
+
+  si.PageShards, err = shardPages(si.Pages, maxSize, write)
+  if err != nil {
+    return err
+  }
+
+  si.Pages = nil
+
+  return nil
+}
@@ -0,0 +1,54 @@
+package search
+
+import (
+  "strings"
+)
+
+// diacritics are removed, ligatures are expanded
+// characters that aren't in this table are kept as is
+var FoldTable = map[rune]string{
+  'à': "a", 'á': "a", 'â': "a", 'ã': "a", 'ä': "a", 'å': "a", 'ā': "a", 'ă': "a", 'ą': "a",
+  'æ': "ae",
+  'ç': "c", 'ć': "c", 'ĉ': "c", 'ċ': "c", 'č': "c",
+  'ď': "d", 'đ': "d", 'ð': "d",
+  'è': "e", 'é': "e", 'ê': "e", 'ë': "e", 'ē': "e", 'ĕ': "e", 'ė': "e", 'ę': "e", 'ě': "e",
+  'ĝ': "g", 'ğ': "g", 'ġ': "g", 'ģ': "g",
+  'ĥ': "h", 'ħ': "h",
+  'ì': "i", 'í': "i", 'î': "i", 'ï': "i", 'ĩ': "i", 'ī': "i", 'ĭ': "i", 'į': "i", 'ı': "i",
+  'ĵ': "j",
+  'ķ': "k",
+  'ĺ': "l", 'ļ': "l", 'ľ': "l", 'ŀ': "l", 'ł': "l",
+  'ñ': "n", 'ń': "n", 'ņ': "n", 'ň': "n",
+  'ò': "o", 'ó': "o", 'ô': "o", 'õ': "o", 'ö': "o", 'ø': "o", 'ō': "o", 'ŏ': "o", 'ő': "o",
+  'œ': "oe",
+  'ŕ': "r", 'ŗ': "r", 'ř': "r",
+  'ś': "s", 'ŝ': "s", 'ş': "s", 'š': "s", 'ß': "ss",
+  'ţ': "t", 'ť': "t", 'ŧ': "t",
+  'þ': "th",
+  'ù': "u", 'ú': "u", 'û': "u", 'ü': "u", 'ũ': "u", 'ū': "u", 'ŭ': "u", 'ů': "u", 'ű': "u", 'ų': "u",
+  'ŵ': "w",
+  'ý': "y", 'ÿ': "y", 'ŷ': "y",
+  'ź': "z", 'ż': "z", 'ž': "z",
+}
+
+// combining diacritical marks (eg. of decomposed "e\u0301") are dropped too
+func isCombiningMark(r rune) bool {
+  return r >= 0x0300 && r <= 0x036f
+}
+
+// expects lowercase input
+func Fold(w string) string {
+  var b strings.Builder
+
+  for _, r := range w {
+    if isCombiningMark(r) {
+      continue
+    } else if s, ok := FoldTable[r]; ok {
+      b.WriteString(s)
+    } else {
+      b.WriteRune(r)
+    }
+  }
+
+  return b.String()
+}
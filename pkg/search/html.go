@@ -0,0 +1,91 @@
+package search
+
+import (
+  "github.com/computeportal/wtsuite/pkg/tree"
+  tokens "github.com/computeportal/wtsuite/pkg/tokens/html"
+)
+
+// selectors don't look inside head, so head is walked manually
+func headChildren(root *tree.Root) []tree.Tag {
+  for _, rootChild := range root.Children() {
+    if rootChild.Name() == "html" {
+      for _, htmlChild := range rootChild.Children() {
+        if htmlChild.Name() == "head" {
+          return htmlChild.Children()
+        }
+      }
+    }
+  }
+
+  return []tree.Tag{}
+}
+
+// content of html -> head -> title
+func HeadTitle(root *tree.Root) string {
+  for _, headChild := range headChildren(root) {
+    if headChild.Name() == "title" {
+      titleTag, ok := headChild.(*tree.Title)
+      if !ok {
+        panic("unexpeced")
+      }
+
+      return titleTag.Content()
+    }
+  }
+
+  return ""
+}
+
+// content of <meta name="description">
+func HeadDescription(root *tree.Root) string {
+  for _, headChild := range headChildren(root) {
+    if headChild.Name() != "meta" {
+      continue
+    }
+
+    attr := headChild.Attributes()
+    if nameToken_, ok := attr.Get("name"); ok {
+      if nameToken, err := tokens.AssertString(nameToken_); err == nil && nameToken.Value() == "description" {
+        if contentToken_, ok := attr.Get("content"); ok {
+          if contentToken, err := tokens.AssertString(contentToken_); err == nil {
+            return contentToken.Value()
+          }
+        }
+      }
+    }
+  }
+
+  return ""
+}
+
+// lang attribute of <html>, empty if not set
+func HTMLLang(root *tree.Root) string {
+  for _, rootChild := range root.Children() {
+    if rootChild.Name() == "html" {
+      if langToken_, ok := rootChild.Attributes().Get("lang"); ok {
+        if langToken, err := tokens.AssertString(langToken_); err == nil {
+          return langToken.Value()
+        }
+      }
+    }
+  }
+
+  return ""
+}
+
+// each text node becomes a separate paragraph
+func TagText(tags []tree.Tag) []string {
+  str := make([]string, 0)
+
+  for _, t := range tags {
+    if err := tree.WalkText(t, []tree.Tag{}, func(_ []tree.Tag, s string) error {
+      str = append(str, s)
+
+      return nil
+    }); err != nil {
+      panic("unexpected")
+    }
+  }
+
+  return str
+}
@@ -0,0 +1,74 @@
+package search
+
+// helpers for the snowball style stemmers, which work on rune slices
+
+func hasSuffix(w []rune, suffix string) bool {
+  s := []rune(suffix)
+  if len(s) > len(w) {
+    return false
+  }
+
+  offset := len(w) - len(s)
+  for i, r := range s {
+    if w[offset+i] != r {
+      return false
+    }
+  }
+
+  return true
+}
+
+// empty string if none of the suffixes match
+func longestSuffix(w []rune, suffixes ...string) string {
+  best := ""
+  for _, s := range suffixes {
+    if len(s) > len(best) && hasSuffix(w, s) {
+      best = s
+    }
+  }
+
+  return best
+}
+
+// the suffix must be ascii
+func suffixStart(w []rune, suffix string) int {
+  return len(w) - len(suffix)
+}
+
+func replaceSuffix(w []rune, suffix string, replacement string) []rune {
+  result := make([]rune, 0, len(w))
+  result = append(result, w[0:suffixStart(w, suffix)]...)
+
+  return append(result, []rune(replacement)...)
+}
+
+// start of the region after the first non-vowel following a vowel, searched from start
+func regionStart(w []rune, start int, isVowel func(rune) bool) int {
+  for i := start + 1; i < len(w); i++ {
+    if isVowel(w[i-1]) && !isVowel(w[i]) {
+      return i + 1
+    }
+  }
+
+  return len(w)
+}
+
+func containsVowel(w []rune, isVowel func(rune) bool) bool {
+  for _, r := range w {
+    if isVowel(r) {
+      return true
+    }
+  }
+
+  return false
+}
+
+func isOneOf(r rune, chars string) bool {
+  for _, c := range chars {
+    if r == c {
+      return true
+    }
+  }
+
+  return false
+}
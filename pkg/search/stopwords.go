@@ -0,0 +1,76 @@
+package search
+
+// based on the snowball stop word lists
+
+var englishStopWords = []string{
+  "a", "about", "above", "after", "again", "against", "all", "am", "an", "and", "any", "are", "as", "at",
+  "be", "because", "been", "before", "being", "below", "between", "both", "but", "by",
+  "can", "cannot", "could",
+  "did", "do", "does", "doing", "down", "during",
+  "each",
+  "few", "for", "from", "further",
+  "had", "has", "have", "having", "he", "her", "here", "hers", "herself", "him", "himself", "his", "how",
+  "i", "if", "in", "into", "is", "it", "its", "itself",
+  "me", "more", "most", "my", "myself",
+  "no", "nor", "not",
+  "of", "off", "on", "once", "only", "or", "other", "ought", "our", "ours", "ourselves", "out", "over", "own",
+  "same", "she", "should", "so", "some", "such",
+  "than", "that", "the", "their", "theirs", "them", "themselves", "then", "there", "these", "they", "this",
+  "those", "through", "to", "too",
+  "under", "until", "up",
+  "very",
+  "was", "we", "were", "what", "when", "where", "which", "while", "who", "whom", "why", "with", "would",
+  "you", "your", "yours", "yourself", "yourselves",
+}
+
+var germanStopWords = []string{
+  "aber", "alle", "allem", "allen", "aller", "alles", "als", "also", "am", "an", "ander", "andere", "anderem",
+  "anderen", "anderer", "anderes", "anderm", "andern", "anderr", "anders", "auch", "auf", "aus",
+  "bei", "bin", "bis", "bist",
+  "da", "damit", "dann", "der", "den", "des", "dem", "die", "das", "dass", "daß", "derselbe", "derselben",
+  "denselben", "desselben", "demselben", "dieselbe", "dieselben", "dasselbe", "dazu", "dein", "deine",
+  "deinem", "deinen", "deiner", "deines", "denn", "derer", "dessen", "dich", "dir", "du", "dies", "diese",
+  "diesem", "diesen", "dieser", "dieses", "doch", "dort", "durch",
+  "ein", "eine", "einem", "einen", "einer", "eines", "einig", "einige", "einigem", "einigen", "einiger",
+  "einiges", "einmal", "er", "ihn", "ihm", "es", "etwas", "euer", "eure", "eurem", "euren", "eurer", "eures",
+  "für",
+  "gegen", "gewesen",
+  "hab", "habe", "haben", "hat", "hatte", "hatten", "hier", "hin", "hinter",
+  "ich", "mich", "mir", "ihr", "ihre", "ihrem", "ihren", "ihrer", "ihres", "euch", "im", "in", "indem", "ins",
+  "ist",
+  "jede", "jedem", "jeden", "jeder", "jedes", "jene", "jenem", "jenen", "jener", "jenes", "jetzt",
+  "kann", "kein", "keine", "keinem", "keinen", "keiner", "keines", "können", "könnte",
+  "machen", "man", "manche", "manchem", "manchen", "mancher", "manches", "mein", "meine", "meinem", "meinen",
+  "meiner", "meines", "mit", "muss", "musste",
+  "nach", "nicht", "nichts", "noch", "nun", "nur",
+  "ob", "oder", "ohne",
+  "sehr", "sein", "seine", "seinem", "seinen", "seiner", "seines", "selbst", "sich", "sie", "ihnen", "sind",
+  "so", "solche", "solchem", "solchen", "solcher", "solches", "soll", "sollte", "sondern", "sonst",
+  "über", "um", "und", "uns", "unsere", "unserem", "unseren", "unser", "unseres", "unter",
+  "viel", "vom", "von", "vor",
+  "während", "war", "waren", "warst", "was", "weg", "weil", "weiter", "welche", "welchem", "welchen",
+  "welcher", "welches", "wenn", "werde", "werden", "wie", "wieder", "will", "wir", "wird", "wirst", "wo",
+  "wollen", "wollte", "würde", "würden",
+  "zu", "zum", "zur", "zwar", "zwischen",
+}
+
+var dutchStopWords = []string{
+  "aan", "al", "alles", "als", "altijd", "andere",
+  "ben", "bij",
+  "daar", "dan", "dat", "de", "der", "deze", "die", "dit", "doch", "doen", "door", "dus",
+  "een", "eens", "en", "er",
+  "ge", "geen", "geweest",
+  "haar", "had", "heb", "hebben", "heeft", "hem", "het", "hier", "hij", "hoe", "hun",
+  "iemand", "iets", "ik", "in", "is",
+  "ja", "je",
+  "kan", "kon", "kunnen",
+  "maar", "me", "meer", "men", "met", "mij", "mijn", "moet",
+  "na", "naar", "niet", "niets", "nog", "nu",
+  "of", "om", "omdat", "onder", "ons", "ook", "op", "over",
+  "reeds",
+  "te", "tegen", "toch", "toen", "tot",
+  "u", "uit", "uw",
+  "van", "veel", "voor",
+  "want", "waren", "was", "wat", "werd", "wezen", "wie", "wil", "worden", "wordt",
+  "zal", "ze", "zelf", "zich", "zij", "zijn", "zo", "zonder", "zou",
+}
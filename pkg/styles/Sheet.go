@@ -16,6 +16,7 @@ type Sheet interface {
   Len() int
   //IsNotLazy() bool
   Write(compr bool, nl string, tab string) (string, error)
+  WriteRules(compr bool, nl string, tab string) (string, error) // without the font-face header
   ExpandNested() (Sheet, error) // expanding a second time does nothing
   ApplyExtensions(root *tree.Root) (*tree.Root, error)
 }
@@ -51,12 +52,21 @@ func (s *SheetData) Len() int {
 }*/
 
 func (s *SheetData) Write(compr bool, nl string, tab string) (string, error) {
-  var b strings.Builder
+  rules, err := s.WriteRules(compr, nl, tab)
+  if err != nil {
+    return "", err
+  }
 
   if directives.MATH_FONT_URL != "" {
-    b.WriteString(writeMathFontFace(directives.MATH_FONT_URL))
+    return writeMathFontFace(directives.MATH_FONT_URL) + rules, nil
   }
 
+  return rules, nil
+}
+
+func (s *SheetData) WriteRules(compr bool, nl string, tab string) (string, error) {
+  var b strings.Builder
+
   for _, r := range s.rules {
     inner, err := r.Write("", nl, tab)
     if err != nil {
@@ -119,11 +129,18 @@ func (s *SheetData) ApplyExtensions(root *tree.Root) (*tree.Root, error) {
 }
 
 func WriteSheetToFile(s Sheet, path string) error {
+  return WriteBundleToFile(s, []string{}, path)
+}
+
+// component styles are appended after the main sheet, so they take precedence
+func WriteBundleToFile(s Sheet, componentStyles []string, path string) error {
   content, err := s.Write(true, patterns.NL, patterns.TAB)
   if err != nil {
     return err
   }
 
+  content += strings.Join(componentStyles, "")
+
   if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
     return errors.New("Error: " + err.Error())
   }
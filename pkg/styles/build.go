@@ -107,4 +107,15 @@ func BuildDictWriteSheet(d *tokens.StringDict, node directives.Node) (string, er
   return sheet.Write(true, patterns.NL, patterns.TAB)
 }
 
+// component styles are collected per document, so no header and no registration
+func BuildDictWriteRules(d *tokens.StringDict) (string, error) {
+  sheet, err := BuildDict(d)
+  if err != nil {
+    return "", err
+  }
+
+  return sheet.WriteRules(true, patterns.NL, patterns.TAB)
+}
+
 var _buildDictWriteSheetRegistered = directives.RegisterBuildStyle(BuildDictWriteSheet)
+var _buildDictWriteRulesRegistered = directives.RegisterBuildComponentStyle(BuildDictWriteRules)
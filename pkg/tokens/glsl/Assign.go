@@ -81,6 +81,12 @@ func (t *Assign) EvalStatement() error {
     return err
   }
 
+  if err := checkLoopIndexUnmodified(t.lhs, t.Context()); err != nil {
+    return err
+  }
+
+  markWritten(t.lhs)
+
   switch lhsExpr := t.lhs.(type) {
   case *VarExpression:
     return lhsExpr.EvalSet(rhsValue, t.Context())
@@ -40,3 +40,18 @@ func (t *Attribute) WriteStatement(usage Usage, indent string, nl string, tab st
 
   return b.String()
 }
+
+func (t *Attribute) ResolveStatementNames(scope Scope) error {
+  if IsES300() {
+    errCtx := t.Context()
+    return errCtx.NewError("Error: attribute not available in #version 300 es (hint: use 'in' instead)")
+  }
+
+  return t.Pointer.ResolveStatementNames(scope)
+}
+
+func (t *Attribute) Collect(attributes map[string]string) error {
+  attributes[t.Name()] = t.typeExpr.WriteExpression()
+
+  return nil
+}
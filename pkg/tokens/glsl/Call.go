@@ -97,6 +97,9 @@ func (t *Call) evalArgs() ([]values.Value, error) {
 			return nil, err
 		}
 
+    // argument roles aren't known here, so varyings passed to functions are assumed to be written (by out arguments)
+    markWritten(a)
+
 		result[i] = val
 	}
 
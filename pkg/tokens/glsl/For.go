@@ -102,6 +102,18 @@ func (t *For) EvalStatement() error {
     return err
   }
 
+  if !IsES300() {
+    if err := t.checkHeader(); err != nil {
+      return err
+    }
+  }
+
+  // while the body is evaluated the loop index is recognized as such via its object
+  if loopIndex := t.loopIndex(); loopIndex != nil {
+    loopIndex.SetObject(t)
+    defer loopIndex.SetObject(nil)
+  }
+
   if err := t.Block.evalStatements(); err != nil {
     return err
   }
@@ -109,6 +121,61 @@ func (t *For) EvalStatement() error {
   return err
 }
 
+func (t *For) loopIndex() Variable {
+  if init, ok := t.init.(*VarStatement); ok {
+    return init.nameExpr.GetVariable()
+  }
+
+  return nil
+}
+
+func (t *For) isLoopIndex(expr_ Expression) bool {
+  expr, ok := expr_.(*VarExpression)
+
+  return ok && expr.GetVariable() == t.loopIndex()
+}
+
+// GLSL ES 1.00 Appendix A.4:
+//  for (int i = <const>; i <relop> <const>; i++ or i--)
+func (t *For) checkHeader() error {
+  init, ok := t.init.(*VarStatement)
+  if !ok || init.length > 0 || init.rhsExpr == nil || !isConstantExpression(init.rhsExpr) {
+    errCtx := t.init.Context()
+    return errCtx.NewError("Error: loop index must be initialized with a constant expression")
+  }
+
+  if typeName := init.typeExpr.WriteExpression(); typeName != "int" && typeName != "float" {
+    errCtx := t.init.Context()
+    return errCtx.NewError("Error: loop index must be int or float, got " + typeName)
+  }
+
+  var a, b Expression = nil, nil
+  switch comp := t.comp.(type) {
+  case *LTOp, *GTOp, *LEOp, *GEOp, *EqOp, *NEOp:
+    a, b = comp.(binaryExpression).operands()
+  }
+
+  if a == nil || !t.isLoopIndex(a) || !isConstantExpression(b) {
+    errCtx := t.comp.Context()
+    return errCtx.NewError("Error: loop condition must compare the loop index to a constant expression")
+  }
+
+  var incr Expression = nil
+  switch st := t.incr.(type) {
+  case *PostIncrOp:
+    incr = st.a
+  case *PostDecrOp:
+    incr = st.a
+  }
+
+  if incr == nil || !t.isLoopIndex(incr) {
+    errCtx := t.incr.Context()
+    return errCtx.NewError("Error: loop expression must increment or decrement the loop index")
+  }
+
+  return nil
+}
+
 func (t *For) ResolveStatementActivity(usage Usage) error {
 
   if err := t.Block.ResolveStatementActivity(usage); err != nil {
@@ -0,0 +1,247 @@
+package glsl
+
+import (
+  "strings"
+
+	"github.com/computeportal/wtsuite/pkg/tokens/context"
+	"github.com/computeportal/wtsuite/pkg/tokens/glsl/values"
+)
+
+// GLSL ES 3.00 replacement of attribute and varying
+//  vertex 'in' -> attribute
+//  vertex 'out' and fragment 'in' -> varying
+//  fragment 'out' -> render target
+type InOut struct {
+  layout *Layout // can be nil
+  interp string // "flat", "smooth" or empty
+  precType PrecisionType
+  written bool // only used by vertex 'out'
+  Pointer
+}
+
+type In struct {
+  InOut
+}
+
+type Out struct {
+  InOut
+}
+
+func newInOut(layout *Layout, interp string, precType PrecisionType, typeExpr *TypeExpression, name string, ctx context.Context) InOut {
+  return InOut{layout, interp, precType, false, newPointer(typeExpr, NewVarExpression(name, ctx), -1, ctx)}
+}
+
+func NewIn(layout *Layout, interp string, precType PrecisionType, typeExpr *TypeExpression, name string, ctx context.Context) *In {
+  return &In{newInOut(layout, interp, precType, typeExpr, name, ctx)}
+}
+
+func NewOut(layout *Layout, interp string, precType PrecisionType, typeExpr *TypeExpression, name string, ctx context.Context) *Out {
+  return &Out{newInOut(layout, interp, precType, typeExpr, name, ctx)}
+}
+
+func (t *InOut) dump(indent string, qualifier string) string {
+  var b strings.Builder
+
+  b.WriteString(indent)
+  b.WriteString(qualifier)
+  b.WriteString("(")
+  if t.interp != "" {
+    b.WriteString(t.interp)
+    b.WriteString(" ")
+  }
+  b.WriteString(PrecisionTypeToString(t.precType))
+  b.WriteString(" ")
+  b.WriteString(t.typeExpr.Dump(""))
+  b.WriteString(" ")
+  b.WriteString(t.nameExpr.Dump(""))
+  b.WriteString("\n")
+
+  return b.String()
+}
+
+func (t *In) Dump(indent string) string {
+  return t.dump(indent, "In")
+}
+
+func (t *Out) Dump(indent string) string {
+  return t.dump(indent, "Out")
+}
+
+func (t *InOut) writeStatement(indent string, qualifier string) string {
+  var b strings.Builder
+
+  b.WriteString(indent)
+  b.WriteString(t.layout.WriteQualifier())
+  if t.interp != "" {
+    b.WriteString(t.interp)
+    b.WriteString(" ")
+  }
+  b.WriteString(qualifier)
+  b.WriteString(" ")
+  if t.precType != DEFAULTP {
+    b.WriteString(PrecisionTypeToString(t.precType))
+    b.WriteString(" ")
+  }
+  b.WriteString(t.typeExpr.WriteExpression())
+  b.WriteString(" ")
+  b.WriteString(t.nameExpr.WriteExpression())
+  b.WriteString(";")
+
+  return b.String()
+}
+
+func (t *In) WriteStatement(usage Usage, indent string, nl string, tab string) string {
+  return t.writeStatement(indent, "in")
+}
+
+func (t *Out) WriteStatement(usage Usage, indent string, nl string, tab string) string {
+  return t.writeStatement(indent, "out")
+}
+
+func (t *In) isVarying() bool {
+  return TARGET == "fragment"
+}
+
+func (t *Out) isVarying() bool {
+  return TARGET == "vertex"
+}
+
+func (t *InOut) resolveStatementNames(scope Scope, qualifier string, isVarying bool) error {
+  if !IsES300() {
+    errCtx := t.Context()
+    return errCtx.NewError("Error: '" + qualifier + "' declarations require #version 300 es")
+  }
+
+  if isVarying {
+    // locations of varyings aren't supported by GLSL ES 3.00
+    if err := t.layout.Check(map[string]bool{}); err != nil {
+      return err
+    }
+  } else {
+    if t.interp != "" {
+      errCtx := t.Context()
+      return errCtx.NewError("Error: " + t.interp + " only applies to values passed from vertex to fragment shader")
+    }
+
+    if err := t.layout.Check(map[string]bool{"location": true}); err != nil {
+      return err
+    }
+  }
+
+  if err := t.Pointer.ResolveStatementNames(scope); err != nil {
+    return err
+  }
+
+  t.GetVariable().SetObject(t)
+
+  return nil
+}
+
+func (t *In) ResolveStatementNames(scope Scope) error {
+  return t.resolveStatementNames(scope, "in", t.isVarying())
+}
+
+func (t *Out) ResolveStatementNames(scope Scope) error {
+  return t.resolveStatementNames(scope, "out", t.isVarying())
+}
+
+func (t *InOut) evalStatement(isVarying bool) error {
+  if err := t.Pointer.EvalStatement(); err != nil {
+    return err
+  }
+
+  variable := t.GetVariable()
+
+  val := variable.GetValue()
+
+  if !values.IsSimple(val) {
+    errCtx := t.Context()
+    return errCtx.NewError("Error: expected simple type, got " + val.TypeName())
+  }
+
+  typeName := val.TypeName()
+  if typeName == "bool" || strings.HasPrefix(typeName, "bvec") {
+    errCtx := t.Context()
+    return errCtx.NewError("Error: boolean types can't be used for shader inputs or outputs")
+  }
+
+  isIntegral := !(typeName == "float" || strings.HasPrefix(typeName, "vec"))
+  if isVarying && isIntegral && t.interp != "flat" {
+    errCtx := t.Context()
+    return errCtx.NewError("Error: integer values passed from vertex to fragment shader must be flat")
+  }
+
+  return nil
+}
+
+func (t *In) EvalStatement() error {
+  return t.evalStatement(t.isVarying())
+}
+
+func (t *Out) EvalStatement() error {
+  return t.evalStatement(t.isVarying())
+}
+
+// outputs can be set without usage
+func (t *Out) ResolveStatementActivity(usage Usage) error {
+  return nil
+}
+
+func (t *InOut) markWritten() {
+  t.written = true
+}
+
+func (t *InOut) collect(varyings map[string]string, isVarying bool) error {
+  if isVarying {
+    typeName := t.typeExpr.WriteExpression()
+    if t.interp != "" {
+      typeName = t.interp + " " + typeName
+    }
+
+    varyings[t.Name()] = typeName
+  }
+
+  return nil
+}
+
+func (t *In) Collect(varyings map[string]string) error {
+  return t.collect(varyings, t.isVarying())
+}
+
+func (t *Out) Collect(varyings map[string]string) error {
+  return t.collect(varyings, t.isVarying())
+}
+
+func (t *Out) CollectWritten(written map[string]bool) error {
+  if t.isVarying() && t.written {
+    written[t.Name()] = true
+  }
+
+  return nil
+}
+
+// vertex 'in' declarations are the attributes
+func (t *In) CollectAttribute(attributes map[string]string) error {
+  if !t.isVarying() {
+    attributes[t.Name()] = t.typeExpr.WriteExpression()
+  }
+
+  return nil
+}
+
+// vertex 'in' locations are registered in ins, fragment 'out' locations in outs
+func (t *In) CollectLocations(ins *LayoutLocations, outs *LayoutLocations) error {
+  if t.isVarying() {
+    return nil
+  }
+
+  return ins.Register(t.layout.Location(), t.Context())
+}
+
+func (t *Out) CollectLocations(ins *LayoutLocations, outs *LayoutLocations) error {
+  if t.isVarying() {
+    return nil
+  }
+
+  return outs.Register(t.layout.Location(), t.Context())
+}
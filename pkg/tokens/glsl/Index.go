@@ -63,13 +63,9 @@ func (t *Index) EvalExpression() (values.Value, error) {
 		return nil, err
 	}
 
-	indexValue_, err := t.index.EvalExpression()
-	if err != nil {
-		return nil, err
-	}
-
-  indexValue, err := values.AssertLiteralInt(indexValue_)
+  indexValue, err := t.evalIndex(containerValue, false)
   if err != nil {
+    context.AppendString(err, "Hint: use getIndex(arr, i)\n")
     return nil, err
   }
 
@@ -82,12 +78,7 @@ func (t *Index) EvalSet(rhsValue values.Value, ctx context.Context) error {
 		return err
 	}
 
-	indexValue_, err := t.index.EvalExpression()
-	if err != nil {
-		return err
-	}
-
-  indexValue, err := values.AssertLiteralInt(indexValue_)
+  indexValue, err := t.evalIndex(containerValue, true)
   if err != nil {
     context.AppendString(err, "Hint: use setIndex(arr, i, x)\n")
     return err
@@ -96,6 +87,58 @@ func (t *Index) EvalSet(rhsValue values.Value, ctx context.Context) error {
   return containerValue.SetIndex(indexValue, rhsValue, t.Context())
 }
 
+// non-literal indices can't be range checked, so a dummy literal is returned instead
+// GLSL ES 1.00 Appendix A.5 only allows constant-index-expressions (constants and loop indices), except for reading uniforms in the vertex shader
+// GLSL ES 3.00 allows any index, except for samplers
+func (t *Index) evalIndex(containerValue values.Value, isSet bool) (*values.LiteralInt, error) {
+  indexValue, err := t.index.EvalExpression()
+  if err != nil {
+    return nil, err
+  }
+
+  if literalIndex, err := values.AssertLiteralInt(indexValue); err == nil {
+    return literalIndex, nil
+  }
+
+  if _, err := values.AssertInt(indexValue); err != nil {
+    return nil, err
+  }
+
+  dummyIndex := values.NewLiteralInt(0, t.index.Context())
+
+  contentValue, err := containerValue.GetIndex(dummyIndex, t.Context())
+  if err != nil {
+    return nil, err
+  }
+
+  isSampler := values.IsSampler(contentValue)
+
+  ok := false
+  switch {
+  case IsES300() && isSampler:
+    ok = isConstantExpression(t.index)
+  case IsES300():
+    ok = true
+  case isConstantIndexExpression(t.index):
+    ok = true
+  case TARGET == "vertex" && !isSampler && !isSet:
+    if variable := rootVariable(t.container); variable != nil {
+      _, ok = variable.GetObject().(*Uniform)
+    }
+  }
+
+  if !ok {
+    errCtx := t.index.Context()
+    if IsES300() {
+      return nil, errCtx.NewError("Error: sampler index must be a constant expression")
+    } else {
+      return nil, errCtx.NewError("Error: index must be a constant expression or loop index (except for uniforms in the vertex shader)")
+    }
+  }
+
+  return dummyIndex, nil
+}
+
 func (t *Index) ResolveExpressionActivity(usage Usage) error {
   if err := t.index.ResolveExpressionActivity(usage); err != nil {
     return err
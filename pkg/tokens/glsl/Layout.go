@@ -0,0 +1,154 @@
+package glsl
+
+import (
+  "strconv"
+  "strings"
+
+	"github.com/computeportal/wtsuite/pkg/tokens/context"
+)
+
+// eg. layout(location = 0) or layout(std140)
+type LayoutEntry struct {
+  name string
+  value int // -1 if no value is specified
+  ctx context.Context
+}
+
+type Layout struct {
+  entries []*LayoutEntry
+  TokenData
+}
+
+func NewLayoutEntry(name string, value int, ctx context.Context) *LayoutEntry {
+  return &LayoutEntry{name, value, ctx}
+}
+
+func NewLayout(entries []*LayoutEntry, ctx context.Context) *Layout {
+  return &Layout{entries, newTokenData(ctx)}
+}
+
+func (t *LayoutEntry) Context() context.Context {
+  return t.ctx
+}
+
+func (t *LayoutEntry) write() string {
+  if t.value < 0 {
+    return t.name
+  } else {
+    return t.name + "=" + strconv.Itoa(t.value)
+  }
+}
+
+func (t *Layout) Dump(indent string) string {
+  return indent + "Layout(" + t.write() + ")\n"
+}
+
+func (t *Layout) write() string {
+  var b strings.Builder
+
+  for i, entry := range t.entries {
+    b.WriteString(entry.write())
+
+    if i < len(t.entries) - 1 {
+      b.WriteString(",")
+    }
+  }
+
+  return b.String()
+}
+
+// nil layout writes nothing
+func (t *Layout) WriteQualifier() string {
+  if t == nil {
+    return ""
+  }
+
+  return "layout(" + t.write() + ") "
+}
+
+// returns -1 if not specified
+func (t *Layout) Location() int {
+  if t == nil {
+    return -1
+  }
+
+  for _, entry := range t.entries {
+    if entry.name == "location" {
+      return entry.value
+    }
+  }
+
+  return -1
+}
+
+// allowed maps entry names to whether or not they require a value
+func (t *Layout) Check(allowed map[string]bool) error {
+  if t == nil {
+    return nil
+  }
+
+  for i, entry := range t.entries {
+    requiresValue, ok := allowed[entry.name]
+    if !ok {
+      errCtx := entry.Context()
+      return errCtx.NewError("Error: layout qualifier " + entry.name + " not allowed here")
+    }
+
+    if requiresValue && entry.value < 0 {
+      errCtx := entry.Context()
+      return errCtx.NewError("Error: layout qualifier " + entry.name + " requires a value")
+    } else if !requiresValue && entry.value >= 0 {
+      errCtx := entry.Context()
+      return errCtx.NewError("Error: layout qualifier " + entry.name + " can't have a value")
+    }
+
+    for j := 0; j < i; j++ {
+      if t.entries[j].name == entry.name {
+        errCtx := entry.Context()
+        err := errCtx.NewError("Error: duplicate layout qualifier")
+        err.AppendContextString("Info: also declared here", t.entries[j].Context())
+        return err
+      }
+    }
+  }
+
+  return nil
+}
+
+// collected across the whole bundle, so that locations can be checked for uniqueness
+type LayoutLocations struct {
+  located map[int]context.Context
+  unlocated []context.Context
+}
+
+func NewLayoutLocations() *LayoutLocations {
+  return &LayoutLocations{make(map[int]context.Context), make([]context.Context, 0)}
+}
+
+func (ll *LayoutLocations) Register(location int, ctx context.Context) error {
+  if location < 0 {
+    ll.unlocated = append(ll.unlocated, ctx)
+    return nil
+  }
+
+  if prev, ok := ll.located[location]; ok {
+    errCtx := ctx
+    err := errCtx.NewError("Error: location " + strconv.Itoa(location) + " already used")
+    err.AppendContextString("Info: also used here", prev)
+    return err
+  }
+
+  ll.located[location] = ctx
+
+  return nil
+}
+
+// multiple render targets must all have explicit locations
+func (ll *LayoutLocations) CheckRenderTargets() error {
+  if len(ll.located) + len(ll.unlocated) > 1 && len(ll.unlocated) > 0 {
+    errCtx := ll.unlocated[0]
+    return errCtx.NewError("Error: multiple outputs, each must specify layout(location=...)")
+  }
+
+  return nil
+}
@@ -199,7 +199,16 @@ func (m *ModuleData) CollectVersion(version *Word) (*Word, error) {
 
 func (m *ModuleData) CollectVaryings(varyings map[string]string) error {
   for _, st_ := range m.statements {
-    if st, ok := st_.(*Varying); ok {
+    switch st := st_.(type) {
+    case *Varying:
+      if err := st.Collect(varyings); err != nil {
+        return err
+      }
+    case *In:
+      if err := st.Collect(varyings); err != nil {
+        return err
+      }
+    case *Out:
       if err := st.Collect(varyings); err != nil {
         return err
       }
@@ -209,6 +218,156 @@ func (m *ModuleData) CollectVaryings(varyings map[string]string) error {
   return nil
 }
 
+func (m *ModuleData) CollectUniforms(uniforms map[string]string) error {
+  for _, st_ := range m.statements {
+    if st, ok := st_.(*Uniform); ok {
+      if err := st.Collect(uniforms); err != nil {
+        return err
+      }
+    }
+  }
+
+  return nil
+}
+
+func (m *ModuleData) CollectAttributes(attributes map[string]string) error {
+  for _, st_ := range m.statements {
+    switch st := st_.(type) {
+    case *Attribute:
+      if err := st.Collect(attributes); err != nil {
+        return err
+      }
+    case *In:
+      if err := st.CollectAttribute(attributes); err != nil {
+        return err
+      }
+    }
+  }
+
+  return nil
+}
+
+// declarations of the varyings, for errors concerning both shaders of a pair
+func (m *ModuleData) CollectVaryingContexts(ctxs map[string]context.Context) {
+  for _, st_ := range m.statements {
+    switch st := st_.(type) {
+    case *Varying:
+      ctxs[st.Name()] = st.Context()
+    case *In:
+      if st.isVarying() {
+        ctxs[st.Name()] = st.Context()
+      }
+    case *Out:
+      if st.isVarying() {
+        ctxs[st.Name()] = st.Context()
+      }
+    }
+  }
+}
+
+// varyings that are assigned to (vertex shader only)
+func (m *ModuleData) CollectWrittenVaryings(written map[string]bool) error {
+  for _, st_ := range m.statements {
+    switch st := st_.(type) {
+    case *Varying:
+      if err := st.CollectWritten(written); err != nil {
+        return err
+      }
+    case *Out:
+      if err := st.CollectWritten(written); err != nil {
+        return err
+      }
+    }
+  }
+
+  return nil
+}
+
+// eg. precision mediump float;
+func (m *ModuleData) HasDefaultPrecision(typeName string) bool {
+  for _, st_ := range m.statements {
+    if st, ok := st_.(*Precision); ok && st.typeExpr.WriteExpression() == typeName {
+      return true
+    }
+  }
+
+  return false
+}
+
+// uniform blocks aren't part of the default uniform block, so aren't counted
+func (m *ModuleData) PackUniforms(p *VectorPacking) error {
+  for _, st_ := range m.statements {
+    if st, ok := st_.(*Uniform); ok {
+      if err := p.Add(st.GetVariable().GetValue(), st.Context()); err != nil {
+        return err
+      }
+    }
+  }
+
+  return nil
+}
+
+func (m *ModuleData) PackVaryings(p *VectorPacking) error {
+  for _, st_ := range m.statements {
+    switch st := st_.(type) {
+    case *Varying:
+      if err := p.Add(st.GetVariable().GetValue(), st.Context()); err != nil {
+        return err
+      }
+    case *In:
+      if st.isVarying() {
+        if err := p.Add(st.GetVariable().GetValue(), st.Context()); err != nil {
+          return err
+        }
+      }
+    case *Out:
+      if st.isVarying() {
+        if err := p.Add(st.GetVariable().GetValue(), st.Context()); err != nil {
+          return err
+        }
+      }
+    }
+  }
+
+  return nil
+}
+
+func (m *ModuleData) PackAttributes(p *VectorPacking) error {
+  for _, st_ := range m.statements {
+    switch st := st_.(type) {
+    case *Attribute:
+      if err := p.Add(st.GetVariable().GetValue(), st.Context()); err != nil {
+        return err
+      }
+    case *In:
+      if !st.isVarying() {
+        if err := p.Add(st.GetVariable().GetValue(), st.Context()); err != nil {
+          return err
+        }
+      }
+    }
+  }
+
+  return nil
+}
+
+func (m *ModuleData) CollectLocations(ins *LayoutLocations, outs *LayoutLocations) error {
+  for _, st_ := range m.statements {
+    switch st := st_.(type) {
+    case *In:
+      if err := st.CollectLocations(ins, outs); err != nil {
+        return err
+      }
+    case *Out:
+      if err := st.CollectLocations(ins, outs); err != nil {
+        return err
+      }
+    }
+  }
+
+  return nil
+}
+
 func (m *ModuleData) FindExportedConst(name string) *Const {
   if exported, ok := m.exported[name]; ok {
     variable := exported.v
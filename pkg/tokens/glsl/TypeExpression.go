@@ -50,12 +50,9 @@ func (t *TypeExpression) Instantiate(ctx context.Context) (values.Value, error)
     return nil, err
   }
 
-  if values.IsSampler2D(val) {
+  if values.IsSampler(val) {
     errCtx := t.Context()
-    return nil, errCtx.NewError("Error: sampler2D only available as uniform")
-  } else if values.IsSamplerCube(val) {
-    errCtx := t.Context()
-    return nil, errCtx.NewError("Error: samplerCube only available as uniform")
+    return nil, errCtx.NewError("Error: " + val.TypeName() + " only available as uniform")
   }
 
   return val, nil
@@ -57,6 +57,17 @@ func (t *Uniform) WriteStatement(usage Usage, indent string, nl string, tab stri
   return b.String()
 }
 
+// the object is used to allow dynamic indexing in the vertex shader
+func (t *Uniform) ResolveStatementNames(scope Scope) error {
+  if err := t.Pointer.ResolveStatementNames(scope); err != nil {
+    return err
+  }
+
+  t.GetVariable().SetObject(t)
+
+  return nil
+}
+
 // different from Pointer.EvalStatement(), because different types are allowed
 func (t *Uniform) EvalStatement() error {
   variable := t.nameExpr.GetVariable()
@@ -74,3 +85,14 @@ func (t *Uniform) EvalStatement() error {
 
   return nil
 }
+
+// struct uniforms are skipped, because they don't have a single setter
+func (t *Uniform) Collect(uniforms map[string]string) error {
+  typeName := t.GetVariable().GetValue().TypeName()
+
+  if !strings.HasPrefix(typeName, "struct ") {
+    uniforms[t.Name()] = typeName
+  }
+
+  return nil
+}
@@ -0,0 +1,189 @@
+package glsl
+
+import (
+  "strings"
+
+	"github.com/computeportal/wtsuite/pkg/tokens/context"
+	"github.com/computeportal/wtsuite/pkg/tokens/glsl/values"
+)
+
+// GLSL ES 3.00
+//  uniform Name {...}; -> entries are available as global variables
+//  uniform Name {...} instance; -> entries are available as members of instance
+type UniformBlock struct {
+  layout *Layout // can be nil
+  name string // block name is used by js (getUniformBlockIndex), so it is never renamed
+  entries []*StructEntry
+  instanceExpr *VarExpression // can be nil
+  TokenData
+}
+
+func NewUniformBlock(layout *Layout, name string, entries []*StructEntry, instanceExpr *VarExpression, ctx context.Context) (*UniformBlock, error) {
+  for i, entry := range entries {
+    for j := 0; j < i; j++ {
+      if entries[j].Name() == entry.Name() {
+        errCtx := entry.Context()
+        err := errCtx.NewError("Error: duplicate uniform block entry name")
+        err.AppendContextString("Info: other declared here", entries[j].Context())
+        return nil, err
+      }
+    }
+  }
+
+  return &UniformBlock{layout, name, entries, instanceExpr, newTokenData(ctx)}, nil
+}
+
+func (t *UniformBlock) Name() string {
+  return t.name
+}
+
+func (t *UniformBlock) Dump(indent string) string {
+  var b strings.Builder
+
+  b.WriteString(indent)
+  b.WriteString("UniformBlock(")
+  b.WriteString(t.name)
+  b.WriteString(")\n")
+
+  for _, entry := range t.entries {
+    b.WriteString(entry.Dump(indent + "  "))
+  }
+
+  if t.instanceExpr != nil {
+    b.WriteString(t.instanceExpr.Dump(indent + "  "))
+  }
+
+  return b.String()
+}
+
+func (t *UniformBlock) WriteStatement(usage Usage, indent string, nl string, tab string) string {
+  var b strings.Builder
+
+  b.WriteString(indent)
+  b.WriteString(t.layout.WriteQualifier())
+  b.WriteString("uniform ")
+  b.WriteString(t.name)
+  b.WriteString("{")
+  b.WriteString(nl)
+
+  for _, entry := range t.entries {
+    b.WriteString(indent + tab)
+    b.WriteString(entry.writeEntry())
+    b.WriteString(nl)
+  }
+
+  b.WriteString(indent)
+  b.WriteString("}")
+  if t.instanceExpr != nil {
+    b.WriteString(t.instanceExpr.WriteExpression())
+  }
+  b.WriteString(";")
+
+  return b.String()
+}
+
+func (t *UniformBlock) ResolveStatementNames(scope Scope) error {
+  if !IsES300() {
+    errCtx := t.Context()
+    return errCtx.NewError("Error: uniform blocks require #version 300 es")
+  }
+
+  if err := t.layout.Check(map[string]bool{
+    "shared": false,
+    "packed": false,
+    "std140": false,
+    "row_major": false,
+    "column_major": false,
+  }); err != nil {
+    return err
+  }
+
+  for _, entry := range t.entries {
+    if err := entry.ResolveNames(scope); err != nil {
+      return err
+    }
+
+    if t.instanceExpr == nil {
+      if err := scope.SetVariable(entry.Name(), entry.nameExpr.GetVariable()); err != nil {
+        return err
+      }
+    }
+  }
+
+  if t.instanceExpr != nil {
+    if err := scope.SetVariable(t.instanceExpr.Name(), t.instanceExpr.GetVariable()); err != nil {
+      return err
+    }
+  }
+
+  return nil
+}
+
+func (t *UniformBlock) EvalStatement() error {
+  for _, entry := range t.entries {
+    val, err := entry.Instantiate(entry.Context())
+    if err != nil {
+      return err
+    }
+
+    if t.instanceExpr == nil {
+      variable := entry.nameExpr.GetVariable()
+      variable.SetConstant()
+      variable.SetValue(val)
+    }
+  }
+
+  if t.instanceExpr != nil {
+    t.instanceExpr.GetVariable().SetValue(values.NewStruct(t, t.Context()))
+  }
+
+  return nil
+}
+
+func (t *UniformBlock) CheckConstruction(args []values.Value, ctx context.Context) error {
+  return ctx.NewError("Error: uniform block " + t.name + " can't be constructed")
+}
+
+func (t *UniformBlock) GetMember(key string, ctx context.Context) (values.Value, error) {
+  for _, entry := range t.entries {
+    if entry.Name() == key {
+      return entry.Instantiate(ctx)
+    }
+  }
+
+  return nil, ctx.NewError("Error: " + t.name + "." + key + " not found")
+}
+
+func (t *UniformBlock) SetMember(key string, arg values.Value, ctx context.Context) error {
+  return ctx.NewError("Error: uniform block entries are read-only")
+}
+
+func (t *UniformBlock) ResolveStatementActivity(usage Usage) error {
+  if t.instanceExpr != nil {
+    return usage.Rereference(t.instanceExpr.GetVariable(), t.Context())
+  }
+
+  // the layout of the block must match the buffer, so unused entries can't be removed
+  for _, entry := range t.entries {
+    if err := usage.Use(entry.nameExpr.GetVariable(), entry.Context()); err != nil {
+      return err
+    }
+  }
+
+  return nil
+}
+
+// entry names are part of the block interface, so are kept
+func (t *UniformBlock) UniqueStatementNames(ns Namespace) error {
+  if t.instanceExpr != nil {
+    return ns.OrigName(t.instanceExpr.GetVariable())
+  }
+
+  for _, entry := range t.entries {
+    if err := ns.OrigName(entry.nameExpr.GetVariable()); err != nil {
+      return err
+    }
+  }
+
+  return nil
+}
@@ -9,11 +9,12 @@ import (
 
 type Varying struct {
   precType PrecisionType
+  written bool // assigned to in the vertex shader
   Pointer
 }
 
 func NewVarying(precType PrecisionType, typeExpr *TypeExpression, name string, ctx context.Context) *Varying {
-  return &Varying{precType, newPointer(typeExpr, NewVarExpression(name, ctx), -1, ctx)}
+  return &Varying{precType, false, newPointer(typeExpr, NewVarExpression(name, ctx), -1, ctx)}
 }
 
 func (t *Varying) Dump(indent string) string {
@@ -49,6 +50,21 @@ func (t *Varying) WriteStatement(usage Usage, indent string, nl string, tab stri
   return b.String()
 }
 
+func (t *Varying) ResolveStatementNames(scope Scope) error {
+  if IsES300() {
+    errCtx := t.Context()
+    return errCtx.NewError("Error: varying not available in #version 300 es (hint: use 'out' in vertex shader and 'in' in fragment shader instead)")
+  }
+
+  if err := t.Pointer.ResolveStatementNames(scope); err != nil {
+    return err
+  }
+
+  t.GetVariable().SetObject(t)
+
+  return nil
+}
+
 func (t *Varying) EvalStatement() error {
   if err := t.Pointer.EvalStatement(); err != nil {
     return err
@@ -75,9 +91,21 @@ func (t *Varying) ResolveStatementActivity(usage Usage) error {
   }
 }
 
+func (t *Varying) markWritten() {
+  t.written = true
+}
+
 func (t *Varying) Collect(varyings map[string]string) error {
   // expecting only simple types
   varyings[t.Name()] = t.typeExpr.WriteExpression()
 
   return nil
 }
+
+func (t *Varying) CollectWritten(written map[string]bool) error {
+  if t.written {
+    written[t.Name()] = true
+  }
+
+  return nil
+}
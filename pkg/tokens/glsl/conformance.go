@@ -0,0 +1,215 @@
+package glsl
+
+import (
+  "strconv"
+
+	"github.com/computeportal/wtsuite/pkg/tokens/context"
+	"github.com/computeportal/wtsuite/pkg/tokens/glsl/values"
+)
+
+// GLSL ES 1.00 Appendix A restrictions, and the minimum resource limits every implementation guarantees
+
+type binaryExpression interface {
+  operands() (Expression, Expression)
+}
+
+type unaryExpression interface {
+  operand() Expression
+}
+
+func (t *BinaryOp) operands() (Expression, Expression) {
+  return t.a, t.b
+}
+
+func (t *UnaryOp) operand() Expression {
+  return t.a
+}
+
+// literals, consts, and operators applied to them
+func isConstantExpression(expr Expression) bool {
+  return isConstantExpressionOrLoopIndex(expr, false)
+}
+
+// loop indices are also allowed as indices (GLSL ES 1.00 Appendix A.5)
+func isConstantIndexExpression(expr Expression) bool {
+  return isConstantExpressionOrLoopIndex(expr, true)
+}
+
+func isConstantExpressionOrLoopIndex(expr_ Expression, allowLoopIndex bool) bool {
+  isConstantVariable := func(variable Variable) bool {
+    switch variable.GetObject().(type) {
+    case *Const:
+      return true
+    case *For:
+      return allowLoopIndex
+    default:
+      return false
+    }
+  }
+
+  switch expr := expr_.(type) {
+  case *LiteralInt, *LiteralFloat, *LiteralBool:
+    return true
+  case *VarExpression:
+    return isConstantVariable(expr.GetVariable())
+  case *Member:
+    if pkgMember, err := expr.GetPackageMember(); err == nil && pkgMember != nil {
+      return isConstantVariable(pkgMember)
+    }
+
+    return false
+  case *Parens:
+    return isConstantExpressionOrLoopIndex(expr.expr, allowLoopIndex)
+  case binaryExpression:
+    a, b := expr.operands()
+    return isConstantExpressionOrLoopIndex(a, allowLoopIndex) && isConstantExpressionOrLoopIndex(b, allowLoopIndex)
+  case unaryExpression:
+    return isConstantExpressionOrLoopIndex(expr.operand(), allowLoopIndex)
+  default:
+    return false
+  }
+}
+
+// variable that is modified when expr is assigned to, nil if expr isn't assignable
+func rootVariable(expr_ Expression) Variable {
+  switch expr := expr_.(type) {
+  case *VarExpression:
+    return expr.GetVariable()
+  case *Member:
+    if pkgMember, err := expr.GetPackageMember(); err == nil && pkgMember != nil {
+      return pkgMember
+    }
+
+    return rootVariable(expr.object)
+  case *Index:
+    return rootVariable(expr.container)
+  default:
+    return nil
+  }
+}
+
+// GLSL ES 1.00 Appendix A.4
+func checkLoopIndexUnmodified(expr Expression, ctx context.Context) error {
+  if IsES300() {
+    return nil
+  }
+
+  if variable := rootVariable(expr); variable != nil {
+    if _, ok := variable.GetObject().(*For); ok {
+      return ctx.NewError("Error: loop index can't be modified inside the loop body")
+    }
+  }
+
+  return nil
+}
+
+// varyings that are assigned to in the vertex shader
+type writable interface {
+  markWritten()
+}
+
+func markWritten(expr Expression) {
+  if variable := rootVariable(expr); variable != nil {
+    if w, ok := variable.GetObject().(writable); ok {
+      w.markWritten()
+    }
+  }
+}
+
+// minimum guaranteed values of gl_MaxVertexAttribs etc.
+type ResourceLimits struct {
+  VertexAttribs int
+  VertexUniformVectors int
+  FragmentUniformVectors int
+  VaryingVectors int
+}
+
+func MinResourceLimits() ResourceLimits {
+  if IsES300() {
+    return ResourceLimits{16, 256, 224, 15}
+  } else {
+    return ResourceLimits{8, 128, 16, 8}
+  }
+}
+
+// counts the number of 4-component vectors used by a set of variables
+// packed like GLSL ES 1.00 Appendix A.7, but without reordering columns, so that the result is a slight overestimate at most
+type VectorPacking struct {
+  limitName string // eg. "gl_MaxVaryingVectors"
+  limit int
+  packed bool // attributes aren't packed, each occupies whole vectors
+  rows4 int
+  rows3 int
+  rows2 int
+  n1 int
+}
+
+func NewVectorPacking(limitName string, limit int, packed bool) *VectorPacking {
+  return &VectorPacking{limitName, limit, packed, 0, 0, 0, 0}
+}
+
+// samplers don't count
+func (p *VectorPacking) add(val_ values.Value, n int) {
+  switch val := values.UnpackContextValue(val_).(type) {
+  case *values.Array:
+    p.add(val.Content(), n*val.Length())
+  case *values.Struct:
+    if str, ok := val.GetStructable().(*Struct); ok {
+      for _, entry := range str.entries {
+        if entryVal, err := entry.Instantiate(entry.Context()); err == nil {
+          p.add(entryVal, n)
+        }
+      }
+    }
+  case *values.Mat:
+    switch val.Length() {
+    case 2:
+      p.rows2 += 2*n
+    case 3:
+      p.rows3 += 3*n
+    default:
+      p.rows4 += 4*n
+    }
+  default:
+    if values.IsSimple(val) {
+      switch val.Length() {
+      case 2:
+        p.rows2 += n
+      case 3:
+        p.rows3 += n
+      case 4:
+        p.rows4 += n
+      default:
+        p.n1 += n
+      }
+    }
+  }
+}
+
+func (p *VectorPacking) Rows() int {
+  if !p.packed {
+    return p.rows4 + p.rows3 + p.rows2 + p.n1
+  }
+
+  rows := p.rows4 + p.rows3 + (p.rows2 + 1)/2
+
+  // floats first fill the free column of vec3 rows, and the free half of an unpaired vec2 row
+  free := p.rows3 + 2*(p.rows2 % 2)
+  if p.n1 > free {
+    rows += (p.n1 - free + 3)/4
+  }
+
+  return rows
+}
+
+// the error refers to the declaration that exceeds the limit
+func (p *VectorPacking) Add(val values.Value, ctx context.Context) error {
+  p.add(val, 1)
+
+  if rows := p.Rows(); rows > p.limit {
+    return ctx.NewError("Error: requires " + strconv.Itoa(rows) + " vectors, which exceeds the minimum guaranteed " +
+      p.limitName + " (" + strconv.Itoa(p.limit) + ")")
+  }
+
+  return nil
+}
@@ -6,6 +6,13 @@ import (
 )
 
 var TARGET = "vertex"
+
+// set by the shader bundle, based on the #version directive
+var VERSION = "100 es"
+
+func IsES300() bool {
+  return VERSION == "300 es"
+}
  
 func handleRegistrationError(err error) {
   if err != nil {
@@ -47,6 +54,10 @@ func FillCoreScope(scope Scope) {
   registerValue(scope, "ivec3", true, values.NewVecType("int", 3, ctx))
   registerValue(scope, "ivec4", true, values.NewVecType("int", 4, ctx))
 
+  registerValue(scope, "mat2", true, values.NewMatType(2, ctx))
+  registerValue(scope, "mat3", true, values.NewMatType(3, ctx))
+  registerValue(scope, "mat4", true, values.NewMatType(4, ctx))
+
 
   // builtin functions
   registerValue(scope, "abs"        , true, values.NewOneToOneFunction(ctx))
@@ -95,12 +106,40 @@ func FillCoreScope(scope Scope) {
   registerValue(scope, "tan"        , true, values.NewOneToOneFunction(ctx))
 }
 
+// GLSL ES 3.00 types and builtins that are available in both vertex and fragment shaders
+func FillES300Scope(scope Scope) {
+  ctx := context.NewDummyContext()
+
+  registerValue(scope, "uint", true, values.NewScalarType("uint", ctx))
+
+  registerValue(scope, "uvec2", true, values.NewVecType("uint", 2, ctx))
+  registerValue(scope, "uvec3", true, values.NewVecType("uint", 3, ctx))
+  registerValue(scope, "uvec4", true, values.NewVecType("uint", 4, ctx))
+
+  registerValue(scope, "sampler2D", true, values.NewSampler2DType(ctx))
+  registerValue(scope, "samplerCube", true, values.NewSamplerCubeType(ctx))
+  registerValue(scope, "sampler3D", true, values.NewSampler3DType(ctx))
+  registerValue(scope, "sampler2DArray", true, values.NewSampler2DArrayType(ctx))
+
+  registerValue(scope, "texture"    , true, values.NewTextureFunction(ctx))
+  registerValue(scope, "textureLod" , true, values.NewTextureLodFunction(ctx))
+  registerValue(scope, "texelFetch" , true, values.NewTexelFetchFunction(ctx))
+  registerValue(scope, "textureSize", true, values.NewTextureSizeFunction(ctx))
+}
+
 func FillVertexShaderScope(scope Scope) {
   ctx := context.NewDummyContext()
 
   FillCoreScope(scope)
 
   registerValue(scope, "gl_Position", false, values.NewVec("float", 4, ctx))
+
+  if IsES300() {
+    FillES300Scope(scope)
+
+    registerValue(scope, "gl_VertexID", true, values.NewScalar("int", ctx))
+    registerValue(scope, "gl_InstanceID", true, values.NewScalar("int", ctx))
+  }
 }
 
 func FillFragmentShaderScope(scope Scope) {
@@ -108,13 +147,21 @@ func FillFragmentShaderScope(scope Scope) {
 
   FillCoreScope(scope)
 
-  registerValue(scope, "sampler2D", true, values.NewSampler2DType(ctx))
-  registerValue(scope, "samplerCube", true, values.NewSamplerCubeType(ctx))
-
   registerValue(scope, "gl_FragCoord", true, values.NewVec("float", 4, ctx))
   registerValue(scope, "gl_FrontFacing" , true, values.NewScalar("bool", ctx))
   registerValue(scope, "gl_PointCoord" , true, values.NewVec("float", 2, ctx))
 
+  if IsES300() {
+    // gl_FragColor is replaced by user declared 'out' variables
+    FillES300Scope(scope)
+
+    registerValue(scope, "gl_FragDepth", false, values.NewScalar("float", ctx))
+    return
+  }
+
+  registerValue(scope, "sampler2D", true, values.NewSampler2DType(ctx))
+  registerValue(scope, "samplerCube", true, values.NewSamplerCubeType(ctx))
+
   registerValue(scope, "gl_FragColor", false, values.NewVec("float", 4, ctx))
 
   registerValue(scope, "texture2D"  , true, values.NewTexture2DFunction(ctx))
@@ -323,6 +323,11 @@ func (t *AddOp) EvalExpression() (values.Value, error) {
       return nil, err
     }
     return values.NewContextValue(a, t.Context()), nil
+  case values.IsUint(a):
+    if _, err := values.AssertUint(b); err != nil {
+      return nil, err
+    }
+    return values.NewContextValue(a, t.Context()), nil
   case values.IsFloat(a):
     if _, err := values.AssertFloat(b); err != nil {
       return nil, err
@@ -346,6 +351,11 @@ func (t *SubOp) EvalExpression() (values.Value, error) {
       return nil, err
     }
     return values.NewContextValue(a, t.Context()), nil
+  case values.IsUint(a):
+    if _, err := values.AssertUint(b); err != nil {
+      return nil, err
+    }
+    return values.NewContextValue(a, t.Context()), nil
   case values.IsFloat(a):
     if _, err := values.AssertFloat(b); err != nil {
       return nil, err
@@ -382,11 +392,27 @@ func (t *MulOp) EvalExpression() (values.Value, error) {
   }
 
   switch {
+  case values.IsMat(a):
+    // matN*matN -> matN, matN*vecN -> vecN
+    check := values.NewVec("float", a.Length(), t.Context())
+    if values.IsMat(b) {
+      check = values.NewMat(a.Length(), t.Context())
+    }
+
+    if err := check.Check(b, t.Context()); err != nil {
+      return nil, err
+    }
+    return values.NewContextValue(b, t.Context()), nil
   case values.IsInt(a):
     if _, err := values.AssertInt(b); err != nil {
       return nil, err
     }
     return values.NewContextValue(a, t.Context()), nil
+  case values.IsUint(a):
+    if _, err := values.AssertUint(b); err != nil {
+      return nil, err
+    }
+    return values.NewContextValue(a, t.Context()), nil
   case values.IsFloat(a):
     if _, err := values.AssertFloat(b); err != nil {
       return nil, err
@@ -470,6 +496,7 @@ func (t *OrderCompareOp) EvalExpression() (values.Value, error) {
 
   switch {
   case values.IsInt(a) && values.IsInt(b):
+  case values.IsUint(a) && values.IsUint(b):
   case values.IsFloat(a) && values.IsFloat(b):
   default:
     errCtx := t.Context()
@@ -493,6 +520,10 @@ func (t *EqCompareOp) EvalExpression() (values.Value, error) {
 }
 
 func (t *PostIncrOp) EvalStatement() error {
+  if err := checkLoopIndexUnmodified(t.a, t.Context()); err != nil {
+    return err
+  }
+
   a, err := t.a.EvalExpression()
   if err != nil {
     return err
@@ -506,6 +537,10 @@ func (t *PostIncrOp) EvalStatement() error {
 }
 
 func (t *PostDecrOp) EvalStatement() error {
+  if err := checkLoopIndexUnmodified(t.a, t.Context()); err != nil {
+    return err
+  }
+
   a, err := t.a.EvalExpression()
   if err != nil {
     return err
@@ -70,6 +70,10 @@ func (v *Array) SetIndex(idx *LiteralInt, arg Value, ctx context.Context) error
   return v.content.Check(arg, ctx)
 }
 
+func (v *Array) Content() Value {
+  return v.content
+}
+
 func (v *Array) Length() int {
   return v.length
 }
@@ -301,3 +301,77 @@ func NewTextureCubeFunction(ctx context.Context) Value {
     []Value{sC, v3, f, v4},
   }, ctx)
 }
+
+// GLSL ES 3.00: a single texture function for all sampler types
+func NewTextureFunction(ctx context.Context) Value {
+  s2D := NewSampler2D(ctx)
+  sC := NewSamplerCube(ctx)
+  s3D := NewSampler3D(ctx)
+  s2DA := NewSampler2DArray(ctx)
+  f := NewFloat(ctx)
+  v2 := NewVec2(ctx)
+  v3 := NewVec3(ctx)
+  v4 := NewVec4(ctx)
+
+  return NewBuiltinFunction([][]Value{
+    []Value{s2D, v2, v4},
+    []Value{s2D, v2, f, v4},
+    []Value{sC, v3, v4},
+    []Value{sC, v3, f, v4},
+    []Value{s3D, v3, v4},
+    []Value{s3D, v3, f, v4},
+    []Value{s2DA, v3, v4},
+    []Value{s2DA, v3, f, v4},
+  }, ctx)
+}
+
+func NewTextureLodFunction(ctx context.Context) Value {
+  s2D := NewSampler2D(ctx)
+  sC := NewSamplerCube(ctx)
+  s3D := NewSampler3D(ctx)
+  s2DA := NewSampler2DArray(ctx)
+  f := NewFloat(ctx)
+  v2 := NewVec2(ctx)
+  v3 := NewVec3(ctx)
+  v4 := NewVec4(ctx)
+
+  return NewBuiltinFunction([][]Value{
+    []Value{s2D, v2, f, v4},
+    []Value{sC, v3, f, v4},
+    []Value{s3D, v3, f, v4},
+    []Value{s2DA, v3, f, v4},
+  }, ctx)
+}
+
+func NewTexelFetchFunction(ctx context.Context) Value {
+  s2D := NewSampler2D(ctx)
+  s3D := NewSampler3D(ctx)
+  s2DA := NewSampler2DArray(ctx)
+  i := NewInt(ctx)
+  i2 := NewIVec2(ctx)
+  i3 := NewIVec3(ctx)
+  v4 := NewVec4(ctx)
+
+  return NewBuiltinFunction([][]Value{
+    []Value{s2D, i2, i, v4},
+    []Value{s3D, i3, i, v4},
+    []Value{s2DA, i3, i, v4},
+  }, ctx)
+}
+
+func NewTextureSizeFunction(ctx context.Context) Value {
+  s2D := NewSampler2D(ctx)
+  sC := NewSamplerCube(ctx)
+  s3D := NewSampler3D(ctx)
+  s2DA := NewSampler2DArray(ctx)
+  i := NewInt(ctx)
+  i2 := NewIVec2(ctx)
+  i3 := NewIVec3(ctx)
+
+  return NewBuiltinFunction([][]Value{
+    []Value{s2D, i, i2},
+    []Value{sC, i, i2},
+    []Value{s3D, i, i3},
+    []Value{s2DA, i, i3},
+  }, ctx)
+}
@@ -0,0 +1,66 @@
+package values
+
+import (
+  "strconv"
+
+	"github.com/computeportal/wtsuite/pkg/tokens/context"
+)
+
+type MatType struct {
+  n int // 2, 3 or 4
+  TypeData
+}
+
+func matTypeName(n int) string {
+  if n < 2 || n > 4 {
+    panic("unhandled")
+  }
+
+  return "mat" + strconv.Itoa(n)
+}
+
+func NewMatType(n int, ctx context.Context) Value {
+  return &MatType{n, newTypeData(matTypeName(n), ctx)}
+}
+
+func (v *MatType) Check(other Value, ctx context.Context) error {
+  instance, _ := v.Instantiate(v.Context())
+
+  return instance.Check(other, ctx)
+}
+
+func (v *MatType) Instantiate(ctx context.Context) (Value, error) {
+  return NewMat(v.n, ctx), nil
+}
+
+// matN(s) -> diagonal matrix
+// matN(m) -> from other matrix
+// matN(c0, c1, ...) -> from columns
+// matN(s00, s01, ...) -> from scalars, column major
+func (v *MatType) EvalFunction(args []Value, ctx context.Context) (Value, error) {
+  switch {
+  case len(args) == 1 && (IsScalar(args[0]) || IsMat(args[0])):
+    return v.Instantiate(ctx)
+  case len(args) == v.n:
+    col := NewVec("float", v.n, ctx)
+
+    for _, arg := range args {
+      if err := col.Check(arg, arg.Context()); err != nil {
+        return nil, err
+      }
+    }
+
+    return v.Instantiate(ctx)
+  case len(args) == v.n*v.n:
+    for _, arg := range args {
+      if !IsScalar(arg) {
+        errCtx := arg.Context()
+        return nil, errCtx.NewError("Error: expected scalar argument")
+      }
+    }
+
+    return v.Instantiate(ctx)
+  default:
+    return nil, ctx.NewError("Error: expected 1, " + strconv.Itoa(v.n) + " or " + strconv.Itoa(v.n*v.n) + " arguments")
+  }
+}
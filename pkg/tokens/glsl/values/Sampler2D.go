@@ -23,6 +23,11 @@ func IsSampler2D(v_ Value) bool {
   return ok
 }
 
+// samplers of any kind can only be used as uniforms
+func IsSampler(v_ Value) bool {
+  return IsSampler2D(v_) || IsSamplerCube(v_) || IsSampler3D(v_) || IsSampler2DArray(v_)
+}
+
 func (v *Sampler2D) Check(other_ Value, ctx context.Context) error {
   if IsSampler2D(other_) {
     return nil
@@ -0,0 +1,60 @@
+package values
+
+import (
+	"github.com/computeportal/wtsuite/pkg/tokens/context"
+)
+
+type Sampler2DArray struct {
+  ValueData
+}
+
+func NewSampler2DArray(ctx context.Context) Value {
+  return &Sampler2DArray{newValueData(ctx)}
+}
+
+func (v *Sampler2DArray) TypeName() string {
+  return "sampler2DArray"
+}
+
+func IsSampler2DArray(v_ Value) bool {
+  v_ = UnpackContextValue(v_)
+
+  _, ok := v_.(*Sampler2DArray)
+  return ok
+}
+
+func (v *Sampler2DArray) Check(other_ Value, ctx context.Context) error {
+  if IsSampler2DArray(other_) {
+    return nil
+  } else {
+    return ctx.NewError("Error: expected " + v.TypeName() + ", got " + other_.TypeName())
+  }
+}
+
+func (v *Sampler2DArray) EvalFunction(args []Value, ctx context.Context) (Value, error) {
+  return nil, ctx.NewError("Error: not a function")
+}
+
+func (v *Sampler2DArray) GetMember(key string, ctx context.Context) (Value, error) {
+  return nil, ctx.NewError("Error: can't get member of sampler2DArray")
+}
+
+func (v *Sampler2DArray) SetMember(key string, arg Value, ctx context.Context) error {
+  return ctx.NewError("Error: can't set member of sampler2DArray")
+}
+
+func (v *Sampler2DArray) GetIndex(idx *LiteralInt, ctx context.Context) (Value, error) {
+  return nil, ctx.NewError("Error: can't get index of sampler2DArray")
+}
+
+func (v *Sampler2DArray) SetIndex(idx *LiteralInt, arg Value, ctx context.Context) error {
+  return ctx.NewError("Error: can't set index of sampler2DArray")
+}
+
+func (v *Sampler2DArray) LiteralIntValue() (int, bool) {
+  return 0, false
+}
+
+func (v *Sampler2DArray) Length() int {
+  return 1
+}
@@ -0,0 +1,27 @@
+package values
+
+import (
+	"github.com/computeportal/wtsuite/pkg/tokens/context"
+)
+
+type Sampler2DArrayType struct {
+  TypeData
+}
+
+func NewSampler2DArrayType(ctx context.Context) Value {
+  return &Sampler2DArrayType{newTypeData("sampler2DArray", ctx)}
+}
+
+func (v *Sampler2DArrayType) Check(other Value, ctx context.Context) error {
+  instance, _ := v.Instantiate(v.Context())
+
+  return instance.Check(other, ctx)
+}
+
+func (v *Sampler2DArrayType) Instantiate(ctx context.Context) (Value, error) {
+  return NewSampler2DArray(ctx), nil
+}
+
+func (v *Sampler2DArrayType) EvalFunction(args []Value, ctx context.Context) (Value, error) {
+  return nil, ctx.NewError("Error: not a constructor")
+}
@@ -0,0 +1,60 @@
+package values
+
+import (
+	"github.com/computeportal/wtsuite/pkg/tokens/context"
+)
+
+type Sampler3D struct {
+  ValueData
+}
+
+func NewSampler3D(ctx context.Context) Value {
+  return &Sampler3D{newValueData(ctx)}
+}
+
+func (v *Sampler3D) TypeName() string {
+  return "sampler3D"
+}
+
+func IsSampler3D(v_ Value) bool {
+  v_ = UnpackContextValue(v_)
+
+  _, ok := v_.(*Sampler3D)
+  return ok
+}
+
+func (v *Sampler3D) Check(other_ Value, ctx context.Context) error {
+  if IsSampler3D(other_) {
+    return nil
+  } else {
+    return ctx.NewError("Error: expected " + v.TypeName() + ", got " + other_.TypeName())
+  }
+}
+
+func (v *Sampler3D) EvalFunction(args []Value, ctx context.Context) (Value, error) {
+  return nil, ctx.NewError("Error: not a function")
+}
+
+func (v *Sampler3D) GetMember(key string, ctx context.Context) (Value, error) {
+  return nil, ctx.NewError("Error: can't get member of sampler3D")
+}
+
+func (v *Sampler3D) SetMember(key string, arg Value, ctx context.Context) error {
+  return ctx.NewError("Error: can't set member of sampler3D")
+}
+
+func (v *Sampler3D) GetIndex(idx *LiteralInt, ctx context.Context) (Value, error) {
+  return nil, ctx.NewError("Error: can't get index of sampler3D")
+}
+
+func (v *Sampler3D) SetIndex(idx *LiteralInt, arg Value, ctx context.Context) error {
+  return ctx.NewError("Error: can't set index of sampler3D")
+}
+
+func (v *Sampler3D) LiteralIntValue() (int, bool) {
+  return 0, false
+}
+
+func (v *Sampler3D) Length() int {
+  return 1
+}
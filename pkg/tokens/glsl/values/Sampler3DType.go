@@ -0,0 +1,27 @@
+package values
+
+import (
+	"github.com/computeportal/wtsuite/pkg/tokens/context"
+)
+
+type Sampler3DType struct {
+  TypeData
+}
+
+func NewSampler3DType(ctx context.Context) Value {
+  return &Sampler3DType{newTypeData("sampler3D", ctx)}
+}
+
+func (v *Sampler3DType) Check(other Value, ctx context.Context) error {
+  instance, _ := v.Instantiate(v.Context())
+
+  return instance.Check(other, ctx)
+}
+
+func (v *Sampler3DType) Instantiate(ctx context.Context) (Value, error) {
+  return NewSampler3D(ctx), nil
+}
+
+func (v *Sampler3DType) EvalFunction(args []Value, ctx context.Context) (Value, error) {
+  return nil, ctx.NewError("Error: not a constructor")
+}
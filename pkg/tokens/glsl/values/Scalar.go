@@ -27,6 +27,10 @@ func NewInt(ctx context.Context) Value {
   return NewScalar("int", ctx)
 }
 
+func NewUint(ctx context.Context) Value {
+  return NewScalar("uint", ctx)
+}
+
 func NewBool(ctx context.Context) Value {
   return NewScalar("bool", ctx)
 }
@@ -132,6 +136,14 @@ func AssertInt(v_ Value) (*Scalar, error) {
   }
 }
 
+func IsUint(v_ Value) bool {
+  return isScalar(v_, "uint")
+}
+
+func AssertUint(v_ Value) (*Scalar, error) {
+  return assertScalar(v_, "uint")
+}
+
 func IsFloat(v_ Value) bool {
   return isScalar(v_, "float")
 }
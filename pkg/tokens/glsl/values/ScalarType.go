@@ -29,7 +29,7 @@ func (v *ScalarType) EvalFunction(args []Value, ctx context.Context) (Value, err
 
   arg0 := UnpackContextValue(args[0])
 
-  if _, ok := arg0.(*Scalar); !ok {
+  if !IsScalar(arg0) {
     errCtx := arg0.Context()
     return nil, errCtx.NewError("Error: expected scalar argument")
   }
@@ -40,6 +40,18 @@ func NewIVec4(ctx context.Context) Value {
   return NewVec("int", 4, ctx)
 }
 
+func NewUVec2(ctx context.Context) Value {
+  return NewVec("uint", 2, ctx)
+}
+
+func NewUVec3(ctx context.Context) Value {
+  return NewVec("uint", 3, ctx)
+}
+
+func NewUVec4(ctx context.Context) Value {
+  return NewVec("uint", 4, ctx)
+}
+
 func NewBVec2(ctx context.Context) Value {
   return NewVec("bool", 2, ctx)
 }
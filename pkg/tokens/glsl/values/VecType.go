@@ -7,7 +7,7 @@ import (
 )
 
 type VecType struct {
-  compType string // float, int or bool
+  compType string // float, int, uint or bool
   n int // 2, 3 or 4
   TypeData
 }
@@ -20,6 +20,8 @@ func vecTypeName(compType string, n int) string {
     typeName = "b" + typeName
   case "int":
     typeName = "i" + typeName
+  case "uint":
+    typeName = "u" + typeName
   case "float":
     // ok
   default:
@@ -59,7 +61,7 @@ func (v *VecType) EvalFunction(args []Value, ctx context.Context) (Value, error)
     argCtx := args[i].Context()
     arg := UnpackContextValue(args[i])
 
-    if _, ok := arg.(*Scalar); !ok {
+    if !IsScalar(arg) {
       errCtx := argCtx
       return nil, errCtx.NewError("Error: expected scalar argument")
     }
@@ -10,6 +10,7 @@ import (
 type Parens struct {
 	values []Token
 	alts   []Token // rhs of argDefaults for function and class, can contain nil in case of no defaults
+	types  []*Type // type annotations of template args, nil if none are annotated
 	TokenData
 }
 
@@ -34,7 +35,20 @@ func NewParens(values []Token, alts []Token, ctx context.Context) *Parens {
 		panic("expected same lenghts")
 	}
 
-	return &Parens{values, alts, TokenData{ctx}}
+	return &Parens{values, alts, nil, TokenData{ctx}}
+}
+
+// entries of types can be nil
+func NewTypedParens(values []Token, types []*Type, alts []Token, ctx context.Context) *Parens {
+  p := NewParens(values, alts, ctx)
+
+  if len(types) != len(p.values) {
+    panic("expected same lengths")
+  }
+
+  p.types = types
+
+  return p
 }
 
 func (t *Parens) Values() []Token {
@@ -45,6 +59,15 @@ func (t *Parens) Alts() []Token {
 	return t.alts
 }
 
+// always the same length as Values(), entries are nil for untyped values
+func (t *Parens) Types() []*Type {
+  if t.types == nil {
+    return make([]*Type, len(t.values))
+  }
+
+  return t.types
+}
+
 func (t *Parens) Dump(indent string) string {
 	var b strings.Builder
 
@@ -54,6 +77,9 @@ func (t *Parens) Dump(indent string) string {
 	for i, v := range t.values {
 		b.WriteString(v.Dump(indent + "  "))
 		b.WriteString("\n")
+		if t.types != nil && t.types[i] != nil {
+			b.WriteString(t.types[i].Dump(indent + ": "))
+		}
 		if t.alts[i] != nil {
 			b.WriteString(t.alts[i].Dump(indent + "= "))
 			b.WriteString("\n")
@@ -0,0 +1,217 @@
+package html
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/computeportal/wtsuite/pkg/tokens/context"
+)
+
+// type annotation of template args and vars (eg. list<dict>, int?)
+type Type struct {
+	name       string
+	parameters []*Type // content of list or dict, can be empty
+	nullable   bool
+	TokenData
+}
+
+var VALID_TYPE_NAMES = []string{"any", "bool", "color", "dict", "float", "int", "list", "null", "string"}
+
+func NewType(name string, parameters []*Type, nullable bool, ctx context.Context) (*Type, error) {
+	valid := false
+	for _, test := range VALID_TYPE_NAMES {
+		if test == name {
+			valid = true
+			break
+		}
+	}
+
+	if !valid {
+		err := ctx.NewError("Error: invalid type " + name)
+		err.AppendString("Hint, valid types: " + strings.Join(VALID_TYPE_NAMES, ", "))
+		return nil, err
+	}
+
+	if len(parameters) > 0 && name != "list" && name != "dict" {
+		return nil, ctx.NewError("Error: only list and dict can have type parameters")
+	} else if len(parameters) > 1 {
+		return nil, ctx.NewError("Error: expected 1 type parameter")
+	}
+
+	return &Type{name, parameters, nullable, TokenData{ctx}}, nil
+}
+
+func (t *Type) Write() string {
+	var b strings.Builder
+
+	b.WriteString(t.name)
+
+	if len(t.parameters) == 1 {
+		b.WriteString("<")
+		b.WriteString(t.parameters[0].Write())
+		b.WriteString(">")
+	}
+
+	if t.nullable {
+		b.WriteString("?")
+	}
+
+	return b.String()
+}
+
+func (t *Type) Dump(indent string) string {
+	return indent + "Type(" + t.Write() + ")\n"
+}
+
+func (t *Type) Eval(scope Scope) (Token, error) {
+	return t, nil
+}
+
+func (t *Type) EvalLazy(tag FinalTag) (Token, error) {
+	return t, nil
+}
+
+func (t *Type) IsSame(other Token) bool {
+	if b, ok := other.(*Type); ok {
+		return t.Write() == b.Write()
+	} else {
+		return false
+	}
+}
+
+func (t *Type) accepts(v Token) bool {
+	if t.nullable && IsNull(v) {
+		return true
+	}
+
+	switch t.name {
+	case "any":
+		return true
+	case "bool":
+		return IsBool(v)
+	case "color":
+		return IsColor(v)
+	case "float":
+		// ints can always be used as floats
+		return IsFloat(v) || IsInt(v)
+	case "int":
+		return IsInt(v)
+	case "null":
+		return IsNull(v)
+	case "string":
+		return IsString(v)
+	case "list":
+		return IsList(v) && t.acceptsContent(v)
+	case "dict":
+		return IsDict(v) && t.acceptsContent(v)
+	default:
+		panic("unhandled type")
+	}
+}
+
+func (t *Type) acceptsContent(v Token) bool {
+	if len(t.parameters) == 0 {
+		return true
+	}
+
+	for _, item := range contentOf(v) {
+		if !t.parameters[0].accepts(item) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// unlike LoopValues, nested containers aren't flattened
+func contentOf(v Token) []Token {
+	var items []struct{ key, value Token }
+
+	switch v_ := v.(type) {
+	case *List:
+		return v_.values
+	case *RawDict:
+		items = v_.items
+	case *StringDict:
+		items = v_.items
+	case *IntDict:
+		items = v_.items
+	default:
+		return []Token{}
+	}
+
+	res := make([]Token, len(items))
+	for i, item := range items {
+		res[i] = item.value
+	}
+
+	return res
+}
+
+// lazy values can't be checked yet
+// the error is placed at ctx, which should be at the caller
+func (t *Type) Check(v Token, ctx context.Context) error {
+	if IsLazy(v) || t.accepts(v) {
+		return nil
+	}
+
+	err := ctx.NewError("Error: expected " + t.Write() + ", got " + TypeOf(v))
+	err.AppendContextString("Info: type declared here", t.Context())
+	return err
+}
+
+// uses the same names as type annotations, lists and dicts with a single content type get a type parameter
+func TypeOf(v Token) string {
+	switch v_ := v.(type) {
+	case *Bool:
+		return "bool"
+	case *Color:
+		return "color"
+	case *Float:
+		return "float"
+	case *Int:
+		return "int"
+	case *Null:
+		return "null"
+	case *String:
+		return "string"
+	case *List:
+		return "list" + typeOfContent(v_)
+	case Dict:
+		return "dict" + typeOfContent(v_)
+	default:
+		return strings.ToLower(strings.TrimPrefix(reflect.TypeOf(v).String(), "*html."))
+	}
+}
+
+func typeOfContent(v Token) string {
+	res := ""
+	for _, item := range contentOf(v) {
+		itemType := TypeOf(item)
+		if res == "" {
+			res = itemType
+		} else if res != itemType {
+			res = "any"
+		}
+	}
+
+	if res == "" || res == "any" {
+		return ""
+	}
+
+	return "<" + res + ">"
+}
+
+func IsType(t Token) bool {
+	_, ok := t.(*Type)
+	return ok
+}
+
+func AssertType(t Token) (*Type, error) {
+	if typ, ok := t.(*Type); ok {
+		return typ, nil
+	} else {
+		errCtx := t.Context()
+		return nil, errCtx.NewError("Error: expected type")
+	}
+}
@@ -126,6 +126,9 @@ func (t *Assign) EvalExpression() (values.Value, error) {
 		if err := lhs.EvalSet(rhsValue, t.Context()); err != nil {
 			return nil, err
 		}
+	case *OptionalMember, *OptionalIndex:
+		errCtx := t.Context()
+		return nil, errCtx.NewError("Error: can't assign to optional chain")
 	default:
 		errCtx := t.Context()
 		return nil, errCtx.NewError("Error: unexpected assign lhs")
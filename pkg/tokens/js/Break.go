@@ -6,19 +6,29 @@ import (
 	"github.com/computeportal/wtsuite/pkg/tokens/context"
 )
 
+// label is empty for a plain break
 type Break struct {
+	label string
 	TokenData
 }
 
-func NewBreak(ctx context.Context) (*Break, error) {
-	return &Break{TokenData{ctx}}, nil
+func NewBreak(label string, ctx context.Context) (*Break, error) {
+	return &Break{label, TokenData{ctx}}, nil
 }
 
 func (t *Break) Dump(indent string) string {
 	var b strings.Builder
 
 	b.WriteString(indent)
-	b.WriteString("Break\n")
+	b.WriteString("Break")
+
+	if t.label != "" {
+		b.WriteString("(")
+		b.WriteString(t.label)
+		b.WriteString(")")
+	}
+
+	b.WriteString("\n")
 
 	return b.String()
 }
@@ -27,7 +37,14 @@ func (t *Break) WriteStatement(usage Usage, indent string, nl string, tab string
 	var b strings.Builder
 
 	b.WriteString(indent)
-	b.WriteString("break;")
+	b.WriteString("break")
+
+	if t.label != "" {
+		b.WriteString(" ")
+		b.WriteString(t.label)
+	}
+
+	b.WriteString(";")
 	return b.String()
 }
 
@@ -44,6 +61,12 @@ func (t *Break) ResolveStatementNames(scope Scope) error {
 		errCtx := t.Context()
 		return errCtx.NewError("Error: break not in breakable scope (i.e. switch, while or for)")
 	}
+
+	if t.label != "" && !hasLabel(scope, t.label) {
+		errCtx := t.Context()
+		return errCtx.NewError("Error: label " + t.label + " not found")
+	}
+
 	return nil
 }
 
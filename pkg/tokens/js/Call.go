@@ -50,6 +50,13 @@ func (t *Call) WriteExpression() string {
 	var b strings.Builder
 
 	b.WriteString(t.lhs.WriteExpression())
+	b.WriteString(t.writeArgs())
+
+	return b.String()
+}
+
+func (t *Call) writeArgs() string {
+	var b strings.Builder
 
 	b.WriteString("(")
 
@@ -97,10 +104,21 @@ func (t *Call) ResolveStatementNames(scope Scope) error {
 	return t.ResolveExpressionNames(scope)
 }
 
+// spread arguments are expanded, see Spread.go
 func (t *Call) evalArgs() ([]values.Value, error) {
 	result := make([]values.Value, 0)
 
 	for _, a := range t.args {
+		if spread, ok := a.(*Spread); ok {
+			vals, err := spread.evalItems()
+			if err != nil {
+				return nil, err
+			}
+
+			result = append(result, vals...)
+			continue
+		}
+
 		val, err := a.EvalExpression()
 		if err != nil {
 			return nil, err
@@ -113,11 +131,25 @@ func (t *Call) evalArgs() ([]values.Value, error) {
 }
 
 func (t *Call) EvalExpression() (values.Value, error) {
-	lhsVal, err := t.lhs.EvalExpression()
+	res, optional, err := t.evalLink()
 	if err != nil {
 		return nil, err
 	}
 
+	return endOptionalChain(res, optional, t.Context()), nil
+}
+
+func (t *Call) evalLink() (values.Value, bool, error) {
+	lhsVal, optional, err := evalChainObject(t.lhs)
+	if err != nil {
+		return nil, false, err
+	}
+
+	res, err := t.evalCall(lhsVal)
+	return res, optional, err
+}
+
+func (t *Call) evalCall(lhsVal values.Value) (values.Value, error) {
   if lhsVal == nil {
     hereCtx := t.lhs.Context()
     panic(hereCtx.NewError("can't be nil").Error())
@@ -140,11 +172,15 @@ func (t *Call) EvalExpression() (values.Value, error) {
 }
 
 func (t *Call) EvalStatement() error {
-	lhsVal, err := t.lhs.EvalExpression()
+	lhsVal, _, err := evalChainObject(t.lhs)
 	if err != nil {
 		return err
 	}
 
+	return t.evalCallStatement(lhsVal)
+}
+
+func (t *Call) evalCallStatement(lhsVal values.Value) error {
 	args, err := t.evalArgs()
 	if err != nil {
 		return err
@@ -19,10 +19,12 @@ type Class struct {
   isAbstract       bool // can't be combined with final
   isFinal          bool // can't be combined with abstract
 	universalName    string
+  decorators       []Decorator // applied after the member decorators
+  memberDecorators map[ClassMember][]Decorator
 	TokenData
 }
 
-func NewClass(nameExpr *TypeExpression, parentExpr *TypeExpression, interfExprs []*VarExpression, isAbstract bool, isFinal bool, universalName string, ctx context.Context) (*Class, error) {
+func NewClass(nameExpr *TypeExpression, parentExpr *TypeExpression, interfExprs []*VarExpression, isAbstract bool, isFinal bool, universalName string, decorators []Decorator, ctx context.Context) (*Class, error) {
   for _, interfExpr := range interfExprs {
     if interfExpr == nil {
       panic("interfExpr can't be nil")
@@ -38,6 +40,8 @@ func NewClass(nameExpr *TypeExpression, parentExpr *TypeExpression, interfExprs
 		isAbstract,
     isFinal,
     universalName,
+    decorators,
+    make(map[ClassMember][]Decorator),
 		TokenData{ctx},
 	}
 
@@ -162,7 +166,26 @@ func (t *Class) getMember(name string, preferSetter bool) ClassMember {
   return nil
 }
 
-func (t *Class) AddProperty(name *Word, expr *TypeExpression) error {
+func (t *Class) HasDecorators() bool {
+  return len(t.decorators) > 0 || len(t.memberDecorators) > 0
+}
+
+func (t *Class) addMemberDecorators(member ClassMember, decorators []Decorator) error {
+  if len(decorators) == 0 {
+    return nil
+  }
+
+  if prototypes.IsGetter(member) || prototypes.IsSetter(member) || prototypes.IsAbstract(member) {
+    errCtx := decorators[0].Context()
+    return errCtx.NewError("Error: getters, setters and abstract members can't be decorated")
+  }
+
+  t.memberDecorators[member] = decorators
+
+  return nil
+}
+
+func (t *Class) AddProperty(name *Word, expr *TypeExpression, decorators []Decorator) error {
   if prev := t.getMember(name.Value(), false); prev != nil {
     errCtx := name.Context()
     err := errCtx.NewError("Error: already have a member named " + name.Value())
@@ -170,9 +193,11 @@ func (t *Class) AddProperty(name *Word, expr *TypeExpression) error {
     return err
   }
 
-  t.members = append(t.members, NewClassProperty(name, expr))
+  member := NewClassProperty(name, expr)
 
-  return nil
+  t.members = append(t.members, member)
+
+  return t.addMemberDecorators(member, decorators)
 }
 
 func (t *Class) Properties() (map[string]values.Value, error) {
@@ -208,12 +233,17 @@ func (t *Class) AddConstructor(fn *Function) error {
   return nil
 }
 
-func (t *Class) AddFunction(fn *Function) error {
+func (t *Class) AddFunction(fn *Function, decorators []Decorator) error {
 	if fn.Name() == "" {
 		errCtx := fn.Context()
 		err := errCtx.NewError("Error: member function doesn't have a name")
 		return err
 	} else if fn.Name() == "constructor" {
+    if len(decorators) > 0 {
+      errCtx := decorators[0].Context()
+      return errCtx.NewError("Error: constructor can't be decorated (hint: decorate the class instead)")
+    }
+
     return t.AddConstructor(fn)
   }
 
@@ -265,7 +295,21 @@ func (t *Class) AddFunction(fn *Function) error {
 
 	t.members = append(t.members, member)
 
-	return nil
+	return t.addMemberDecorators(member, decorators)
+}
+
+// Class for static members, Class.prototype otherwise
+func (t *Class) WriteDecoratorTarget(member ClassMember) string {
+  if member != nil && prototypes.IsStatic(member) {
+    return t.Name()
+  } else {
+    return t.Name() + ".prototype"
+  }
+}
+
+// mangled in compact mode
+func (t *Class) WriteDecoratorMemberName(member ClassMember) string {
+  return t.writeMemberName(member.Name())
 }
 
 func (t *Class) Dump(indent string) string {
@@ -381,6 +425,25 @@ func (t *Class) WriteStatement(usage Usage, indent string, nl string, tab string
     }
   }
 
+  // member decorators in order of member declaration, followed by class decorators
+  // the decorators of a single target are applied bottom-up (nearest first, like TC39 decorators)
+  for _, member := range t.members {
+    ds := t.memberDecorators[member]
+    for i := len(ds) - 1; i >= 0; i-- {
+      b.WriteString(";")
+      b.WriteString(nl)
+      b.WriteString(indent)
+      b.WriteString(ds[i].WriteDecorator(t, member))
+    }
+  }
+
+  for i := len(t.decorators) - 1; i >= 0; i-- {
+    b.WriteString(";")
+    b.WriteString(nl)
+    b.WriteString(indent)
+    b.WriteString(t.decorators[i].WriteDecorator(t, nil))
+  }
+
 	return b.String()
 }
 
@@ -493,6 +556,45 @@ func (t *Class) resolveMemberNames(scope Scope) error {
 	return nil
 }
 
+// decorators are evaluated in the scope surrounding the class
+func (t *Class) resolveDecoratorNames(scope Scope) error {
+  for _, d := range t.decorators {
+    if err := d.ResolveExpressionNames(scope); err != nil {
+      return err
+    }
+  }
+
+  for _, ds := range t.memberDecorators {
+    for _, d := range ds {
+      if err := d.ResolveExpressionNames(scope); err != nil {
+        return err
+      }
+    }
+  }
+
+  return nil
+}
+
+// same order as WriteStatement
+func (t *Class) evalDecorators() error {
+  for _, member := range t.members {
+    ds := t.memberDecorators[member]
+    for i := len(ds) - 1; i >= 0; i-- {
+      if err := ds[i].EvalDecorator(t, member); err != nil {
+        return err
+      }
+    }
+  }
+
+  for i := len(t.decorators) - 1; i >= 0; i-- {
+    if err := t.decorators[i].EvalDecorator(t, nil); err != nil {
+      return err
+    }
+  }
+
+  return nil
+}
+
 func (t *Class) collectAbstractMembers() ([]*ClassFunction, error) {
   result := make([]*ClassFunction, 0)
 
@@ -581,6 +683,11 @@ func (t *Class) checkUniversalness() error {
   }
 
 	for _, member := range t.members {
+    if prop, ok := member.(*ClassProperty); ok && prop.typeExpr != nil && !prop.typeExpr.hasUniversalUnions() {
+      errCtx := prop.Context()
+      return errCtx.NewError("Error: union type of universal property must be a single nullable type")
+    }
+
     if !member.IsUniversal() {
       errCtx := ctx
       err := errCtx.NewError("Error: not universal")
@@ -613,7 +720,7 @@ func (t *Class) ResolveExpressionNames(scope Scope) error {
     return err
   }
 
-	return nil
+  return t.resolveDecoratorNames(scope)
 }
 
 func (t *Class) ResolveStatementNames(scope Scope) error {
@@ -721,7 +828,7 @@ func (t *Class) evalInternal() error {
     return err
   }
 
-  return nil
+  return t.evalDecorators()
 }
 
 func (t *Class) EvalExpression() (values.Value, error) {
@@ -870,6 +977,21 @@ func (t *Class) ResolveExpressionActivity(usage Usage) error {
 
 	usage.SetInFunction(tmp)
 
+  // decorators are called at the same level as the class
+  for _, d := range t.decorators {
+    if err := d.ResolveExpressionActivity(usage); err != nil {
+      return err
+    }
+  }
+
+  for _, member := range t.members {
+    for _, d := range t.memberDecorators[member] {
+      if err := d.ResolveExpressionActivity(usage); err != nil {
+        return err
+      }
+    }
+  }
+
 	return nil
 }
 
@@ -917,8 +1039,20 @@ func (t *Class) UniversalExpressionNames(ns Namespace) error {
 		if err := member.UniversalNames(ns); err != nil {
 			return err
 		}
+
+    for _, d := range t.memberDecorators[member] {
+      if err := d.UniversalExpressionNames(ns); err != nil {
+        return err
+      }
+    }
 	}
 
+  for _, d := range t.decorators {
+    if err := d.UniversalExpressionNames(ns); err != nil {
+      return err
+    }
+  }
+
 	return nil
 }
 
@@ -930,11 +1064,27 @@ func (t *Class) UniqueExpressionNames(ns Namespace) error {
 	}
 
 	for _, member := range t.members {
+		if fn, ok := member.(*ClassFunction); ok {
+			fn.writeName = t.writeMemberName(fn.Name())
+		}
+
 		if err := member.UniqueNames(ns); err != nil {
 			return err
 		}
+
+    for _, d := range t.memberDecorators[member] {
+      if err := d.UniqueExpressionNames(ns); err != nil {
+        return err
+      }
+    }
 	}
 
+  for _, d := range t.decorators {
+    if err := d.UniqueExpressionNames(ns); err != nil {
+      return err
+    }
+  }
+
 	return nil
 }
 
@@ -973,6 +1123,18 @@ func (t *Class) Walk(fn WalkFunc) error {
     if err := member.Walk(fn); err != nil {
       return err
     }
+
+    for _, d := range t.memberDecorators[member] {
+      if err := d.Walk(fn); err != nil {
+        return err
+      }
+    }
+  }
+
+  for _, d := range t.decorators {
+    if err := d.Walk(fn); err != nil {
+      return err
+    }
   }
 
   return fn(t)
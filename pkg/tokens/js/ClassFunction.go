@@ -12,10 +12,11 @@ import (
 // implements the values.Callable interface
 type ClassFunction struct {
 	function *Function
+	writeName string // differs from Name() if mangled, set during UniqueNames stage
 }
 
 func NewClassFunction(fn *Function) *ClassFunction {
-	return &ClassFunction{fn}
+	return &ClassFunction{fn, fn.Name()}
 }
 
 func (m *ClassFunction) Context() context.Context {
@@ -84,7 +85,7 @@ func (m *ClassFunction) WriteStatement(usage Usage, indent string, nl string, ta
 	b.WriteString(m.getModifierString())
 
 	fn := m.function
-	b.WriteString(fn.Name())
+	b.WriteString(m.writeName)
 
 	b.WriteString(fn.writeBody(usage, indent, nl, tab))
 
@@ -23,7 +23,7 @@ func (p *ClassProperty) Name() string {
 }
 
 func (p *ClassProperty) Role() prototypes.FunctionRole {
-  if strings.HasPrefix("_", p.Name()) {
+  if strings.HasPrefix(p.Name(), "_") {
     return prototypes.PRIVATE | prototypes.PROPERTY
   } else {
     return prototypes.PROPERTY
@@ -140,7 +140,7 @@ func (p *ClassProperty) UniversalNames(ns Namespace) error {
 }
 
 func (p *ClassProperty) UniqueNames(ns Namespace) error {
-  // TODO: give members other names when compacting
+  // properties aren't declared in the output, mangled names are written by Member
   return nil
 }
 
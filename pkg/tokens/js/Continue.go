@@ -6,19 +6,29 @@ import (
 	"github.com/computeportal/wtsuite/pkg/tokens/context"
 )
 
+// label is empty for a plain continue
 type Continue struct {
+	label string
 	TokenData
 }
 
-func NewContinue(ctx context.Context) (*Continue, error) {
-	return &Continue{TokenData{ctx}}, nil
+func NewContinue(label string, ctx context.Context) (*Continue, error) {
+	return &Continue{label, TokenData{ctx}}, nil
 }
 
 func (t *Continue) Dump(indent string) string {
 	var b strings.Builder
 
 	b.WriteString(indent)
-	b.WriteString("Continue\n")
+	b.WriteString("Continue")
+
+	if t.label != "" {
+		b.WriteString("(")
+		b.WriteString(t.label)
+		b.WriteString(")")
+	}
+
+	b.WriteString("\n")
 
 	return b.String()
 }
@@ -27,7 +37,14 @@ func (t *Continue) WriteStatement(usage Usage, indent string, nl string, tab str
 	var b strings.Builder
 
 	b.WriteString(indent)
-	b.WriteString("continue;")
+	b.WriteString("continue")
+
+	if t.label != "" {
+		b.WriteString(" ")
+		b.WriteString(t.label)
+	}
+
+	b.WriteString(";")
 	return b.String()
 }
 
@@ -45,6 +62,11 @@ func (t *Continue) ResolveStatementNames(scope Scope) error {
 		return errCtx.NewError("Error: not in continueable scope (i.e. for or while)")
 	}
 
+	if t.label != "" && !hasLabel(scope, t.label) {
+		errCtx := t.Context()
+		return errCtx.NewError("Error: label " + t.label + " not found")
+	}
+
 	return nil
 }
 
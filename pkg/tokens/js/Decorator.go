@@ -0,0 +1,144 @@
+package js
+
+import (
+	"strings"
+
+	"github.com/computeportal/wtsuite/pkg/tokens/js/prototypes"
+	"github.com/computeportal/wtsuite/pkg/tokens/js/values"
+
+	"github.com/computeportal/wtsuite/pkg/tokens/context"
+)
+
+// decorators are applied at compile time, and written as plain statements after the class
+// member is nil for class decorators
+type Decorator interface {
+	Context() context.Context
+	Dump(indent string) string
+
+	WriteDecorator(cl *Class, member ClassMember) string
+	EvalDecorator(cl *Class, member ClassMember) error
+
+	ResolveExpressionNames(scope Scope) error
+	ResolveExpressionActivity(usage Usage) error
+	UniversalExpressionNames(ns Namespace) error
+	UniqueExpressionNames(ns Namespace) error
+	Walk(fn WalkFunc) error
+}
+
+// user function used as decorator:
+//   class:    fn(cl class, args...)
+//   method:   fn(method function, name String, args...) function
+//   property: fn(proto Class, name String, args...)
+type FunctionDecorator struct {
+	Call
+}
+
+func NewFunctionDecorator(lhs *VarExpression, args []Expression, ctx context.Context) *FunctionDecorator {
+	return &FunctionDecorator{Call{lhs, args, TokenData{ctx}}}
+}
+
+func (d *FunctionDecorator) Dump(indent string) string {
+	return indent + "@" + d.Call.Dump("")
+}
+
+// calls fn with the decorated class or member, and replaces methods by the result
+func WriteDecoratorCall(fn string, args []Expression, cl *Class, member ClassMember) string {
+	var b strings.Builder
+
+	if member == nil {
+		b.WriteString(fn)
+		b.WriteString("(")
+		b.WriteString(cl.Name())
+	} else if prototypes.IsProperty(member) {
+		b.WriteString(fn)
+		b.WriteString("(")
+		b.WriteString(cl.WriteDecoratorTarget(member))
+		b.WriteString(",'")
+		b.WriteString(member.Name())
+		b.WriteString("'")
+	} else {
+		target := cl.WriteDecoratorTarget(member) + "." + cl.WriteDecoratorMemberName(member)
+		b.WriteString(target)
+		b.WriteString("=")
+		b.WriteString(fn)
+		b.WriteString("(")
+		b.WriteString(target)
+		b.WriteString(",'")
+		b.WriteString(member.Name())
+		b.WriteString("'")
+	}
+
+	for _, arg := range args {
+		b.WriteString(",")
+		b.WriteString(arg.WriteExpression())
+	}
+
+	b.WriteString(")")
+
+	return b.String()
+}
+
+func (d *FunctionDecorator) WriteDecorator(cl *Class, member ClassMember) string {
+	return WriteDecoratorCall(d.lhs.WriteExpression(), d.args, cl, member)
+}
+
+func (d *FunctionDecorator) EvalDecorator(cl *Class, member ClassMember) error {
+	ctx := d.Context()
+
+	fnVal, err := d.lhs.EvalExpression()
+	if err != nil {
+		return err
+	}
+
+	args, err := d.evalArgs()
+	if err != nil {
+		return err
+	}
+
+	if member == nil {
+		classVal, err := cl.GetClassValue()
+		if err != nil {
+			return err
+		}
+
+		if classVal == nil {
+			classVal = values.NewClass([][]values.Value{[]values.Value{}}, cl, ctx)
+		}
+
+		_, err = fnVal.EvalFunction(append([]values.Value{classVal}, args...), true, ctx)
+		return err
+	}
+
+	name := prototypes.NewLiteralString(member.Name(), ctx)
+
+	if prototypes.IsProperty(member) {
+		proto := values.NewInstance(cl, ctx)
+
+		_, err = fnVal.EvalFunction(append([]values.Value{proto, name}, args...), true, ctx)
+		return err
+	}
+
+	var memberVal values.Value
+	if prototypes.IsStatic(member) {
+		memberVal, err = cl.GetClassMember(member.Name(), true, ctx)
+	} else {
+		memberVal, err = cl.GetInstanceMember(member.Name(), true, ctx)
+	}
+	if err != nil {
+		return err
+	}
+
+	res, err := fnVal.EvalFunction(append([]values.Value{memberVal, name}, args...), false, ctx)
+	if err != nil {
+		return err
+	} else if res == nil {
+		return ctx.NewError("Error: method decorator must return a function")
+	}
+
+	// the wrapper replaces the method, so it must at least be a function
+	if err := values.NewOverloadedFunction(nil, ctx).Check(res, ctx); err != nil {
+		return err
+	}
+
+	return nil
+}
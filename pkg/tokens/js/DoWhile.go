@@ -0,0 +1,81 @@
+package js
+
+import (
+	"strings"
+
+	"github.com/computeportal/wtsuite/pkg/tokens/context"
+)
+
+// the block is evaluated before the condition, otherwise the same as While
+type DoWhile struct {
+	While
+}
+
+func NewDoWhile(cond Expression, ctx context.Context) (*DoWhile, error) {
+	return &DoWhile{While{cond, newBlock(ctx)}}, nil
+}
+
+func (t *DoWhile) Dump(indent string) string {
+	var b strings.Builder
+
+	b.WriteString(indent)
+
+	b.WriteString("DoWhile(")
+	b.WriteString(strings.Replace(t.cond.WriteExpression(), "\n", "", -1))
+
+	b.WriteString(")\n")
+
+	for _, s := range t.statements {
+		b.WriteString(s.Dump(indent + "{ "))
+	}
+
+	return b.String()
+}
+
+func (t *DoWhile) WriteStatement(usage Usage, indent string, nl string, tab string) string {
+	var b strings.Builder
+
+	b.WriteString(indent)
+
+	b.WriteString("do{")
+	b.WriteString(nl)
+
+	b.WriteString(t.writeBlockStatements(usage, indent+tab, nl, tab))
+
+	b.WriteString(nl)
+	b.WriteString(indent)
+	b.WriteString("}while(")
+	b.WriteString(t.cond.WriteExpression())
+	b.WriteString(")")
+
+	return b.String()
+}
+
+func (t *DoWhile) EvalStatement() error {
+	if err := t.Block.EvalStatement(); err != nil {
+		return err
+	}
+
+	return t.While.evalCond()
+}
+
+// usage is resolved in reverse order
+func (t *DoWhile) ResolveStatementActivity(usage Usage) error {
+	if err := t.cond.ResolveExpressionActivity(usage); err != nil {
+		return err
+	}
+
+	return t.Block.ResolveStatementActivity(usage)
+}
+
+func (t *DoWhile) Walk(fn WalkFunc) error {
+  if err := t.Block.Walk(fn); err != nil {
+    return err
+  }
+
+  if err := t.cond.Walk(fn); err != nil {
+    return err
+  }
+
+  return fn(t)
+}
@@ -15,6 +15,13 @@ type EnumMember struct {
 	val Expression
 }
 
+// Enum or TaggedUnion
+type EnumStatement interface {
+	Statement
+	Name() string
+	GetVariable() Variable
+}
+
 // enum is a statement (and also a Class!)
 type Enum struct {
 	nameExpr   *TypeExpression
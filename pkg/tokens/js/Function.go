@@ -242,6 +242,25 @@ func (t *Function) assertLastStatementReturns(lastStatement Statement) error {
 		for i, _ := range st.conds {
 			groupStatements := st.grouped[i]
 
+			if err := t.assertLastStatementReturns(groupStatements[len(groupStatements)-1]); err != nil {
+				return err
+			}
+		}
+	case *Switch:
+		if !st.exhaustive {
+			errCtx := st.Context()
+			return errCtx.NewError("Error: not every case returns a value (switch isn't exhaustive)")
+		}
+
+		for i, groupStatements := range st.grouped {
+			// empty groups fall through
+			if len(groupStatements) == 0 && i < len(st.grouped)-1 {
+				continue
+			} else if len(groupStatements) == 0 {
+				errCtx := st.Context()
+				return errCtx.NewError("Error: not every case returns a value")
+			}
+
 			if err := t.assertLastStatementReturns(groupStatements[len(groupStatements)-1]); err != nil {
 				return err
 			}
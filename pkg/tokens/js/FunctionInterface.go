@@ -248,7 +248,7 @@ func (fi *FunctionInterface) writeRPCNewEntry(indent string, nl string, tab stri
       b.WriteString("(parseInt(__checkType__(result.value,Number)),ctx);")
     } else if retInterf.IsUniversal() {
       b.WriteString("__checkType__(result.value,")
-      b.WriteString(fi.ret.WriteUniversalRuntimeType())
+      b.WriteString(fi.ret.parameters[0].typeExpr.WriteUniversalRuntimeType()) // content of the promise
       b.WriteString(");")
     } else {
       panic("unexpected")
@@ -587,6 +587,42 @@ func (fi *FunctionInterface) CheckRPC() error {
   return nil
 }
 
+// rpc args and rpc return values open new channels, which must persist between messages
+func (fi *FunctionInterface) CheckStatelessRPC() error {
+  for _, arg := range fi.args {
+    argVal, err := arg.GetValue()
+    if err != nil {
+      return err
+    }
+
+    interf := values.GetInterface(argVal)
+    if interf != nil && interf.IsRPC() {
+      errCtx := argVal.Context()
+      return errCtx.NewError("Error: rpc arg requires a persistent channel (hint: use SocketRPC instead)")
+    }
+  }
+
+  retVal, err := fi.GetReturnValue()
+  if err != nil {
+    return err
+  }
+
+  promiseContent, err := prototypes.GetPromiseContent(retVal)
+  if err != nil {
+    return err
+  }
+
+  if promiseContent != nil {
+    interf := values.GetInterface(promiseContent)
+    if interf != nil && interf.IsRPC() {
+      errCtx := retVal.Context()
+      return errCtx.NewError("Error: rpc return value requires a persistent channel (hint: use SocketRPC instead)")
+    }
+  }
+
+  return nil
+}
+
 func (fi *FunctionInterface) UniversalNames(ns Namespace) error {
 	for _, arg := range fi.args {
 		if err := arg.UniversalNames(ns); err != nil {
@@ -4,8 +4,8 @@ import (
 	"strings"
 
 	"github.com/computeportal/wtsuite/pkg/tokens/context"
-  "github.com/computeportal/wtsuite/pkg/tokens/js/values"
 	"github.com/computeportal/wtsuite/pkg/tokens/js/prototypes"
+	"github.com/computeportal/wtsuite/pkg/tokens/js/values"
 )
 
 type If struct {
@@ -142,38 +142,15 @@ func (t *If) ResolveStatementNames(scope Scope) error {
 	return nil
 }
 
-func (t *If) evalTypeGuards(cond Expression) (map[Variable]values.Interface, error) {
-	if cond == nil {
-		return nil, nil
-	}
-
-	if typeGuardCond, ok := cond.(TypeGuard); ok {
-    typeGuards := make(map[Variable]values.Interface)
-		hasTypeGuards, err := typeGuardCond.CollectTypeGuards(typeGuards)
-		if err != nil {
-			return nil, err
-		}
-
-		if hasTypeGuards {
-			if len(typeGuards) == 0 {
-				// some conditions can work alongside typeguards, even though they dont add any typeguards
-				return nil, nil
-			} else {
-				return typeGuards, nil
-			}
-		} else {
-			return nil, nil
-		}
-	} else {
-		return nil, nil
-	}
-}
-
 func (t *If) EvalStatement() error {
+	// the later branches are only reached if the earlier conditions are false
+	elseValues := make(map[typeGuardTarget]values.Value)
+	defer restoreTypeGuards(elseValues)
+
 	for i, cond := range t.conds {
 		condIsLit := false
 		condLitVal := false
-		typeGuards, err := t.evalTypeGuards(cond)
+		typeGuards, err := collectTypeGuards(cond)
 		if err != nil {
 			return err
 		}
@@ -197,25 +174,24 @@ func (t *If) EvalStatement() error {
 			}
 		}
 
-    oldValues := make(map[Variable]values.Value)
-    if typeGuards != nil {
-      for key, typeGuard := range typeGuards {
-        oldValues[key] = key.GetValue()
-        key.SetValue(values.NewInstance(typeGuard, typeGuard.Context()))
-      }
-    }
+    oldValues := applyTypeGuards(typeGuards)
 
 		if err := t.Block.evalStatements(t.grouped[i]); err != nil {
 			return err
 		}
 
-    for key, val := range oldValues {
-      key.SetValue(val)
-    }
+    restoreTypeGuards(oldValues)
 
 		if condIsLit && condLitVal {
 			break
 		}
+
+		complementGuards, err := collectComplementTypeGuards(cond)
+		if err != nil {
+			return err
+		}
+
+		accumulateTypeGuards(complementGuards, elseValues)
 	}
 
 	return nil
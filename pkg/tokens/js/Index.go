@@ -34,13 +34,15 @@ func (t *Index) WriteExpression() string {
 	var b strings.Builder
 
 	b.WriteString(t.container.WriteExpression())
-	b.WriteString("[")
-	b.WriteString(t.index.WriteExpression())
-	b.WriteString("]")
+	b.WriteString(t.writeIndex())
 
 	return b.String()
 }
 
+func (t *Index) writeIndex() string {
+	return "[" + t.index.WriteExpression() + "]"
+}
+
 ///////////////////////////
 // 1. Name resolution stage
 ///////////////////////////
@@ -57,11 +59,25 @@ func (t *Index) ResolveExpressionNames(scope Scope) error {
 }
 
 func (t *Index) EvalExpression() (values.Value, error) {
-	containerValue, err := t.container.EvalExpression()
+	res, optional, err := t.evalLink()
 	if err != nil {
 		return nil, err
 	}
 
+	return endOptionalChain(res, optional, t.Context()), nil
+}
+
+func (t *Index) evalLink() (values.Value, bool, error) {
+	containerValue, optional, err := evalChainObject(t.container)
+	if err != nil {
+		return nil, false, err
+	}
+
+	res, err := t.evalIndex(containerValue)
+	return res, optional, err
+}
+
+func (t *Index) evalIndex(containerValue values.Value) (values.Value, error) {
 	indexValue, err := t.index.EvalExpression()
 	if err != nil {
 		return nil, err
@@ -87,6 +103,11 @@ func (t *Index) EvalExpression() (values.Value, error) {
 }
 
 func (t *Index) EvalSet(rhsValue values.Value, ctx context.Context) error {
+	if isOptionalChain(t.container) {
+		errCtx := t.Context()
+		return errCtx.NewError("Error: can't assign to optional chain")
+	}
+
 	containerValue, err := t.container.EvalExpression()
 	if err != nil {
 		return err
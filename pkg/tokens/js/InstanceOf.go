@@ -153,6 +153,11 @@ func (t *InstanceOf) evalInternal() error {
     return errCtx.NewError("Error: not an instance")
   }
 
+  if _, ok := t.interf.(*TaggedUnion); ok {
+    errCtx := t.b.Context()
+    return errCtx.NewError("Error: tagged unions are plain objects (hint: use switch instead of instanceof)")
+  }
+
   if t.interf == nil {
     b, err := t.b.EvalExpression()
     if err != nil {
@@ -176,21 +181,19 @@ func (t *InstanceOf) EvalExpression() (values.Value, error) {
 	return prototypes.NewBoolean(t.Context()), nil
 }
 
-func (t *InstanceOf) CollectTypeGuards(c map[Variable]values.Interface) (bool, error) {
-	// only if lhs is VarExpression
-	if lhs, ok := t.a.(*VarExpression); ok {
-		ref := lhs.GetVariable()
-
+func (t *InstanceOf) CollectTypeGuards(c map[typeGuardTarget]values.Value) (bool, error) {
+	// only if lhs is a variable or a property of a variable
+	if _, ok := getTypeGuardTarget(t.a); ok {
 		if err := t.evalInternal(); err != nil {
 			return false, err
 		}
 
 		// only if rhs is a single interface/class
 		if t.interf != nil { // in case of multiple or no classes
-			if _, ok := c[ref]; !ok {
-				c[ref] = t.interf
-				return true, nil
-			} // else: c already contains another type guard for the same variable -> void all
+			// false if c already contains another type guard for the same variable -> void all
+			return addTypeGuard(c, t.a, func(v values.Value) values.Value {
+				return values.Narrow(v, values.NewInstance(t.interf, t.interf.Context()), t.Context())
+			})
 		}
 	}
 
@@ -198,6 +201,17 @@ func (t *InstanceOf) CollectTypeGuards(c map[Variable]values.Interface) (bool, e
 	return false, nil
 }
 
+// the class/interface is excluded from the alternatives
+func (t *InstanceOf) CollectComplementTypeGuards(c map[typeGuardTarget]values.Value) (bool, error) {
+	if t.interf == nil {
+		return false, nil
+	}
+
+	return addTypeGuard(c, t.a, func(v values.Value) values.Value {
+		return values.Exclude(v, values.NewInstance(t.interf, t.interf.Context()), t.Context())
+	})
+}
+
 func (t *InstanceOf) Walk(fn WalkFunc) error {
   if err := t.BinaryOp.Walk(fn); err != nil {
     return err
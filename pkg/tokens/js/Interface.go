@@ -485,6 +485,8 @@ func (t *Interface) ResolveStatementNames(scope Scope) error {
 
 		// interface members cant have default arguments
 		for _, member := range t.members {
+      registerInterfaceMemberName(member.Name())
+
       subScope := NewSubScope(scope)
 			if err := member.ResolveNames(subScope); err != nil {
 				return err
@@ -633,6 +635,25 @@ func (t *Interface) IsRPC() bool {
   return t.isRPC
 }
 
+// used by transports that create a new server for every message
+func (t *Interface) CheckStatelessRPC() error {
+  for _, parent := range t.parents {
+    if parentInterf, ok := parent.GetInterface().(*Interface); ok {
+      if err := parentInterf.CheckStatelessRPC(); err != nil {
+        return err
+      }
+    }
+  }
+
+  for _, member := range t.members {
+    if err := member.CheckStatelessRPC(); err != nil {
+      return err
+    }
+  }
+
+  return nil
+}
+
 func (t *Interface) ResolveStatementActivity(usage Usage) error {
 	return nil
 }
@@ -0,0 +1,90 @@
+package js
+
+import (
+	"strings"
+
+	"github.com/computeportal/wtsuite/pkg/tokens/context"
+)
+
+// only loops can be labelled, so the label can be used by break and continue
+type Label struct {
+	label string
+	loop  Statement
+	TokenData
+}
+
+func NewLabel(label string, loop Statement, ctx context.Context) (*Label, error) {
+	switch loop.(type) {
+	case *For, *ForIn, *ForOf, *While, *DoWhile:
+	default:
+		return nil, ctx.NewError("Error: only loops can be labelled")
+	}
+
+	return &Label{label, loop, TokenData{ctx}}, nil
+}
+
+func (t *Label) Dump(indent string) string {
+	var b strings.Builder
+
+	b.WriteString(indent)
+	b.WriteString("Label(")
+	b.WriteString(t.label)
+	b.WriteString(")\n")
+
+	b.WriteString(t.loop.Dump(indent + "  "))
+
+	return b.String()
+}
+
+func (t *Label) WriteStatement(usage Usage, indent string, nl string, tab string) string {
+	var b strings.Builder
+
+	b.WriteString(indent)
+	b.WriteString(t.label)
+	b.WriteString(":")
+	b.WriteString(nl)
+	b.WriteString(t.loop.WriteStatement(usage, indent, nl, tab))
+
+	return b.String()
+}
+
+func (t *Label) AddStatement(st Statement) {
+	panic("not a block")
+}
+
+func (t *Label) HoistNames(scope Scope) error {
+	return t.loop.HoistNames(scope)
+}
+
+func (t *Label) ResolveStatementNames(scope Scope) error {
+	if hasLabel(scope, t.label) {
+		errCtx := t.Context()
+		return errCtx.NewError("Error: label " + t.label + " already used by enclosing loop")
+	}
+
+	return t.loop.ResolveStatementNames(NewLabelScope(t.label, scope))
+}
+
+func (t *Label) EvalStatement() error {
+	return t.loop.EvalStatement()
+}
+
+func (t *Label) ResolveStatementActivity(usage Usage) error {
+	return t.loop.ResolveStatementActivity(usage)
+}
+
+func (t *Label) UniversalStatementNames(ns Namespace) error {
+	return t.loop.UniversalStatementNames(ns)
+}
+
+func (t *Label) UniqueStatementNames(ns Namespace) error {
+	return t.loop.UniqueStatementNames(ns)
+}
+
+func (t *Label) Walk(fn WalkFunc) error {
+	if err := t.loop.Walk(fn); err != nil {
+		return err
+	}
+
+	return fn(t)
+}
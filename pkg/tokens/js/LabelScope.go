@@ -0,0 +1,41 @@
+package js
+
+// scope of a labelled loop, so that nested break and continue statements can target it
+type LabelScope struct {
+	label string
+	ScopeData
+}
+
+func NewLabelScope(label string, parent Scope) *LabelScope {
+	return &LabelScope{label, newScopeData(parent)}
+}
+
+func (scope *LabelScope) IsBreakable() bool {
+	return scope.parent.IsBreakable()
+}
+
+func (scope *LabelScope) IsContinueable() bool {
+	return scope.parent.IsContinueable()
+}
+
+func (scope *LabelScope) IsAsync() bool {
+	return scope.parent.IsAsync()
+}
+
+// labels aren't visible inside nested functions
+func hasLabel(scope Scope, label string) bool {
+	for scope != nil {
+		switch s := scope.(type) {
+		case *LabelScope:
+			if s.label == label {
+				return true
+			}
+		case *FunctionScope:
+			return false
+		}
+
+		scope = scope.Parent()
+	}
+
+	return false
+}
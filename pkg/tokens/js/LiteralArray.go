@@ -60,15 +60,28 @@ func (t *LiteralArray) ResolveExpressionNames(scope Scope) error {
 }
 
 func (t *LiteralArray) EvalExpression() (values.Value, error) {
-	items := make([]values.Value, len(t.items))
+	items := make([]values.Value, 0, len(t.items))
+
+	for _, itemExpr := range t.items {
+		if spread, ok := itemExpr.(*Spread); ok {
+			spreadItems, err := spread.evalItems()
+			if err != nil {
+				return nil, err
+			}
+
+			for _, item := range spreadItems {
+				items = append(items, values.UnpackSpread(item))
+			}
+
+			continue
+		}
 
-	for i, itemExpr := range t.items {
 		item, err := itemExpr.EvalExpression()
 		if err != nil {
 			return nil, err
 		}
 
-		items[i] = item
+		items = append(items, item)
 	}
 
   common := values.CommonValue(items, t.Context())
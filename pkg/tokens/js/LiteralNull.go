@@ -23,7 +23,7 @@ func (t *LiteralNull) WriteExpression() string {
 }
 
 func (t *LiteralNull) EvalExpression() (values.Value, error) {
-	return values.NewAll(t.Context()), nil
+	return values.NewNull(t.Context()), nil
 }
 
 func (t *LiteralNull) Walk(fn WalkFunc) error {
@@ -9,6 +9,7 @@ import (
 	"github.com/computeportal/wtsuite/pkg/tokens/context"
 )
 
+// key is nil for spread entries (eg. {...a, b: 1})
 type LiteralObjectMember struct {
 	key   *Word
 	value Expression
@@ -29,12 +30,12 @@ func NewLiteralObject(keys []*Word, values []Expression, ctx context.Context) (*
 	items := make([]*LiteralObjectMember, n)
 	for i, key := range keys {
 		value := values[i]
-		if key == nil {
-			panic("nil key")
-		}
 		if value == nil {
 			panic("nil value")
 		}
+		if _, ok := value.(*Spread); key == nil && !ok {
+			panic("nil key")
+		}
 
 		items[i] = &LiteralObjectMember{key, value}
 	}
@@ -49,6 +50,11 @@ func (t *LiteralObject) Dump(indent string) string {
 	b.WriteString("LiteralObject\n")
 
 	for _, item := range t.items {
+		if item.key == nil {
+			b.WriteString(item.value.Dump(indent))
+			continue
+		}
+
 		b.WriteString(item.key.Dump(indent) + "  ")
 		b.WriteString(item.value.Dump(indent + ": "))
 	}
@@ -62,11 +68,14 @@ func (t *LiteralObject) WriteExpression() string {
 	b.WriteString("{")
 
 	for i, item := range t.items {
-		b.WriteString("'")
-		b.WriteString(item.key.value)
-		b.WriteString("'")
+		if item.key != nil {
+			b.WriteString("'")
+			b.WriteString(item.key.value)
+			b.WriteString("'")
+
+			b.WriteString(":")
+		}
 
-		b.WriteString(":")
 		b.WriteString(item.value.WriteExpression())
 
 		if i < len(t.items)-1 {
@@ -90,14 +99,33 @@ func (t *LiteralObject) ResolveExpressionNames(scope Scope) error {
 
 func (t *LiteralObject) EvalExpression() (values.Value, error) {
 	props := make(map[string]values.Value)
+	explicit := make(map[string]values.Value) // spread keys can be overwritten
+	typed := true
 
 	for _, item := range t.items {
+		if spread, ok := item.value.(*Spread); ok {
+			members, err := spread.evalMembers()
+			if err != nil {
+				return nil, err
+			}
+
+			if members == nil {
+				typed = false
+			}
+
+			for k, v := range members {
+				props[k] = v
+			}
+
+			continue
+		}
+
 		itemValue, err := item.value.EvalExpression()
 		if err != nil {
 			return nil, err
 		}
 
-		if prev, ok := props[item.key.Value()]; ok {
+		if prev, ok := explicit[item.key.Value()]; ok {
 			errCtx := item.key.Context()
 			err := errCtx.NewError("Error: key already set")
 			err.AppendContextString("Info: set here", prev.Context())
@@ -105,6 +133,11 @@ func (t *LiteralObject) EvalExpression() (values.Value, error) {
 		}
 
 		props[item.key.Value()] = itemValue
+		explicit[item.key.Value()] = itemValue
+	}
+
+	if !typed {
+		return prototypes.NewObject(nil, t.Context()), nil
 	}
 
   return prototypes.NewObject(props, t.Context()), nil
@@ -155,8 +188,10 @@ func (t *LiteralObject) Walk(fn WalkFunc) error {
 }
 
 func (m *LiteralObjectMember) Walk(fn WalkFunc) error {
-  if err := m.key.Walk(fn); err != nil {
-    return err
+  if m.key != nil {
+    if err := m.key.Walk(fn); err != nil {
+      return err
+    }
   }
 
   if err := m.value.Walk(fn); err != nil {
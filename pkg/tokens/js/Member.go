@@ -14,6 +14,7 @@ type Member struct {
 	lhsValue           values.Value
 	old                string
 	friendlyPrototypes []values.Prototype
+	class              *Class // set during eval stage if the member belongs to a wts class, used for mangling
 	TokenData
 }
 
@@ -25,6 +26,7 @@ func NewMember(object Expression, key *Word, ctx context.Context) *Member {
 		nil,
 		key.value,
 		[]values.Prototype{},
+		nil,
 		TokenData{mergedCtxs},
 	}
 }
@@ -68,12 +70,20 @@ func (t *Member) WriteExpression() string {
 		}
 
 		b.WriteString(".")
-		b.WriteString(t.key.Value())
+		b.WriteString(t.writeKey())
 
 		return b.String()
 	}
 }
 
+func (t *Member) writeKey() string {
+	if t.class != nil {
+		return t.class.writeMemberName(t.key.Value())
+	} else {
+		return t.key.Value()
+	}
+}
+
 func (t *Member) Args() []Token {
 	return []Token{t.object}
 }
@@ -125,6 +135,16 @@ func (t *Member) havePrivateAccess(objectValue values.Value) bool {
   }
 }
 
+func (t *Member) registerClass(objectValue values.Value) {
+	proto, _ := values.GetMemberPrototype(objectValue)
+
+	if cl, ok := proto.(*Class); ok {
+		t.class = cl
+	} else if values.IsAny(objectValue) || values.IsUnion(objectValue) {
+		registerUntypedMemberName(t.key.Value())
+	}
+}
+
 func (t *Member) getPackage() (*Package, error) {
 	switch obj := t.object.(type) {
 	case *VarExpression:
@@ -200,22 +220,37 @@ func (t *Member) KeyContext() context.Context {
 }
 
 func (t *Member) EvalExpression() (values.Value, error) {
-	pkgMember, err := t.GetPackageMember()
+	res, optional, err := t.evalLink()
 	if err != nil {
 		return nil, err
+	}
+
+	return endOptionalChain(res, optional, t.Context()), nil
+}
+
+func (t *Member) evalLink() (values.Value, bool, error) {
+	pkgMember, err := t.GetPackageMember()
+	if err != nil {
+		return nil, false, err
 	} else if pkgMember != nil {
 		// use a dummy VarExpression to retrieve the pkgMember value
 		tmpVe := NewConstantVarExpression("", t.Context()) // doesn't need a name
 		tmpVe.variable = pkgMember
 
-		return tmpVe.EvalExpression()
+		res, err := tmpVe.EvalExpression()
+		return res, false, err
 	}
 
-	objectValue, err := t.object.EvalExpression()
+	objectValue, optional, err := evalChainObject(t.object)
 	if err != nil {
-		return nil, err
+		return nil, false, err
 	}
 
+	res, err := t.evalMember(objectValue)
+	return res, optional, err
+}
+
+func (t *Member) evalMember(objectValue values.Value) (values.Value, error) {
 	includePrivate := t.havePrivateAccess(objectValue)
 
 	res, err := objectValue.GetMember(t.key.value, includePrivate, t.key.Context())
@@ -223,6 +258,14 @@ func (t *Member) EvalExpression() (values.Value, error) {
 		return nil, err
 	}
 
+	t.registerClass(objectValue)
+
+	if ref, ok := getTypeGuardTarget(t); ok {
+		if narrowed := ref.getNarrowed(); narrowed != nil {
+			res = values.NewContextValue(narrowed, t.Context())
+		}
+	}
+
 	return res, nil
 	//return values.NewContextValue(res, t.Context()), nil
 }
@@ -236,6 +279,11 @@ func (t *Member) EvalSet(rhsValue values.Value, ctx context.Context) error {
     return errCtx.NewError("Error: can't set package member")
   }
 
+  if isOptionalChain(t.object) {
+    errCtx := t.Context()
+    return errCtx.NewError("Error: can't assign to optional chain")
+  }
+
 	objectValue, err := t.object.EvalExpression()
 	if err != nil {
 		return err
@@ -250,6 +298,12 @@ func (t *Member) EvalSet(rhsValue values.Value, ctx context.Context) error {
 		return err
 	}
 
+	t.registerClass(objectValue)
+
+	if ref, ok := getTypeGuardTarget(t); ok {
+		ref.setNarrowed(nil)
+	}
+
 	return nil
 }
 
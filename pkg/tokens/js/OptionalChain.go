@@ -0,0 +1,234 @@
+package js
+
+import (
+	"strings"
+
+	"github.com/computeportal/wtsuite/pkg/tokens/js/values"
+
+	"github.com/computeportal/wtsuite/pkg/tokens/context"
+)
+
+// a?.b, a?.[i] and a?.(...) short-circuit the rest of the chain if a is null or undefined,
+// so null is only removed from a, and only added once to the result of the whole chain (eg. a?.b.c)
+
+// links of a chain: Member, Index, Call and their optional variants
+type chainLink interface {
+	evalLink() (values.Value, bool, error) // bool is true if the chain so far is optional
+}
+
+type OptionalMember struct {
+	Member
+}
+
+type OptionalIndex struct {
+	Index
+}
+
+type OptionalCall struct {
+	Call
+}
+
+func NewOptionalMember(object Expression, key *Word, ctx context.Context) *OptionalMember {
+	return &OptionalMember{*NewMember(object, key, ctx)}
+}
+
+func NewOptionalIndex(container Expression, index Expression, ctx context.Context) *OptionalIndex {
+	return &OptionalIndex{Index{container, index, TokenData{ctx}}}
+}
+
+func NewOptionalCall(lhs Expression, args []Expression, ctx context.Context) *OptionalCall {
+	return &OptionalCall{Call{lhs, args, TokenData{ctx}}}
+}
+
+func evalChainObject(object Expression) (values.Value, bool, error) {
+	if link, ok := object.(chainLink); ok {
+		return link.evalLink()
+	}
+
+	val, err := object.EvalExpression()
+	return val, false, err
+}
+
+func endOptionalChain(val values.Value, optional bool, ctx context.Context) values.Value {
+	if optional {
+		return values.AddNull(val, ctx)
+	}
+
+	return val
+}
+
+// parentheses end the chain
+func isOptionalChain(t Expression) bool {
+	switch t_ := t.(type) {
+	case *OptionalMember, *OptionalIndex, *OptionalCall:
+		return true
+	case *Member:
+		return isOptionalChain(t_.object)
+	case *Index:
+		return isOptionalChain(t_.container)
+	case *Call:
+		return isOptionalChain(t_.lhs)
+	default:
+		return false
+	}
+}
+
+func (t *OptionalMember) Dump(indent string) string {
+	var b strings.Builder
+
+	b.WriteString(indent)
+	b.WriteString("OptionalMember(")
+	b.WriteString(t.key.Value())
+	b.WriteString(")\n")
+
+	b.WriteString(t.object.Dump(indent + "  "))
+
+	return b.String()
+}
+
+func (t *OptionalIndex) Dump(indent string) string {
+	var b strings.Builder
+
+	b.WriteString(indent)
+	b.WriteString("OptionalIndex\n")
+
+	b.WriteString(t.container.Dump(indent + "  "))
+	b.WriteString(t.index.Dump(indent + "[ "))
+
+	return b.String()
+}
+
+func (t *OptionalCall) Dump(indent string) string {
+	var b strings.Builder
+
+	b.WriteString(indent)
+	b.WriteString("OptionalCall\n")
+
+	b.WriteString(t.lhs.Dump(indent + "  "))
+
+	for _, arg := range t.args {
+		b.WriteString(arg.Dump(indent + "( "))
+	}
+
+	return b.String()
+}
+
+func (t *OptionalMember) WriteExpression() string {
+	return t.object.WriteExpression() + "?." + t.writeKey()
+}
+
+func (t *OptionalIndex) WriteExpression() string {
+	return t.container.WriteExpression() + "?." + t.writeIndex()
+}
+
+func (t *OptionalCall) WriteExpression() string {
+	return t.lhs.WriteExpression() + "?." + t.writeArgs()
+}
+
+func (t *OptionalCall) WriteStatement(usage Usage, indent string, nl string, tab string) string {
+	return indent + t.WriteExpression()
+}
+
+func (t *OptionalMember) EvalExpression() (values.Value, error) {
+	res, _, err := t.evalLink()
+	if err != nil {
+		return nil, err
+	}
+
+	return endOptionalChain(res, true, t.Context()), nil
+}
+
+func (t *OptionalIndex) EvalExpression() (values.Value, error) {
+	res, _, err := t.evalLink()
+	if err != nil {
+		return nil, err
+	}
+
+	return endOptionalChain(res, true, t.Context()), nil
+}
+
+func (t *OptionalCall) EvalExpression() (values.Value, error) {
+	res, _, err := t.evalLink()
+	if err != nil {
+		return nil, err
+	}
+
+	return endOptionalChain(res, true, t.Context()), nil
+}
+
+func (t *OptionalMember) evalLink() (values.Value, bool, error) {
+	objectValue, _, err := evalChainObject(t.object)
+	if err != nil {
+		return nil, false, err
+	}
+
+	res, err := t.evalMember(values.RemoveNull(objectValue))
+	return res, true, err
+}
+
+func (t *OptionalIndex) evalLink() (values.Value, bool, error) {
+	containerValue, _, err := evalChainObject(t.container)
+	if err != nil {
+		return nil, false, err
+	}
+
+	res, err := t.evalIndex(values.RemoveNull(containerValue))
+	return res, true, err
+}
+
+func (t *OptionalCall) evalLink() (values.Value, bool, error) {
+	lhsVal, _, err := evalChainObject(t.lhs)
+	if err != nil {
+		return nil, false, err
+	}
+
+	res, err := t.evalCall(values.RemoveNull(lhsVal))
+	return res, true, err
+}
+
+func (t *OptionalCall) EvalStatement() error {
+	lhsVal, _, err := evalChainObject(t.lhs)
+	if err != nil {
+		return err
+	}
+
+	return t.evalCallStatement(values.RemoveNull(lhsVal))
+}
+
+func (t *OptionalMember) Walk(fn WalkFunc) error {
+  if err := t.key.Walk(fn); err != nil {
+    return err
+  }
+
+  if err := t.object.Walk(fn); err != nil {
+    return err
+  }
+
+  return fn(t)
+}
+
+func (t *OptionalIndex) Walk(fn WalkFunc) error {
+  if err := t.container.Walk(fn); err != nil {
+    return err
+  }
+
+  if err := t.index.Walk(fn); err != nil {
+    return err
+  }
+
+  return fn(t)
+}
+
+func (t *OptionalCall) Walk(fn WalkFunc) error {
+  if err := t.lhs.Walk(fn); err != nil {
+    return err
+  }
+
+  for _, arg := range t.args {
+    if err := arg.Walk(fn); err != nil {
+      return err
+    }
+  }
+
+  return fn(t)
+}
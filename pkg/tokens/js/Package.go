@@ -125,6 +125,18 @@ func (t *Package) GetValue() values.Value {
 	panic("not applicable")
 }
 
+func (t *Package) GetNarrowed(member string) values.Value {
+	return nil
+}
+
+func (t *Package) SetNarrowed(member string, v values.Value) {
+	panic("not applicable")
+}
+
+func (t *Package) ClearNarrowed() {
+	panic("not applicable")
+}
+
 func (t *Package) Path() string {
   // used for renaming packages
   return t.path
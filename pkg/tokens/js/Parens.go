@@ -46,7 +46,7 @@ func (t *Parens) EvalExpression() (values.Value, error) {
 	return t.expr.EvalExpression()
 }
 
-func (t *Parens) CollectTypeGuards(c map[Variable]values.Interface) (bool, error) {
+func (t *Parens) CollectTypeGuards(c map[typeGuardTarget]values.Value) (bool, error) {
 	if expr, ok := t.expr.(TypeGuard); ok {
 		return expr.CollectTypeGuards(c)
 	} else {
@@ -54,6 +54,14 @@ func (t *Parens) CollectTypeGuards(c map[Variable]values.Interface) (bool, error
 	}
 }
 
+func (t *Parens) CollectComplementTypeGuards(c map[typeGuardTarget]values.Value) (bool, error) {
+	if expr, ok := t.expr.(TypeGuard); ok {
+		return expr.CollectComplementTypeGuards(c)
+	} else {
+		return false, nil
+	}
+}
+
 func (t *Parens) ResolveExpressionActivity(usage Usage) error {
 	return t.expr.ResolveExpressionActivity(usage)
 }
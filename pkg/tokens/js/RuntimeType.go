@@ -0,0 +1,356 @@
+package js
+
+import (
+	"strconv"
+	"strings"
+)
+
+// runtime type descriptors, used by the JSON.parseAs and JSON.stringifyAs macros:
+//   'String', 'Number', 'Int', 'Boolean', 'Date', 'Object', 'any', 'null'
+//   {array:D}, {tuple:[D...]}, {map:D}, {object:{k:D...}}, {union:[D...]}, {enum:[v...],name:'Enum'}
+//   {tagged:{key:{field:D...}...},name:'Union'}
+//   {cl:Class,name:'Class',props:[[jsonKey,runtimeKey,D]...]}
+// class descriptors are collected in a table, so that recursive classes can be described
+type runtimeTypeWriter struct {
+	classes []*Class
+	props   []string
+}
+
+func (w *runtimeTypeWriter) writeClassProps(cl *Class) (string, error) {
+	var b strings.Builder
+
+	b.WriteString("[")
+
+	done := make(map[string]bool)
+	first := true
+
+	// inherited properties first
+	hierarchy := []*Class{}
+	for c := cl; c != nil; {
+		hierarchy = append([]*Class{c}, hierarchy...)
+
+		parent_, err := c.GetParent()
+		if err != nil {
+			return "", err
+		}
+
+		if parent_ == nil {
+			c = nil
+		} else if parent, ok := parent_.(*Class); ok {
+			c = parent
+		} else {
+			errCtx := c.parentExpr.Context()
+			return "", errCtx.NewError("Error: classes with builtin parents can't be decoded")
+		}
+	}
+
+	for _, c := range hierarchy {
+		for _, member_ := range c.members {
+			member, ok := member_.(*ClassProperty)
+			if !ok || done[member.Name()] {
+				continue
+			}
+
+			if member.typeExpr == nil {
+				errCtx := member.Context()
+				return "", errCtx.NewError("Error: untyped property can't be decoded")
+			}
+
+			d, err := w.write(member.typeExpr)
+			if err != nil {
+				return "", err
+			}
+
+			if !first {
+				b.WriteString(",")
+			}
+
+			b.WriteString("['")
+			b.WriteString(member.Name())
+			b.WriteString("','")
+			b.WriteString(cl.writeMemberName(member.Name()))
+			b.WriteString("',")
+			b.WriteString(d)
+			b.WriteString("]")
+
+			done[member.Name()] = true
+			first = false
+		}
+	}
+
+	b.WriteString("]")
+
+	return b.String(), nil
+}
+
+func (w *runtimeTypeWriter) writeClass(cl *Class) (string, error) {
+	i := -1
+	for j, other := range w.classes {
+		if other == cl {
+			i = j
+			break
+		}
+	}
+
+	if i == -1 {
+		if cl.IsAbstract() {
+			errCtx := cl.Context()
+			return "", errCtx.NewError("Error: abstract class " + cl.Name() + " can't be decoded")
+		}
+
+		i = len(w.classes)
+		w.classes = append(w.classes, cl)
+		w.props = append(w.props, "")
+
+		props, err := w.writeClassProps(cl)
+		if err != nil {
+			return "", err
+		}
+
+		w.props[i] = props
+	}
+
+	return "T[" + strconv.Itoa(i) + "]", nil
+}
+
+func (w *runtimeTypeWriter) writeList(tes []*TypeExpressionMember) (string, error) {
+	var b strings.Builder
+
+	b.WriteString("[")
+	for i, p := range tes {
+		d, err := w.write(p.typeExpr)
+		if err != nil {
+			return "", err
+		}
+
+		if i > 0 {
+			b.WriteString(",")
+		}
+		b.WriteString(d)
+	}
+	b.WriteString("]")
+
+	return b.String(), nil
+}
+
+func (w *runtimeTypeWriter) writeFields(names []*Word, tes []*TypeExpression) (string, error) {
+	var b strings.Builder
+
+	b.WriteString("{")
+	for i, te := range tes {
+		d, err := w.write(te)
+		if err != nil {
+			return "", err
+		}
+
+		if i > 0 {
+			b.WriteString(",")
+		}
+		b.WriteString(names[i].Value())
+		b.WriteString(":")
+		b.WriteString(d)
+	}
+	b.WriteString("}")
+
+	return b.String(), nil
+}
+
+func (w *runtimeTypeWriter) writeVariants(name string, variants []*TaggedUnionVariant) (string, error) {
+	var b strings.Builder
+
+	b.WriteString("{tagged:{")
+	for i, v := range variants {
+		fields, err := w.writeFields(v.fieldNames, v.fieldTypes)
+		if err != nil {
+			return "", err
+		}
+
+		if i > 0 {
+			b.WriteString(",")
+		}
+		b.WriteString(v.Key())
+		b.WriteString(":")
+		b.WriteString(fields)
+	}
+	b.WriteString("},name:'")
+	b.WriteString(name)
+	b.WriteString("'}")
+
+	return b.String(), nil
+}
+
+func (w *runtimeTypeWriter) write(te *TypeExpression) (string, error) {
+	ctx := te.Context()
+
+	switch te.Name() {
+	case "any", "null":
+		return "'" + te.Name() + "'", nil
+	case "Array":
+		if te.parameters == nil {
+			return "{array:'any'}", nil
+		}
+
+		d, err := w.write(te.parameters[0].typeExpr)
+		if err != nil {
+			return "", err
+		}
+
+		return "{array:" + d + "}", nil
+	case "Tuple":
+		ds, err := w.writeList(te.parameters)
+		if err != nil {
+			return "", err
+		}
+
+		return "{tuple:" + ds + "}", nil
+	case "Object":
+		if te.parameters == nil {
+			return "'Object'", nil
+		} else if te.parameters[0].key == nil {
+			d, err := w.write(te.parameters[0].typeExpr)
+			if err != nil {
+				return "", err
+			}
+
+			return "{map:" + d + "}", nil
+		} else {
+			names := make([]*Word, len(te.parameters))
+			tes := make([]*TypeExpression, len(te.parameters))
+			for i, p := range te.parameters {
+				names[i] = p.key
+				tes[i] = p.typeExpr
+			}
+
+			fields, err := w.writeFields(names, tes)
+			if err != nil {
+				return "", err
+			}
+
+			return "{object:" + fields + "}", nil
+		}
+	case "Union":
+		ds, err := w.writeList(te.parameters)
+		if err != nil {
+			return "", err
+		}
+
+		return "{union:" + ds + "}", nil
+	case "class", "function", "void", "Set", "Map", "Promise", "Event", "IDBRequest":
+		return "", ctx.NewError("Error: " + te.Name() + " can't be decoded from JSON")
+	}
+
+	interf := te.GetInterface()
+	if interf == nil {
+		return "", ctx.NewError("Error: expected an interface")
+	}
+
+	switch interf := interf.(type) {
+	case *Class:
+		return w.writeClass(interf)
+	case *Enum:
+		var b strings.Builder
+		b.WriteString("{enum:[")
+		for i, member := range interf.members {
+			if i > 0 {
+				b.WriteString(",")
+			}
+			b.WriteString(member.val.WriteExpression())
+		}
+		b.WriteString("],name:'")
+		b.WriteString(interf.nameExpr.origName)
+		b.WriteString("'}")
+
+		return b.String(), nil
+	case *TaggedUnion:
+		return w.writeVariants(interf.nameExpr.origName, interf.variants)
+	case *TaggedUnionVariant:
+		return w.writeVariants(interf.union.nameExpr.origName+"."+interf.Key(), []*TaggedUnionVariant{interf})
+	case *Interface:
+		if interf.IsRPC() {
+			return "", ctx.NewError("Error: rpc interface " + interf.Name() + " can't be decoded from JSON")
+		}
+
+		// the first implementation that matches is used
+		var b strings.Builder
+		b.WriteString("{union:[")
+		for i, proto := range interf.prototypes {
+			cl, ok := proto.(*Class)
+			if !ok {
+				errCtx := proto.Context()
+				return "", errCtx.NewError("Error: implementation of " + interf.Name() + " can't be decoded")
+			}
+
+			d, err := w.writeClass(cl)
+			if err != nil {
+				return "", err
+			}
+
+			if i > 0 {
+				b.WriteString(",")
+			}
+			b.WriteString(d)
+		}
+		b.WriteString("]}")
+
+		return b.String(), nil
+	}
+
+	switch interf.Name() {
+	case "Boolean", "Date", "Int", "Number", "String":
+		return "'" + interf.Name() + "'", nil
+	default:
+		return "", ctx.NewError("Error: " + interf.Name() + " can't be decoded from JSON")
+	}
+}
+
+// returns an error if the type can't be decoded, so this should be called during the eval stage
+func CheckRuntimeType(te *TypeExpression) error {
+	w := &runtimeTypeWriter{make([]*Class, 0), make([]string, 0)}
+
+	_, err := w.write(te)
+
+	return err
+}
+
+func (t *TypeExpression) WriteRuntimeType() string {
+	w := &runtimeTypeWriter{make([]*Class, 0), make([]string, 0)}
+
+	d, err := w.write(t)
+	if err != nil {
+		panic("should've been caught by CheckRuntimeType")
+	}
+
+	if len(w.classes) == 0 {
+		return d
+	}
+
+	var b strings.Builder
+
+	b.WriteString("(function(){let T=[")
+	for i, cl := range w.classes {
+		if i > 0 {
+			b.WriteString(",")
+		}
+
+		b.WriteString("{cl:")
+		b.WriteString(cl.nameExpr.WriteExpression())
+		b.WriteString(",name:'")
+		b.WriteString(cl.nameExpr.origName) // unaffected by compact renaming
+		b.WriteString("'}")
+	}
+	b.WriteString("];")
+
+	for i, props := range w.props {
+		b.WriteString("T[")
+		b.WriteString(strconv.Itoa(i))
+		b.WriteString("].props=")
+		b.WriteString(props)
+		b.WriteString(";")
+	}
+
+	b.WriteString("return ")
+	b.WriteString(d)
+	b.WriteString("}())")
+
+	return b.String()
+}
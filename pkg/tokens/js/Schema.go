@@ -0,0 +1,481 @@
+package js
+
+import (
+	"encoding/json"
+	"strconv"
+
+	"github.com/computeportal/wtsuite/pkg/tokens/context"
+)
+
+const (
+	JSON_SCHEMA_DIALECT = "https://json-schema.org/draft/2020-12/schema"
+	OPENAPI_VERSION     = "3.1.0"
+)
+
+// schemas describe the serialized form (see ObjectFromInstanceHeader), so every object has a __type__ property
+type Schema map[string]interface{}
+
+// implemented by Class, Interface, Enum and TaggedUnion
+type SchemaType interface {
+	Name() string
+	Context() context.Context
+	schema(b *SchemaBuilder) (Schema, error)
+}
+
+type SchemaBuilder struct {
+	refPrefix string
+	defs      map[string]Schema
+	types     map[string]SchemaType // to detect different types with the same name
+}
+
+func newSchemaBuilder(refPrefix string) *SchemaBuilder {
+	return &SchemaBuilder{refPrefix, make(map[string]Schema), make(map[string]SchemaType)}
+}
+
+func (b *SchemaBuilder) ref(t SchemaType) (Schema, error) {
+	name := t.Name()
+
+	if prev, ok := b.types[name]; ok {
+		if prev != t {
+			errCtx := t.Context()
+			err := errCtx.NewError("Error: schema name " + name + " already used by another type")
+			err.AppendContextString("Info: other type defined here", prev.Context())
+			return nil, err
+		}
+	} else {
+		// registered before generating the schema, so that recursive types terminate
+		b.types[name] = t
+
+		s, err := t.schema(b)
+		if err != nil {
+			return nil, err
+		}
+
+		b.defs[name] = s
+	}
+
+	return Schema{"$ref": b.refPrefix + name}, nil
+}
+
+func typeTagSchema(typeName string) Schema {
+	return Schema{"const": typeName}
+}
+
+func newObjectSchema(typeName string, props map[string]Schema, required []string) Schema {
+	allProps := Schema{"__type__": typeTagSchema(typeName)}
+	for k, p := range props {
+		allProps[k] = p
+	}
+
+	return Schema{
+		"type":       "object",
+		"properties": allProps,
+		"required":   append([]string{"__type__"}, required...),
+	}
+}
+
+func (b *SchemaBuilder) typeSchema(te *TypeExpression) (Schema, error) {
+	ctx := te.Context()
+
+	switch te.Name() {
+	case "any":
+		return Schema{}, nil
+	case "null":
+		return Schema{"type": "null"}, nil
+	case "Array":
+		if te.parameters == nil {
+			return Schema{"type": "array"}, nil
+		}
+
+		items, err := b.typeSchema(te.parameters[0].typeExpr)
+		if err != nil {
+			return nil, err
+		}
+
+		return Schema{"type": "array", "items": items}, nil
+	case "Tuple":
+		items := make([]Schema, len(te.parameters))
+		for i, p := range te.parameters {
+			item, err := b.typeSchema(p.typeExpr)
+			if err != nil {
+				return nil, err
+			}
+
+			items[i] = item
+		}
+
+		return Schema{
+			"type":        "array",
+			"prefixItems": items,
+			"minItems":    len(items),
+			"maxItems":    len(items),
+		}, nil
+	case "Object":
+		if te.parameters == nil {
+			return newObjectSchema("Object", nil, nil), nil
+		} else if te.parameters[0].key == nil {
+			// map-like
+			content, err := b.typeSchema(te.parameters[0].typeExpr)
+			if err != nil {
+				return nil, err
+			}
+
+			s := newObjectSchema("Object", nil, nil)
+			s["additionalProperties"] = content
+			return s, nil
+		} else {
+			props := make(map[string]Schema)
+			required := make([]string, 0)
+			for _, p := range te.parameters {
+				prop, err := b.typeSchema(p.typeExpr)
+				if err != nil {
+					return nil, err
+				}
+
+				props[p.key.Value()] = prop
+				required = append(required, p.key.Value())
+			}
+
+			return newObjectSchema("Object", props, required), nil
+		}
+	case "Union":
+		alts := make([]Schema, len(te.parameters))
+		for i, p := range te.parameters {
+			alt, err := b.typeSchema(p.typeExpr)
+			if err != nil {
+				return nil, err
+			}
+
+			alts[i] = alt
+		}
+
+		return Schema{"anyOf": alts}, nil
+	case "class", "function", "void", "Set", "Map", "Promise", "Event", "IDBRequest":
+		return nil, ctx.NewError("Error: " + te.Name() + " can't be serialized")
+	}
+
+	interf := te.GetInterface()
+	if interf == nil {
+		return nil, ctx.NewError("Error: expected an interface")
+	}
+
+	switch interf := interf.(type) {
+	case SchemaType:
+		return b.ref(interf)
+	case *TaggedUnionVariant:
+		return interf.variantSchema(b)
+	}
+
+	switch interf.Name() {
+	case "Boolean":
+		return Schema{"type": "boolean"}, nil
+	case "Int":
+		return Schema{"type": "integer"}, nil
+	case "Number":
+		return Schema{"type": "number"}, nil
+	case "String":
+		return Schema{"type": "string"}, nil
+	case "Date":
+		return newObjectSchema("Date", map[string]Schema{"time": Schema{"type": "number"}}, []string{"time"}), nil
+	case "Error":
+		return newObjectSchema("Error", map[string]Schema{"message": Schema{"type": "string"}}, []string{"message"}), nil
+	default:
+		return nil, ctx.NewError("Error: " + interf.Name() + " can't be exported as a schema")
+	}
+}
+
+func (t *Class) schema(b *SchemaBuilder) (Schema, error) {
+	if !t.IsUniversal() {
+		errCtx := t.Context()
+		return nil, errCtx.NewError("Error: class " + t.Name() + " isn't universal (hint: class " + t.Name() + " universe <name> {...})")
+	}
+
+	props := make(map[string]Schema)
+	required := make([]string, 0)
+
+	// inherited properties are serialized too
+	for cl := t; cl != nil; {
+		for _, member_ := range cl.members {
+			if member, ok := member_.(*ClassProperty); ok {
+				if _, ok := props[member.Name()]; ok {
+					continue
+				}
+
+				if member.typeExpr == nil {
+					errCtx := member.Context()
+					return nil, errCtx.NewError("Error: untyped property can't be exported as a schema")
+				}
+
+				prop, err := b.typeSchema(member.typeExpr)
+				if err != nil {
+					return nil, err
+				}
+
+				props[member.Name()] = prop
+				required = append(required, member.Name())
+			}
+		}
+
+		if cl.parentExpr == nil {
+			cl = nil
+		} else if parent, ok := cl.parentExpr.GetInterface().(*Class); ok {
+			cl = parent
+		} else {
+			errCtx := cl.parentExpr.Context()
+			return nil, errCtx.NewError("Error: parent can't be exported as a schema")
+		}
+	}
+
+	return newObjectSchema(t.universalName, props, required), nil
+}
+
+func (t *Interface) schema(b *SchemaBuilder) (Schema, error) {
+	if t.IsRPC() {
+		errCtx := t.Context()
+		return nil, errCtx.NewError("Error: rpc interface " + t.Name() + " can only be exported as an openapi document")
+	}
+
+	if !t.IsUniversal() {
+		errCtx := t.Context()
+		return nil, errCtx.NewError("Error: interface " + t.Name() + " isn't universal")
+	}
+
+	alts := make([]Schema, 0)
+	for _, proto := range t.prototypes {
+		cl, ok := proto.(*Class)
+		if !ok {
+			errCtx := proto.Context()
+			return nil, errCtx.NewError("Error: implementation of " + t.Name() + " can't be exported as a schema")
+		}
+
+		alt, err := b.ref(cl)
+		if err != nil {
+			return nil, err
+		}
+
+		alts = append(alts, alt)
+	}
+
+	return Schema{"anyOf": alts}, nil
+}
+
+func (t *Enum) schema(b *SchemaBuilder) (Schema, error) {
+	s, err := b.typeSchema(t.parentExpr)
+	if err != nil {
+		return nil, err
+	}
+
+	vs := make([]interface{}, len(t.members))
+	for i, member := range t.members {
+		switch lit := member.val.(type) {
+		case *LiteralBoolean:
+			vs[i] = lit.Value()
+		case *LiteralFloat:
+			vs[i] = lit.Value()
+		case *LiteralInt:
+			vs[i] = lit.Value()
+		case *LiteralString:
+			vs[i] = lit.Value()
+		default:
+			errCtx := member.Context()
+			return nil, errCtx.NewError("Error: enum member can't be exported as a schema (expected a literal value)")
+		}
+	}
+
+	s["enum"] = vs
+
+	return s, nil
+}
+
+func (t *TaggedUnion) schema(b *SchemaBuilder) (Schema, error) {
+	alts := make([]Schema, len(t.variants))
+	for i, v := range t.variants {
+		alt, err := v.variantSchema(b)
+		if err != nil {
+			return nil, err
+		}
+
+		alts[i] = alt
+	}
+
+	return Schema{"oneOf": alts}, nil
+}
+
+// variants are plain objects
+func (v *TaggedUnionVariant) variantSchema(b *SchemaBuilder) (Schema, error) {
+	props := map[string]Schema{TaggedUnionTagName: Schema{"const": v.Key()}}
+	required := []string{TaggedUnionTagName}
+
+	for i, fieldName := range v.fieldNames {
+		field, err := b.typeSchema(v.fieldTypes[i])
+		if err != nil {
+			return nil, err
+		}
+
+		props[fieldName.Value()] = field
+		required = append(required, fieldName.Value())
+	}
+
+	return newObjectSchema("Object", props, required), nil
+}
+
+func writeSchemaDocument(doc Schema) (string, error) {
+	out, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return "", err
+	}
+
+	return string(out) + "\n", nil
+}
+
+// a JSON Schema document with a definition for each type (and the types they depend on)
+func WriteJSONSchema(ts []SchemaType) (string, error) {
+	b := newSchemaBuilder("#/$defs/")
+
+	for _, t := range ts {
+		if _, err := b.ref(t); err != nil {
+			return "", err
+		}
+	}
+
+	return writeSchemaDocument(Schema{
+		"$schema": JSON_SCHEMA_DIALECT,
+		"$defs":   b.defs,
+	})
+}
+
+func (fi *FunctionInterface) openAPISchemas(b *SchemaBuilder) (Schema, Schema, error) {
+	props := map[string]Schema{
+		"type":    Schema{"const": "request"},
+		"id":      Schema{"type": "integer"},
+		"channel": Schema{"const": 0},
+		"name":    Schema{"const": fi.Name()},
+	}
+	required := []string{"type", "id", "channel", "name"}
+
+	for i, arg := range fi.args {
+		argSchema, err := b.typeSchema(arg.typeExpr)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		argName := "arg" + strconv.Itoa(i)
+		props[argName] = argSchema
+		required = append(required, argName)
+	}
+
+	request := newObjectSchema("Object", props, required)
+
+	props = map[string]Schema{
+		"type": Schema{"const": "response"},
+		"id":   Schema{"type": "integer"},
+	}
+	required = []string{"type", "id"}
+
+	// rpc members are checked to return promises
+	ret := fi.ret
+	if ret.Name() == "Promise" && ret.parameters != nil {
+		ret = ret.parameters[0].typeExpr
+	}
+
+	if ret.Name() != "void" && ret.Name() != "Promise" {
+		value, err := b.typeSchema(ret)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		props["value"] = value
+		required = append(required, "value")
+	}
+
+	response := newObjectSchema("Object", props, required)
+
+	return request, response, nil
+}
+
+func (t *Interface) collectRPCMembers(members []*FunctionInterface) []*FunctionInterface {
+	members = append(members, t.members...)
+
+	for _, parent := range t.parents {
+		if parentInterf, ok := parent.GetInterface().(*Interface); ok {
+			members = parentInterf.collectRPCMembers(members)
+		}
+	}
+
+	return members
+}
+
+// the envelope of each message is described, because all members share the same endpoint
+func (t *Interface) openAPIPathItem(b *SchemaBuilder) (Schema, error) {
+	if !t.IsRPC() {
+		errCtx := t.Context()
+		return nil, errCtx.NewError("Error: " + t.Name() + " isn't an rpc interface")
+	}
+
+	if err := t.CheckStatelessRPC(); err != nil {
+		return nil, err
+	}
+
+	requests := make([]Schema, 0)
+	responses := []Schema{
+		newObjectSchema("Error", map[string]Schema{"message": Schema{"type": "string"}}, []string{"message"}),
+	}
+
+	for _, member := range t.collectRPCMembers([]*FunctionInterface{}) {
+		request, response, err := member.openAPISchemas(b)
+		if err != nil {
+			return nil, err
+		}
+
+		requests = append(requests, request)
+		responses = append(responses, response)
+	}
+
+	return Schema{
+		"post": Schema{
+			"operationId": t.Name(),
+			"requestBody": Schema{
+				"required": true,
+				"content": Schema{
+					"application/json": Schema{"schema": Schema{"oneOf": requests}},
+				},
+			},
+			"responses": Schema{
+				"200": Schema{
+					"description": "response to the request with the same id, or an Error",
+					"content": Schema{
+						"application/json": Schema{"schema": Schema{"anyOf": responses}},
+					},
+				},
+				"400": Schema{"description": "malformed request"},
+			},
+		},
+	}, nil
+}
+
+// an OpenAPI document with a path for each rpc interface, as served by HTTPRPC
+func WriteOpenAPI(title string, interfs []*Interface) (string, error) {
+	b := newSchemaBuilder("#/components/schemas/")
+
+	paths := make(Schema)
+	for _, interf := range interfs {
+		item, err := interf.openAPIPathItem(b)
+		if err != nil {
+			return "", err
+		}
+
+		paths["/"+interf.Name()] = item
+	}
+
+	return writeSchemaDocument(Schema{
+		"openapi": OPENAPI_VERSION,
+		"info": Schema{
+			"title":   title,
+			"version": "1.0.0",
+		},
+		"paths": paths,
+		"components": Schema{
+			"schemas": b.defs,
+		},
+	})
+}
@@ -0,0 +1,112 @@
+package js
+
+import (
+	"strings"
+
+	"github.com/computeportal/wtsuite/pkg/tokens/js/prototypes"
+	"github.com/computeportal/wtsuite/pkg/tokens/js/values"
+
+	"github.com/computeportal/wtsuite/pkg/tokens/context"
+)
+
+// ...x, only valid as call argument, literal array item or literal object entry
+type Spread struct {
+	expr Expression
+	TokenData
+}
+
+func NewSpread(expr Expression, ctx context.Context) *Spread {
+	return &Spread{expr, TokenData{ctx}}
+}
+
+func (t *Spread) Dump(indent string) string {
+	var b strings.Builder
+
+	b.WriteString(indent)
+	b.WriteString("Spread\n")
+
+	b.WriteString(t.expr.Dump(indent + "..."))
+
+	return b.String()
+}
+
+func (t *Spread) WriteExpression() string {
+	return "..." + t.expr.WriteExpression()
+}
+
+func (t *Spread) ResolveExpressionNames(scope Scope) error {
+	return t.expr.ResolveExpressionNames(scope)
+}
+
+func (t *Spread) EvalExpression() (values.Value, error) {
+	errCtx := t.Context()
+	return nil, errCtx.NewError("Error: unexpected spread")
+}
+
+// tuples expand into their items, other iterables into a spread of unknown length
+func (t *Spread) evalItems() ([]values.Value, error) {
+	val, err := t.expr.EvalExpression()
+	if err != nil {
+		return nil, err
+	}
+
+	ctx := t.Context()
+
+	if content := prototypes.GetTupleContent(val); content != nil {
+		items := make([]values.Value, len(content))
+		for i, item := range content {
+			items[i] = values.NewContextValue(item, ctx)
+		}
+
+		return items, nil
+	}
+
+	ofValue, err := val.GetMember(".getof", false, ctx)
+	if err != nil || ofValue == nil {
+		return nil, ctx.NewError("Error: " + val.TypeName() + " is not iterable")
+	}
+
+	return []values.Value{values.NewSpread(ofValue, ctx)}, nil
+}
+
+// returns nil if the keys of the spread Object aren't known
+func (t *Spread) evalMembers() (map[string]values.Value, error) {
+	val, err := t.expr.EvalExpression()
+	if err != nil {
+		return nil, err
+	}
+
+	if values.IsAny(val) {
+		return nil, nil
+	} else if !prototypes.IsObject(val) {
+		errCtx := t.Context()
+		return nil, errCtx.NewError("Error: expected Object, got " + val.TypeName())
+	}
+
+	members, err := prototypes.GetLiteralObjectMembers(val)
+	if err != nil {
+		return nil, nil
+	}
+
+	return members, nil
+}
+
+func (t *Spread) ResolveExpressionActivity(usage Usage) error {
+	return t.expr.ResolveExpressionActivity(usage)
+}
+
+func (t *Spread) UniversalExpressionNames(ns Namespace) error {
+	return t.expr.UniversalExpressionNames(ns)
+}
+
+func (t *Spread) UniqueExpressionNames(ns Namespace) error {
+	return t.expr.UniqueExpressionNames(ns)
+}
+
+func (t *Spread) Walk(fn WalkFunc) error {
+	if err := t.expr.Walk(fn); err != nil {
+		return err
+	}
+
+	return fn(t)
+}
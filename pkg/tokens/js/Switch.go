@@ -13,11 +13,13 @@ type Switch struct {
 	clauses    []Expression
 	grouped    [][]Statement
 	hasDefault bool
+	tags       []string // set during eval stage if expr is a tagged union
+	exhaustive bool     // set during eval stage
 	Block      // dont use the Block.statements
 }
 
 func NewSwitch(expr Expression, ctx context.Context) (*Switch, error) {
-	return &Switch{expr, make([]Expression, 0), make([][]Statement, 0), false, newBlock(ctx)}, nil
+	return &Switch{expr, make([]Expression, 0), make([][]Statement, 0), false, nil, false, newBlock(ctx)}, nil
 }
 
 func (t *Switch) AddCase(clause Expression) error {
@@ -127,7 +129,11 @@ func (t *Switch) WriteStatement(usage Usage, indent string, nl string, tab strin
 
 	b.WriteString(indent)
 	b.WriteString("switch(")
-	b.WriteString(t.expr.WriteExpression())
+	if t.tags != nil {
+		b.WriteString(t.writeTaggedUnionExpression())
+	} else {
+		b.WriteString(t.expr.WriteExpression())
+	}
 	b.WriteString("){")
 
 	for i, clause := range t.clauses {
@@ -136,6 +142,10 @@ func (t *Switch) WriteStatement(usage Usage, indent string, nl string, tab strin
 		b.WriteString(tab)
 		if clause == nil {
 			b.WriteString("default")
+		} else if t.tags != nil {
+			b.WriteString("case '")
+			b.WriteString(t.tags[i])
+			b.WriteString("'")
 		} else {
 			b.WriteString("case ")
 			b.WriteString(clause.WriteExpression())
@@ -206,6 +216,12 @@ func (t *Switch) EvalStatement() error {
 		return errCtx.NewError("Error: not a switchable value (" + exprVal.TypeName() + ")")
   }
 
+	t.exhaustive = t.hasDefault
+
+	if tu, ok := values.GetInterface(exprVal).(*TaggedUnion); ok {
+		return t.evalTaggedUnion(tu)
+	}
+
 	for i, clause := range t.clauses {
 		if clause != nil {
 			clauseVal, err := clause.EvalExpression()
@@ -227,6 +243,185 @@ func (t *Switch) EvalStatement() error {
 		}
 	}
 
+	if en, ok := values.GetInterface(exprVal).(*Enum); ok {
+		if err := t.checkEnumExhaustive(en); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (t *Switch) writeTaggedUnionExpression() string {
+	switch t.expr.(type) {
+	case *VarExpression, *Member, *Call:
+		return t.expr.WriteExpression() + "." + TaggedUnionTagName
+	default:
+		return "(" + t.expr.WriteExpression() + ")." + TaggedUnionTagName
+	}
+}
+
+// returns the object (eg. *Enum or *TaggedUnion) and the key of clauses like Color.Red, nil if clause isn't of that form
+func getSwitchClauseMember(clause Expression) (interface{}, *Word) {
+	member, ok := clause.(*Member)
+	if !ok {
+		return nil, nil
+	}
+
+	var variable Variable = nil
+	switch obj := member.object.(type) {
+	case *VarExpression:
+		variable = obj.GetVariable()
+	case *Member:
+		pkgMember, err := obj.GetPackageMember()
+		if err == nil {
+			variable = pkgMember
+		}
+	}
+
+	if variable == nil {
+		return nil, nil
+	}
+
+	return variable.GetObject(), member.key
+}
+
+func (t *Switch) newMissingCasesError(name string, missing []string) error {
+	errCtx := t.Context()
+	return errCtx.NewError("Error: switch over " + name + " isn't exhaustive (missing " +
+		strings.Join(missing, ", ") + ", hint: add the cases or a default)")
+}
+
+// only possible if all the clauses are members of the enum
+func (t *Switch) checkEnumExhaustive(en *Enum) error {
+	if t.hasDefault {
+		return nil
+	}
+
+	covered := make(map[string]bool)
+	for _, clause := range t.clauses {
+		obj, key := getSwitchClauseMember(clause)
+		if obj != interface{}(en) {
+			return nil
+		}
+
+		covered[key.Value()] = true
+	}
+
+	missing := make([]string, 0)
+	for _, member := range en.members {
+		if !covered[member.key.Value()] {
+			missing = append(missing, member.key.Value())
+		}
+	}
+
+	if len(missing) > 0 {
+		return t.newMissingCasesError(en.Name(), missing)
+	}
+
+	t.exhaustive = true
+
+	return nil
+}
+
+func isJumpStatement(st Statement) bool {
+	switch st.(type) {
+	case *Break, *Continue, *Return, *Throw:
+		return true
+	default:
+		return false
+	}
+}
+
+func (t *Switch) evalTaggedUnion(tu *TaggedUnion) error {
+	variants := make([]*TaggedUnionVariant, len(t.clauses))
+	covered := make(map[string]Expression)
+	t.tags = make([]string, len(t.clauses))
+
+	for i, clause := range t.clauses {
+		if clause == nil {
+			continue
+		}
+
+		obj, key := getSwitchClauseMember(clause)
+		if obj != interface{}(tu) {
+			errCtx := clause.Context()
+			return errCtx.NewError("Error: expected a variant of " + tu.Name())
+		}
+
+		variant := tu.GetVariant(key.Value())
+		if variant == nil {
+			errCtx := key.Context()
+			return errCtx.NewError("Error: " + tu.Name() + "." + key.Value() + " isn't a variant")
+		}
+
+		if other, ok := covered[variant.Key()]; ok {
+			errCtx := clause.Context()
+			err := errCtx.NewError("Error: duplicate case")
+			err.AppendContextString("Info: also handled here", other.Context())
+			return err
+		}
+
+		covered[variant.Key()] = clause
+
+		if _, err := clause.EvalExpression(); err != nil {
+			return err
+		}
+
+		variants[i] = variant
+		t.tags[i] = variant.Key()
+	}
+
+	if !t.hasDefault {
+		missing := make([]string, 0)
+		for _, variant := range tu.GetVariants() {
+			if _, ok := covered[variant.Key()]; !ok {
+				missing = append(missing, variant.Key())
+			}
+		}
+
+		if len(missing) > 0 {
+			return t.newMissingCasesError(tu.Name(), missing)
+		}
+
+		t.exhaustive = true
+	}
+
+	// the switched variable is narrowed to the variants that can reach each case (including fallthrough)
+	narrowed, canNarrow := getTypeGuardTarget(t.expr)
+
+	reaching := make([]values.Value, 0)
+	reachedByDefault := false
+
+	for i, group := range t.grouped {
+		if variants[i] == nil {
+			reachedByDefault = true
+		} else {
+			reaching = append(reaching, values.NewInstance(variants[i], t.clauses[i].Context()))
+		}
+
+		if canNarrow && !reachedByDefault {
+			oldValues := applyTypeGuards(map[typeGuardTarget]values.Value{
+				narrowed: values.NewUnion(reaching, t.clauses[i].Context()),
+			})
+
+			err := t.Block.evalStatements(group)
+
+			restoreTypeGuards(oldValues)
+
+			if err != nil {
+				return err
+			}
+		} else if err := t.Block.evalStatements(group); err != nil {
+			return err
+		}
+
+		if len(group) > 0 && isJumpStatement(group[len(group)-1]) {
+			reaching = make([]values.Value, 0)
+			reachedByDefault = false
+		}
+	}
+
 	return nil
 }
 
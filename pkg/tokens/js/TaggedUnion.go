@@ -0,0 +1,504 @@
+package js
+
+import (
+	"strings"
+
+	"github.com/computeportal/wtsuite/pkg/tokens/js/prototypes"
+	"github.com/computeportal/wtsuite/pkg/tokens/js/values"
+
+	"github.com/computeportal/wtsuite/pkg/tokens/context"
+)
+
+// the discriminant of the plain js objects
+const TaggedUnionTagName = "tag"
+
+type TaggedUnionVariant struct {
+	union      *TaggedUnion
+	key        *Word
+	fieldNames []*Word
+	fieldTypes []*TypeExpression
+}
+
+// enum with payload variants, eg. enum Shape {Circle(radius Number), Rect(w Number, h Number), Empty}
+// variants are plain js objects with a tag
+type TaggedUnion struct {
+	nameExpr *TypeExpression
+	variants []*TaggedUnionVariant
+	TokenData
+}
+
+func NewTaggedUnion(nameExpr *TypeExpression, ctx context.Context) *TaggedUnion {
+	tu := &TaggedUnion{
+		nameExpr,
+		make([]*TaggedUnionVariant, 0),
+		TokenData{ctx},
+	}
+
+	tu.nameExpr.GetVariable().SetObject(tu)
+
+	return tu
+}
+
+func (t *TaggedUnion) AddVariant(key *Word, fieldNames []*Word, fieldTypes []*TypeExpression) error {
+	switch key.Value() {
+	case TaggedUnionTagName, "constructor", "prototype", "name", "length":
+		errCtx := key.Context()
+		return errCtx.NewError("Error: forbidden name for tagged union variant")
+	}
+
+	uniqueNames := make(map[string]*Word)
+	for _, fieldName := range fieldNames {
+		if fieldName.Value() == TaggedUnionTagName {
+			errCtx := fieldName.Context()
+			return errCtx.NewError("Error: forbidden name for tagged union field (used as discriminant)")
+		}
+
+		if other, ok := uniqueNames[fieldName.Value()]; ok {
+			errCtx := fieldName.Context()
+			err := errCtx.NewError("Error: field name not unique")
+			err.AppendContextString("Info: also defined here", other.Context())
+			return err
+		}
+
+		uniqueNames[fieldName.Value()] = fieldName
+	}
+
+	t.variants = append(t.variants, &TaggedUnionVariant{t, key, fieldNames, fieldTypes})
+
+	return nil
+}
+
+func (t *TaggedUnion) Name() string {
+	return t.nameExpr.Name()
+}
+
+func (t *TaggedUnion) GetVariable() Variable {
+	return t.nameExpr.GetVariable()
+}
+
+// returns nil if not found
+func (t *TaggedUnion) GetVariant(key string) *TaggedUnionVariant {
+	for _, variant := range t.variants {
+		if variant.key.Value() == key {
+			return variant
+		}
+	}
+
+	return nil
+}
+
+func (t *TaggedUnion) GetVariants() []*TaggedUnionVariant {
+	return t.variants
+}
+
+func (t *TaggedUnion) GetPrototypes() ([]values.Prototype, error) {
+	return []values.Prototype{}, nil
+}
+
+func (t *TaggedUnion) GetParent() (values.Prototype, error) {
+	return nil, nil
+}
+
+func (t *TaggedUnion) GetInterfaces() ([]values.Interface, error) {
+	return []values.Interface{}, nil
+}
+
+// can never be directly constructed
+func (t *TaggedUnion) GetClassValue() (*values.Class, error) {
+	return nil, nil
+}
+
+func (t *TaggedUnion) AddStatement(st Statement) {
+	panic("not available")
+}
+
+func (t *TaggedUnion) Dump(indent string) string {
+	var b strings.Builder
+
+	b.WriteString(indent)
+	b.WriteString("TaggedUnion(")
+	b.WriteString(t.nameExpr.Dump(""))
+	b.WriteString(")\n")
+
+	for _, variant := range t.variants {
+		b.WriteString(indent + "  ")
+		b.WriteString(variant.key.Value())
+		b.WriteString("\n")
+
+		for i, fieldName := range variant.fieldNames {
+			b.WriteString(variant.fieldTypes[i].Dump(indent + "    " + fieldName.Value() + ":"))
+		}
+	}
+
+	return b.String()
+}
+
+func (t *TaggedUnion) WriteStatement(usage Usage, indent string, nl string, tab string) string {
+	var b strings.Builder
+
+	b.WriteString(indent)
+	b.WriteString("class ")
+	b.WriteString(t.nameExpr.WriteExpression())
+	b.WriteString("{")
+
+	for _, variant := range t.variants {
+		b.WriteString(nl)
+		b.WriteString(indent + tab)
+		b.WriteString("static ")
+		if len(variant.fieldNames) == 0 {
+			b.WriteString("get ")
+		}
+		b.WriteString(variant.key.Value())
+		b.WriteString("(")
+		for i, fieldName := range variant.fieldNames {
+			b.WriteString(fieldName.Value())
+			if i < len(variant.fieldNames)-1 {
+				b.WriteString(",")
+			}
+		}
+		b.WriteString("){return {")
+		b.WriteString(TaggedUnionTagName)
+		b.WriteString(":'")
+		b.WriteString(variant.key.Value())
+		b.WriteString("'")
+		for _, fieldName := range variant.fieldNames {
+			b.WriteString(",")
+			b.WriteString(fieldName.Value())
+			b.WriteString(":")
+			b.WriteString(fieldName.Value())
+		}
+		b.WriteString("}}")
+	}
+
+	b.WriteString(nl)
+	b.WriteString(indent + tab)
+	b.WriteString("constructor(){throw new Error(\"Cannot call constructor on tagged union\")}")
+
+	b.WriteString(nl)
+	b.WriteString(indent)
+	b.WriteString("}")
+
+	return b.String()
+}
+
+func (t *TaggedUnion) HoistNames(scope Scope) error {
+	return nil
+}
+
+func (t *TaggedUnion) ResolveStatementNames(scope Scope) error {
+	if scope.HasVariable(t.Name()) {
+		errCtx := t.Context()
+		err := errCtx.NewError("Error: '" + t.Name() + "' already defined " +
+			"(tagged union needs unique name)")
+		other, _ := scope.GetVariable(t.Name())
+		err.AppendContextString("Info: defined here ", other.Context())
+		return err
+	}
+
+	if err := scope.SetVariable(t.Name(), t.GetVariable()); err != nil {
+		return err
+	}
+
+	// resolved after setting the variable so variants can refer to the union itself
+	for _, variant := range t.variants {
+		for _, fieldType := range variant.fieldTypes {
+			if err := fieldType.ResolveExpressionNames(scope); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (t *TaggedUnion) EvalStatement() error {
+	for _, variant := range t.variants {
+		if _, err := variant.evalFieldValues(); err != nil {
+			return err
+		}
+	}
+
+	variable := t.GetVariable()
+
+	variable.SetValue(values.NewEnum(t, t.Context()))
+
+	return nil
+}
+
+func (t *TaggedUnion) ResolveStatementActivity(usage Usage) error {
+	if usage.InFunction() {
+		nameVar := t.nameExpr.GetVariable()
+
+		if err := usage.Rereference(nameVar, t.Context()); err != nil {
+			return err
+		}
+	}
+
+	// in reverse order
+	for i := len(t.variants) - 1; i >= 0; i-- {
+		variant := t.variants[i]
+		for j := len(variant.fieldTypes) - 1; j >= 0; j-- {
+			if err := variant.fieldTypes[j].ResolveExpressionActivity(usage); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (t *TaggedUnion) UniversalStatementNames(ns Namespace) error {
+	for _, variant := range t.variants {
+		for _, fieldType := range variant.fieldTypes {
+			if err := fieldType.UniversalExpressionNames(ns); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (t *TaggedUnion) UniqueStatementNames(ns Namespace) error {
+	if err := ns.ClassName(t.nameExpr.GetVariable()); err != nil {
+		return err
+	}
+
+	for _, variant := range t.variants {
+		for _, fieldType := range variant.fieldTypes {
+			if err := fieldType.UniqueExpressionNames(ns); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (t *TaggedUnion) Walk(fn WalkFunc) error {
+	if err := t.nameExpr.Walk(fn); err != nil {
+		return err
+	}
+
+	for _, variant := range t.variants {
+		if err := variant.Walk(fn); err != nil {
+			return err
+		}
+	}
+
+	return fn(t)
+}
+
+func (t *TaggedUnion) Check(other_ values.Interface, ctx context.Context) error {
+	switch other := other_.(type) {
+	case *TaggedUnion:
+		if other == t {
+			return nil
+		}
+	case *TaggedUnionVariant:
+		if other.union == t {
+			return nil
+		}
+	}
+
+	return ctx.NewError("Error: expected tagged union " + t.Name() + ", got " + other_.Name())
+}
+
+func (t *TaggedUnion) IsUniversal() bool {
+	return false
+}
+
+func (t *TaggedUnion) IsRPC() bool {
+	return false
+}
+
+func (t *TaggedUnion) IsAbstract() bool {
+	return false
+}
+
+func (t *TaggedUnion) IsFinal() bool {
+	return true
+}
+
+func (t *TaggedUnion) GetInstanceMember(key string, includePrivate bool, ctx context.Context) (values.Value, error) {
+	if key == TaggedUnionTagName {
+		return prototypes.NewString(ctx), nil
+	}
+
+	for _, variant := range t.variants {
+		if variant.hasField(key) {
+			return nil, ctx.NewError("Error: " + t.Name() + "." + key + " is only available after narrowing to " +
+				variant.Name() + " (hint: use switch)")
+		}
+	}
+
+	return nil, nil
+}
+
+func (t *TaggedUnion) SetInstanceMember(key string, includePrivate bool, arg values.Value, ctx context.Context) error {
+	return ctx.NewError("Error: can't set members of " + t.Name() + " (hint: narrow to a variant first)")
+}
+
+func (t *TaggedUnion) GetClassMember(key string, includePrivate bool, ctx context.Context) (values.Value, error) {
+	variant := t.GetVariant(key)
+	if variant == nil {
+		return nil, nil
+	}
+
+	fieldValues, err := variant.evalFieldValues()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(fieldValues) == 0 {
+		return values.NewInstance(variant, ctx), nil
+	}
+
+	return values.NewFunction(append(fieldValues, values.NewInstance(variant, ctx)), ctx), nil
+}
+
+func (v *TaggedUnionVariant) Name() string {
+	return v.union.Name() + "." + v.key.Value()
+}
+
+func (v *TaggedUnionVariant) Key() string {
+	return v.key.Value()
+}
+
+func (v *TaggedUnionVariant) Context() context.Context {
+	return v.key.Context()
+}
+
+func (v *TaggedUnionVariant) Dump(indent string) string {
+	return indent + "TaggedUnionVariant(" + v.Name() + ")\n"
+}
+
+func (v *TaggedUnionVariant) hasField(key string) bool {
+	for _, fieldName := range v.fieldNames {
+		if fieldName.Value() == key {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (v *TaggedUnionVariant) evalFieldValues() ([]values.Value, error) {
+	res := make([]values.Value, len(v.fieldTypes))
+
+	for i, fieldType := range v.fieldTypes {
+		val, err := fieldType.EvalExpression()
+		if err != nil {
+			return nil, err
+		}
+
+		if val == nil {
+			errCtx := fieldType.Context()
+			return nil, errCtx.NewError("Error: field can't be void")
+		}
+
+		res[i] = val
+	}
+
+	return res, nil
+}
+
+func (v *TaggedUnionVariant) Walk(fn WalkFunc) error {
+	if err := v.key.Walk(fn); err != nil {
+		return err
+	}
+
+	for i, fieldName := range v.fieldNames {
+		if err := fieldName.Walk(fn); err != nil {
+			return err
+		}
+
+		if err := v.fieldTypes[i].Walk(fn); err != nil {
+			return err
+		}
+	}
+
+	return fn(v)
+}
+
+func (v *TaggedUnionVariant) Check(other_ values.Interface, ctx context.Context) error {
+	if other, ok := other_.(*TaggedUnionVariant); ok && other == v {
+		return nil
+	}
+
+	return ctx.NewError("Error: expected " + v.Name() + ", got " + other_.Name())
+}
+
+func (v *TaggedUnionVariant) IsUniversal() bool {
+	return false
+}
+
+func (v *TaggedUnionVariant) IsRPC() bool {
+	return false
+}
+
+func (v *TaggedUnionVariant) IsAbstract() bool {
+	return false
+}
+
+func (v *TaggedUnionVariant) IsFinal() bool {
+	return true
+}
+
+func (v *TaggedUnionVariant) GetInterfaces() ([]values.Interface, error) {
+	return []values.Interface{}, nil
+}
+
+func (v *TaggedUnionVariant) GetPrototypes() ([]values.Prototype, error) {
+	return []values.Prototype{}, nil
+}
+
+func (v *TaggedUnionVariant) GetParent() (values.Prototype, error) {
+	return v.union, nil
+}
+
+func (v *TaggedUnionVariant) GetClassValue() (*values.Class, error) {
+	return nil, nil
+}
+
+func (v *TaggedUnionVariant) GetClassMember(key string, includePrivate bool, ctx context.Context) (values.Value, error) {
+	return nil, nil
+}
+
+func (v *TaggedUnionVariant) GetInstanceMember(key string, includePrivate bool, ctx context.Context) (values.Value, error) {
+	if key == TaggedUnionTagName {
+		return prototypes.NewString(ctx), nil
+	}
+
+	for i, fieldName := range v.fieldNames {
+		if fieldName.Value() == key {
+			val, err := v.fieldTypes[i].EvalExpression()
+			if err != nil {
+				return nil, err
+			}
+
+			return values.NewContextValue(val, ctx), nil
+		}
+	}
+
+	return nil, ctx.NewError("Error: " + v.Name() + "." + key + " not found")
+}
+
+func (v *TaggedUnionVariant) SetInstanceMember(key string, includePrivate bool, arg values.Value, ctx context.Context) error {
+	if key == TaggedUnionTagName {
+		return ctx.NewError("Error: can't set the discriminant of " + v.Name())
+	}
+
+	for i, fieldName := range v.fieldNames {
+		if fieldName.Value() == key {
+			val, err := v.fieldTypes[i].EvalExpression()
+			if err != nil {
+				return err
+			}
+
+			return val.Check(arg, ctx)
+		}
+	}
+
+	return ctx.NewError("Error: " + v.Name() + "." + key + " not found")
+}
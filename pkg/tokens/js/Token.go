@@ -38,7 +38,7 @@ func MergeContexts(ts ...Token) context.Context {
 // used by the parser
 func IsCallable(t Token) bool {
 	switch t.(type) {
-	case *Function, *VarExpression, *Call, *Index, *Member, *Parens:
+	case *Function, *VarExpression, *Call, *Index, *Member, *Parens, *OptionalCall, *OptionalIndex, *OptionalMember:
 		return true
 	case *LiteralBoolean, *LiteralInt, *LiteralFloat, *LiteralString, Op, *Class:
 		return false
@@ -53,6 +53,10 @@ func NewTypeExpression(name string, parameterKeys []*Word,
 	return &TypeExpression{parameters, nil, newVarExpression(name, true, ctx)}, nil
 }
 
+func NewNullTypeExpression(ctx context.Context) *TypeExpression {
+	return &TypeExpression{nil, nil, newVarExpression("null", true, ctx)}
+}
+
 func (t *TypeExpression) hasKeys() bool {
   if t.parameters != nil {
     if t.parameters[0].key != nil {
@@ -127,7 +131,7 @@ func (t *TypeExpression) Dump(indent string) string {
 }
 
 func (t *TypeExpression) ResolveExpressionNames(scope Scope) error {
-	if t.Name() == "any" || t.Name() == "void" {
+	if t.Name() == "any" || t.Name() == "void" || t.Name() == "null" {
     if t.parameters != nil {
       errCtx := t.Context()
       return errCtx.NewError("Error: doesn't accept type parameters")
@@ -136,7 +140,7 @@ func (t *TypeExpression) ResolveExpressionNames(scope Scope) error {
     }
 	}
 
-  if t.Name() != "function" && t.Name() != "class" {
+  if t.Name() != "function" && t.Name() != "class" && t.Name() != "Union" {
     if err := t.VarExpression.ResolveExpressionNames(scope); err != nil {
       return err
     }
@@ -351,6 +355,46 @@ func (t *TypeExpression) generateTuple() (values.Value, error) {
   return prototypes.NewTuple(content, t.Context()), nil
 }
 
+// A | B | ..., T? is Union<T, null>
+func (t *TypeExpression) generateUnion() (values.Value, error) {
+  if t.parameters == nil || len(t.parameters) < 2 {
+    errCtx := t.Context()
+    return nil, errCtx.NewError("Error: expected at least 2 type parameters")
+  }
+
+  if t.hasKeys() {
+    errCtx := t.Context()
+    return nil, errCtx.NewError("Error: unexpected named type parameters")
+  }
+
+  alts := make([]values.Value, 0)
+
+  for _, p := range t.parameters {
+    // without strict null checking every type is nullable, and null itself would make the union any
+    if !values.STRICT_NULL && p.typeExpr.Name() == "null" {
+      continue
+    }
+
+    val, err := p.typeExpr.EvalExpression()
+    if err != nil {
+      return nil, err
+    }
+
+    if val == nil {
+      errCtx := p.typeExpr.Context()
+      return nil, errCtx.NewError("Error: unexpected void value")
+    }
+
+    alts = append(alts, val)
+  }
+
+  if len(alts) == 0 {
+    return values.NewNull(t.Context()), nil
+  }
+
+  return values.NewUnion(alts, t.Context()), nil
+}
+
 func (t *TypeExpression) generateObject() (values.Value, error) {
   var props map[string]values.Value = nil
 
@@ -392,12 +436,54 @@ func (t *TypeExpression) generateObject() (values.Value, error) {
   return prototypes.NewObject(props, t.Context()), nil
 }
 
+// __checkType__ always accepts null, so a union can only be written if it is a nullable single type
+func (t *TypeExpression) hasUniversalUnions() bool {
+  if t.Name() == "Union" {
+    nNonNull := 0
+    for _, param := range t.parameters {
+      if param.typeExpr.Name() != "null" {
+        nNonNull += 1
+      }
+    }
+
+    if nNonNull != 1 {
+      return false
+    }
+  }
+
+  for _, param := range t.parameters {
+    if !param.typeExpr.hasUniversalUnions() {
+      return false
+    }
+  }
+
+  return true
+}
+
 func (t *TypeExpression) WriteUniversalRuntimeType() string {
   var b strings.Builder
   
   switch t.Name() {
-  case "any", "class", "function", "void", "Set", "Map", "Promise", "Event", "IDBRequest":
+  case "any", "class", "function", "void", "null", "Set", "Map", "Promise", "Event", "IDBRequest":
     panic("not a universal type")
+  case "Union":
+    // __checkType__ always accepts null, so only nullable types are supported
+    var content *TypeExpression = nil
+    for _, param := range t.parameters {
+      if param.typeExpr.Name() == "null" {
+        continue
+      } else if content != nil {
+        panic("not a universal union")
+      }
+
+      content = param.typeExpr
+    }
+
+    if content == nil {
+      panic("not a universal union")
+    }
+
+    return content.WriteUniversalRuntimeType()
   case "Array":
     if t.parameters == nil {
       panic("not a universal array")
@@ -479,6 +565,11 @@ func (t *TypeExpression) EvalExpression() (values.Value, error) {
       panic("should've been checked during resolve stage")
     }
     return values.NewAny(ctx), nil
+  case "null":
+    if t.parameters != nil {
+      panic("should've been checked during resolve stage")
+    }
+    return values.NewNull(ctx), nil
   case "class": 
     return t.generateClass();
   case "function":
@@ -530,6 +621,8 @@ func (t *TypeExpression) EvalExpression() (values.Value, error) {
     return t.generateObject()
   case "Tuple":
     return t.generateTuple()
+  case "Union":
+    return t.generateUnion()
   default:
     if t.parameters != nil {
 			errCtx := ctx
@@ -1,12 +1,186 @@
 package js
 
 import (
+	"github.com/computeportal/wtsuite/pkg/tokens/context"
+	"github.com/computeportal/wtsuite/pkg/tokens/js/prototypes"
 	"github.com/computeportal/wtsuite/pkg/tokens/js/values"
 )
 
+// a variable, or a property of a variable (eg. this.timer)
+type typeGuardTarget struct {
+	variable Variable
+	member   string // empty for the variable itself
+}
+
+// returns false if expr can't be narrowed
+func getTypeGuardTarget(expr Expression) (typeGuardTarget, bool) {
+	switch expr := expr.(type) {
+	case *VarExpression:
+		return typeGuardTarget{expr.GetVariable(), ""}, true
+	case *Member:
+		if obj, ok := expr.object.(*VarExpression); ok {
+			if _, isPkg := obj.GetVariable().(*Package); !isPkg {
+				return typeGuardTarget{obj.GetVariable(), expr.key.Value()}, true
+			}
+		}
+	}
+
+	return typeGuardTarget{}, false
+}
+
+func (t typeGuardTarget) getNarrowed() values.Value {
+	return t.variable.GetNarrowed(t.member)
+}
+
+func (t typeGuardTarget) setNarrowed(v values.Value) {
+	t.variable.SetNarrowed(t.member, v)
+}
+
 type TypeGuard interface {
-	// collect all variables/interfaces
+	// collect all variables/narrowed values
 	// return false if all typeguards should be voided
 	// (should also do everything EvalExpression does)
-	CollectTypeGuards(c map[Variable]values.Interface) (bool, error)
+	CollectTypeGuards(c map[typeGuardTarget]values.Value) (bool, error)
+
+	// narrowed values in case the condition is false (eg. else branch)
+	// called after the condition has been evaluated, so doesn't evaluate anything itself
+	CollectComplementTypeGuards(c map[typeGuardTarget]values.Value) (bool, error)
+}
+
+// returns nil if cond doesn't contain any typeguards
+func collectTypeGuards(cond Expression) (map[typeGuardTarget]values.Value, error) {
+	if cond == nil {
+		return nil, nil
+	}
+
+	if typeGuardCond, ok := cond.(TypeGuard); ok {
+		typeGuards := make(map[typeGuardTarget]values.Value)
+		hasTypeGuards, err := typeGuardCond.CollectTypeGuards(typeGuards)
+		if err != nil {
+			return nil, err
+		}
+
+		if hasTypeGuards && len(typeGuards) > 0 {
+			return typeGuards, nil
+		}
+	}
+
+	// some conditions can work alongside typeguards, even though they dont add any typeguards
+	return nil, nil
+}
+
+// returns nil if cond doesn't contain any typeguards for the case that it is false
+func collectComplementTypeGuards(cond Expression) (map[typeGuardTarget]values.Value, error) {
+	if cond == nil {
+		return nil, nil
+	}
+
+	if typeGuardCond, ok := cond.(TypeGuard); ok {
+		typeGuards := make(map[typeGuardTarget]values.Value)
+		hasTypeGuards, err := typeGuardCond.CollectComplementTypeGuards(typeGuards)
+		if err != nil {
+			return nil, err
+		}
+
+		if hasTypeGuards && len(typeGuards) > 0 {
+			return typeGuards, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// like applyTypeGuards, but the original values are only saved the first time a variable is narrowed
+// (eg. for the successive complements of an if/else if chain)
+func accumulateTypeGuards(typeGuards map[typeGuardTarget]values.Value, oldValues map[typeGuardTarget]values.Value) {
+	for key, typeGuard := range typeGuards {
+		if _, ok := oldValues[key]; !ok {
+			oldValues[key] = key.getNarrowed()
+		}
+
+		key.setNarrowed(typeGuard)
+	}
+}
+
+// returns the old values, so they can be restored afterwards
+func applyTypeGuards(typeGuards map[typeGuardTarget]values.Value) map[typeGuardTarget]values.Value {
+	oldValues := make(map[typeGuardTarget]values.Value)
+
+	for key, typeGuard := range typeGuards {
+		oldValues[key] = key.getNarrowed()
+		key.setNarrowed(typeGuard)
+	}
+
+	return oldValues
+}
+
+func restoreTypeGuards(oldValues map[typeGuardTarget]values.Value) {
+	for key, val := range oldValues {
+		key.setNarrowed(val) // nil if it wasn't narrowed before
+	}
+}
+
+// x != null or null != x, returns nil if cond isn't of that form
+func getNullCheckExpression(a Expression, b Expression) Expression {
+	if _, ok := b.(*LiteralNull); ok {
+		return a
+	} else if _, ok := a.(*LiteralNull); ok {
+		return b
+	}
+
+	return nil
+}
+
+// typeof x == "..." or "..." == typeof x, returns nil if cond isn't of that form
+func getTypeOfCheck(a Expression, b Expression) (Expression, string) {
+	if _, ok := a.(*LiteralString); ok {
+		a, b = b, a
+	}
+
+	typeOf, ok := a.(*TypeOfOp)
+	if !ok {
+		return nil, ""
+	}
+
+	str, ok := b.(*LiteralString)
+	if !ok {
+		return nil, ""
+	}
+
+	return typeOf.a, str.Value()
+}
+
+// adds the narrowed value of expr, which has already been evaluated as part of the condition
+// returns false if expr can't be narrowed, or if it is already narrowed by another typeguard in c (which voids all)
+func addTypeGuard(c map[typeGuardTarget]values.Value, expr Expression, narrow func(values.Value) values.Value) (bool, error) {
+	ref, ok := getTypeGuardTarget(expr)
+	if !ok {
+		return false, nil
+	}
+
+	if _, ok := c[ref]; ok {
+		return false, nil
+	}
+
+	val, err := expr.EvalExpression()
+	if err != nil {
+		return false, err
+	}
+
+	c[ref] = narrow(val)
+	return true, nil
+}
+
+// returns nil for typeof results that don't correspond to a type
+func typeOfValue(typeName string, ctx context.Context) values.Value {
+	switch typeName {
+	case "string":
+		return prototypes.NewString(ctx)
+	case "number":
+		return prototypes.NewNumber(ctx)
+	case "boolean":
+		return prototypes.NewBoolean(ctx)
+	default:
+		return nil
+	}
 }
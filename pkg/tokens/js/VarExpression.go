@@ -171,6 +171,9 @@ func (t *VarExpression) EvalExpression() (values.Value, error) {
 
   // note that both Object and Value must be set for builtin classes/interfaces
   res := t.variable.GetValue()
+  if narrowed := t.variable.GetNarrowed(""); narrowed != nil {
+    res = narrowed
+  }
 
 	return values.NewContextValue(res, t.Context()), nil
 }
@@ -181,9 +184,16 @@ func (t *VarExpression) EvalSet(v values.Value, ctx context.Context) error {
     return ctx.NewError("Error: can't assign to const")
   }
 
+  // checked against the declared type, not the narrowed type
   thisVal := t.variable.GetValue()
 
-  return thisVal.Check(v, ctx)
+  if err := thisVal.Check(v, ctx); err != nil {
+    return err
+  }
+
+  t.variable.ClearNarrowed()
+
+  return nil
 }
 
 func (t *VarExpression) ResolveExpressionActivity(usage Usage) error {
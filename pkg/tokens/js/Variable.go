@@ -20,6 +20,12 @@ type Variable interface {
   GetValue() values.Value
   SetValue(values.Value)
 
+  // typeguards only narrow the value that is read, assignments are still checked against GetValue()
+  // member is empty for the variable itself, or the name of a property (eg. this.timer)
+  GetNarrowed(member string) values.Value // nil if not narrowed
+  SetNarrowed(member string, v values.Value) // nil removes the narrowing
+  ClearNarrowed() // after assignment, also voids the narrowed properties
+
   // anything that can be evaluated during the resolve names stage (eg. class statement)
 	GetObject() interface{} 
 	SetObject(interface{})
@@ -29,12 +35,13 @@ type VariableData struct {
 	name     string
 	constant bool
   value    values.Value
+  narrowed map[string]values.Value
 	object   interface{}
 	TokenData
 }
 
 func newVariableData(name string, constant bool, ctx context.Context) VariableData {
-	return VariableData{name, constant, nil, nil, TokenData{ctx}}
+	return VariableData{name, constant, nil, nil, nil, TokenData{ctx}}
 }
 
 func NewVariable(name string, constant bool, ctx context.Context) *VariableData {
@@ -77,6 +84,31 @@ func (t *VariableData) SetValue(v values.Value) {
   t.value = v
 }
 
+func (t *VariableData) GetNarrowed(member string) values.Value {
+  if t.narrowed == nil {
+    return nil
+  }
+
+  return t.narrowed[member]
+}
+
+func (t *VariableData) SetNarrowed(member string, v values.Value) {
+  if v == nil {
+    delete(t.narrowed, member)
+    return
+  }
+
+  if t.narrowed == nil {
+    t.narrowed = make(map[string]values.Value)
+  }
+
+  t.narrowed[member] = v
+}
+
+func (t *VariableData) ClearNarrowed() {
+  t.narrowed = nil
+}
+
 func (t *VariableData) GetObject() interface{} {
 	return t.object
 }
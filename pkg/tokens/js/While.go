@@ -68,6 +68,14 @@ func (t *While) ResolveStatementNames(scope Scope) error {
 }
 
 func (t *While) EvalStatement() error {
+	if err := t.evalCond(); err != nil {
+		return err
+	}
+
+	return t.Block.EvalStatement()
+}
+
+func (t *While) evalCond() error {
 	condVal, err := t.cond.EvalExpression()
 	if err != nil {
 		return err
@@ -78,7 +86,7 @@ func (t *While) EvalStatement() error {
 		return errCtx.NewError("Error: expected boolean condition")
 	}
 
-	return t.Block.EvalStatement()
+	return nil
 }
 
 func (t *While) ResolveStatementActivity(usage Usage) error {
@@ -159,6 +159,7 @@ func FillBrowserAndWorkerCommonScope(scope Scope) {
   registerPrototype(scope, pr.NewIDBTransactionPrototype())
   registerPrototype(scope, pr.NewIDBVersionChangeEventPrototype())
   registerPrototype(scope, pr.NewLocationPrototype())
+  registerPrototype(scope, pr.NewMessageChannelPrototype())
   registerPrototype(scope, pr.NewMessageEventPrototype())
   registerPrototype(scope, pr.NewMessagePortPrototype())
   registerPrototype(scope, pr.NewResponsePrototype())
@@ -166,6 +167,7 @@ func FillBrowserAndWorkerCommonScope(scope Scope) {
   registerPrototype(scope, pr.NewTextEncoderPrototype())
   registerPrototype(scope, pr.NewWebAssemblyPrototype())
   registerPrototype(scope, pr.NewWebAssemblyEnvPrototype())
+  registerPrototype(scope, pr.NewWebSocketPrototype())
   registerPrototype(scope, pr.NewXMLHttpRequestPrototype())
 
   ctx := context.NewDummyContext()
@@ -211,18 +213,21 @@ func FillBrowserScope(scope Scope) {
   registerPrototype(scope, pr.NewKeyboardEventPrototype(nil))
   registerPrototype(scope, pr.NewMouseEventPrototype())
   registerPrototype(scope, pr.NewNodePrototype())
-  //registerPrototype(scope, pr.NewSearchIndexPrototype())
+  registerPrototype(scope, pr.NewSearchIndexPrototype())
   registerPrototype(scope, pr.NewSharedWorkerPrototype())
   registerPrototype(scope, pr.NewStoragePrototype())
   registerPrototype(scope, pr.NewTextPrototype())
   registerPrototype(scope, pr.NewURLPrototype())
   registerPrototype(scope, pr.NewURLSearchParamsPrototype())
+  registerPrototype(scope, pr.NewWebGL2RenderingContextPrototype())
   registerPrototype(scope, pr.NewWebGLBufferPrototype())
   registerPrototype(scope, pr.NewWebGLExtensionPrototype())
+  registerPrototype(scope, pr.NewWebGLFramebufferPrototype())
   registerPrototype(scope, pr.NewWebGLProgramPrototype())
   registerPrototype(scope, pr.NewWebGLRenderingContextPrototype())
   registerPrototype(scope, pr.NewWebGLShaderPrototype())
   registerPrototype(scope, pr.NewWebGLTexturePrototype())
+  registerPrototype(scope, pr.NewWebGLVertexArrayObjectPrototype())
   registerPrototype(scope, pr.NewWheelEventPrototype())
   registerPrototype(scope, pr.NewWorkerPrototype())
 
@@ -0,0 +1,49 @@
+package macros
+
+import (
+  "strings"
+
+  "github.com/computeportal/wtsuite/pkg/tokens/context"
+  "github.com/computeportal/wtsuite/pkg/tokens/js"
+  "github.com/computeportal/wtsuite/pkg/tokens/js/prototypes"
+)
+
+// @bind binds a method to its instance, so it can be passed as an event listener
+type BindDecorator struct {
+  DecoratorMacro
+}
+
+func NewBindDecorator(args []js.Expression, ctx context.Context) (js.Decorator, error) {
+  return &BindDecorator{newDecoratorMacro("bind", args, ctx)}, nil
+}
+
+// the method is replaced by a getter, so the usual wrapping can't be used
+func (m *BindDecorator) WriteDecorator(cl *js.Class, member js.ClassMember) string {
+  var b strings.Builder
+
+  b.WriteString(decoratorsHeader.Name())
+  b.WriteString(".bind(")
+  b.WriteString(cl.WriteDecoratorTarget(member))
+  b.WriteString(",'")
+  b.WriteString(cl.WriteDecoratorMemberName(member))
+  b.WriteString("')")
+
+  return b.String()
+}
+
+func (m *BindDecorator) EvalDecorator(cl *js.Class, member js.ClassMember) error {
+  if err := m.assertNoArgs(); err != nil {
+    return err
+  }
+
+  if err := m.assertMethod(member); err != nil {
+    return err
+  }
+
+  if prototypes.IsStatic(member) {
+    errCtx := m.Context()
+    return errCtx.NewError("Error: @bind can't decorate static methods")
+  }
+
+  return nil
+}
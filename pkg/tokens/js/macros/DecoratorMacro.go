@@ -0,0 +1,59 @@
+package macros
+
+import (
+  "github.com/computeportal/wtsuite/pkg/tokens/context"
+  "github.com/computeportal/wtsuite/pkg/tokens/js"
+  "github.com/computeportal/wtsuite/pkg/tokens/js/prototypes"
+)
+
+type DecoratorMacroConstructor func([]js.Expression, context.Context) (js.Decorator, error)
+
+// builtin decorators call the static methods of the __decorators__ header
+type DecoratorMacro struct {
+  name string
+  Macro
+}
+
+func newDecoratorMacro(name string, args []js.Expression, ctx context.Context) DecoratorMacro {
+  return DecoratorMacro{name, newMacro(args, ctx)}
+}
+
+func (m *DecoratorMacro) Dump(indent string) string {
+  return indent + "@" + m.name + "(...)\n"
+}
+
+func (m *DecoratorMacro) assertMethod(member js.ClassMember) error {
+  if member == nil || prototypes.IsProperty(member) {
+    errCtx := m.Context()
+    return errCtx.NewError("Error: @" + m.name + " can only decorate methods")
+  }
+
+  return nil
+}
+
+func (m *DecoratorMacro) assertNoArgs() error {
+  if len(m.args) != 0 {
+    errCtx := m.Context()
+    return errCtx.NewError("Error: @" + m.name + " doesn't take any arguments")
+  }
+
+  return nil
+}
+
+func (m *DecoratorMacro) WriteDecorator(cl *js.Class, member js.ClassMember) string {
+  return js.WriteDecoratorCall(decoratorsHeader.Name() + "." + m.name, m.args, cl, member)
+}
+
+func (m *DecoratorMacro) ResolveExpressionActivity(usage js.Usage) error {
+  ResolveHeaderActivity(decoratorsHeader, m.Context())
+
+  return m.Macro.ResolveExpressionActivity(usage)
+}
+
+func (m *DecoratorMacro) UniqueExpressionNames(ns js.Namespace) error {
+  if err := UniqueHeaderNames(decoratorsHeader, ns); err != nil {
+    return err
+  }
+
+  return m.Macro.UniqueExpressionNames(ns)
+}
@@ -0,0 +1,63 @@
+package macros
+
+// methods are wrapped as fn(method,name,...args), see js.WriteDecoratorCall
+type DecoratorsHeader struct {
+  HeaderData
+}
+
+func (h *DecoratorsHeader) Dependencies() []Header {
+  return []Header{}
+}
+
+func (h *DecoratorsHeader) Write() string {
+  b := NewHeaderBuilder()
+
+  b.n()
+  b.cccn("class ", h.Name(), "{")
+
+  b.tcn("static log(fn,name){")
+  b.ttcn("return function(...a){")
+  b.tttcn("let r=fn.apply(this,a);")
+  b.tttcn("console.log(name+'(',...a,') ->',r);")
+  b.tttcn("return r;")
+  b.ttcn("};")
+  b.tcn("}")
+
+  b.tcn("static memoize(fn,name){")
+  b.ttcn("let cache=new WeakMap();")
+  b.ttcn("return function(...a){")
+  b.tttcn("let m=cache.get(this);")
+  b.tttcn("if(m===undefined){m=new Map();cache.set(this,m);}")
+  b.tttcn("let k=JSON.stringify(a);")
+  b.tttcn("if(!m.has(k)){m.set(k,fn.apply(this,a));}")
+  b.tttcn("return m.get(k);")
+  b.ttcn("};")
+  b.tcn("}")
+
+  b.tcn("static deprecated(fn,name,msg){")
+  b.ttcn("let warned=false;")
+  b.ttcn("return function(...a){")
+  b.tttcn("if(!warned){")
+  b.ttttcn("warned=true;")
+  b.ttttcn("console.warn(name+' is deprecated'+(msg===undefined?'':': '+msg));")
+  b.tttcn("}")
+  b.tttcn("return fn.apply(this,a);")
+  b.ttcn("};")
+  b.tcn("}")
+
+  // the bound function is cached on the instance upon first access
+  b.tcn("static bind(p,k){")
+  b.ttcn("let fn=p[k];")
+  b.ttcn("Object.defineProperty(p,k,{configurable:true,get(){")
+  b.tttcn("let f=fn.bind(this);")
+  b.tttcn("Object.defineProperty(this,k,{value:f,configurable:true,writable:true});")
+  b.tttcn("return f;")
+  b.ttcn("}});")
+  b.tcn("}")
+
+  b.c("}")
+
+  return b.String()
+}
+
+var decoratorsHeader = &DecoratorsHeader{newHeaderData("__decorators__")}
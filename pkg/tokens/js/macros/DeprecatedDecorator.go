@@ -0,0 +1,38 @@
+package macros
+
+import (
+  "github.com/computeportal/wtsuite/pkg/tokens/context"
+  "github.com/computeportal/wtsuite/pkg/tokens/js"
+  "github.com/computeportal/wtsuite/pkg/tokens/js/prototypes"
+)
+
+// @deprecated or @deprecated(msg) warns once, when the method is first called
+type DeprecatedDecorator struct {
+  DecoratorMacro
+}
+
+func NewDeprecatedDecorator(args []js.Expression, ctx context.Context) (js.Decorator, error) {
+  if len(args) > 1 {
+    return nil, ctx.NewError("Error: expected 0 or 1 arguments")
+  }
+
+  return &DeprecatedDecorator{newDecoratorMacro("deprecated", args, ctx)}, nil
+}
+
+func (m *DeprecatedDecorator) EvalDecorator(cl *js.Class, member js.ClassMember) error {
+  if err := m.assertMethod(member); err != nil {
+    return err
+  }
+
+  args, err := m.evalArgs()
+  if err != nil {
+    return err
+  }
+
+  if len(args) == 1 && !prototypes.IsString(args[0]) {
+    errCtx := args[0].Context()
+    return errCtx.NewError("Error: expected String, got " + args[0].TypeName())
+  }
+
+  return nil
+}
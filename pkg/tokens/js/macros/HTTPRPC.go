@@ -0,0 +1,138 @@
+package macros
+
+import (
+  "strings"
+
+  "github.com/computeportal/wtsuite/pkg/tokens/context"
+  "github.com/computeportal/wtsuite/pkg/tokens/js"
+  "github.com/computeportal/wtsuite/pkg/tokens/js/prototypes"
+  "github.com/computeportal/wtsuite/pkg/tokens/js/values"
+)
+
+// client:
+//   new HTTPRPC(Interf, url[, timeout]) -> rpc client instance of Interf, POSTing every message to url (timeout in ms)
+// server (nodejs only):
+//   new HTTPRPC(Interf, impl[, path[, maxBodySize]]) -> request listener for http.Server (eg. server.addListener("request", ...))
+//     path "" matches every url, requests with a body larger than maxBodySize bytes (default 1MB, 0 for no limit) get a 413 reply
+type HTTPRPC struct {
+  isClient bool // determined during eval stage
+  RPCMacro
+}
+
+func NewHTTPRPC(args []js.Expression, ctx context.Context) (js.Expression, error) {
+  if len(args) < 2 || len(args) > 4 {
+    return nil, ctx.NewError("Error: expected 2, 3 or 4 arguments")
+  }
+
+  rpcMacro, err := newRPCMacro(args, ctx)
+  if err != nil {
+    return nil, err
+  }
+
+  return &HTTPRPC{false, rpcMacro}, nil
+}
+
+func (m *HTTPRPC) Dump(indent string) string {
+  return indent + "HTTPRPC(...)\n"
+}
+
+func (m *HTTPRPC) WriteExpression() string {
+  var b strings.Builder
+
+  b.WriteString("new ")
+  b.WriteString(m.header().Name())
+  b.WriteString("(")
+  b.WriteString(m.interf.Name())
+  for _, arg := range m.args {
+    b.WriteString(",")
+    b.WriteString(arg.WriteExpression())
+  }
+  if m.isClient && len(m.args) == 1 {
+    b.WriteString(",0")
+  }
+  b.WriteString(")")
+
+  return b.String()
+}
+
+func (m *HTTPRPC) EvalExpression() (values.Value, error) {
+  ctx := m.Context()
+
+  if !m.interf.IsRPC() {
+    errCtx := m.interfExpr.Context()
+    return nil, errCtx.NewError("Error: " + m.interf.Name() + " is not an rpc interface")
+  }
+
+  if interf, ok := m.interf.(*js.Interface); ok {
+    if err := interf.CheckStatelessRPC(); err != nil {
+      return nil, err
+    }
+  }
+
+  args, err := m.evalArgs()
+  if err != nil {
+    return nil, err
+  }
+
+  m.isClient = prototypes.IsString(args[0])
+
+  if m.isClient {
+    if len(args) == 3 {
+      return nil, ctx.NewError("Error: expected 2 or 3 arguments for http rpc client")
+    }
+
+    if len(args) == 2 && !prototypes.IsInt(args[1]) {
+      errCtx := m.args[1].Context()
+      return nil, errCtx.NewError("Error: expected Int timeout, got " + args[1].TypeName())
+    }
+
+    return values.NewInstance(m.interf, ctx), nil
+  } else {
+    if js.TARGET != "nodejs" {
+      return nil, ctx.NewError("Error: http rpc server only available if target is nodejs, (now it is " + js.TARGET + ")")
+    }
+
+    checkVal := values.NewInstance(m.interf, ctx)
+    if err := checkVal.Check(args[0], ctx); err != nil {
+      return nil, err
+    }
+
+    if len(args) >= 2 && !prototypes.IsString(args[1]) {
+      errCtx := m.args[1].Context()
+      return nil, errCtx.NewError("Error: expected String path, got " + args[1].TypeName())
+    }
+
+    if len(args) == 3 && !prototypes.IsInt(args[2]) {
+      errCtx := m.args[2].Context()
+      return nil, errCtx.NewError("Error: expected Int maxBodySize, got " + args[2].TypeName())
+    }
+
+    return values.NewFunction([]values.Value{
+      prototypes.NewNodeJS_http_IncomingMessage(ctx), 
+      prototypes.NewNodeJS_http_ServerResponse(ctx),
+      nil,
+    }, ctx), nil
+  }
+}
+
+func (m *HTTPRPC) header() Header {
+  if m.isClient {
+    return httpRPCClientHeader
+  } else {
+    return httpRPCServerHeader
+  }
+}
+
+func (m *HTTPRPC) ResolveExpressionActivity(usage js.Usage) error {
+  ResolveHeaderActivity(m.header(), m.Context())
+
+  return m.RPCMacro.ResolveExpressionActivity(usage)
+}
+
+func (m *HTTPRPC) UniqueExpressionNames(ns js.Namespace) error {
+  if err := UniqueHeaderNames(m.header(), ns); err != nil {
+    return err
+  }
+
+  return m.RPCMacro.UniqueExpressionNames(ns)
+}
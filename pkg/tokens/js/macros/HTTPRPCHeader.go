@@ -0,0 +1,111 @@
+package macros
+
+import (
+  "strconv"
+)
+
+// default max number of bytes of a request body received by an http rpc server
+var HTTP_RPC_MAX_BODY_SIZE = 1024*1024
+
+type HTTPRPCClientHeader struct {
+  HeaderData
+}
+
+func (h *HTTPRPCClientHeader) Dependencies() []Header {
+  return []Header{rpcClientHeader}
+}
+
+func (h *HTTPRPCClientHeader) Write() string {
+  b := NewHeaderBuilder()
+
+  // every message is POSTed separately, a timeout of 0 means no timeout
+  b.n()
+  b.cccn("var ", h.Name(), "=(function(interf,url,timeout){")
+  b.tcccn("return new ", rpcClientHeader.Name(), "(interf,async function(msg){")
+  b.ttcn("let ctrl=new AbortController();")
+  b.ttcn("let timer=null;")
+  b.ttcn("if(timeout>0){timer=setTimeout(function(){ctrl.abort()},timeout)};")
+  b.ttcn("try{")
+  b.tttcn("let resp=await fetch(url,{method:'POST',headers:{'Content-Type':'application/json'},body:msg,signal:ctrl.signal});")
+  b.tttcn("if(!resp.ok){throw new Error('rpc request failed ('+resp.status.toString()+')')};")
+  b.tttcn("return await resp.text();")
+  b.ttcn("}catch(e){")
+  b.tttcn("if(e.name==='AbortError'){throw new Error('rpc request timed out')};")
+  b.tttcn("throw e;")
+  b.ttcn("}finally{")
+  b.tttcn("if(timer!==null){clearTimeout(timer)};")
+  b.ttcn("}")
+  b.tcn("});")
+  b.c("});")
+  b.n()
+
+  return b.String()
+}
+
+type HTTPRPCServerHeader struct {
+  HeaderData
+}
+
+func (h *HTTPRPCServerHeader) Dependencies() []Header {
+  return []Header{rpcServerHeader, checkTypeHeader}
+}
+
+func (h *HTTPRPCServerHeader) Write() string {
+  b := NewHeaderBuilder()
+
+  // stateless: a new server for every request, so no channels can persist between requests
+  // an empty path matches every url, a maxSize of 0 means no limit
+  b.n()
+  b.cccn("var ", h.Name(), "=(function(interf,value,path,maxSize){")
+  b.tcccn("if(maxSize===undefined){maxSize=", strconv.Itoa(HTTP_RPC_MAX_BODY_SIZE), "};")
+  b.tcn("return function(req,res){")
+  b.ttcn("if(path!==undefined&&path!==''&&req.url.split('?')[0]!==path){return};")
+  b.ttcn("if(req.method!=='POST'){")
+  b.tttcn("res.writeHead(405,{'Allow':'POST'});")
+  b.tttcn("res.end();")
+  b.tttcn("return;")
+  b.ttcn("}")
+  b.ttcn("let chunks=[];")
+  b.ttcn("let size=0;")
+  b.ttcn("let tooLarge=false;")
+  // the rest of the body is still read, but discarded
+  b.ttcn("let rejectTooLarge=function(){")
+  b.tttcn("tooLarge=true;")
+  b.tttcn("chunks=[];")
+  b.tttcn("res.writeHead(413,{'Content-Type':'text/plain','Connection':'close'});")
+  b.tttcn("res.end('rpc message too large');")
+  b.ttcn("};")
+  b.ttcn("if(maxSize>0&&parseInt(req.headers['content-length'])>maxSize){rejectTooLarge()};")
+  b.ttcn("req.on('data',function(c){")
+  b.tttcn("if(tooLarge){return};")
+  b.tttcn("size+=c.length;")
+  b.tttcn("if(maxSize>0&&size>maxSize){rejectTooLarge()}else{chunks.push(c)};")
+  b.ttcn("});")
+  b.ttcn("req.on('end',async function(){")
+  b.tttcn("if(tooLarge){return};")
+  b.tttcn("let body=Buffer.concat(chunks).toString('utf8');")
+  b.tttcn("try{")
+  b.ttttcn("let msg=JSON.parse(body);")
+  b.ttttcn("if(msg.type!=='request'){throw new Error('expected request')};")
+  b.ttttcccn(checkTypeHeader.Name(), "(msg.id,Int)", ";")
+  b.ttttcccn(checkTypeHeader.Name(), "(msg.channel,Int)", ";")
+  b.ttttcccn(checkTypeHeader.Name(), "(msg.name,String)", ";")
+  b.tttcn("}catch(e){")
+  b.ttttcn("res.writeHead(400,{'Content-Type':'text/plain'});")
+  b.ttttcn("res.end('malformed rpc message: '+e.message);")
+  b.ttttcn("return;")
+  b.tttcn("}")
+  b.tttcccn("let server=new ", rpcServerHeader.Name(), "(interf,value);")
+  b.tttcn("let reply=await server.handle(body);")
+  b.tttcn("res.writeHead(200,{'Content-Type':'application/json'});")
+  b.tttcn("res.end(reply);")
+  b.ttcn("});")
+  b.tcn("};")
+  b.c("});")
+  b.n()
+
+  return b.String()
+}
+
+var httpRPCClientHeader = &HTTPRPCClientHeader{newHeaderData("HTTPRPCClient")}
+var httpRPCServerHeader = &HTTPRPCServerHeader{newHeaderData("HTTPRPCServer")}
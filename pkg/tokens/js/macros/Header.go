@@ -64,14 +64,14 @@ func UniqueHeaderNames(h Header, ns js.Namespace) error {
 	return h.UniqueNames(ns)
 }
 
-func WriteHeaders() string {
-	var b strings.Builder
-
+func allHeaders() []Header {
 	// order probably not important due to hoisting
-	all := []Header{
+	return []Header{
     checkTypeHeader,
 		objectFromInstanceHeader,
 		objectToInstanceHeader,
+		jsonTypeHeader,
+		decoratorsHeader,
 		blobFromInstanceHeader,
 		blobToInstanceHeader,
 		sharedWorkerPostHeader,
@@ -79,13 +79,30 @@ func WriteHeaders() string {
     rpcContextHeader,
     rpcClientHeader,
     rpcServerHeader,
+    workerRPCClientHeader,
+    workerRPCServerHeader,
+    httpRPCClientHeader,
+    httpRPCServerHeader,
+    socketRPCClientHeader,
+    socketRPCServerHeader,
 		webAssemblyEnvHeader,
     webGLProgramHeader,
-		//searchIndexHeader,
+		searchIndexHeader,
     mathFontHeader,
 	}
+}
+
+// so that another bundle can be built (eg. a worker bundle)
+func ResetHeaders() {
+	for _, h := range allHeaders() {
+		h.SetVariable(nil)
+	}
+}
+
+func WriteHeaders() string {
+	var b strings.Builder
 
-	for _, h := range all {
+	for _, h := range allHeaders() {
 		if h.GetVariable() != nil {
 			b.WriteString(h.Write())
 		}
@@ -158,3 +158,15 @@ func (b *HeaderBuilder) ttttttcn(s string) {
 	b.b.WriteString(s)
 	b.b.WriteString(pat.NL)
 }
+
+func (b *HeaderBuilder) tttttttcn(s string) {
+	b.b.WriteString(pat.TAB)
+	b.b.WriteString(pat.TAB)
+	b.b.WriteString(pat.TAB)
+	b.b.WriteString(pat.TAB)
+	b.b.WriteString(pat.TAB)
+	b.b.WriteString(pat.TAB)
+	b.b.WriteString(pat.TAB)
+	b.b.WriteString(s)
+	b.b.WriteString(pat.NL)
+}
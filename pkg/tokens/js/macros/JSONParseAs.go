@@ -0,0 +1,87 @@
+package macros
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/computeportal/wtsuite/pkg/tokens/context"
+	"github.com/computeportal/wtsuite/pkg/tokens/js"
+	"github.com/computeportal/wtsuite/pkg/tokens/js/prototypes"
+	"github.com/computeportal/wtsuite/pkg/tokens/js/values"
+)
+
+// JSON.parseAs(str, Type) parses and deeply validates, unlike Object.toInstance the type doesn't need to be universal
+type JSONParseAs struct {
+	typeExpr *js.TypeExpression
+	Macro
+}
+
+func NewJSONParseAs(args []js.Expression, ctx context.Context) (js.Expression, error) {
+	if len(args) != 2 {
+		return nil, ctx.NewError(fmt.Sprintf("Error: expected 2 arguments, got %d", len(args)))
+	}
+
+	typeExpr, err := getTypeExpression(args[1])
+	if err != nil {
+		return nil, err
+	}
+
+	return &JSONParseAs{typeExpr, newMacro(args[0:1], ctx)}, nil
+}
+
+func (m *JSONParseAs) Dump(indent string) string {
+	return indent + "JSONParseAs(...)"
+}
+
+func (m *JSONParseAs) WriteExpression() string {
+	var b strings.Builder
+
+	b.WriteString(jsonTypeHeader.Name())
+	b.WriteString(".decode(JSON.parse(")
+	b.WriteString(m.args[0].WriteExpression())
+	b.WriteString("),")
+	b.WriteString(m.typeExpr.WriteRuntimeType())
+	b.WriteString(",'$')")
+
+	return b.String()
+}
+
+func (m *JSONParseAs) ResolveExpressionNames(scope js.Scope) error {
+	if err := m.Macro.ResolveExpressionNames(scope); err != nil {
+		return err
+	}
+
+	return m.typeExpr.ResolveExpressionNames(scope)
+}
+
+func (m *JSONParseAs) EvalExpression() (values.Value, error) {
+	args, err := m.evalArgs()
+	if err != nil {
+		return nil, err
+	}
+
+	if !prototypes.IsString(args[0]) {
+		errCtx := args[0].Context()
+		return nil, errCtx.NewError("Error: expected String, got " + args[0].TypeName())
+	}
+
+	if err := js.CheckRuntimeType(m.typeExpr); err != nil {
+		return nil, err
+	}
+
+	return m.typeExpr.EvalExpression()
+}
+
+func (m *JSONParseAs) ResolveExpressionActivity(usage js.Usage) error {
+	ResolveHeaderActivity(jsonTypeHeader, m.Context())
+
+	return m.Macro.ResolveExpressionActivity(usage)
+}
+
+func (m *JSONParseAs) UniqueExpressionNames(ns js.Namespace) error {
+	if err := UniqueHeaderNames(jsonTypeHeader, ns); err != nil {
+		return err
+	}
+
+	return m.Macro.UniqueExpressionNames(ns)
+}
@@ -0,0 +1,92 @@
+package macros
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/computeportal/wtsuite/pkg/tokens/context"
+	"github.com/computeportal/wtsuite/pkg/tokens/js"
+	"github.com/computeportal/wtsuite/pkg/tokens/js/prototypes"
+	"github.com/computeportal/wtsuite/pkg/tokens/js/values"
+)
+
+// JSON.stringifyAs(x, Type) is the inverse of JSON.parseAs(str, Type)
+type JSONStringifyAs struct {
+	typeExpr *js.TypeExpression
+	Macro
+}
+
+func NewJSONStringifyAs(args []js.Expression, ctx context.Context) (js.Expression, error) {
+	if len(args) != 2 {
+		return nil, ctx.NewError(fmt.Sprintf("Error: expected 2 arguments, got %d", len(args)))
+	}
+
+	typeExpr, err := getTypeExpression(args[1])
+	if err != nil {
+		return nil, err
+	}
+
+	return &JSONStringifyAs{typeExpr, newMacro(args[0:1], ctx)}, nil
+}
+
+func (m *JSONStringifyAs) Dump(indent string) string {
+	return indent + "JSONStringifyAs(...)"
+}
+
+func (m *JSONStringifyAs) WriteExpression() string {
+	var b strings.Builder
+
+	b.WriteString("JSON.stringify(")
+	b.WriteString(jsonTypeHeader.Name())
+	b.WriteString(".encode(")
+	b.WriteString(m.args[0].WriteExpression())
+	b.WriteString(",")
+	b.WriteString(m.typeExpr.WriteRuntimeType())
+	b.WriteString(",'$'))")
+
+	return b.String()
+}
+
+func (m *JSONStringifyAs) ResolveExpressionNames(scope js.Scope) error {
+	if err := m.Macro.ResolveExpressionNames(scope); err != nil {
+		return err
+	}
+
+	return m.typeExpr.ResolveExpressionNames(scope)
+}
+
+func (m *JSONStringifyAs) EvalExpression() (values.Value, error) {
+	args, err := m.evalArgs()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := js.CheckRuntimeType(m.typeExpr); err != nil {
+		return nil, err
+	}
+
+	typeVal, err := m.typeExpr.EvalExpression()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := typeVal.Check(args[0], args[0].Context()); err != nil {
+		return nil, err
+	}
+
+	return prototypes.NewString(m.Context()), nil
+}
+
+func (m *JSONStringifyAs) ResolveExpressionActivity(usage js.Usage) error {
+	ResolveHeaderActivity(jsonTypeHeader, m.Context())
+
+	return m.Macro.ResolveExpressionActivity(usage)
+}
+
+func (m *JSONStringifyAs) UniqueExpressionNames(ns js.Namespace) error {
+	if err := UniqueHeaderNames(jsonTypeHeader, ns); err != nil {
+		return err
+	}
+
+	return m.Macro.UniqueExpressionNames(ns)
+}
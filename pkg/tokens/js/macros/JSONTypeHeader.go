@@ -0,0 +1,170 @@
+package macros
+
+import (
+	"github.com/computeportal/wtsuite/pkg/tokens/js"
+)
+
+// descriptors are generated by js.TypeExpression.WriteRuntimeType()
+// errors carry the JSON path of the offending value (eg. $.items[3].price: expected Number)
+type JSONTypeHeader struct {
+	HeaderData
+}
+
+func (h *JSONTypeHeader) Dependencies() []Header {
+	return []Header{}
+}
+
+func (h *JSONTypeHeader) Write() string {
+	b := NewHeaderBuilder()
+
+	b.n()
+	b.cccn("class ", h.Name(), "{")
+
+	// name used in error messages
+	b.tcn("static typeName(t){")
+	b.ttcn("if(typeof t==='string'){")
+	b.tttcn("return t;")
+	b.ttcn("}else if(t.name!==undefined){")
+	b.tttcn("return t.name;")
+	b.ttcn("}else if(t.array!==undefined){")
+	b.tttcn("return 'Array';")
+	b.ttcn("}else if(t.tuple!==undefined){")
+	b.tttcn("return 'Tuple';")
+	b.ttcn("}else if(t.union!==undefined){")
+	b.tttcccn("return t.union.map(", h.Name(), ".typeName).join('|');")
+	b.ttcn("}else{")
+	b.tttcn("return 'Object';")
+	b.ttcn("}")
+	b.tcn("}")
+
+	b.tcn("static isObject(x){")
+	b.ttcn("return x!==null&&typeof x==='object'&&!Array.isArray(x);")
+	b.tcn("}")
+
+	b.tcn("static fail(p,t){")
+	b.ttcccn("throw new Error(p+': expected '+", h.Name(), ".typeName(t));")
+	b.tcn("}")
+
+	// missing keys are treated as null, so that optional fields can be omitted
+	b.tcn("static field(x,k,t,p,fn){")
+	b.ttcn("let v=x[k];")
+	b.ttcn("if(v===undefined){v=null;}")
+	b.ttcn("return fn(v,t,p+'.'+k);")
+	b.tcn("}")
+
+	b.tcn("static decode(x,t,p){")
+	b.ttcn("let self=" + h.Name() + ";")
+	b.ttcn("if(typeof t==='string'){")
+	b.tttcn("switch(t){")
+	b.tttcn("case 'any':return x;")
+	b.tttcn("case 'null':if(x===null){return x;};break;")
+	b.tttcn("case 'String':if(typeof x==='string'){return x;};break;")
+	b.tttcn("case 'Number':if(typeof x==='number'){return x;};break;")
+	b.tttcn("case 'Int':if(Number.isInteger(x)){return x;};break;")
+	b.tttcn("case 'Boolean':if(typeof x==='boolean'){return x;};break;")
+	b.tttcn("case 'Date':if(typeof x==='string'||typeof x==='number'){let d=new Date(x);if(!isNaN(d.getTime())){return d;}};break;")
+	b.tttcn("case 'Object':if(self.isObject(x)){return x;};break;")
+	b.tttcn("}")
+	b.ttcn("}else if(t.array!==undefined){")
+	b.tttcn("if(Array.isArray(x)){")
+	b.ttttcn("return x.map(function(c,i){return self.decode(c,t.array,p+'['+i.toString()+']');});")
+	b.tttcn("}")
+	b.ttcn("}else if(t.tuple!==undefined){")
+	b.tttcn("if(Array.isArray(x)&&x.length===t.tuple.length){")
+	b.ttttcn("return x.map(function(c,i){return self.decode(c,t.tuple[i],p+'['+i.toString()+']');});")
+	b.tttcn("}")
+	b.ttcn("}else if(t.map!==undefined){")
+	b.tttcn("if(self.isObject(x)){")
+	b.ttttcn("let y={};")
+	b.ttttcn("for(let k in x){y[k]=self.decode(x[k],t.map,p+'.'+k);}")
+	b.ttttcn("return y;")
+	b.tttcn("}")
+	b.ttcn("}else if(t.object!==undefined){")
+	b.tttcn("if(self.isObject(x)){")
+	b.ttttcn("let y={};")
+	b.ttttcn("for(let k in t.object){y[k]=self.field(x,k,t.object[k],p,self.decode);}")
+	b.ttttcn("return y;")
+	b.tttcn("}")
+	b.ttcn("}else if(t.union!==undefined){")
+	b.tttcn("let errs=[];")
+	b.tttcn("for(let a of t.union){")
+	b.ttttcn("try{return self.decode(x,a,p);}catch(e){if(a!=='null'){errs.push(e);}}")
+	b.tttcn("}")
+	b.tttcn("if(errs.length===1){throw errs[0];}") // nullable type, keep the deeper path
+	b.ttcn("}else if(t.enum!==undefined){")
+	b.tttcn("if(t.enum.indexOf(x)!==-1){return x;}")
+	b.ttcn("}else if(t.tagged!==undefined){")
+	b.tttcn("if(self.isObject(x)){")
+	b.ttttcn("if(typeof x." + js.TaggedUnionTagName + "!=='string'||!Object.prototype.hasOwnProperty.call(t.tagged,x." + js.TaggedUnionTagName + ")){")
+	b.tttttcn("throw new Error(p+'." + js.TaggedUnionTagName + ": expected '+Object.keys(t.tagged).join('|'));")
+	b.ttttcn("}")
+	b.ttttcn("let f=t.tagged[x." + js.TaggedUnionTagName + "];")
+	b.ttttcn("let y={" + js.TaggedUnionTagName + ":x." + js.TaggedUnionTagName + "};")
+	b.ttttcn("for(let k in f){y[k]=self.field(x,k,f[k],p,self.decode);}")
+	b.ttttcn("return y;")
+	b.tttcn("}")
+	b.ttcn("}else if(self.isObject(x)){")
+	b.tttcn("let y=Object.create(t.cl.prototype);")
+	b.tttcn("for(let q of t.props){y[q[1]]=self.field(x,q[0],q[2],p,self.decode);}")
+	b.tttcn("return y;")
+	b.ttcn("}")
+	b.ttcn("self.fail(p,t);")
+	b.tcn("}")
+
+	// inverse of decode, only declared fields are written
+	b.tcn("static encode(x,t,p){")
+	b.ttcn("let self=" + h.Name() + ";")
+	b.ttcn("if(x===undefined){x=null;}")
+	b.ttcn("if(typeof t==='string'){")
+	b.tttcn("switch(t){")
+	b.tttcn("case 'Date':if(x instanceof Date&&!isNaN(x.getTime())){return x.toISOString();};break;")
+	b.tttcn("default:return self.decode(x,t,p);")
+	b.tttcn("}")
+	b.ttcn("}else if(t.array!==undefined){")
+	b.tttcn("if(Array.isArray(x)){")
+	b.ttttcn("return x.map(function(c,i){return self.encode(c,t.array,p+'['+i.toString()+']');});")
+	b.tttcn("}")
+	b.ttcn("}else if(t.tuple!==undefined){")
+	b.tttcn("if(Array.isArray(x)&&x.length===t.tuple.length){")
+	b.ttttcn("return x.map(function(c,i){return self.encode(c,t.tuple[i],p+'['+i.toString()+']');});")
+	b.tttcn("}")
+	b.ttcn("}else if(t.map!==undefined){")
+	b.tttcn("if(self.isObject(x)){")
+	b.ttttcn("let y={};")
+	b.ttttcn("for(let k in x){y[k]=self.encode(x[k],t.map,p+'.'+k);}")
+	b.ttttcn("return y;")
+	b.tttcn("}")
+	b.ttcn("}else if(t.object!==undefined){")
+	b.tttcn("if(self.isObject(x)){")
+	b.ttttcn("let y={};")
+	b.ttttcn("for(let k in t.object){y[k]=self.field(x,k,t.object[k],p,self.encode);}")
+	b.ttttcn("return y;")
+	b.tttcn("}")
+	b.ttcn("}else if(t.union!==undefined){")
+	b.tttcn("let errs=[];")
+	b.tttcn("for(let a of t.union){")
+	b.ttttcn("try{return self.encode(x,a,p);}catch(e){if(a!=='null'){errs.push(e);}}")
+	b.tttcn("}")
+	b.tttcn("if(errs.length===1){throw errs[0];}") // nullable type, keep the deeper path
+	b.ttcn("}else if(t.enum!==undefined){")
+	b.tttcn("if(t.enum.indexOf(x)!==-1){return x;}")
+	b.ttcn("}else if(t.tagged!==undefined){")
+	b.tttcn("if(self.isObject(x)&&Object.prototype.hasOwnProperty.call(t.tagged,x." + js.TaggedUnionTagName + ")){")
+	b.ttttcn("let f=t.tagged[x." + js.TaggedUnionTagName + "];")
+	b.ttttcn("let y={" + js.TaggedUnionTagName + ":x." + js.TaggedUnionTagName + "};")
+	b.ttttcn("for(let k in f){y[k]=self.field(x,k,f[k],p,self.encode);}")
+	b.ttttcn("return y;")
+	b.tttcn("}")
+	b.ttcn("}else if(x instanceof t.cl){")
+	b.tttcn("let y={};")
+	b.tttcn("for(let q of t.props){let v=x[q[1]];if(v===undefined){v=null;};y[q[0]]=self.encode(v,q[2],p+'.'+q[0]);}")
+	b.tttcn("return y;")
+	b.ttcn("}")
+	b.ttcn("self.fail(p,t);")
+	b.tcn("}")
+	b.c("}")
+
+	return b.String()
+}
+
+var jsonTypeHeader = &JSONTypeHeader{newHeaderData("__jsonType__")}
@@ -0,0 +1,23 @@
+package macros
+
+import (
+  "github.com/computeportal/wtsuite/pkg/tokens/context"
+  "github.com/computeportal/wtsuite/pkg/tokens/js"
+)
+
+// @log prints the arguments and the return value of each call
+type LogDecorator struct {
+  DecoratorMacro
+}
+
+func NewLogDecorator(args []js.Expression, ctx context.Context) (js.Decorator, error) {
+  return &LogDecorator{newDecoratorMacro("log", args, ctx)}, nil
+}
+
+func (m *LogDecorator) EvalDecorator(cl *js.Class, member js.ClassMember) error {
+  if err := m.assertNoArgs(); err != nil {
+    return err
+  }
+
+  return m.assertMethod(member)
+}
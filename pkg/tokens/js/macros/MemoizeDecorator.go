@@ -0,0 +1,23 @@
+package macros
+
+import (
+  "github.com/computeportal/wtsuite/pkg/tokens/context"
+  "github.com/computeportal/wtsuite/pkg/tokens/js"
+)
+
+// @memoize caches the results per instance, with the JSON of the arguments as key
+type MemoizeDecorator struct {
+  DecoratorMacro
+}
+
+func NewMemoizeDecorator(args []js.Expression, ctx context.Context) (js.Decorator, error) {
+  return &MemoizeDecorator{newDecoratorMacro("memoize", args, ctx)}, nil
+}
+
+func (m *MemoizeDecorator) EvalDecorator(cl *js.Class, member js.ClassMember) error {
+  if err := m.assertNoArgs(); err != nil {
+    return err
+  }
+
+  return m.assertMethod(member)
+}
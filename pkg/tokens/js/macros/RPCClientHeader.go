@@ -43,14 +43,27 @@ func (h *RPCClientHeader) Write() string {
   b.ttttcn("reject(new Error('invalid message type: '+message.type));")
   b.tttcn("}")
 
+  // transport errors (eg. timeouts) are passed on as is
+  b.tttcn("let str;")
+  b.tttcn("try{")
+  b.ttttcn("str=await fn(ctx.serialize(message));")
+  b.tttcn("}catch(e){")
+  b.ttttcn("if(message.type==='request'){delete callbacks[message.id]};")
+  b.ttttcn("reject(e);")
+  b.ttttcn("continue;")
+  b.tttcn("}")
+
   b.tttcn("let reply;")
   b.tttcn("try{")
-  b.ttttcn("reply=ctx.deserialize(await fn(ctx.serialize(message)));")
+  b.ttttcn("reply=ctx.deserialize(str);")
   b.tttcn("}catch(e){")
   b.ttttcn("reject(new Error('malformed response'));")
   b.tttcn("}")
 
-  b.tttcn("if(reply==undefined||reply.type==undefined||reply.id==undefined||!Number.isInteger(reply.id)){")
+  b.tttcn("if(reply instanceof Error){")
+  b.ttttcn("if(message.type==='request'){delete callbacks[message.id]};")
+  b.ttttcn("reject(reply);") // errors thrown by the server
+  b.tttcn("}else if(reply==undefined||reply.type==undefined||reply.id==undefined||!Number.isInteger(reply.id)){")
   b.ttttcn("reject(new Error('malformed response'))")
   b.tttcn("}else if(reply.type==='response'){")
   b.ttttcn("let callback=callbacks[reply.id];")
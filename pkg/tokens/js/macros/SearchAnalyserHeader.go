@@ -0,0 +1,260 @@
+package macros
+
+import (
+  "sort"
+  "strings"
+
+  "github.com/computeportal/wtsuite/pkg/search"
+)
+
+// ports of the stemmers of the search package
+var jsStemmers = map[string]string{
+  "de": "_stemDe",
+  "en": "_stemEn",
+  "nl": "_stemNl",
+}
+
+// browser side version of search.Analyser, must give exactly the same terms
+// the tables are taken from the search package, the stemmers are ports of the go versions
+func writeSearchAnalyser(b *HeaderBuilder) {
+  // fold table
+  chars := make([]string, 0)
+  for r, s := range search.FoldTable {
+    chars = append(chars, "['" + string(r) + "','" + s + "']")
+  }
+  sort.Strings(chars)
+
+  b.ttcccn("var FOLD=new Map([", strings.Join(chars, ","), "]);")
+
+  // stop words per language
+  langs := make([]string, 0)
+  for _, lang := range search.Languages() {
+    words := search.NewAnalyser(lang).StopWords()
+    langs = append(langs, "['" + lang + "',new Set(['" + strings.Join(words, "','") + "'])]")
+  }
+
+  b.ttcccn("var STOP=new Map([", strings.Join(langs, ","), "]);")
+
+	b.ttcn("var _oneOf=function(c,s){return c!==undefined&&s.indexOf(c)>=0};")
+	b.ttcn("var _suf=function(w,s){")
+	b.tttcn("if(s.length>w.length){return false}")
+	b.tttcn("for(let i=0;i<s.length;i++){")
+	b.ttttcn("if(w[w.length-s.length+i]!=s[i]){return false}")
+	b.tttcn("}")
+	b.tttcn("return true;")
+	b.ttcn("};")
+	b.ttcn("var _longest=function(w,ss){")
+	b.tttcn("let b='';")
+	b.tttcn("ss.forEach((s)=>{if(s.length>b.length&&_suf(w,s)){b=s}});")
+	b.tttcn("return b;")
+	b.ttcn("};")
+	b.ttcn("var _rep=function(w,s,r){return w.slice(0,w.length-s.length).concat(Array.from(r))};")
+	b.ttcn("var _region=function(w,s,v){")
+	b.tttcn("for(let i=s+1;i<w.length;i++){")
+	b.ttttcn("if(v(w[i-1])&&!v(w[i])){return i+1}")
+	b.tttcn("}")
+	b.tttcn("return w.length;")
+	b.ttcn("};")
+	b.ttcn("var EN_EXC=new Map([['skis','ski'],['skies','sky'],['dying','die'],['lying','lie'],['tying','tie'],['idly','idl'],['gently','gentl'],['ugly','ugli'],['early','earli'],['only','onli'],['singly','singl'],['sky','sky'],['news','news'],['howe','howe'],['atlas','atlas'],['cosmos','cosmos'],['bias','bias'],['andes','andes']]);")
+	b.ttcn("var EN_INV=new Set(['inning','outing','canning','herring','earring','proceed','exceed','succeed']);")
+	b.ttcn("var EN_2=new Map([['tional','tion'],['enci','ence'],['anci','ance'],['abli','able'],['entli','ent'],['izer','ize'],['ization','ize'],['ational','ate'],['ation','ate'],['ator','ate'],['alism','al'],['aliti','al'],['alli','al'],['fulness','ful'],['ousli','ous'],['ousness','ous'],['iveness','ive'],['iviti','ive'],['biliti','ble'],['bli','ble'],['ogi','og'],['fulli','ful'],['lessli','less'],['li','']]);")
+	b.ttcn("var EN_3=new Map([['tional','tion'],['ational','ate'],['alize','al'],['icate','ic'],['iciti','ic'],['ical','ic'],['ful',''],['ness',''],['ative','']]);")
+	b.ttcn("var _enV=function(c){return _oneOf(c,'aeiouy')};")
+	b.ttcn("var _enShort=function(w){")
+	b.tttcn("let n=w.length;")
+	b.tttcn("if(n==2){return _enV(w[0])&&!_enV(w[1])}")
+	b.tttcn("if(n>2){return !_enV(w[n-3])&&_enV(w[n-2])&&!_enV(w[n-1])&&!_oneOf(w[n-1],'wxY')}")
+	b.tttcn("return false;")
+	b.ttcn("};")
+	b.ttcn("var _stemEn=function(s){")
+	b.tttcn("if(EN_EXC.has(s)){return EN_EXC.get(s)}")
+	b.tttcn("let w=Array.from(s);")
+	b.tttcn("if(w.length<=2){return s}")
+	b.tttcn("if(w[0]=='y'){w[0]='Y'}")
+	b.tttcn("for(let i=1;i<w.length;i++){")
+	b.ttttcn("if(w[i]=='y'&&_enV(w[i-1])){w[i]='Y'}")
+	b.tttcn("}")
+	b.tttcn("let r1=_region(w,0,_enV);")
+	b.tttcn("['gener','commun','arsen'].forEach((p)=>{if(s.startsWith(p)){r1=p.length}});")
+	b.tttcn("let r2=_region(w,r1,_enV);")
+	b.tttcn("let x=_longest(w,['sses','ied','ies','us','ss','s']);")
+	b.tttcn("if(x=='sses'){")
+	b.ttttcn("w=_rep(w,x,'ss');")
+	b.tttcn("}else if(x=='ied'||x=='ies'){")
+	b.ttttcn("w=_rep(w,x,(w.length>4)?'i':'ie');")
+	b.tttcn("}else if(x=='s'&&w.slice(0,w.length-2).some(_enV)){")
+	b.ttttcn("w=_rep(w,x,'');")
+	b.tttcn("}")
+	b.tttcn("if(EN_INV.has(w.join(''))){return w.join('')}")
+	b.tttcn("x=_longest(w,['eed','eedly','ed','edly','ing','ingly']);")
+	b.tttcn("if(x=='eed'||x=='eedly'){")
+	b.ttttcn("if(w.length-x.length>=r1){w=_rep(w,x,'ee')}")
+	b.tttcn("}else if(x!=''&&w.slice(0,w.length-x.length).some(_enV)){")
+	b.ttttcn("w=_rep(w,x,'');")
+	b.ttttcn("if(_longest(w,['at','bl','iz'])!=''){")
+	b.tttttcn("w.push('e');")
+	b.ttttcn("}else if(_longest(w,['bb','dd','ff','gg','mm','nn','pp','rr','tt'])!=''){")
+	b.tttttcn("w.pop();")
+	b.ttttcn("}else if(r1>=w.length&&_enShort(w)){")
+	b.tttttcn("w.push('e');")
+	b.ttttcn("}")
+	b.tttcn("}")
+	b.tttcn("let n=w.length;")
+	b.tttcn("if(n>2&&(w[n-1]=='y'||w[n-1]=='Y')&&!_enV(w[n-2])){w[n-1]='i'}")
+	b.tttcn("x=_longest(w,Array.from(EN_2.keys()));")
+	b.tttcn("if(x!=''&&w.length-x.length>=r1&&(x!='ogi'||_suf(w,'logi'))&&(x!='li'||_oneOf(w[w.length-3],'cdeghkmnrt'))){w=_rep(w,x,EN_2.get(x))}")
+	b.tttcn("x=_longest(w,Array.from(EN_3.keys()));")
+	b.tttcn("if(x!=''&&w.length-x.length>=r1&&(x!='ative'||w.length-x.length>=r2)){w=_rep(w,x,EN_3.get(x))}")
+	b.tttcn("x=_longest(w,['al','ance','ence','er','ic','able','ible','ant','ement','ment','ent','ism','ate','iti','ous','ive','ize','ion']);")
+	b.tttcn("if(x!=''&&w.length-x.length>=r2&&(x!='ion'||_suf(w,'sion')||_suf(w,'tion'))){w=_rep(w,x,'')}")
+	b.tttcn("n=w.length;")
+	b.tttcn("if(_suf(w,'e')){")
+	b.ttttcn("if(n-1>=r2||(n-1>=r1&&!_enShort(w.slice(0,n-1)))){w.pop()}")
+	b.tttcn("}else if(_suf(w,'ll')&&n-1>=r2){")
+	b.ttttcn("w.pop();")
+	b.tttcn("}")
+	b.tttcn("return w.join('').replace(/Y/g,'y');")
+	b.ttcn("};")
+	b.ttcn("var _deV=function(c){return _oneOf(c,'aeiouyäöü')};")
+	b.ttcn("var _stemDe=function(s){")
+	b.tttcn("let w=Array.from(s.replace(/ß/g,'ss'));")
+	b.tttcn("for(let i=1;i<w.length-1;i++){")
+	b.ttttcn("if(_deV(w[i-1])&&_deV(w[i+1])){")
+	b.tttttcn("if(w[i]=='u'){w[i]='U'}else if(w[i]=='y'){w[i]='Y'}")
+	b.ttttcn("}")
+	b.tttcn("}")
+	b.tttcn("let r1=_region(w,0,_deV);")
+	b.tttcn("let r2=_region(w,r1,_deV);")
+	b.tttcn("if(r1<3){r1=3}")
+	b.tttcn("let x=_longest(w,['em','ern','er','e','en','es','s']);")
+	b.tttcn("if(x!=''&&w.length-x.length>=r1){")
+	b.ttttcn("if(x=='em'||x=='ern'||x=='er'){")
+	b.tttttcn("w=_rep(w,x,'');")
+	b.ttttcn("}else if(x=='s'){")
+	b.tttttcn("if(_oneOf(w[w.length-2],'bdfghklmnrt')){w=_rep(w,x,'')}")
+	b.ttttcn("}else{")
+	b.tttttcn("w=_rep(w,x,'');")
+	b.tttttcn("if(_suf(w,'niss')){w.pop()}")
+	b.ttttcn("}")
+	b.tttcn("}")
+	b.tttcn("x=_longest(w,['en','er','est','st']);")
+	b.tttcn("if(x!=''&&w.length-x.length>=r1&&(x!='st'||(w.length>=6&&_oneOf(w[w.length-3],'bdfghklmnt')))){w=_rep(w,x,'')}")
+	b.tttcn("x=_longest(w,['end','ung','ig','ik','isch','lich','heit','keit']);")
+	b.tttcn("if(x!=''&&w.length-x.length>=r2){")
+	b.ttttcn("if(x=='end'||x=='ung'){")
+	b.tttttcn("w=_rep(w,x,'');")
+	b.tttttcn("if(_suf(w,'ig')&&!_suf(w,'eig')&&w.length-2>=r2){w=_rep(w,'ig','')}")
+	b.ttttcn("}else if(x=='ig'||x=='ik'||x=='isch'){")
+	b.tttttcn("if(!_suf(w,'e'+x)){w=_rep(w,x,'')}")
+	b.ttttcn("}else if(x=='lich'||x=='heit'){")
+	b.tttttcn("w=_rep(w,x,'');")
+	b.tttttcn("let y=_longest(w,['er','en']);")
+	b.tttttcn("if(y!=''&&w.length-y.length>=r1){w=_rep(w,y,'')}")
+	b.ttttcn("}else{")
+	b.tttttcn("w=_rep(w,x,'');")
+	b.tttttcn("let y=_longest(w,['lich','ig']);")
+	b.tttttcn("if(y!=''&&w.length-y.length>=r2){w=_rep(w,y,'')}")
+	b.ttttcn("}")
+	b.tttcn("}")
+	b.tttcn("return w.join('').replace(/U/g,'u').replace(/Y/g,'y').replace(/ä/g,'a').replace(/ö/g,'o').replace(/ü/g,'u');")
+	b.ttcn("};")
+	b.ttcn("var _nlV=function(c){return _oneOf(c,'aeiouyè')};")
+	b.ttcn("var _nlUndouble=function(w){")
+	b.tttcn("if(_longest(w,['kk','dd','tt'])!=''){w.pop()}")
+	b.tttcn("return w;")
+	b.ttcn("};")
+	b.ttcn("var _nlEn=function(w,x,r1){")
+	b.tttcn("let n=w.length-x.length;")
+	b.tttcn("if(n>=r1&&n>0&&!_nlV(w[n-1])&&!_suf(w.slice(0,n),'gem')){return _nlUndouble(_rep(w,x,''))}")
+	b.tttcn("return w;")
+	b.ttcn("};")
+	b.ttcn("var _nlE=function(w,r1){")
+	b.tttcn("let n=w.length-1;")
+	b.tttcn("if(_suf(w,'e')&&n>=r1&&n>0&&!_nlV(w[n-1])){return [_nlUndouble(w.slice(0,n)),true]}")
+	b.tttcn("return [w,false];")
+	b.ttcn("};")
+	b.ttcn("var _stemNl=function(s){")
+	b.tttcn("let w=Array.from(s);")
+	b.tttcn("if(w.length>0&&w[0]=='y'){w[0]='Y'}")
+	b.tttcn("for(let i=1;i<w.length;i++){")
+	b.ttttcn("if(!_nlV(w[i-1])){continue}")
+	b.ttttcn("if(w[i]=='y'){w[i]='Y'}else if(w[i]=='i'&&i<w.length-1&&_nlV(w[i+1])){w[i]='I'}")
+	b.tttcn("}")
+	b.tttcn("let r1=_region(w,0,_nlV);")
+	b.tttcn("let r2=_region(w,r1,_nlV);")
+	b.tttcn("if(r1<3){r1=3}")
+	b.tttcn("let x=_longest(w,['heden','ene','en','se','s']);")
+	b.tttcn("let n=w.length-x.length;")
+	b.tttcn("if(x=='heden'){")
+	b.ttttcn("if(n>=r1){w=_rep(w,x,'heid')}")
+	b.tttcn("}else if(x=='en'||x=='ene'){")
+	b.ttttcn("w=_nlEn(w,x,r1);")
+	b.tttcn("}else if(x!=''&&n>=r1&&n>0&&!_nlV(w[n-1])&&w[n-1]!='j'){")
+	b.ttttcn("w=_rep(w,x,'');")
+	b.tttcn("}")
+	b.tttcn("let e=_nlE(w,r1);")
+	b.tttcn("w=e[0];")
+	b.tttcn("if(_suf(w,'heid')&&w.length-4>=r2&&!_suf(w,'cheid')){")
+	b.ttttcn("w=_rep(w,'heid','');")
+	b.ttttcn("if(_suf(w,'en')){w=_nlEn(w,'en',r1)}")
+	b.tttcn("}")
+	b.tttcn("x=_longest(w,['end','ing','ig','lijk','baar','bar']);")
+	b.tttcn("if(x!=''&&w.length-x.length>=r2){")
+	b.ttttcn("if(x=='end'||x=='ing'){")
+	b.tttttcn("w=_rep(w,x,'');")
+	b.tttttcn("if(_suf(w,'ig')&&w.length-2>=r2&&!_suf(w,'eig')){w=_rep(w,'ig','')}else{w=_nlUndouble(w)}")
+	b.ttttcn("}else if(x=='ig'){")
+	b.tttttcn("if(!_suf(w,'eig')){w=_rep(w,x,'')}")
+	b.ttttcn("}else if(x=='lijk'){")
+	b.tttttcn("w=_nlE(_rep(w,x,''),r1)[0];")
+	b.ttttcn("}else if(x=='baar'||(x=='bar'&&e[1])){")
+	b.tttttcn("w=_rep(w,x,'');")
+	b.ttttcn("}")
+	b.tttcn("}")
+	b.tttcn("n=w.length;")
+	b.tttcn("if(n>=4&&!_nlV(w[n-4])&&w[n-3]==w[n-2]&&_oneOf(w[n-2],'aeou')&&!_nlV(w[n-1])&&w[n-1]!='I'){w.splice(n-2,1)}")
+	b.tttcn("return w.join('').replace(/I/g,'i').replace(/Y/g,'y');")
+	b.ttcn("};")
+
+  // every language of the search package must have a ported stemmer
+  stems := make([]string, 0)
+  for _, lang := range search.Languages() {
+    fn, ok := jsStemmers[lang]
+    if !ok {
+      panic("no js stemmer for " + lang)
+    }
+
+    stems = append(stems, "['" + lang + "'," + fn + "]")
+  }
+
+	b.ttcccn("var STEM=new Map([", strings.Join(stems, ","), "]);")
+	b.ttcn("var _fold=function(w){")
+	b.tttcn("let r='';")
+	b.tttcn("for(let c of w.replace(/[\\u0300-\\u036f]/g,'')){r+=FOLD.has(c)?FOLD.get(c):c}")
+	b.tttcn("return r;")
+	b.ttcn("};")
+	b.ttcn("var _lang=function(l){")
+	b.tttcn("l=(l||'').trim().toLowerCase();")
+	b.tttcn("let i=l.search(/[-_]/);")
+	b.tttcn("return (i<0)?l:l.slice(0,i);")
+	b.ttcn("};")
+	b.ttcn("var _normalize=function(w){return _fold(w.toLowerCase())};")
+	b.ttcn("var _term=function(w,l){")
+	b.tttcn("w=_normalize(w);")
+	b.tttcn("if(STOP.has(l)&&STOP.get(l).has(w)){return ''}")
+	b.tttcn("return STEM.has(l)?STEM.get(l)(w):w;")
+	b.ttcn("};")
+	b.ttcn("var _tokens=function(s){")
+	b.tttcn("let r=[];")
+	b.tttcn("let re=/[^\\x00-\\x2d\\/:;?_]+/g;")
+	b.tttcn("let m;")
+	b.tttcn("while((m=re.exec(s))!==null){")
+	b.ttttcn("let a=m.index;")
+	b.ttttcn("let b=a+m[0].length;")
+	b.ttttcn("while(a<b&&s[a]=='.'){a++}")
+	b.ttttcn("while(b>a&&s[b-1]=='.'){b--}")
+	b.ttttcn("if(a<b){r.push([a,b,s.slice(a,b)])}")
+	b.tttcn("}")
+	b.tttcn("return r;")
+	b.ttcn("};")
+}
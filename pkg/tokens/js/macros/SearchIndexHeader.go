@@ -1,7 +1,7 @@
 package macros
 
 import (
-	//"github.com/computeportal/wtsuite/pkg/tokens/context"
+	"github.com/computeportal/wtsuite/pkg/tokens/context"
 )
 
 type SearchIndexHeader struct {
@@ -19,68 +19,116 @@ func (h *SearchIndexHeader) Write() string {
 	b.cccn("class ", h.Name(), "{")
 	b.tcn("constructor(n,opt){")
 
-	// fetch the index and parse the json
+	// fetch the manifest and parse the json
+	// the tries and the pages can be split into shard files, which are fetched when needed
 	b.ttcn("this._onready=[];") // pending callbacks
-	b.ttcn("var data={pages:[null]};fetch(n).then((r)=>{return r.json()}).catch((e)=>{console.log('unable to fetch ' + n)}).then((d)=>{data=d;this._onready.forEach((fn)=>{fn()});this._onready=[];});")
+	b.ttcn("this._ready=false;")
+	b.ttcn("var dir=n.slice(0,n.lastIndexOf('/')+1);") // shard files are relative to the manifest
+	b.ttcn("var data={ignore:{}};")
+	b.ttcn("var ready=fetch(n).then((r)=>{return r.json()}).catch((e)=>{console.log('unable to fetch ' + n)}).then((d)=>{")
+	b.tttcn("if(d.pages===undefined){d.pages=new Array(d.lengths.length).fill(null)}")
+	b.tttcn("data=d;")
+	b.tttcn("this._ready=true;")
+	b.tttcn("this._onready.forEach((fn)=>{fn()});")
+	b.tttcn("this._onready=[];")
+	b.ttcn("});")
+
+	// cached promises of the shard files
+	b.ttcn("var shards=new Map();")
+	b.ttcn("var _fetchShard=function(f){")
+	b.tttcn("if(!shards.has(f)){")
+	b.ttttcn("shards.set(f,fetch(dir+f).then((r)=>{return r.json()}));")
+	b.tttcn("}")
+	b.tttcn("return shards.get(f);")
+	b.ttcn("};")
 
-	b.ttcn("this.page=function(i){")
-	b.tttcn("return data.pages[i];")
+	// child k of node m, p is the prefix of the child
+	// a shard placeholder is replaced by the loaded subtree
+	b.ttcn("var _node=function(m,k,p){")
+	b.tttcn("let c=m[k];")
+	b.tttcn("if(c===undefined||c.shard===undefined){return Promise.resolve(c)}")
+	b.tttcn("return _fetchShard(c.shard).then((s)=>{m[k]=s[p];return s[p]});")
 	b.ttcn("};")
 
-	b.ttcn("this.ignore=function(w){")
-	b.tttcn("return data.ignore[w]!==undefined;")
+	b.ttcn("var _root=async function(t){")
+	b.tttcn("await ready;")
+	b.tttcn("return _node(data,t,'');")
 	b.ttcn("};")
 
-	b.ttcn("var _collect=function(m,s){")
+	// children are loaded in parallel
+	b.ttcn("var _children=function(m,p,fn){")
+	b.tttcn("let ps=[];")
 	b.tttcn("for(let k in m){")
-	b.ttttcn("if(k=='pages'){")
-	b.tttttcn("m.pages.forEach((i)=>{s.add(i)});")
-	b.ttttcn("}else{")
-	b.tttttcn("_collect(m[k],s)")
+	b.ttttcn("if(k!='pages'&&k!='tf'){") // term frequencies are stored alongside the pages
+	b.tttttcn("ps.push(_node(m,k,p+k).then((c)=>{return fn(c,p+k)}));")
 	b.ttttcn("}")
 	b.tttcn("}")
+	b.tttcn("return Promise.all(ps);")
+	b.ttcn("};")
+
+	b.ttcn("var _page=async function(i){")
+	b.tttcn("await ready;")
+	b.tttcn("if(data.pages[i]!==null){return data.pages[i]}")
+	b.tttcn("let c=data.pageShards.find((c)=>{return i>=c.start&&i<c.stop});")
+	b.tttcn("if(c===undefined){throw new Error('page '+i+' out of range')}")
+	b.tttcn("let ps=await _fetchShard(c.file);")
+	b.tttcn("ps.forEach((p,j)=>{data.pages[c.start+j]=p});")
+	b.tttcn("return data.pages[i];")
+	b.ttcn("};")
+
+	b.ttcn("this.page=function(i){")
+	b.tttcn("return _page(i);")
+	b.ttcn("};")
+
+	b.ttcn("this.ignore=function(w){")
+	b.tttcn("return Object.prototype.hasOwnProperty.call(data.ignore,w);")
+	b.ttcn("};")
+
+	b.ttcn("var _collect=async function(m,p,s){")
+	b.tttcn("if(m.pages!==undefined){m.pages.forEach((i)=>{s.add(i)})}")
+	b.tttcn("await _children(m,p,(c,q)=>{return _collect(c,q,s)});")
+	b.ttcn("};")
+
+	// node of the exact word in trie t, or undefined
+	b.ttcn("var _find=async function(t,w){")
+	b.tttcn("let m=await _root(t);")
+	b.tttcn("let p='';")
+	b.tttcn("for(let c of w){")
+	b.ttttcn("if(m===undefined){return m}")
+	b.ttttcn("p+=c;")
+	b.ttttcn("m=await _node(m,c,p);")
+	b.tttcn("}")
+	b.tttcn("return m;")
 	b.ttcn("};")
 
-	// recursive private function
-	b.ttcn("var _search=function(m,w,sw,s){")
-	b.tttcn("if(w.length==0){")
+	b.ttcn("var _search=async function(t,w,sw){")
+	b.tttcn("let s=new Set();")
+	b.tttcn("let m=await _find(t,w);")
+	b.tttcn("if(m!==undefined){")
 	b.ttttcn("if(sw){")
-	b.tttttcn("_collect(m,s)")
+	b.tttttcn("await _collect(m,w,s);")
 	b.ttttcn("}else if(m.pages!==undefined){")
 	b.tttttcn("m.pages.forEach((i)=>{s.add(i)});")
-	b.tttttcn("return")
 	b.ttttcn("}")
 	b.tttcn("}")
-	b.tttcn("let c=w.slice(0,1);")
-	b.tttcn("let m_=m[c];")
-	b.tttcn("if(m_!==undefined){")
-	b.ttttcn("_search(m_,w.slice(1),sw,s)")
-	b.tttcn("}")
+	b.tttcn("return s;")
 	b.ttcn("};")
 
 	// whole word match
 	b.ttcn("this.match=function(w){")
-	b.tttcn("let s=new Set();")
-	b.tttcn("_search(data.index,w,false,s);")
-	b.tttcn("return s;")
+	b.tttcn("return _search('index',w,false);")
 	b.ttcn("};")
 
 	b.ttcn("this.matchPrefix=function(w){")
-	b.tttcn("let s=new Set();")
-	b.tttcn("_search(data.index,w,true,s);")
-	b.tttcn("return s;")
+	b.tttcn("return _search('index',w,true);")
 	b.ttcn("};")
 
 	b.ttcn("this.matchSuffix=function(w){")
-	b.tttcn("let s=new Set();")
-	b.tttcn("_search(data.partial,w,false,s);")
-	b.tttcn("return s;")
+	b.tttcn("return _search('partial',w,false);")
 	b.ttcn("};")
 
 	b.ttcn("this.matchSubstring=function(w){")
-	b.tttcn("let s=new Set();")
-	b.tttcn("_search(data.partial,w,true,s);")
-	b.tttcn("return s;")
+	b.tttcn("return _search('partial',w,true);")
 	b.ttcn("};")
 
 	// m: map
@@ -89,9 +137,8 @@ func (h *SearchIndexHeader) Write() string {
 	// t: result string, built 1 char at a time
 	// l: limit
 	// s: result set
-	b.ttcn("var _fuzzy=function(m,w,r,t,l,sw,s){")
+	b.ttcn("var _fuzzy=async function(m,w,r,t,l,sw,s){")
 	b.tttcn("let n=w.length;") // the row is one longer than the length of searched word
-	b.tttcn("let d=t.length;") // recursion depth
 	b.tttcn("let i=t.length;") // row index
 
 	// start by building the next row
@@ -119,21 +166,17 @@ func (h *SearchIndexHeader) Write() string {
 
 	// if we are doing a prefix search then an adequate ul can already be used to collect
 	b.tttcn("if(sw&&ul<=l){")
-	b.ttttcn("_collect(m,s[ul]);")
+	b.ttttcn("await _collect(m,t,s[ul]);")
 	b.ttttcn("return;")
 	b.tttcn("}")
 
-	b.tttcn("for(let k in m){")
-	b.ttttcn("if(k=='pages'){")
-	b.tttttcn("if(ul<=l){")
-	b.ttttttcn("m.pages.forEach((i)=>{s[ul].add(i)});")
 	// otherwise ignore the pages of this node
-	b.tttttcn("}")
-	b.ttttcn("}else{")
-	// deeper recursion
-	b.tttttcn("_fuzzy(m[k],w,R,t+k,l,sw,s);")
-	b.ttttcn("}")
+	b.tttcn("if(m.pages!==undefined&&ul<=l){")
+	b.ttttcn("m.pages.forEach((i)=>{s[ul].add(i)});")
 	b.tttcn("}")
+
+	// deeper recursion
+	b.tttcn("await _children(m,t,(c,q)=>{return _fuzzy(c,w,R,q,l,sw,s)});")
 	b.ttcn("};")
 
 	b.ttcn("var _initFuzzy=function(l){")
@@ -145,35 +188,108 @@ func (h *SearchIndexHeader) Write() string {
 	b.tttcn("return s;")
 	b.ttcn("};")
 
-	b.ttcn("this.fuzzy=function(w,l){")
+	b.ttcn("var _fuzzyRoot=async function(t,w,l,sw){")
 	b.tttcn("let s=_initFuzzy(l);")
-	b.tttcn("_fuzzy(data.index,w,null,'',l,false,s);") // first row is filled automatically internally
+	b.tttcn("await _fuzzy(await _root(t),w,null,'',l,sw,s);") // first row is filled automatically internally
 	b.tttcn("return s;")
 	b.ttcn("};")
 
+	b.ttcn("this.fuzzy=function(w,l){")
+	b.tttcn("return _fuzzyRoot('index',w,l,false);")
+	b.ttcn("};")
+
 	b.ttcn("this.fuzzyPrefix=function(w,l){")
-	b.tttcn("let s=_initFuzzy(l);")
-	b.tttcn("_fuzzy(data.index,w,null,'',l,true,s);")
-	b.tttcn("return s;")
+	b.tttcn("return _fuzzyRoot('index',w,l,true);")
 	b.ttcn("};")
 
 	b.ttcn("this.fuzzySuffix=function(w,l){")
-	b.tttcn("let s=_initFuzzy(l);")
-	b.tttcn("_fuzzy(data.partial,w,null,'',l,false,s);")
-	b.tttcn("return s;")
+	b.tttcn("return _fuzzyRoot('partial',w,l,false);")
 	b.ttcn("};")
 
 	b.ttcn("this.fuzzySubstring=function(w,l){")
-	b.tttcn("let s=_initFuzzy(l);")
-	b.tttcn("_fuzzy(data.partial,w,null,'',l,true,s);")
-	b.tttcn("return s;")
+	b.tttcn("return _fuzzyRoot('partial',w,l,true);")
+	b.ttcn("};")
+
+	// ranked search
+	// BM25F: the term frequencies of each field are normalized by the field length and weighted, before being saturated
+	b.ttcn("const K1=1.2,B=0.75;")
+
+	// tokenization, folding, stop words and stemming
+	writeSearchAnalyser(b)
+
+	b.ttcn("this.analyse=function(t,l){")
+	b.tttcn("l=_lang(l);")
+	b.tttcn("return _tokens(t).map((x)=>{return _term(x[2],l)}).filter((x)=>{return x.length>0});")
+	b.ttcn("};")
+
+	// all [word,node] pairs below node m, t is the word so far
+	b.ttcn("var _words=async function(m,t,r){")
+	b.tttcn("if(m.pages!==undefined){r.push([t,m])}")
+	b.tttcn("await _children(m,t,(c,q)=>{return _words(c,q,r)});")
+	b.ttcn("};")
+
+	// offsets of the words of s that analyse to one of the terms ts
+	b.ttcn("var _offsets=function(f,j,s,ts,l,r){")
+	b.tttcn("_tokens(s).forEach((x)=>{")
+	b.ttttcn("if(ts.has(_term(x[2],l))){r.push({field:f,paragraph:j,start:x[0],stop:x[1]})}")
+	b.tttcn("});")
+	b.ttcn("};")
+
+	b.ttcn("var _matches=function(p,ts){")
+	b.tttcn("let r=[];")
+	b.tttcn("let l=_lang(p.lang);")
+	b.tttcn("_offsets('title',0,p.title,ts,l,r);")
+	b.tttcn("if(p.description){_offsets('description',0,p.description,ts,l,r)}")
+	b.tttcn("p.content.forEach((c,j)=>{_offsets('content',j,c,ts,l,r)});")
+	b.tttcn("return r;")
+	b.ttcn("};")
+
+	b.ttcn("this.search=async function(q,opt={}){")
+	b.tttcn("await ready;")
+	b.tttcn("let n=data.lengths.length;")
+	b.tttcn("let s=new Map();")
+	// language of the query
+	b.tttcn("let ql=_lang((opt.lang!==undefined)?opt.lang:(((typeof document!='undefined')&&document.documentElement.lang)||data.language));")
+	// [term,node] pairs of each query word
+	b.tttcn("let rs=await Promise.all(_tokens(q).map(async (x)=>{")
+	b.ttttcn("let r=[];")
+	b.ttttcn("if(this.ignore(_normalize(x[2]))){return r}")
+	b.ttttcn("let w=_term(x[2],ql);")
+	b.ttttcn("if(w.length==0){return r}")
+	b.ttttcn("let m=await _find('index',w);")
+	b.ttttcn("if(m===undefined){return r}")
+	b.ttttcn("if(opt.prefix===true){await _words(m,w,r)}else if(m.pages!==undefined){r.push([w,m])}")
+	b.ttttcn("return r;")
+	b.tttcn("}));")
+	b.tttcn("[].concat(...rs).forEach(([t,l])=>{")
+	b.ttttcn("let idf=Math.log(1+(n-l.pages.length+0.5)/(l.pages.length+0.5));")
+	b.ttttcn("l.pages.forEach((i,j)=>{")
+	b.tttttcn("let x=0;")
+	b.tttttcn("for(let f=0;f<l.tf[j].length;f++){")
+	b.ttttttcn("let a=data.avgLengths[f];")
+	b.ttttttcn("if(a>0){x+=data.weights[f]*l.tf[j][f]/(1-B+B*data.lengths[i][f]/a)}")
+	b.tttttcn("}")
+	b.tttttcn("let e=s.get(i);")
+	b.tttttcn("if(e===undefined){e={index:i,score:0,words:new Set()};s.set(i,e)}")
+	b.tttttcn("e.score+=idf*x/(K1+x);")
+	b.tttttcn("e.words.add(t);")
+	b.ttttcn("});")
+	b.tttcn("});")
+	b.tttcn("let es=Array.from(s.values());")
+	b.tttcn("es.sort((a,b)=>{return b.score-a.score});")
+	b.tttcn("if(opt.limit!==undefined&&es.length>opt.limit){es.length=opt.limit}")
+	// only the pages of the returned results are fetched
+	b.tttcn("return Promise.all(es.map(async (e)=>{")
+	b.ttttcn("let p=await _page(e.index);")
+	b.ttttcn("return {index:e.index,url:p.url,title:p.title,score:e.score,matches:_matches(p,e.words)};")
+	b.tttcn("}));")
 	b.ttcn("};")
 
 	// end of constructor
 	b.tcn("}")
 
 	b.tcn("set onready(fn){")
-	b.ttcn("if(this.page(0)!==null){")
+	b.ttcn("if(this._ready){")
 	b.tttcn("fn()")
 	b.ttcn("}else{")
 	b.tttcn("this._onready.push(fn)")
@@ -182,13 +298,11 @@ func (h *SearchIndexHeader) Write() string {
 	b.c("}")
 	b.n()
 
-	// TODO: add other searches
-
 	return b.String()
 }
 
-/*var searchIndexHeader = &SearchIndexHeader{newHeaderData("SearchIndex")}
+var searchIndexHeader = &SearchIndexHeader{newHeaderData("SearchIndex")}
 
 func ActivateSearchIndexHeader() {
 	ResolveHeaderActivity(searchIndexHeader, context.NewDummyContext())
-}*/
+}
@@ -0,0 +1,120 @@
+package macros
+
+import (
+  "strings"
+
+  "github.com/computeportal/wtsuite/pkg/tokens/context"
+  "github.com/computeportal/wtsuite/pkg/tokens/js"
+  "github.com/computeportal/wtsuite/pkg/tokens/js/prototypes"
+  "github.com/computeportal/wtsuite/pkg/tokens/js/values"
+)
+
+// client:
+//   new SocketRPC(Interf, socket) -> rpc client instance of Interf
+// server:
+//   new SocketRPC(Interf, impl, socket) -> RPCServer, serving impl via socket
+// socket is a WebSocket or a MessagePort (or Any, eg. a nodejs websocket library)
+type SocketRPC struct {
+  RPCMacro
+}
+
+func NewSocketRPC(args []js.Expression, ctx context.Context) (js.Expression, error) {
+  if len(args) != 2 && len(args) != 3 {
+    return nil, ctx.NewError("Error: expected 2 or 3 arguments")
+  }
+
+  rpcMacro, err := newRPCMacro(args, ctx)
+  if err != nil {
+    return nil, err
+  }
+
+  return &SocketRPC{rpcMacro}, nil
+}
+
+func (m *SocketRPC) isClient() bool {
+  return len(m.args) == 1
+}
+
+func (m *SocketRPC) Dump(indent string) string {
+  return indent + "SocketRPC(...)\n"
+}
+
+func (m *SocketRPC) WriteExpression() string {
+  var b strings.Builder
+
+  b.WriteString("new ")
+  b.WriteString(m.header().Name())
+  b.WriteString("(")
+  b.WriteString(m.interf.Name())
+  for _, arg := range m.args {
+    b.WriteString(",")
+    b.WriteString(arg.WriteExpression())
+  }
+  b.WriteString(")")
+
+  return b.String()
+}
+
+func (m *SocketRPC) checkSocket(socket values.Value, ctx context.Context) error {
+  if err := prototypes.NewWebSocket(ctx).Check(socket, ctx); err == nil {
+    return nil
+  }
+
+  if err := prototypes.NewMessagePort(ctx).Check(socket, ctx); err == nil {
+    return nil
+  }
+
+  errCtx := m.args[len(m.args)-1].Context()
+  return errCtx.NewError("Error: expected WebSocket or MessagePort, got " + socket.TypeName())
+}
+
+func (m *SocketRPC) EvalExpression() (values.Value, error) {
+  ctx := m.Context()
+
+  if !m.interf.IsRPC() {
+    errCtx := m.interfExpr.Context()
+    return nil, errCtx.NewError("Error: " + m.interf.Name() + " is not an rpc interface")
+  }
+
+  args, err := m.evalArgs()
+  if err != nil {
+    return nil, err
+  }
+
+  if err := m.checkSocket(args[len(args)-1], ctx); err != nil {
+    return nil, err
+  }
+
+  if m.isClient() {
+    return values.NewInstance(m.interf, ctx), nil
+  } else {
+    checkVal := values.NewInstance(m.interf, ctx)
+    if err := checkVal.Check(args[0], ctx); err != nil {
+      return nil, err
+    }
+
+    return prototypes.NewRPCServer(ctx), nil
+  }
+}
+
+func (m *SocketRPC) header() Header {
+  if m.isClient() {
+    return socketRPCClientHeader
+  } else {
+    return socketRPCServerHeader
+  }
+}
+
+func (m *SocketRPC) ResolveExpressionActivity(usage js.Usage) error {
+  ResolveHeaderActivity(m.header(), m.Context())
+
+  return m.RPCMacro.ResolveExpressionActivity(usage)
+}
+
+func (m *SocketRPC) UniqueExpressionNames(ns js.Namespace) error {
+  if err := UniqueHeaderNames(m.header(), ns); err != nil {
+    return err
+  }
+
+  return m.RPCMacro.UniqueExpressionNames(ns)
+}
@@ -0,0 +1,93 @@
+package macros
+
+type SocketRPCClientHeader struct {
+  HeaderData
+}
+
+func (h *SocketRPCClientHeader) Dependencies() []Header {
+  return []Header{rpcClientHeader, checkTypeHeader}
+}
+
+func (h *SocketRPCClientHeader) Write() string {
+  b := NewHeaderBuilder()
+
+  // like WorkerRPCClient, but WebSockets must be opened first and can be closed by the other side
+  b.n()
+  b.cccn("var ", h.Name(), "=(function(interf,socket){")
+  b.tcn("let pending={};")
+  b.tcn("let seq=0;")
+  b.tcn("let isWebSocket=(typeof socket.send==='function');")
+  b.tcn("let opened=(!isWebSocket||socket.readyState===1)?Promise.resolve():new Promise(function(resolve,reject){")
+  b.ttcn("socket.addEventListener('open',function(){resolve()});")
+  b.ttcn("socket.addEventListener('error',function(){reject(new Error('socket error'))});")
+  b.tcn("});")
+  b.tcn("if(isWebSocket){")
+  b.ttcn("socket.addEventListener('close',function(){")
+  b.tttcn("for(let s in pending){pending[s][1](new Error('socket closed'))};")
+  b.tttcn("pending={};")
+  b.ttcn("});")
+  b.tcn("}")
+  b.tcn("socket.onmessage=function(e){")
+  // frames that don't have the {seq, data} structure are ignored
+  b.ttcn("let d;")
+  b.ttcn("try{")
+  b.tttcn("d=isWebSocket?JSON.parse(e.data):e.data;")
+  b.tttcccn(checkTypeHeader.Name(), "(d.seq,Int)", ";")
+  b.tttcccn(checkTypeHeader.Name(), "(d.data,String)", ";")
+  b.ttcn("}catch(err){return};")
+  b.ttcn("let p=pending[d.seq];")
+  b.ttcn("if(p===undefined){return};")
+  b.ttcn("delete pending[d.seq];")
+  b.ttcn("p[0](d.data);")
+  b.tcn("};")
+  b.tcccn("return new ", rpcClientHeader.Name(), "(interf,async function(msg){")
+  b.ttcn("await opened;")
+  b.ttcn("return new Promise(function(resolve,reject){")
+  b.tttcn("let s=seq++;")
+  b.tttcn("pending[s]=[resolve,reject];")
+  // WebSocket frames are JSON strings, MessagePort messages are structured clones
+  b.tttcn("if(isWebSocket){socket.send(JSON.stringify({seq:s,data:msg}))}else{socket.postMessage({seq:s,data:msg})};")
+  b.ttcn("});")
+  b.tcn("});")
+  b.c("});")
+  b.n()
+
+  return b.String()
+}
+
+type SocketRPCServerHeader struct {
+  HeaderData
+}
+
+func (h *SocketRPCServerHeader) Dependencies() []Header {
+  return []Header{rpcServerHeader, checkTypeHeader}
+}
+
+func (h *SocketRPCServerHeader) Write() string {
+  b := NewHeaderBuilder()
+
+  b.n()
+  b.cccn("var ", h.Name(), "=(function(interf,value,socket){")
+  b.tcccn("let server=new ", rpcServerHeader.Name(), "(interf,value);")
+  b.tcn("let isWebSocket=(typeof socket.send==='function');")
+  b.tcn("socket.onmessage=async function(e){")
+  // frames that don't have the {seq, data} structure are ignored
+  b.ttcn("let d;")
+  b.ttcn("try{")
+  b.tttcn("d=isWebSocket?JSON.parse(e.data):e.data;")
+  b.tttcccn(checkTypeHeader.Name(), "(d.seq,Int)", ";")
+  b.tttcccn(checkTypeHeader.Name(), "(d.data,String)", ";")
+  b.ttcn("}catch(err){return};")
+  b.ttcn("let reply=await server.handle(d.data);")
+  b.ttcn("let r={seq:d.seq,data:reply};")
+  b.ttcn("if(isWebSocket){socket.send(JSON.stringify(r))}else{socket.postMessage(r)};")
+  b.tcn("};")
+  b.tcn("return server;")
+  b.c("});")
+  b.n()
+
+  return b.String()
+}
+
+var socketRPCClientHeader = &SocketRPCClientHeader{newHeaderData("SocketRPCClient")}
+var socketRPCServerHeader = &SocketRPCServerHeader{newHeaderData("SocketRPCServer")}
@@ -1,6 +1,7 @@
 package macros
 
 import (
+  "sort"
   "strconv"
   "strings"
 
@@ -11,13 +12,21 @@ import (
 )
 
 type WebGLProgram struct {
-  vertexSource string
-  fragmentSource string
+  shaders *WebGLShaders // set during eval stage
   Macro
 }
 
+// uniforms and attributes map names to glsl type names (eg. "vec3" or "float[4]")
+type WebGLShaders struct {
+  VertexSource string
+  FragmentSource string
+  Version string // eg. "300 es", empty if not declared
+  Uniforms map[string]string // active uniforms of both shaders, struct uniforms and uniform blocks excluded
+  Attributes map[string]string
+}
+
 type TranspileWebGLShadersFunc func(callerPath string, vertexPath *js.Word, vertexConsts map[string]values.Value,
-  fragmentPath *js.Word, fragmentConsts map[string]values.Value) (string, string, error)
+  fragmentPath *js.Word, fragmentConsts map[string]values.Value) (*WebGLShaders, error)
 
 var transpileWebGLShaders TranspileWebGLShadersFunc = nil
   
@@ -33,7 +42,7 @@ func NewWebGLProgram(args []js.Expression, ctx context.Context) (js.Expression,
     return nil, errCtx.NewError("Error: expected 3, 4 or 5 arguments, got " + strconv.Itoa(len(args)))
   }
 
-  return &WebGLProgram{"", "", newMacro(args, ctx)}, nil
+  return &WebGLProgram{nil, newMacro(args, ctx)}, nil
 }
 
 func (m *WebGLProgram) Dump(indent string) string {
@@ -55,10 +64,31 @@ func (m *WebGLProgram) WriteExpression() string {
   b.WriteString("return ")
   b.WriteString(webGLProgramHeader.Name())
   b.WriteString("(gl,")
-  b.WriteString(m.vertexSource)
+  b.WriteString(m.shaders.VertexSource)
   b.WriteString(",")
-  b.WriteString(m.fragmentSource)
-  b.WriteString(")})(")
+  b.WriteString(m.shaders.FragmentSource)
+  b.WriteString(",{")
+  for i, name := range sortedKeys(m.shaders.Uniforms) {
+    // setter type is known to be valid after eval stage
+    fnName, _ := webGLUniformSetter(m.shaders.Uniforms[name], m.Context())
+    if i > 0 {
+      b.WriteString(",")
+    }
+    b.WriteString(name)
+    b.WriteString(":'")
+    b.WriteString(fnName)
+    b.WriteString("'")
+  }
+  b.WriteString("},[")
+  for i, name := range sortedKeys(m.shaders.Attributes) {
+    if i > 0 {
+      b.WriteString(",")
+    }
+    b.WriteString("'")
+    b.WriteString(name)
+    b.WriteString("'")
+  }
+  b.WriteString("])})(")
   b.WriteString(m.args[0].WriteExpression())
   b.WriteString(",")
   if (len(m.args) > 3) {
@@ -87,9 +117,10 @@ func (m *WebGLProgram) EvalExpression() (values.Value, error) {
     return nil, err
   }
 
+  // WebGL2RenderingContext is also accepted
   if !prototypes.IsWebGLRenderingContext(args[0]) {
     errCtx := m.args[0].Context()
-    return nil, errCtx.NewError("Error: expected WebGLRenderingContext, got " + args[0].TypeName())
+    return nil, errCtx.NewError("Error: expected WebGLRenderingContext or WebGL2RenderingContext, got " + args[0].TypeName())
   }
 
   vertexPath_, ok := args[1].LiteralStringValue()
@@ -130,13 +161,110 @@ func (m *WebGLProgram) EvalExpression() (values.Value, error) {
 
   ctx := m.Context()
   callerPath := ctx.Path()
-  m.vertexSource, m.fragmentSource, err = transpileWebGLShaders(
-    callerPath, vertexPath, vertexConsts, fragmentPath, fragmentConsts)
+  m.shaders, err = transpileWebGLShaders(callerPath, vertexPath, vertexConsts, fragmentPath, fragmentConsts)
   if err != nil {
     return nil, err
   }
 
-  return prototypes.NewWebGLProgram(m.Context()), nil
+  if m.shaders.Version == "300 es" && !prototypes.IsWebGL2RenderingContext(args[0]) {
+    errCtx := m.args[0].Context()
+    return nil, errCtx.NewError("Error: #version 300 es shaders require a WebGL2RenderingContext, got " + args[0].TypeName())
+  }
+
+  // unused uniforms and attributes are already errors in the shaders themselves
+  uniforms := make(map[string]values.Value)
+  for name, typeName := range m.shaders.Uniforms {
+    _, setter := webGLUniformSetter(typeName, ctx)
+    if setter == nil {
+      return nil, ctx.NewError("Error: don't know how to set uniform " + name + " of type " + typeName)
+    }
+
+    uniforms[name] = setter
+  }
+
+  attributes := make(map[string]values.Value)
+  for name, _ := range m.shaders.Attributes {
+    attributes[name] = prototypes.NewInt(ctx)
+  }
+
+  return prototypes.NewReflectedWebGLProgram(uniforms, attributes, ctx), nil
+}
+
+// sorted for consistent output
+func sortedKeys(m map[string]string) []string {
+  result := make([]string, 0)
+  for k, _ := range m {
+    result = append(result, k)
+  }
+
+  sort.Strings(result)
+
+  return result
+}
+
+// returns the name of the WebGLRenderingContext method, and the type of the generated setter (nil if the glsl type isn't supported)
+//  float -> uniform1f(Number), vecN -> uniformNf(Number, ...)
+//  int, samplers -> uniform1i(Int), ivecN -> uniformNi(Int, ...)
+//  bool -> uniform1i(Boolean), bvecN -> uniformNi(Boolean, ...)
+//  uint -> uniform1ui(Int), uvecN -> uniformNui(Int, ...)
+//  matN -> uniformMatrixNfv(Array<Number>|Float32Array)
+//  arrays -> uniformN(f|i|ui)v(Array<...>|TypedArray)
+func webGLUniformSetter(typeName string, ctx context.Context) (string, values.Value) {
+  isArray := false
+  if i := strings.Index(typeName, "["); i != -1 {
+    isArray = true
+    typeName = typeName[0:i]
+  }
+
+  n := 1
+  if last := typeName[len(typeName)-1:]; last >= "2" && last <= "4" {
+    n, _ = strconv.Atoi(last)
+  }
+
+  var (
+    suffix string
+    arg values.Value
+    typedArray values.Value
+  )
+
+  switch {
+  case typeName == "float" || strings.HasPrefix(typeName, "vec"):
+    suffix, arg, typedArray = "f", prototypes.NewNumber(ctx), prototypes.NewFloat32Array(ctx)
+  case typeName == "int" || strings.HasPrefix(typeName, "ivec") || strings.HasPrefix(typeName, "sampler"):
+    suffix, arg, typedArray = "i", prototypes.NewInt(ctx), prototypes.NewInt32Array(ctx)
+  case typeName == "bool" || strings.HasPrefix(typeName, "bvec"):
+    suffix, arg, typedArray = "i", prototypes.NewBoolean(ctx), prototypes.NewInt32Array(ctx)
+  case typeName == "uint" || strings.HasPrefix(typeName, "uvec"):
+    suffix, arg, typedArray = "ui", prototypes.NewInt(ctx), prototypes.NewUint32Array(ctx)
+  case strings.HasPrefix(typeName, "mat"):
+    return "uniformMatrix" + strconv.Itoa(n) + "fv", values.NewOverloadedFunction([][]values.Value{
+      []values.Value{prototypes.NewArray(prototypes.NewNumber(ctx), ctx), nil},
+      []values.Value{prototypes.NewFloat32Array(ctx), nil},
+    }, ctx)
+  default:
+    return "", nil
+  }
+
+  fnName := "uniform" + strconv.Itoa(n) + suffix
+
+  if isArray {
+    if suffix == "i" {
+      // booleans are passed as ints in arrays
+      arg = prototypes.NewInt(ctx)
+    }
+
+    return fnName + "v", values.NewOverloadedFunction([][]values.Value{
+      []values.Value{prototypes.NewArray(arg, ctx), nil},
+      []values.Value{typedArray, nil},
+    }, ctx)
+  }
+
+  args := make([]values.Value, 0)
+  for i := 0; i < n; i++ {
+    args = append(args, arg)
+  }
+
+  return fnName, values.NewFunction(append(args, nil), ctx)
 }
 
 func (m *WebGLProgram) ResolveExpressionActivity(usage js.Usage) error {
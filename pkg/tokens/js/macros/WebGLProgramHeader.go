@@ -17,7 +17,8 @@ func (h *WebGLProgramHeader) Write() string {
 
   b.n()
 
-  b.cccn("function ", h.Name(), "(gl,v,f){")
+  // u maps uniform names to setter methods of gl, a lists the attribute names
+  b.cccn("function ", h.Name(), "(gl,v,f,u,a){")
   b.tcn("let vs=gl.createShader(gl.VERTEX_SHADER);")
   b.tcn("gl.shaderSource(vs,v);")
   b.tcn("gl.compileShader(vs);")
@@ -34,6 +35,19 @@ func (h *WebGLProgramHeader) Write() string {
   b.tcn("gl.attachShader(p,vs);")
   b.tcn("gl.attachShader(p,fs);")
   b.tcn("gl.linkProgram(p);")
+
+  b.tcn("p.uniforms={};")
+  b.tcn("for(let n in u){")
+  b.tcn("let l=gl.getUniformLocation(p,n);")
+  b.tcn("let s=u[n];")
+  b.tcn("if(s.startsWith('uniformMatrix')){p.uniforms[n]=function(m){gl.useProgram(p);gl[s](l,false,m);}}")
+  b.tcn("else if(s.endsWith('v')){p.uniforms[n]=function(x){gl.useProgram(p);gl[s](l,x);}}")
+  b.tcn("else{p.uniforms[n]=function(...x){gl.useProgram(p);gl[s](l,...x);}}")
+  b.tcn("}")
+
+  b.tcn("p.attributes={};")
+  b.tcn("for(let n of a){p.attributes[n]=gl.getAttribLocation(p,n);}")
+
   b.tcn("return p;")
   b.c("}")
   b.n()
@@ -0,0 +1,235 @@
+package macros
+
+import (
+  "path/filepath"
+  "sort"
+  "strings"
+
+  "github.com/computeportal/wtsuite/pkg/files"
+  "github.com/computeportal/wtsuite/pkg/tokens/context"
+  "github.com/computeportal/wtsuite/pkg/tokens/js"
+  "github.com/computeportal/wtsuite/pkg/tokens/js/prototypes"
+  "github.com/computeportal/wtsuite/pkg/tokens/js/values"
+)
+
+// in browser code:
+//   new WorkerRPC(Interf, "./worker.wts") -> rpc client instance of Interf, running worker.wts in a separate Worker
+// in worker.wts (which is built as a separate bundle with target worker):
+//   new WorkerRPC(Interf, impl) -> RPCServer, serving impl via the worker's message channel
+type WorkerRPC struct {
+  workerPath string // only set in browser code
+  RPCMacro
+}
+
+type workerEntry struct {
+  interfName string
+  interfPath string // path of the module in which the interface is defined
+  ctx        context.Context
+}
+
+var (
+  // collected when evaluating WorkerRPC clients, used by wt-site and wt-script to build the worker bundles
+  _workerEntries = make(map[string]workerEntry)
+
+  // set while building a worker bundle
+  _activeWorkerEntry = ""
+  _activeWorkerEntryServed = false
+)
+
+// the url from which the browser loads the bundle of a worker entry module, can be overridden by the cmd tools
+var WorkerURL = func(absPath string) string {
+  return "./" + WorkerBundleName(absPath)
+}
+
+func WorkerBundleName(absPath string) string {
+  base := strings.TrimSuffix(filepath.Base(absPath), files.JSFILE_EXT)
+
+  return base + "." + js.HashControl(absPath) + ".js"
+}
+
+// sorted for consistent behaviour
+func WorkerEntryPoints() []string {
+  result := make([]string, 0)
+  for absPath, _ := range _workerEntries {
+    result = append(result, absPath)
+  }
+
+  sort.Strings(result)
+
+  return result
+}
+
+func StartWorkerBundle(absPath string) {
+  _activeWorkerEntry = absPath
+  _activeWorkerEntryServed = false
+
+  ResetHeaders()
+}
+
+// returns an error if the worker entry module doesn't serve the interface expected by the browser code
+func FinishWorkerBundle() error {
+  entry, ok := _workerEntries[_activeWorkerEntry]
+  if !ok {
+    panic("not a worker entry point")
+  }
+
+  served := _activeWorkerEntryServed
+
+  _activeWorkerEntry = ""
+  _activeWorkerEntryServed = false
+
+  if !served {
+    errCtx := entry.ctx
+    return errCtx.NewError("Error: worker entry module doesn't contain 'new WorkerRPC(" + entry.interfName + ", ...)'")
+  }
+
+  return nil
+}
+
+func NewWorkerRPC(args []js.Expression, ctx context.Context) (js.Expression, error) {
+  if len(args) != 2 {
+    return nil, ctx.NewError("Error: expected 2 arguments")
+  }
+
+  rpcMacro, err := newRPCMacro(args, ctx)
+  if err != nil {
+    return nil, err
+  }
+
+  workerPath := ""
+
+  switch js.TARGET {
+  case "browser":
+    lit, ok := args[1].(*js.LiteralString)
+    if !ok {
+      errCtx := args[1].Context()
+      return nil, errCtx.NewError("Error: expected literal string path to worker entry module")
+    }
+
+    workerPath, err = files.Search(ctx.Path(), lit.Value())
+    if err != nil {
+      errCtx := lit.Context()
+      return nil, errCtx.NewError("Error: " + err.Error())
+    }
+
+    // the imports of the worker entry module are recorded as its own dependencies when the worker bundle is built,
+    // and cache updates are checked recursively
+    if files.AddCacheDependency != nil {
+      files.AddCacheDependency(ctx.Path(), workerPath)
+    }
+  case "worker":
+  default:
+    return nil, ctx.NewError("Error: only available if target is browser or worker, (now it is " + js.TARGET + ")")
+  }
+
+  return &WorkerRPC{workerPath, rpcMacro}, nil
+}
+
+func (m *WorkerRPC) isClient() bool {
+  return m.workerPath != ""
+}
+
+func (m *WorkerRPC) Dump(indent string) string {
+  return indent + "WorkerRPC(...)\n"
+}
+
+func (m *WorkerRPC) WriteExpression() string {
+  var b strings.Builder
+
+  b.WriteString("new ")
+  if m.isClient() {
+    b.WriteString(workerRPCClientHeader.Name())
+  } else {
+    b.WriteString(workerRPCServerHeader.Name())
+  }
+  b.WriteString("(")
+  b.WriteString(m.interf.Name())
+  b.WriteString(",")
+  if m.isClient() {
+    b.WriteString("'")
+    b.WriteString(WorkerURL(m.workerPath))
+    b.WriteString("'")
+  } else {
+    b.WriteString(m.args[0].WriteExpression())
+  }
+  b.WriteString(")")
+
+  return b.String()
+}
+
+func (m *WorkerRPC) interfPath() string {
+  interfCtx := m.interf.Context()
+  return interfCtx.Path()
+}
+
+func (m *WorkerRPC) EvalExpression() (values.Value, error) {
+  ctx := m.Context()
+
+  if !m.interf.IsRPC() {
+    errCtx := m.interfExpr.Context()
+    return nil, errCtx.NewError("Error: " + m.interf.Name() + " is not an rpc interface")
+  }
+
+  if m.isClient() {
+    if prev, ok := _workerEntries[m.workerPath]; ok && (prev.interfName != m.interf.Name() || prev.interfPath != m.interfPath()) {
+      err := ctx.NewError("Error: worker entry module is used with different interfaces")
+      err.AppendContextString("Info: also used here", prev.ctx)
+      return nil, err
+    }
+
+    _workerEntries[m.workerPath] = workerEntry{m.interf.Name(), m.interfPath(), ctx}
+
+    return values.NewInstance(m.interf, ctx), nil
+  } else {
+    args, err := m.evalArgs()
+    if err != nil {
+      return nil, err
+    }
+
+    checkVal := values.NewInstance(m.interf, ctx)
+    if err := checkVal.Check(args[0], ctx); err != nil {
+      return nil, err
+    }
+
+    // check that the browser side uses the same interface
+    if _activeWorkerEntry != "" {
+      entry := _workerEntries[_activeWorkerEntry]
+
+      if entry.interfName != m.interf.Name() || entry.interfPath != m.interfPath() {
+        err := ctx.NewError("Error: worker serves " + m.interf.Name() + ", but the browser expects " + entry.interfName)
+        err.AppendContextString("Info: worker created here", entry.ctx)
+        return nil, err
+      }
+
+      if _activeWorkerEntryServed {
+        return nil, ctx.NewError("Error: worker entry module can only serve one interface")
+      }
+
+      _activeWorkerEntryServed = true
+    }
+
+    return prototypes.NewRPCServer(ctx), nil
+  }
+}
+
+func (m *WorkerRPC) header() Header {
+  if m.isClient() {
+    return workerRPCClientHeader
+  } else {
+    return workerRPCServerHeader
+  }
+}
+
+func (m *WorkerRPC) ResolveExpressionActivity(usage js.Usage) error {
+  ResolveHeaderActivity(m.header(), m.Context())
+
+  return m.RPCMacro.ResolveExpressionActivity(usage)
+}
+
+func (m *WorkerRPC) UniqueExpressionNames(ns js.Namespace) error {
+  if err := UniqueHeaderNames(m.header(), ns); err != nil {
+    return err
+  }
+
+  return m.RPCMacro.UniqueExpressionNames(ns)
+}
@@ -0,0 +1,74 @@
+package macros
+
+type WorkerRPCClientHeader struct {
+  HeaderData
+}
+
+func (h *WorkerRPCClientHeader) Dependencies() []Header {
+  return []Header{rpcClientHeader}
+}
+
+func (h *WorkerRPCClientHeader) Write() string {
+  b := NewHeaderBuilder()
+
+  // replies are matched with requests via a sequence number, because the worker can handle requests concurrently
+  b.n()
+  b.cccn("var ", h.Name(), "=(function(interf,url){")
+  b.tcn("let worker=new Worker(url);")
+  b.tcn("let pending={};")
+  b.tcn("let seq=0;")
+  // replies with an unknown sequence number are ignored
+  b.tcn("worker.onmessage=function(e){")
+  b.ttcn("let p=(e.data===null||typeof e.data!=='object')?undefined:pending[e.data.seq];")
+  b.ttcn("if(p===undefined){return};")
+  b.ttcn("delete pending[e.data.seq];")
+  b.ttcn("p[0](e.data.data);")
+  b.tcn("};")
+  // the replies of the pending calls might never arrive, so they are all rejected
+  b.tcn("let rejectAll=function(err){")
+  b.ttcn("let ps=pending;")
+  b.ttcn("pending={};")
+  b.ttcn("for(let s in ps){ps[s][1](err)};")
+  b.tcn("};")
+  b.tcn("worker.onerror=function(e){rejectAll(new Error('worker error'+(e.message?': '+e.message:'')))};")
+  b.tcn("worker.onmessageerror=function(){rejectAll(new Error('worker message error'))};")
+  b.tcccn("return new ", rpcClientHeader.Name(), "(interf,function(msg){")
+  b.ttcn("return new Promise(function(resolve,reject){")
+  b.tttcn("let s=seq++;")
+  b.tttcn("pending[s]=[resolve,reject];")
+  b.tttcn("worker.postMessage({seq:s,data:msg});")
+  b.ttcn("});")
+  b.tcn("});")
+  b.c("});")
+  b.n()
+
+  return b.String()
+}
+
+type WorkerRPCServerHeader struct {
+  HeaderData
+}
+
+func (h *WorkerRPCServerHeader) Dependencies() []Header {
+  return []Header{rpcServerHeader}
+}
+
+func (h *WorkerRPCServerHeader) Write() string {
+  b := NewHeaderBuilder()
+
+  b.n()
+  b.cccn("var ", h.Name(), "=(function(interf,value){")
+  b.tcccn("let server=new ", rpcServerHeader.Name(), "(interf,value);")
+  b.tcn("self.onmessage=async function(e){")
+  b.ttcn("let reply=await server.handle(e.data.data);")
+  b.ttcn("self.postMessage({seq:e.data.seq,data:reply});")
+  b.tcn("};")
+  b.tcn("return server;")
+  b.c("});")
+  b.n()
+
+  return b.String()
+}
+
+var workerRPCClientHeader = &WorkerRPCClientHeader{newHeaderData("WorkerRPCClient")}
+var workerRPCServerHeader = &WorkerRPCServerHeader{newHeaderData("WorkerRPCServer")}
@@ -39,6 +39,13 @@ var _classMacros = map[string]MacroGroup{
 		},
 	},
 
+	"JSON": MacroGroup{
+		macros: map[string]MacroConstructor{
+			"parseAs":     NewJSONParseAs,
+			"stringifyAs": NewJSONStringifyAs,
+		},
+	},
+
 	"SharedWorker": MacroGroup{
 		macros: map[string]MacroConstructor{
 			"post": NewSharedWorkerPost,
@@ -72,9 +79,20 @@ var _callMacros = map[string]MacroConstructor{
 }
 
 var _constructorMacros = map[string]MacroConstructor{
+  "HTTPRPC": NewHTTPRPC,
   "RPCClient": NewRPCClient,
   "RPCServer": NewRPCServer,
+  "SocketRPC": NewSocketRPC,
   "WebGLProgram": NewWebGLProgram,
+  "WorkerRPC": NewWorkerRPC,
+}
+
+// builtin decorators shadow user functions with the same name
+var _decoratorMacros = map[string]DecoratorMacroConstructor{
+  "bind":       NewBindDecorator,
+  "deprecated": NewDeprecatedDecorator,
+  "log":        NewLogDecorator,
+  "memoize":    NewMemoizeDecorator,
 }
 
 func IsClassMacroGroup(gname string) bool {
@@ -101,6 +119,11 @@ func IsConstructorMacro(name string) bool {
   return ok
 }
 
+func IsDecoratorMacro(name string) bool {
+  _, ok := _decoratorMacros[name]
+  return ok
+}
+
 func MemberIsClassMacro(m *js.Member) bool {
 	if name, key := m.ObjectNameAndKey(); name != "" {
 		return IsClassMacro(name, key)
@@ -157,6 +180,11 @@ func NewConstructorMacro(name string, args []js.Expression,
   return _constructorMacros[name](args, ctx)
 }
 
+func NewDecoratorMacro(name string, args []js.Expression,
+  ctx context.Context) (js.Decorator, error) {
+  return _decoratorMacros[name](args, ctx)
+}
+
 func NewConstructorMacroFromCall(call *js.Call,
   ctx context.Context) (js.Expression, error) {
 	name := call.Name()
@@ -174,8 +202,8 @@ func RegisterActivateMacroHeadersCallback() bool {
 		switch name {
 		case "WebAssemblyEnv":
 			ActivateWebAssemblyEnvHeader()
-		//case "SearchIndex":
-			//ActivateSearchIndexHeader()
+		case "SearchIndex":
+			ActivateSearchIndexHeader()
     case "__checkType__":
       ActivateCheckTypeHeader()
     case "WebGLProgram":
@@ -0,0 +1,160 @@
+package js
+
+import (
+	"github.com/computeportal/wtsuite/pkg/tokens/context"
+	"github.com/computeportal/wtsuite/pkg/tokens/js/prototypes"
+	"github.com/computeportal/wtsuite/pkg/tokens/patterns"
+)
+
+// Class members are mangled project wide: a given member name always gets the same new name.
+// This way overriding members and members accessed via child classes automatically agree.
+// Mangled names start with a '$', which isn't allowed in wts words, so they can never collide
+// with members that are not mangled.
+var (
+	_mangledMemberNames = make(map[string]string)
+
+	// members accessed via interfaces (including rpc interfaces) are looked up by their original name
+	_interfaceMemberNames = make(map[string]bool)
+
+	// members accessed via 'any' values can't be resolved statically
+	_untypedMemberNames = make(map[string]bool)
+)
+
+func registerInterfaceMemberName(name string) {
+	_interfaceMemberNames[name] = true
+}
+
+func registerUntypedMemberName(name string) {
+	_untypedMemberNames[name] = true
+}
+
+func mangleMemberName(name string) string {
+	if mangled, ok := _mangledMemberNames[name]; ok {
+		return mangled
+	}
+
+	i := len(_mangledMemberNames) + 1
+
+	n := "$"
+	for i > 0 {
+		rem := (i - 1) % 26
+		n = n + patterns.COMPACT_LETTER[rem:rem+1]
+		i = (i - 1) / 26
+	}
+
+	_mangledMemberNames[name] = n
+
+	return n
+}
+
+// the decision is made for the whole hierarchy, so that overrides keep the name of the member they override
+func (t *Class) memberIsMangled(name string) bool {
+	if !patterns.COMPACT_NAMING {
+		return false
+	}
+
+	hasBuiltinAncestor := false
+
+	parent_, err := t.GetParent()
+	if err != nil {
+		panic("should've been caught before")
+	}
+
+	if parent_ != nil {
+		if parent, ok := parent_.(*Class); ok {
+			if parent.hasMemberInHierarchy(name) {
+				return parent.memberIsMangled(name)
+			}
+
+			hasBuiltinAncestor = parent.hasBuiltinAncestor()
+		} else {
+			// DOM/builtin prototype members must be left alone
+			hasBuiltinAncestor = true
+			if res, err := parent_.GetInstanceMember(name, true, context.NewDummyContext()); err == nil && res != nil {
+				return false
+			}
+		}
+	}
+
+	// properties of universal classes are exposed via Object.fromInstance, JSON etc.
+	if t.IsUniversal() {
+		return false
+	}
+
+	member := t.getMember(name, false)
+	if member == nil {
+		return false
+	}
+
+	if _, ok := _untypedMemberNames[name]; ok {
+		return false
+	}
+
+	// decorators receive the member name as a string
+	if t.memberIsDecorated(name) {
+		return false
+	}
+
+	if prototypes.IsPrivate(member) {
+		return true
+	}
+
+	if !patterns.MANGLE_ALL_MEMBERS || hasBuiltinAncestor {
+		return false
+	}
+
+	_, isInterfaceMember := _interfaceMemberNames[name]
+
+	return !isInterfaceMember
+}
+
+// getter and setter can have the same name, so all members are checked
+func (t *Class) memberIsDecorated(name string) bool {
+	for _, member := range t.members {
+		if member.Name() == name && len(t.memberDecorators[member]) > 0 {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (t *Class) hasMemberInHierarchy(name string) bool {
+	if t.getMember(name, false) != nil {
+		return true
+	}
+
+	parent_, err := t.GetParent()
+	if err != nil {
+		panic("should've been caught before")
+	}
+
+	if parent, ok := parent_.(*Class); ok {
+		return parent.hasMemberInHierarchy(name)
+	} else {
+		return false
+	}
+}
+
+func (t *Class) hasBuiltinAncestor() bool {
+	parent_, err := t.GetParent()
+	if err != nil {
+		panic("should've been caught before")
+	}
+
+	if parent_ == nil {
+		return false
+	} else if parent, ok := parent_.(*Class); ok {
+		return parent.hasBuiltinAncestor()
+	} else {
+		return true
+	}
+}
+
+func (t *Class) writeMemberName(name string) string {
+	if t.memberIsMangled(name) {
+		return mangleMemberName(name)
+	} else {
+		return name
+	}
+}
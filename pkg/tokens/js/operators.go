@@ -200,6 +200,11 @@ type LogicalOrOp struct {
 	LogicalBinaryOp
 }
 
+// a ?? b
+type NullishOp struct {
+	BinaryOp
+}
+
 type IfElseOp struct {
 	TernaryOp
 }
@@ -239,9 +244,20 @@ func NewBinaryOp(op string, a Expression, b Expression, ctx context.Context) (Op
 	case op == "|":
 		return &BitOrOp{BinaryBitOp{BinaryOp{op, a, b, TokenData{ctx}}}}, nil
 	case op == "||":
+		if err := assertNotMixedWithNullish(op, a, b, ctx); err != nil {
+			return nil, err
+		}
 		return &LogicalOrOp{LogicalBinaryOp{BinaryOp{op, a, b, TokenData{ctx}}}}, nil
 	case op == "&&":
+		if err := assertNotMixedWithNullish(op, a, b, ctx); err != nil {
+			return nil, err
+		}
 		return &LogicalAndOp{LogicalBinaryOp{BinaryOp{op, a, b, TokenData{ctx}}}}, nil
+	case op == "??":
+		if err := assertNotMixedWithNullish(op, a, b, ctx); err != nil {
+			return nil, err
+		}
+		return &NullishOp{BinaryOp{op, a, b, TokenData{ctx}}}, nil
 	case op == "^":
 		return &BitXorOp{BinaryBitOp{BinaryOp{op, a, b, TokenData{ctx}}}}, nil
 	case op == "<<":
@@ -282,6 +298,26 @@ func NewBinaryOp(op string, a Expression, b Expression, ctx context.Context) (Op
 	}
 }
 
+// javascript doesn't allow ?? directly next to || or &&, parentheses are needed
+func assertNotMixedWithNullish(op string, a Expression, b Expression, ctx context.Context) error {
+	for _, operand := range []Expression{a, b} {
+		mixed := false
+		switch operand.(type) {
+		case *NullishOp:
+			mixed = op != "??"
+		case *LogicalOrOp, *LogicalAndOp:
+			mixed = op == "??"
+		}
+
+		if mixed {
+			errCtx := context.MergeContexts(ctx, operand.Context())
+			return errCtx.NewError("Error: ?? can't be mixed with || or && without parentheses")
+		}
+	}
+
+	return nil
+}
+
 func NewPostUnaryOp(op string, a Expression, ctx context.Context) (Op, error) {
 	switch op {
 	case "++":
@@ -314,6 +350,11 @@ func NewPreUnaryOp(op string, a Expression, ctx context.Context) (Op, error) {
 			return nil, errCtx.NewError("Error: new argument is not a function call")
 		}
 
+		if isOptionalChain(a) {
+			errCtx := newCtx
+			return nil, errCtx.NewError("Error: new can't be used with optional chain")
+		}
+
 		return &NewOp{PreUnaryOp{UnaryOp{"new", a, TokenData{newCtx}}}}, nil
 	case "delete":
 		return NewDeleteOp(a, ctx), nil
@@ -1160,6 +1201,48 @@ func (t *StrictEqOp) EvalExpression() (values.Value, error) {
 	return prototypes.NewBoolean(ctx), nil
 }
 
+// typeof x == "string"
+func (t *StrictEqOp) CollectTypeGuards(c map[typeGuardTarget]values.Value) (bool, error) {
+	if _, err := t.EvalExpression(); err != nil {
+		return false, err
+	}
+
+	expr, typeName := getTypeOfCheck(t.a, t.b)
+	if expr == nil {
+		return false, nil
+	}
+
+	target := typeOfValue(typeName, t.Context())
+	if target == nil {
+		return false, nil
+	}
+
+	return addTypeGuard(c, expr, func(v values.Value) values.Value {
+		return values.Narrow(v, target, t.Context())
+	})
+}
+
+// x == null or typeof x == "..."
+func (t *StrictEqOp) CollectComplementTypeGuards(c map[typeGuardTarget]values.Value) (bool, error) {
+	if expr := getNullCheckExpression(t.a, t.b); expr != nil {
+		return addTypeGuard(c, expr, values.RemoveNull)
+	}
+
+	expr, typeName := getTypeOfCheck(t.a, t.b)
+	if expr == nil {
+		return false, nil
+	}
+
+	target := typeOfValue(typeName, t.Context())
+	if target == nil {
+		return false, nil
+	}
+
+	return addTypeGuard(c, expr, func(v values.Value) values.Value {
+		return values.Exclude(v, target, t.Context())
+	})
+}
+
 func (t *StrictEqOp) Walk(fn WalkFunc) error {
   if err := t.BinaryOp.Walk(fn); err != nil {
     return err
@@ -1179,6 +1262,48 @@ func (t *StrictNEOp) EvalExpression() (values.Value, error) {
 	return prototypes.NewBoolean(ctx), nil
 }
 
+// x != null or typeof x != "..."
+func (t *StrictNEOp) CollectTypeGuards(c map[typeGuardTarget]values.Value) (bool, error) {
+	if _, err := t.EvalExpression(); err != nil {
+		return false, err
+	}
+
+	if expr := getNullCheckExpression(t.a, t.b); expr != nil {
+		return addTypeGuard(c, expr, values.RemoveNull)
+	}
+
+	expr, typeName := getTypeOfCheck(t.a, t.b)
+	if expr == nil {
+		return false, nil
+	}
+
+	target := typeOfValue(typeName, t.Context())
+	if target == nil {
+		return false, nil
+	}
+
+	return addTypeGuard(c, expr, func(v values.Value) values.Value {
+		return values.Exclude(v, target, t.Context())
+	})
+}
+
+// typeof x != "..."
+func (t *StrictNEOp) CollectComplementTypeGuards(c map[typeGuardTarget]values.Value) (bool, error) {
+	expr, typeName := getTypeOfCheck(t.a, t.b)
+	if expr == nil {
+		return false, nil
+	}
+
+	target := typeOfValue(typeName, t.Context())
+	if target == nil {
+		return false, nil
+	}
+
+	return addTypeGuard(c, expr, func(v values.Value) values.Value {
+		return values.Narrow(v, target, t.Context())
+	})
+}
+
 func (t *StrictNEOp) Walk(fn WalkFunc) error {
   if err := t.BinaryOp.Walk(fn); err != nil {
     return err
@@ -1377,6 +1502,10 @@ func (t *LogicalBinaryOp) EvalExpression() (values.Value, error) {
 		return nil, err
 	}
 
+	return t.evalResult(a, b)
+}
+
+func (t *LogicalBinaryOp) evalResult(a values.Value, b values.Value) (values.Value, error) {
 	ctx := t.Context()
 
 	// also allow two numbers (to absorb nans, nulls etc)
@@ -1393,6 +1522,68 @@ func (t *LogicalBinaryOp) EvalExpression() (values.Value, error) {
 	}
 }
 
+// the rhs is only evaluated if the lhs is false
+func (t *LogicalOrOp) EvalExpression() (values.Value, error) {
+	a, err := t.a.EvalExpression()
+	if err != nil {
+		return nil, err
+	}
+
+	complementGuards, err := collectComplementTypeGuards(t.a)
+	if err != nil {
+		return nil, err
+	}
+
+	oldValues := applyTypeGuards(complementGuards)
+
+	b, err := t.b.EvalExpression()
+
+	restoreTypeGuards(oldValues)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return t.evalResult(a, b)
+}
+
+// a || b only narrows if it is false
+func (t *LogicalOrOp) CollectTypeGuards(c map[typeGuardTarget]values.Value) (bool, error) {
+	return false, nil
+}
+
+// if a || b is false, both a and b are false
+func (t *LogicalOrOp) CollectComplementTypeGuards(c map[typeGuardTarget]values.Value) (bool, error) {
+	a, ok := t.a.(TypeGuard)
+	if !ok {
+		return false, nil
+	}
+
+	ok, err := a.CollectComplementTypeGuards(c)
+	if err != nil || !ok {
+		return false, err
+	}
+
+	oldValues := applyTypeGuards(c)
+	defer restoreTypeGuards(oldValues)
+
+	if b, ok := t.b.(TypeGuard); ok {
+		cb := make(map[typeGuardTarget]values.Value)
+		ok, err := b.CollectComplementTypeGuards(cb)
+		if err != nil {
+			return false, err
+		}
+
+		if ok {
+			for key, val := range cb {
+				c[key] = val
+			}
+		}
+	}
+
+	return true, nil
+}
+
 func (t *LogicalOrOp) Walk(fn WalkFunc) error {
   if err := t.LogicalBinaryOp.Walk(fn); err != nil {
     return err
@@ -1409,44 +1600,131 @@ func (t *LogicalAndOp) Walk(fn WalkFunc) error {
   return fn(t)
 }
 
-func (t *LogicalAndOp) CollectTypeGuards(c map[Variable]values.Interface) (bool, error) {
-	if _, err := t.EvalExpression(); err != nil {
+// the typeguards of the lhs also apply to the rhs
+func (t *LogicalAndOp) EvalExpression() (values.Value, error) {
+	typeGuards, err := collectTypeGuards(t.a)
+	if err != nil {
+		return nil, err
+	}
+
+	a, err := t.a.EvalExpression()
+	if err != nil {
+		return nil, err
+	}
+
+	oldValues := applyTypeGuards(typeGuards)
+
+	b, err := t.b.EvalExpression()
+
+	restoreTypeGuards(oldValues)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return t.evalResult(a, b)
+}
+
+func (t *LogicalAndOp) CollectTypeGuards(c map[typeGuardTarget]values.Value) (bool, error) {
+	a, ok := t.a.(TypeGuard)
+	if !ok {
+		return false, nil
+	}
+
+	ok, err := a.CollectTypeGuards(c)
+	if err != nil || !ok {
 		return false, err
 	}
 
-	if a, ok := t.a.(TypeGuard); ok {
-		if b, ok := t.b.(TypeGuard); ok {
-			ok, err := a.CollectTypeGuards(c)
-			if err != nil {
-				return false, err
-			}
+	oldValues := applyTypeGuards(c)
+	defer restoreTypeGuards(oldValues)
 
-			if ok {
-				ok, err := b.CollectTypeGuards(c)
-				if err != nil {
-					return false, err
-				}
+	if b, ok := t.b.(TypeGuard); ok {
+		cb := make(map[typeGuardTarget]values.Value)
+		ok, err := b.CollectTypeGuards(cb)
+		if err != nil {
+			return false, err
+		}
 
-				return ok, nil
+		if ok {
+			// the rhs typeguards were collected with the lhs typeguards applied, so they are narrower
+			for key, val := range cb {
+				c[key] = val
 			}
+
+			return true, nil
 		}
 	}
 
+	bVal, err := t.b.EvalExpression()
+	if err != nil {
+		return false, err
+	}
+
+	if !prototypes.IsBoolean(bVal) {
+		errCtx := t.b.Context()
+		return false, errCtx.NewError("Error: expected Boolean, got " + bVal.TypeName())
+	}
+
+	return true, nil
+}
+
+// if a && b is false, either could be false
+func (t *LogicalAndOp) CollectComplementTypeGuards(c map[typeGuardTarget]values.Value) (bool, error) {
 	return false, nil
 }
 
+// the lhs is only used if it isn't null
+func (t *NullishOp) EvalExpression() (values.Value, error) {
+	a, b, err := t.evalArgs()
+	if err != nil {
+		return nil, err
+	}
+
+	common := values.CommonValue([]values.Value{values.RemoveNull(a), b}, t.Context())
+	return values.NewContextValue(common, t.Context()), nil
+}
+
+func (t *NullishOp) Walk(fn WalkFunc) error {
+  if err := t.BinaryOp.Walk(fn); err != nil {
+    return err
+  }
+
+  return fn(t)
+}
+
 func (t *IfElseOp) EvalExpression() (values.Value, error) {
+	typeGuards, err := collectTypeGuards(t.a)
+	if err != nil {
+		return nil, err
+	}
+
 	a, err := t.a.EvalExpression()
 	if err != nil {
 		return nil, err
 	}
 
+	oldValues := applyTypeGuards(typeGuards)
+
 	b, err := t.b.EvalExpression()
+
+	restoreTypeGuards(oldValues)
+
 	if err != nil {
 		return nil, err
 	}
 
+	complementGuards, err := collectComplementTypeGuards(t.a)
+	if err != nil {
+		return nil, err
+	}
+
+	oldValues = applyTypeGuards(complementGuards)
+
 	c, err := t.c.EvalExpression()
+
+	restoreTypeGuards(oldValues)
+
 	if err != nil {
 		return nil, err
 	}
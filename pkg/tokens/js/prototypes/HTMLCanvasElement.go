@@ -56,12 +56,15 @@ func (p *HTMLCanvasElement) GetInstanceMember(key string, includePrivate bool, c
 
     canvas := NewCanvasRenderingContext2D(ctx)
     webgl := NewWebGLRenderingContext(ctx)
+    webgl2 := NewWebGL2RenderingContext(ctx)
 
     return values.NewOverloadedFunction([][]values.Value{
       []values.Value{NewLiteralString("2d", ctx), canvas},
       []values.Value{NewLiteralString("2d", ctx), o2d, canvas},
       []values.Value{NewLiteralString("webgl", ctx), webgl},
       []values.Value{NewLiteralString("webgl", ctx), ogl, webgl},
+      []values.Value{NewLiteralString("webgl2", ctx), webgl2},
+      []values.Value{NewLiteralString("webgl2", ctx), ogl, webgl2},
       []values.Value{s, canvas},
       []values.Value{s, o2d, canvas},
     }, ctx), nil
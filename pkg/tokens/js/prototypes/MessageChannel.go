@@ -0,0 +1,44 @@
+package prototypes
+
+import (
+  "github.com/computeportal/wtsuite/pkg/tokens/js/values"
+
+  "github.com/computeportal/wtsuite/pkg/tokens/context"
+)
+
+type MessageChannel struct {
+  BuiltinPrototype
+}
+
+func NewMessageChannelPrototype() values.Prototype {
+  return &MessageChannel{newBuiltinPrototype("MessageChannel")}
+}
+
+func NewMessageChannel(ctx context.Context) values.Value {
+  return values.NewInstance(NewMessageChannelPrototype(), ctx)
+}
+
+func (p *MessageChannel) Check(other_ values.Interface, ctx context.Context) error {
+  if _, ok := other_.(*MessageChannel); ok {
+    return nil
+  } else {
+    return checkParent(p, other_, ctx)
+  }
+}
+
+func (p *MessageChannel) GetInstanceMember(key string, includePrivate bool, ctx context.Context) (values.Value, error) {
+  switch key {
+  case "port1", "port2":
+    return NewMessagePort(ctx), nil
+  default:
+    return nil, nil
+  }
+}
+
+func (p *MessageChannel) GetClassValue() (*values.Class, error) {
+  ctx := p.Context()
+
+  return values.NewClass([][]values.Value{
+    []values.Value{},
+  }, NewMessageChannelPrototype(), ctx), nil
+}
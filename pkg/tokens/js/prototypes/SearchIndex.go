@@ -37,21 +37,58 @@ func (p *SearchIndex) GetInstanceMember(key string, includePrivate bool, ctx con
     return nil, ctx.NewError("Error: only a setter")
   case "ignore":
     return values.NewFunction([]values.Value{s, b}, ctx), nil
+  // the index can be sharded, so anything that needs the index data returns a promise
   case "page":
-    return values.NewFunction([]values.Value{i, NewObject(map[string]values.Value{
+    return values.NewFunction([]values.Value{i, NewPromise(NewObject(map[string]values.Value{
       "url": s,
       "title": s,
+      "description": s,
       "content": ss,
-    }, ctx)}, ctx), nil
+      "lang": s,
+    }, ctx), ctx)}, ctx), nil
+  case "analyse":
+    // turns text into the terms that are stored in the index
+    return values.NewFunction([]values.Value{s, s, ss}, ctx), nil
+  case "search":
+    opt := NewConfigObject(map[string]values.Value{
+      "prefix": b,
+      "limit": i,
+      "lang": s,
+    }, ctx)
+
+    return values.NewOverloadedFunction([][]values.Value{
+      []values.Value{s, NewPromise(NewArray(NewSearchResult(ctx), ctx), ctx)},
+      []values.Value{s, opt, NewPromise(NewArray(NewSearchResult(ctx), ctx), ctx)},
+    }, ctx), nil
   case "match", "matchPrefix", "matchSuffix", "matchSubstring":
-    return values.NewFunction([]values.Value{s, NewSet(i, ctx)}, ctx), nil
+    return values.NewFunction([]values.Value{s, NewPromise(NewSet(i, ctx), ctx)}, ctx), nil
   case "fuzzy", "fuzzyPrefix", "fuzzySuffix", "fuzzySubstring":
-    return values.NewFunction([]values.Value{s, i, NewArray(NewSet(i, ctx), ctx)}, ctx), nil
+    return values.NewFunction([]values.Value{s, i, NewPromise(NewArray(NewSet(i, ctx), ctx), ctx)}, ctx), nil
   default:
     return nil, nil
   }
 }
 
+// result of SearchIndex.search(), sorted by descending score
+// matches contains the offsets of the matched words in the title, description or content paragraphs of page(index), for highlighting
+func NewSearchResult(ctx context.Context) values.Value {
+  i := NewInt(ctx)
+  s := NewString(ctx)
+
+  return NewObject(map[string]values.Value{
+    "index": i,
+    "url": s,
+    "title": s,
+    "score": NewNumber(ctx),
+    "matches": NewArray(NewObject(map[string]values.Value{
+      "field": s,
+      "paragraph": i,
+      "start": i,
+      "stop": i,
+    }, ctx), ctx),
+  }, ctx)
+}
+
 func (p *SearchIndex) SetInstanceMember(key string, includePrivate bool, arg values.Value, ctx context.Context) error {
   switch key {
   case "onready":
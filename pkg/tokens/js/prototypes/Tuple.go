@@ -155,3 +155,12 @@ func (p *Tuple) GetClassValue() (*values.Class, error) {
       return NewTuplePrototype(args), nil
     }, ctx), nil
 }
+
+// returns nil if v is not a Tuple with known content
+func GetTupleContent(v values.Value) []values.Value {
+  if tuple, ok := values.GetPrototype(v).(*Tuple); ok {
+    return tuple.content
+  }
+
+  return nil
+}
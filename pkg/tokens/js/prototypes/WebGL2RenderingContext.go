@@ -0,0 +1,96 @@
+package prototypes
+
+import (
+  "github.com/computeportal/wtsuite/pkg/tokens/js/values"
+
+  "github.com/computeportal/wtsuite/pkg/tokens/context"
+)
+
+// the WebGL2 api is a superset of the WebGL api, so WebGLRenderingContext is treated as the parent
+type WebGL2RenderingContext struct {
+  BuiltinPrototype
+}
+
+func NewWebGL2RenderingContextPrototype() values.Prototype {
+  return &WebGL2RenderingContext{newBuiltinPrototype("WebGL2RenderingContext")}
+}
+
+func NewWebGL2RenderingContext(ctx context.Context) values.Value {
+  return values.NewInstance(NewWebGL2RenderingContextPrototype(), ctx)
+}
+
+func (p *WebGL2RenderingContext) GetParent() (values.Prototype, error) {
+  return NewWebGLRenderingContextPrototype(), nil
+}
+
+func (p *WebGL2RenderingContext) Check(other_ values.Interface, ctx context.Context) error {
+  if _, ok := other_.(*WebGL2RenderingContext); ok {
+    return nil
+  } else {
+    return checkParent(p, other_, ctx)
+  }
+}
+
+func IsWebGL2RenderingContext(v values.Value) bool {
+  ctx := context.NewDummyContext()
+
+  checkVal := NewWebGL2RenderingContext(ctx)
+
+  return checkVal.Check(v, ctx) == nil
+}
+
+func (p *WebGL2RenderingContext) GetInstanceMember(key string, includePrivate bool, ctx context.Context) (values.Value, error) {
+  i := NewInt(ctx)
+  s := NewString(ctx)
+  enum := NewGLEnum(ctx)
+
+  switch key {
+  case "COLOR_ATTACHMENT1", "COLOR_ATTACHMENT2", "COLOR_ATTACHMENT3", "COLOR_ATTACHMENT4", "COLOR_ATTACHMENT5", "COLOR_ATTACHMENT6", "COLOR_ATTACHMENT7", "DRAW_FRAMEBUFFER", "DYNAMIC_COPY", "HALF_FLOAT", "INT", "MAX_3D_TEXTURE_SIZE", "MAX_ARRAY_TEXTURE_LAYERS", "MAX_COLOR_ATTACHMENTS", "MAX_DRAW_BUFFERS", "MAX_UNIFORM_BLOCK_SIZE", "MAX_UNIFORM_BUFFER_BINDINGS", "NONE", "R8", "R16F", "R32F", "READ_FRAMEBUFFER", "RED", "RED_INTEGER", "RG", "RG8", "RG16F", "RG32F", "RGB8", "RGBA8", "RGBA16F", "RGBA32F", "RGBA32UI", "RGBA_INTEGER", "STATIC_COPY", "STREAM_COPY", "TEXTURE_2D_ARRAY", "TEXTURE_3D", "TEXTURE_WRAP_R", "UNIFORM_BUFFER":
+    return NewNamedGLEnum(key, ctx), nil
+  case "INVALID_INDEX":
+    return i, nil
+  case "bindBufferBase":
+    return values.NewFunction([]values.Value{enum, i, NewWebGLBuffer(ctx), nil}, ctx), nil
+  case "bindVertexArray", "deleteVertexArray":
+    return values.NewFunction([]values.Value{NewWebGLVertexArrayObject(ctx), nil}, ctx), nil
+  case "createVertexArray":
+    return values.NewFunction([]values.Value{NewWebGLVertexArrayObject(ctx)}, ctx), nil
+  case "drawArraysInstanced":
+    return values.NewFunction([]values.Value{enum, i, i, i, nil}, ctx), nil
+  case "drawBuffers":
+    return values.NewFunction([]values.Value{NewArray(enum, ctx), nil}, ctx), nil
+  case "drawElementsInstanced":
+    return values.NewFunction([]values.Value{enum, i, enum, i, i, nil}, ctx), nil
+  case "getUniformBlockIndex":
+    return values.NewFunction([]values.Value{NewWebGLProgram(ctx), s, i}, ctx), nil
+  case "texImage3D":
+    return values.NewFunction([]values.Value{enum, i, enum, i, i, i, i, enum, enum, NewTypedArray(ctx), nil}, ctx), nil
+  case "texStorage2D":
+    return values.NewFunction([]values.Value{enum, i, enum, i, i, nil}, ctx), nil
+  case "texStorage3D":
+    return values.NewFunction([]values.Value{enum, i, enum, i, i, i, nil}, ctx), nil
+  case "uniform1ui":
+    return values.NewFunction([]values.Value{i, i, nil}, ctx), nil
+  case "uniform2ui":
+    return values.NewFunction([]values.Value{i, i, i, nil}, ctx), nil
+  case "uniform3ui":
+    return values.NewFunction([]values.Value{i, i, i, i, nil}, ctx), nil
+  case "uniform4ui":
+    return values.NewFunction([]values.Value{i, i, i, i, i, nil}, ctx), nil
+  case "uniform1uiv", "uniform2uiv", "uniform3uiv", "uniform4uiv":
+    return values.NewFunction([]values.Value{i, NewArray(i, ctx), nil}, ctx), nil
+  case "uniformBlockBinding":
+    return values.NewFunction([]values.Value{NewWebGLProgram(ctx), i, i, nil}, ctx), nil
+  case "vertexAttribDivisor":
+    return values.NewFunction([]values.Value{i, i, nil}, ctx), nil
+  case "vertexAttribIPointer":
+    return values.NewFunction([]values.Value{i, i, enum, i, i, nil}, ctx), nil
+  default:
+    return nil, nil
+  }
+}
+
+func (p *WebGL2RenderingContext) GetClassValue() (*values.Class, error) {
+  ctx := p.Context()
+  return values.NewUnconstructableClass(NewWebGL2RenderingContextPrototype(), ctx), nil
+}
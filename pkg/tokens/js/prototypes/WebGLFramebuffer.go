@@ -0,0 +1,32 @@
+package prototypes
+
+import (
+  "github.com/computeportal/wtsuite/pkg/tokens/js/values"
+
+  "github.com/computeportal/wtsuite/pkg/tokens/context"
+)
+
+type WebGLFramebuffer struct {
+  BuiltinPrototype
+}
+
+func NewWebGLFramebufferPrototype() values.Prototype {
+  return &WebGLFramebuffer{newBuiltinPrototype("WebGLFramebuffer")}
+}
+
+func NewWebGLFramebuffer(ctx context.Context) values.Value {
+  return values.NewInstance(NewWebGLFramebufferPrototype(), ctx)
+}
+
+func (p *WebGLFramebuffer) Check(other_ values.Interface, ctx context.Context) error {
+  if _, ok := other_.(*WebGLFramebuffer); ok {
+    return nil
+  } else {
+    return checkParent(p, other_, ctx)
+  }
+}
+
+func (p *WebGLFramebuffer) GetClassValue() (*values.Class, error) {
+  ctx := p.Context()
+  return values.NewUnconstructableClass(NewWebGLFramebufferPrototype(), ctx), nil
+}
@@ -7,17 +7,31 @@ import (
 )
 
 type WebGLProgram struct {
+  uniforms values.Value // object with a typed setter per active uniform, nil if not reflected
+  attributes values.Value // object with a location per attribute, nil if not reflected
+
   BuiltinPrototype
 }
 
 func NewWebGLProgramPrototype() values.Prototype {
-  return &WebGLProgram{newBuiltinPrototype("WebGLProgram")}
+  return &WebGLProgram{nil, nil, newBuiltinPrototype("WebGLProgram")}
 }
 
 func NewWebGLProgram(ctx context.Context) values.Value {
   return values.NewInstance(NewWebGLProgramPrototype(), ctx)
 }
 
+// returned by the WebGLProgram macro, which knows the uniforms and attributes of the shaders
+func NewReflectedWebGLProgram(uniforms map[string]values.Value, attributes map[string]values.Value, ctx context.Context) values.Value {
+  proto := &WebGLProgram{
+    NewObject(uniforms, ctx), 
+    NewObject(attributes, ctx), 
+    newBuiltinPrototype("WebGLProgram"),
+  }
+
+  return values.NewInstance(proto, ctx)
+}
+
 func (p *WebGLProgram) Check(other_ values.Interface, ctx context.Context) error {
   if _, ok := other_.(*WebGLProgram); ok {
     return nil
@@ -26,6 +40,25 @@ func (p *WebGLProgram) Check(other_ values.Interface, ctx context.Context) error
   }
 }
 
+func (p *WebGLProgram) GetInstanceMember(key string, includePrivate bool, ctx context.Context) (values.Value, error) {
+  switch key {
+  case "uniforms":
+    if p.uniforms == nil {
+      return nil, ctx.NewError("Error: uniforms only available for WebGLProgram macro results")
+    }
+
+    return values.NewContextValue(p.uniforms, ctx), nil
+  case "attributes":
+    if p.attributes == nil {
+      return nil, ctx.NewError("Error: attributes only available for WebGLProgram macro results")
+    }
+
+    return values.NewContextValue(p.attributes, ctx), nil
+  default:
+    return nil, nil
+  }
+}
+
 func (p *WebGLProgram) GetClassValue() (*values.Class, error) {
   ctx := p.Context()
   return values.NewUnconstructableClass(NewWebGLProgramPrototype(), ctx), nil
@@ -42,7 +42,7 @@ func (p *WebGLRenderingContext) GetInstanceMember(key string, includePrivate boo
   enum := NewGLEnum(ctx)
 
   switch key {
-  case "ACTIVE_ATTRIBUTES", "ACTIVE_UNIFORMS", "ALPHA", "ALWAYS", "ARRAY_BUFFER", "ATTACHED_SHADERS", "BLEND", "BLEND_COLOR", "COMPILE_STATUS", "CONTEXT_LOST_WEBGL", "CULL_FACE", "DELETE_STATUS", "DEPTH_TEST", "DITHER", "DST_ALPHA", "DST_COLOR", "DYNAMIC_DRAW", "ELEMENT_ARRAY_BUFFER", "EQUAL", "FLOAT", "FRAGMENT_SHADER", "GEQUAL", "GREATER", "INVALID_ENUM", "INVALID_VALUE", "INVALID_OPERATION", "INVALID_FRAMEBUFFER_OPERATION", "LEQUAL", "LESS", "LINES", "LINE_LOOP", "LINE_STRIP", "LINK_STATUS", "LUMINANCE", "LUMINANCE_ALPHA", "MAX_TEXTURE_IMAGE_UNITS", "MAX_COMBINED_TEXTURE_IMAGE_UNITS", "MAX_VERTEX_TEXTURE_IMAGE_UNITS", "MAX_VERTEX_UNIFORM_VECTORS", "MAX_FRAGMENT_UNIFORM_VECTORS", "NEVER", "NO_ERROR", "NOTEQUAL", "ONE", "ONE_MINUS_DST_ALPHA", "ONE_MINUS_DST_COLOR", "ONE_MINUS_SRC_ALPHA", "ONE_MINUS_SRC_COLOR", "OUT_OF_MEMORY", "POINTS", "POLYGON_OFFSET_FILL", "RGB", "RGBA", "SAMPLE_ALPHA_TO_COVERAGE", "SAMPLE_COVERAGE", "SCISSOR_TEST", "SHADER_TYPE", "SRC_ALPHA", "SRC_COLOR", "STATIC_DRAW", "STENCIL_TEST", "STREAM_DRAW", "TEXTURE_2D", "TEXTURE_MAG_FILTER", "TEXTURE_MIN_FILTER", "TEXTURE_WRAP_S", "TEXTURE_WRAP_T", "TRIANGLES", "TRIANGLE_FAN", "TRIANGLE_STRIP", "UNSIGNED_BYTE", "UNSIGNED_INT", "UNSIGNED_SHORT", "VALIDATE_STATUS", "VERTEX_SHADER", "ZERO":
+  case "ACTIVE_ATTRIBUTES", "ACTIVE_UNIFORMS", "ALPHA", "ALWAYS", "ARRAY_BUFFER", "ATTACHED_SHADERS", "BLEND", "BLEND_COLOR", "COLOR_ATTACHMENT0", "COMPILE_STATUS", "CONTEXT_LOST_WEBGL", "CULL_FACE", "DELETE_STATUS", "DEPTH_TEST", "DITHER", "DST_ALPHA", "DST_COLOR", "DYNAMIC_DRAW", "ELEMENT_ARRAY_BUFFER", "EQUAL", "FLOAT", "FRAGMENT_SHADER", "FRAMEBUFFER", "FRAMEBUFFER_COMPLETE", "GEQUAL", "GREATER", "INVALID_ENUM", "INVALID_VALUE", "INVALID_OPERATION", "INVALID_FRAMEBUFFER_OPERATION", "LEQUAL", "LESS", "LINES", "LINE_LOOP", "LINE_STRIP", "LINK_STATUS", "LUMINANCE", "LUMINANCE_ALPHA", "MAX_TEXTURE_IMAGE_UNITS", "MAX_COMBINED_TEXTURE_IMAGE_UNITS", "MAX_VERTEX_TEXTURE_IMAGE_UNITS", "MAX_VERTEX_UNIFORM_VECTORS", "MAX_FRAGMENT_UNIFORM_VECTORS", "NEVER", "NO_ERROR", "NOTEQUAL", "ONE", "ONE_MINUS_DST_ALPHA", "ONE_MINUS_DST_COLOR", "ONE_MINUS_SRC_ALPHA", "ONE_MINUS_SRC_COLOR", "OUT_OF_MEMORY", "POINTS", "POLYGON_OFFSET_FILL", "RGB", "RGBA", "SAMPLE_ALPHA_TO_COVERAGE", "SAMPLE_COVERAGE", "SCISSOR_TEST", "SHADER_TYPE", "SRC_ALPHA", "SRC_COLOR", "STATIC_DRAW", "STENCIL_TEST", "STREAM_DRAW", "TEXTURE_2D", "TEXTURE_MAG_FILTER", "TEXTURE_MIN_FILTER", "TEXTURE_WRAP_S", "TEXTURE_WRAP_T", "TRIANGLES", "TRIANGLE_FAN", "TRIANGLE_STRIP", "UNSIGNED_BYTE", "UNSIGNED_INT", "UNSIGNED_SHORT", "VALIDATE_STATUS", "VERTEX_SHADER", "ZERO":
     return NewNamedGLEnum(key, ctx), nil
   case "CLAMP_TO_BORDERS", "CLAMP_TO_EDGE", "COLOR_BUFFER_BIT", "DEPTH_BUFFER_BIT", "LINEAR", "MIRRORED_REPEAT", "NEAREST", "REPEAT", "TEXTURE0", "TEXTURE1", "TEXTURE2", "TEXTURE3", "TEXTURE4", "TEXTURE5", "TEXTURE6", "TEXTURE7", "TEXTURE8", "TEXTURE9", "TEXTURE10", "TEXTURE11", "TEXTURE12", "TEXTURE13", "TEXTURE14", "TEXTURE15":
     return i, nil
@@ -52,6 +52,8 @@ func (p *WebGLRenderingContext) GetInstanceMember(key string, includePrivate boo
     return values.NewFunction([]values.Value{NewWebGLProgram(ctx), NewWebGLShader(ctx), nil}, ctx), nil
   case "bindBuffer":
     return values.NewFunction([]values.Value{enum, NewWebGLBuffer(ctx), nil}, ctx), nil
+  case "bindFramebuffer":
+    return values.NewFunction([]values.Value{enum, NewWebGLFramebuffer(ctx), nil}, ctx), nil
   case "bindTexture":
     return values.NewFunction([]values.Value{enum, NewWebGLTexture(ctx), nil}, ctx), nil
   case "blendFunc":
@@ -60,10 +62,14 @@ func (p *WebGLRenderingContext) GetInstanceMember(key string, includePrivate boo
     return values.NewFunction([]values.Value{enum, enum, enum, enum, nil}, ctx), nil
   case "bufferData":
     return values.NewFunction([]values.Value{enum, NewTypedArray(ctx), enum, nil}, ctx), nil
+  case "checkFramebufferStatus":
+    return values.NewFunction([]values.Value{enum, enum}, ctx), nil
   case "compileShader":
     return values.NewFunction([]values.Value{NewWebGLShader(ctx), nil}, ctx), nil
   case "createBuffer":
     return values.NewFunction([]values.Value{NewWebGLBuffer(ctx)}, ctx), nil
+  case "createFramebuffer":
+    return values.NewFunction([]values.Value{NewWebGLFramebuffer(ctx)}, ctx), nil
   case "createProgram":
     return values.NewFunction([]values.Value{NewWebGLProgram(ctx)}, ctx), nil
   case "createShader":
@@ -76,6 +82,8 @@ func (p *WebGLRenderingContext) GetInstanceMember(key string, includePrivate boo
     return values.NewFunction([]values.Value{enum, i, i, nil}, ctx), nil
   case "drawElements":
     return values.NewFunction([]values.Value{enum, i, enum, i, nil}, ctx), nil
+  case "framebufferTexture2D":
+    return values.NewFunction([]values.Value{enum, enum, enum, NewWebGLTexture(ctx), i, nil}, ctx), nil
   case "getAttribLocation":
     return values.NewFunction([]values.Value{NewWebGLProgram(ctx), s, i}, ctx), nil
   case "getError":
@@ -0,0 +1,32 @@
+package prototypes
+
+import (
+  "github.com/computeportal/wtsuite/pkg/tokens/js/values"
+
+  "github.com/computeportal/wtsuite/pkg/tokens/context"
+)
+
+type WebGLVertexArrayObject struct {
+  BuiltinPrototype
+}
+
+func NewWebGLVertexArrayObjectPrototype() values.Prototype {
+  return &WebGLVertexArrayObject{newBuiltinPrototype("WebGLVertexArrayObject")}
+}
+
+func NewWebGLVertexArrayObject(ctx context.Context) values.Value {
+  return values.NewInstance(NewWebGLVertexArrayObjectPrototype(), ctx)
+}
+
+func (p *WebGLVertexArrayObject) Check(other_ values.Interface, ctx context.Context) error {
+  if _, ok := other_.(*WebGLVertexArrayObject); ok {
+    return nil
+  } else {
+    return checkParent(p, other_, ctx)
+  }
+}
+
+func (p *WebGLVertexArrayObject) GetClassValue() (*values.Class, error) {
+  ctx := p.Context()
+  return values.NewUnconstructableClass(NewWebGLVertexArrayObjectPrototype(), ctx), nil
+}
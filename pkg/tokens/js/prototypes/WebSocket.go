@@ -0,0 +1,83 @@
+package prototypes
+
+import (
+  "github.com/computeportal/wtsuite/pkg/tokens/js/values"
+
+  "github.com/computeportal/wtsuite/pkg/tokens/context"
+)
+
+type WebSocket struct {
+  BuiltinPrototype
+}
+
+func NewWebSocketPrototype() values.Prototype {
+  return &WebSocket{newBuiltinPrototype("WebSocket")}
+}
+
+func NewWebSocket(ctx context.Context) values.Value {
+  return values.NewInstance(NewWebSocketPrototype(), ctx)
+}
+
+func (p *WebSocket) GetParent() (values.Prototype, error) {
+  return NewEventTargetPrototype(), nil
+}
+
+func (p *WebSocket) Check(other_ values.Interface, ctx context.Context) error {
+  if _, ok := other_.(*WebSocket); ok {
+    return nil
+  } else {
+    return checkParent(p, other_, ctx)
+  }
+}
+
+func (p *WebSocket) GetInstanceMember(key string, includePrivate bool, ctx context.Context) (values.Value, error) {
+  i := NewInt(ctx)
+  s := NewString(ctx)
+
+  switch key {
+  case "onclose", "onerror", "onmessage", "onopen":
+    return nil, ctx.NewError("Error: is only a setter")
+  case "bufferedAmount", "readyState":
+    return i, nil
+  case "protocol", "url":
+    return s, nil
+  case "close":
+    return values.NewOverloadedFunction([][]values.Value{
+      []values.Value{nil},
+      []values.Value{i, nil},
+      []values.Value{i, s, nil},
+    }, ctx), nil
+  case "send":
+    return values.NewOverloadedFunction([][]values.Value{
+      []values.Value{s, nil},
+      []values.Value{NewArrayBuffer(ctx), nil},
+      []values.Value{NewBlob(ctx), nil},
+    }, ctx), nil
+  default:
+    return nil, nil
+  }
+}
+
+func (p *WebSocket) SetInstanceMember(key string, includePrivate bool, arg values.Value, ctx context.Context) error {
+  switch key {
+  case "onmessage":
+    callback := values.NewFunction([]values.Value{NewMessageEvent(ctx), nil}, ctx)
+    return callback.Check(arg, ctx)
+  case "onclose", "onerror", "onopen":
+    callback := values.NewFunction([]values.Value{NewEvent(NewWebSocket(ctx), ctx), nil}, ctx)
+    return callback.Check(arg, ctx)
+  default:
+    return ctx.NewError("Error: WebSocket." + key + " not setable")
+  }
+}
+
+func (p *WebSocket) GetClassValue() (*values.Class, error) {
+  ctx := p.Context()
+  s := NewString(ctx)
+
+  return values.NewClass([][]values.Value{
+    []values.Value{s},
+    []values.Value{s, s},
+    []values.Value{s, NewArray(s, ctx)},
+  }, NewWebSocketPrototype(), ctx), nil
+}
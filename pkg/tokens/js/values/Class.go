@@ -102,6 +102,8 @@ func (v *Class) Check(other_ Value, ctx context.Context) error {
 
   if IsAny(other_) {
     return nil
+  } else if other, ok := other_.(*Union); ok {
+    return other.checkAlternatives(v, ctx)
   } else if other, ok := other_.(*Class); ok {
     if err := checkAllOverloads(v.args, other.args, ctx); err != nil {
       return err
@@ -127,7 +129,8 @@ func (v *Class) EvalConstructor(args []Value, ctx context.Context) (Value, error
 
 func (v *Class) evalConstructor(args []Value, ctx context.Context, allowAbstract bool) (Value, error) {
   if args != nil {
-    if _, err := checkAnyOverload(v.args, args, ctx); err != nil {
+    i, err := checkAnyOverload(v.args, args, ctx)
+    if err != nil {
 
       for _, overload := range v.args {
         n := len(overload)
@@ -143,6 +146,8 @@ func (v *Class) evalConstructor(args []Value, ctx context.Context, allowAbstract
       return nil, err
     }
 
+    args = expandSpreadArgs(v.args[i], args, ctx)
+
     if proto, ok := v.interf.(Prototype); ok && proto.IsAbstract() && !allowAbstract {
       return nil, ctx.NewError("Error: can't construct abstract " + proto.Name())
     }
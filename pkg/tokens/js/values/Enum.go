@@ -22,6 +22,8 @@ func (v *Enum) Check(other_ Value, ctx context.Context) error {
 
   if IsAny(other_) {
     return nil
+  } else if other, ok := other_.(*Union); ok {
+    return other.checkAlternatives(v, ctx)
   } else if other, ok := other_.(*Enum); ok && other == v {
     return nil
   } 
@@ -100,6 +100,8 @@ func (v *Function) Check(other_ Value, ctx context.Context) error {
 
   if IsAny(other_) {
     return nil
+  } else if other, ok := other_.(*Union); ok {
+    return other.checkAlternatives(v, ctx)
   } else if other, ok := other_.(*Function); ok {
     if v.args != nil {
       if other.args == nil {
@@ -197,12 +199,12 @@ func (v *Function) EvalFunction(args []Value, preferMethod bool, ctx context.Con
       return nil, err
     }
   } else {
-    _, err := checkAnyOverload(v.args, args, ctx)
+    i, err := checkAnyOverload(v.args, args, ctx)
     if err != nil {
       return nil, err
     }
 
-    ret, err = v.fn(args, preferMethod, ctx)
+    ret, err = v.fn(expandSpreadArgs(v.args[i], args, ctx), preferMethod, ctx)
     if err != nil {
       return nil, err
     }
@@ -59,6 +59,8 @@ func (v *Instance) Check(other_ Value, ctx context.Context) error {
     }
 
     return nil
+  case *Union:
+    return other.checkAlternatives(v, ctx)
   default:
     if IsAny(other_) {
       return nil
@@ -144,7 +146,7 @@ func AssertInstance(v_ Value) (*Instance, error) {
 func IsInstance(v_ Value) bool {
   v_ = UnpackContextValue(v_)
 
-  switch v_.(type) {
+  switch v_ := v_.(type) {
   case *LiteralStringInstance:
     return true
   case *LiteralBooleanInstance:
@@ -154,6 +156,16 @@ func IsInstance(v_ Value) bool {
   case *Instance:
     return true
   case *Any:
+    return true
+  case *Null:
+    return true
+  case *Union:
+    for _, alt := range v_.GetAlternatives() {
+      if !IsInstance(alt) {
+        return false
+      }
+    }
+
     return true
   default:
     return false
@@ -19,6 +19,8 @@ func (v *LiteralStringInstance) Check(other_ Value, ctx context.Context) error {
 
   if IsAny(other_) {
     return nil
+  } else if other, ok := other_.(*Union); ok {
+    return other.checkAlternatives(v, ctx)
   } else if other, ok := other_.(*LiteralStringInstance); ok && v.value == other.value {
     return nil
   } 
@@ -0,0 +1,58 @@
+package values
+
+import (
+	"github.com/computeportal/wtsuite/pkg/tokens/context"
+)
+
+// only used with strict null checking
+type Null struct {
+	ValueData
+}
+
+// without strict null checking null is compatible with everything
+func NewNull(ctx context.Context) Value {
+  if !STRICT_NULL {
+    return NewAll(ctx)
+  }
+
+	return &Null{ValueData{ctx}}
+}
+
+func (v *Null) TypeName() string {
+  return "null"
+}
+
+func (v *Null) Check(other_ Value, ctx context.Context) error {
+  other_ = UnpackContextValue(other_)
+
+  if IsAny(other_) || IsNull(other_) {
+    return nil
+  } else if other, ok := other_.(*Union); ok {
+    return other.checkAlternatives(v, ctx)
+  } else {
+    return ctx.NewError("Error: have " + other_.TypeName() + ", want null")
+  }
+}
+
+func (v *Null) EvalConstructor(args []Value, ctx context.Context) (Value, error) {
+  return nil, ctx.NewError("Error: null is not a constructor")
+}
+
+func (v *Null) EvalFunction(args []Value, preferMethod bool, ctx context.Context) (Value, error) {
+  return nil, ctx.NewError("Error: null is not a function")
+}
+
+func (v *Null) GetMember(key string, includePrivate bool, ctx context.Context) (Value, error) {
+  return nil, ctx.NewError("Error: can't get member " + key + " of null")
+}
+
+func (v *Null) SetMember(key string, includePrivate bool, arg Value, ctx context.Context) error {
+  return ctx.NewError("Error: can't set member " + key + " of null")
+}
+
+func IsNull(v_ Value) bool {
+  v_ = UnpackContextValue(v_)
+
+  _, ok := v_.(*Null)
+  return ok
+}
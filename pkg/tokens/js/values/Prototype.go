@@ -74,3 +74,18 @@ func PrototypeIsAncestorOf(parent Prototype, child Prototype) bool {
 
   return false
 }
+
+// like GetPrototype, but also looks through This and Class values
+// the second return value is true if v_ is a Class (i.e. static members are accessed)
+func GetMemberPrototype(v_ Value) (Prototype, bool) {
+  v_ = UnpackContextValue(v_)
+
+  switch v := v_.(type) {
+  case *This:
+    return GetPrototype(v.this), false
+  case *Class:
+    return v.getPrototype(), true
+  default:
+    return GetPrototype(v), false
+  }
+}
@@ -0,0 +1,67 @@
+package values
+
+import (
+	"github.com/computeportal/wtsuite/pkg/tokens/context"
+)
+
+// spread call argument of unknown length (eg. fn(...xs) with xs an Array)
+// only valid as an argument during overload checking
+type Spread struct {
+  content Value
+
+	ValueData
+}
+
+func NewSpread(content Value, ctx context.Context) Value {
+  return &Spread{content, ValueData{ctx}}
+}
+
+func (v *Spread) TypeName() string {
+  return "..." + v.content.TypeName()
+}
+
+func (v *Spread) Check(other Value, ctx context.Context) error {
+  return ctx.NewError("Error: unexpected spread")
+}
+
+func (v *Spread) EvalConstructor(args []Value, ctx context.Context) (Value, error) {
+  return nil, ctx.NewError("Error: spread is not a constructor")
+}
+
+func (v *Spread) EvalFunction(args []Value, preferMethod bool, ctx context.Context) (Value, error) {
+  return nil, ctx.NewError("Error: spread is not a function")
+}
+
+func (v *Spread) GetMember(key string, includePrivate bool, ctx context.Context) (Value, error) {
+  return nil, ctx.NewError("Error: can't get member " + key + " of spread")
+}
+
+func (v *Spread) SetMember(key string, includePrivate bool, arg Value, ctx context.Context) error {
+  return ctx.NewError("Error: can't set member " + key + " of spread")
+}
+
+func IsSpread(v_ Value) bool {
+  v_ = UnpackContextValue(v_)
+
+  _, ok := v_.(*Spread)
+  return ok
+}
+
+// returns v_ itself if it isn't a spread
+func UnpackSpread(v_ Value) Value {
+  if v, ok := UnpackContextValue(v_).(*Spread); ok {
+    return NewContextValue(v.content, v.Context())
+  }
+
+  return v_
+}
+
+func hasSpread(ts []Value) bool {
+  for _, t := range ts {
+    if t != nil && IsSpread(t) {
+      return true
+    }
+  }
+
+  return false
+}
@@ -0,0 +1,246 @@
+package values
+
+import (
+  "strings"
+
+	"github.com/computeportal/wtsuite/pkg/tokens/context"
+)
+
+// A | B | ..., a nullable type is a union with null
+type Union struct {
+  alts []Value // at least 2, never nested
+
+	ValueData
+}
+
+// nested unions are flattened, and alternatives that are covered by other alternatives are dropped
+// (returns a regular value if only one alternative remains)
+func NewUnion(alts_ []Value, ctx context.Context) Value {
+  flat := make([]Value, 0)
+
+  for _, alt_ := range alts_ {
+    alt := UnpackContextValue(alt_)
+
+    if IsAny(alt) {
+      return NewAny(ctx)
+    }
+
+    if u, ok := alt.(*Union); ok {
+      flat = append(flat, u.alts...)
+    } else {
+      flat = append(flat, RemoveLiteralness(alt))
+    }
+  }
+
+  alts := make([]Value, 0)
+
+Outer:
+  for _, alt := range flat {
+    for i, prev := range alts {
+      if prev.Check(alt, ctx) == nil {
+        continue Outer
+      } else if alt.Check(prev, ctx) == nil {
+        alts[i] = alt
+        continue Outer
+      }
+    }
+
+    alts = append(alts, alt)
+  }
+
+  if len(alts) == 0 {
+    return NewNull(ctx)
+  } else if len(alts) == 1 {
+    return NewContextValue(alts[0], ctx)
+  } else {
+    return &Union{alts, ValueData{ctx}}
+  }
+}
+
+func (v *Union) TypeName() string {
+  var b strings.Builder
+
+  for i, alt := range v.alts {
+    if i > 0 {
+      b.WriteString("|")
+    }
+
+    b.WriteString(alt.TypeName())
+  }
+
+  return b.String()
+}
+
+func (v *Union) GetAlternatives() []Value {
+  return v.alts
+}
+
+func (v *Union) IsNullable() bool {
+  for _, alt := range v.alts {
+    if IsNull(alt) {
+      return true
+    }
+  }
+
+  return false
+}
+
+func (v *Union) nonNullAlternatives() []Value {
+  res := make([]Value, 0)
+
+  for _, alt := range v.alts {
+    if !IsNull(alt) {
+      res = append(res, alt)
+    }
+  }
+
+  return res
+}
+
+// each alternative of v must be accepted by want
+func (v *Union) checkAlternatives(want Value, ctx context.Context) error {
+  for _, alt := range v.alts {
+    if err := want.Check(alt, ctx); err != nil {
+      return err
+    }
+  }
+
+  return nil
+}
+
+func (v *Union) Check(other_ Value, ctx context.Context) error {
+  other_ = UnpackContextValue(other_)
+
+  if IsAny(other_) {
+    return nil
+  } else if other, ok := other_.(*Union); ok {
+    return other.checkAlternatives(v, ctx)
+  }
+
+  for _, alt := range v.alts {
+    if err := alt.Check(other_, ctx); err == nil {
+      return nil
+    }
+  }
+
+  return ctx.NewError("Error: have " + other_.TypeName() + ", want " + v.TypeName())
+}
+
+func (v *Union) assertNotNull(ctx context.Context) error {
+  if v.IsNullable() {
+    return ctx.NewError("Error: " + v.TypeName() + " is possibly null (hint: check != null first)")
+  }
+
+  return nil
+}
+
+func (v *Union) EvalConstructor(args []Value, ctx context.Context) (Value, error) {
+  return nil, ctx.NewError("Error: can't construct " + v.TypeName())
+}
+
+func (v *Union) EvalFunction(args []Value, preferMethod bool, ctx context.Context) (Value, error) {
+  if err := v.assertNotNull(ctx); err != nil {
+    return nil, err
+  }
+
+  alts := v.nonNullAlternatives()
+  if len(alts) != 1 {
+    return nil, ctx.NewError("Error: can't call " + v.TypeName())
+  }
+
+  return alts[0].EvalFunction(args, preferMethod, ctx)
+}
+
+func (v *Union) GetMember(key string, includePrivate bool, ctx context.Context) (Value, error) {
+  if err := v.assertNotNull(ctx); err != nil {
+    return nil, err
+  }
+
+  members := make([]Value, 0)
+  for _, alt := range v.alts {
+    member, err := alt.GetMember(key, includePrivate, ctx)
+    if err != nil {
+      return nil, err
+    }
+
+    members = append(members, member)
+  }
+
+  return NewUnion(members, ctx), nil
+}
+
+func (v *Union) SetMember(key string, includePrivate bool, arg Value, ctx context.Context) error {
+  if err := v.assertNotNull(ctx); err != nil {
+    return err
+  }
+
+  for _, alt := range v.alts {
+    if err := alt.SetMember(key, includePrivate, arg, ctx); err != nil {
+      return err
+    }
+  }
+
+  return nil
+}
+
+func IsUnion(v_ Value) bool {
+  v_ = UnpackContextValue(v_)
+
+  _, ok := v_.(*Union)
+  return ok
+}
+
+// the nullable alternative is removed (eg. after a != null check)
+func RemoveNull(v_ Value) Value {
+  if u, ok := UnpackContextValue(v_).(*Union); ok {
+    return NewUnion(u.nonNullAlternatives(), v_.Context())
+  }
+
+  return v_
+}
+
+// eg. the result of an optional chain (a?.b), without strict null checking v_ is returned as is
+func AddNull(v_ Value, ctx context.Context) Value {
+  if !STRICT_NULL {
+    return v_
+  }
+
+  return NewUnion([]Value{v_, NewNull(ctx)}, ctx)
+}
+
+// returns the alternatives of v_ that don't satisfy target, or v_ itself if none or all do
+// (eg. in the else branch of an instanceof check)
+func Exclude(v_ Value, target Value, ctx context.Context) Value {
+  if u, ok := UnpackContextValue(v_).(*Union); ok {
+    alts := make([]Value, 0)
+    for _, alt := range u.alts {
+      if target.Check(alt, ctx) != nil {
+        alts = append(alts, alt)
+      }
+    }
+
+    if len(alts) > 0 && len(alts) < len(u.alts) {
+      return NewUnion(alts, ctx)
+    }
+  }
+
+  return v_
+}
+
+// returns the alternatives of v_ that satisfy target, or target itself if none do
+func Narrow(v_ Value, target Value, ctx context.Context) Value {
+  if u, ok := UnpackContextValue(v_).(*Union); ok {
+    alts := make([]Value, 0)
+    for _, alt := range u.alts {
+      if target.Check(alt, ctx) == nil {
+        alts = append(alts, alt)
+      }
+    }
+
+    if len(alts) > 0 {
+      return NewUnion(alts, ctx)
+    }
+  }
+
+  return target
+}
@@ -4,7 +4,12 @@ import (
 	"github.com/computeportal/wtsuite/pkg/tokens/context"
 )
 
-var VERBOSITY = 0
+var (
+  VERBOSITY = 0
+
+  // null isn't compatible with other types, and nullable values must be checked before use
+  STRICT_NULL = false
+)
 
 type Value interface {
 	Context() context.Context
@@ -45,7 +50,30 @@ func (v *ValueData) LiteralStringValue() (string, bool) {
 }
 
 // TODO: use parent prototypes too
-func CommonValue(vs []Value, ctx context.Context) Value {
+func CommonValue(vs_ []Value, ctx context.Context) Value {
+  // with strict null checking a null makes the common value nullable
+  vs := make([]Value, 0, len(vs_))
+  hasNull := false
+  for _, v := range vs_ {
+    if IsNull(v) {
+      hasNull = true
+    } else {
+      vs = append(vs, v)
+    }
+  }
+
+  if hasNull {
+    if len(vs) == 0 {
+      return NewNull(ctx)
+    }
+
+    return NewUnion([]Value{commonValue(vs, ctx), NewNull(ctx)}, ctx)
+  }
+
+  return commonValue(vs, ctx)
+}
+
+func commonValue(vs []Value, ctx context.Context) Value {
   if len(vs) == 0 {
     return NewAny(ctx)
   } else if len(vs) == 1 {
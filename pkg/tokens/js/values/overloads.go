@@ -7,6 +7,11 @@ import (
 )
 
 func checkOverload(overload []Value, ts []Value, ctx context.Context) error {
+  if hasSpread(ts) {
+    _, err := expandSpread(overload, ts, ctx)
+    return err
+  }
+
   if len(overload) == len(ts) {
     for j, arg := range overload {
       if j == len(overload) -1 && arg == nil && ts[j] != nil {
@@ -30,6 +35,18 @@ func checkOverload(overload []Value, ts []Value, ctx context.Context) error {
 }
 
 func checkAnyOverload(overloads [][]Value, ts []Value, ctx context.Context) (int, error) {
+  if hasSpread(ts) {
+    for i, overload := range overloads {
+      if _, err := expandSpread(overload, ts, ctx); err == nil {
+        return i, nil
+      } else if len(overloads) == 1 {
+        return 0, err
+      }
+    }
+
+    return 0, ctx.NewError("Error: incompatible function interface")
+  }
+
   for i, overload := range overloads {
     if len(overload) == len(ts) {
       ok := true
@@ -121,3 +138,75 @@ func checkAllOverloads(overloads [][]Value, tss [][]Value, ctx context.Context)
   return nil
 }
 
+
+// a spread of unknown length can fill any number of the remaining arguments (including none)
+// the spread is replaced by its content for each argument it fills
+func expandSpread(overload []Value, ts []Value, ctx context.Context) ([]Value, error) {
+  if len(ts) == 0 {
+    if len(overload) != 0 {
+      return nil, ctx.NewError(fmt.Sprintf("Error: expected %d more arguments", len(overload)))
+    }
+
+    return []Value{}, nil
+  }
+
+  if IsSpread(ts[0]) {
+    content := UnpackSpread(ts[0])
+
+    var lastErr error = nil
+    for k := 0; k <= len(overload); k++ {
+      if k > 0 {
+        arg := overload[k-1]
+        if arg == nil {
+          break
+        } else if err := arg.Check(content, content.Context()); err != nil {
+          lastErr = err
+          break
+        }
+      }
+
+      rest, err := expandSpread(overload[k:], ts[1:], ctx)
+      if err == nil {
+        res := make([]Value, k, k + len(rest))
+        for i := 0; i < k; i++ {
+          res[i] = content
+        }
+
+        return append(res, rest...), nil
+      }
+
+      lastErr = err
+    }
+
+    return nil, lastErr
+  }
+
+  if len(overload) == 0 {
+    return nil, ctx.NewError("Error: too many arguments")
+  } else if overload[0] == nil {
+    return nil, ctx.NewError("Error: expected void return value")
+  } else if err := overload[0].Check(ts[0], ts[0].Context()); err != nil {
+    return nil, err
+  }
+
+  rest, err := expandSpread(overload[1:], ts[1:], ctx)
+  if err != nil {
+    return nil, err
+  }
+
+  return append([]Value{ts[0]}, rest...), nil
+}
+
+// custom functions receive the expanded arguments
+func expandSpreadArgs(overload []Value, ts []Value, ctx context.Context) []Value {
+  if !hasSpread(ts) {
+    return ts
+  }
+
+  args, err := expandSpread(overload, ts, ctx)
+  if err != nil {
+    panic("should've been checked before")
+  }
+
+  return args
+}
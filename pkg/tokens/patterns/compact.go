@@ -2,6 +2,7 @@ package patterns
 
 var (
   COMPACT_NAMING = false
+  MANGLE_ALL_MEMBERS = false // only has effect if COMPACT_NAMING is also true
   NL = "\n"
   TAB = "  "
   LAST_SEMICOLON = ";"
@@ -10,11 +10,12 @@ import (
 // doesn't need to implement Tag interface
 // TODO: maybe it is more convenient if it DOES implement the Tag interface
 type Root struct {
+	componentStyles []string // css of the templates used in this document, each scoped by a unique class
 	tagData
 }
 
 func NewRoot(ctx context.Context) *Root {
-	return &Root{tagData{"", "", false, nil, nil, make([]Tag, 0), ctx}}
+	return &Root{make([]string, 0), tagData{"", "", false, nil, nil, make([]Tag, 0), ctx}}
 }
 
 func (t *Root) GetDocTypeAndHTML() (*DocType, *HTML, error) {
@@ -145,6 +146,15 @@ func (t *Root) IncludeStyle(styles string) error {
 	return html.IncludeStyle(styles)
 }
 
+func (t *Root) AddComponentStyle(css string) {
+	t.componentStyles = append(t.componentStyles, css)
+}
+
+// must be written to the bundle by the caller, or included with t.IncludeStyle()
+func (t *Root) ComponentStyles() []string {
+	return t.componentStyles
+}
+
 func (t *Root) IncludeControl(code string) error {
 	_, html, err := t.GetDocTypeAndHTML()
 	if err != nil {
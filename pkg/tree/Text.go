@@ -7,16 +7,22 @@ import (
 )
 
 type Text struct {
-	value string
+	value    string
+	verbatim bool // leading and trailing newlines are kept
 	LeafTag
 }
 
 func NewTextValue(value string, ctx context.Context) Text {
-	return Text{value, NewLeafTag(ctx)}
+	return Text{value, false, NewLeafTag(ctx)}
 }
 
 func NewText(value string, ctx context.Context) *Text {
-	return &Text{value, NewLeafTag(ctx)}
+	return &Text{value, false, NewLeafTag(ctx)}
+}
+
+// eg. for highlighted code inside pre tags
+func NewVerbatimText(value string, ctx context.Context) *Text {
+	return &Text{value, true, NewLeafTag(ctx)}
 }
 
 func (t *Text) Value() string {
@@ -24,6 +30,10 @@ func (t *Text) Value() string {
 }
 
 func (t *Text) Write(indent string, nl, tab string) string {
+	if t.verbatim {
+		return t.value
+	}
+
 	return strings.Trim(t.value, "\n")
 }
 
@@ -87,7 +87,7 @@ var table = map[string]TagBuilder{
 	"p":        gbInline,
   "param":    gb,
   "picture":  gb,
-  "pre":      gb,
+  "pre":      gbInline,
   "progress": gb,
   "q":        gbInline,
   "rp":       gb,
@@ -0,0 +1,54 @@
+package scripts
+
+import (
+	"github.com/computeportal/wtsuite/pkg/tokens/js"
+	"github.com/computeportal/wtsuite/pkg/tokens/js/macros"
+)
+
+// Builds a separate bundle, with target worker, for each worker entry module requested by 'new WorkerRPC(...)'
+// in the previously finalized bundle.
+// The returned map contains the content of each worker bundle, with macros.WorkerBundleName() as key.
+func BuildWorkerBundles(cmdDefines map[string]string) (map[string]string, error) {
+	result := make(map[string]string)
+
+	entryPoints := macros.WorkerEntryPoints()
+	if len(entryPoints) == 0 {
+		return result, nil
+	}
+
+	prevTarget := js.TARGET
+	js.TARGET = "worker"
+	defer func() {
+		js.TARGET = prevTarget
+	}()
+
+	for _, absPath := range entryPoints {
+		macros.StartWorkerBundle(absPath)
+
+		entryScript, err := NewInitFileScript(absPath)
+		if err != nil {
+			return nil, err
+		}
+
+		bundle := NewFileBundle(cmdDefines)
+
+		bundle.Append(entryScript)
+
+		if err := bundle.Finalize(); err != nil {
+			return nil, err
+		}
+
+		if err := macros.FinishWorkerBundle(); err != nil {
+			return nil, err
+		}
+
+		content, err := bundle.Write()
+		if err != nil {
+			return nil, err
+		}
+
+		result[macros.WorkerBundleName(absPath)] = content
+	}
+
+	return result, nil
+}
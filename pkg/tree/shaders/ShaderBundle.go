@@ -11,6 +11,7 @@ import (
 	"github.com/computeportal/wtsuite/pkg/tokens/context"
 	"github.com/computeportal/wtsuite/pkg/tokens/glsl"
 	"github.com/computeportal/wtsuite/pkg/tokens/js"
+	"github.com/computeportal/wtsuite/pkg/tokens/js/macros"
 	"github.com/computeportal/wtsuite/pkg/tokens/js/prototypes"
 	jsv "github.com/computeportal/wtsuite/pkg/tokens/js/values"
 	"github.com/computeportal/wtsuite/pkg/tokens/patterns"
@@ -20,10 +21,16 @@ type ShaderBundle struct {
   usage glsl.Usage
   version *glsl.Word // eg. "100 es"
   shaders []ShaderFile
+
+  // collected during Finalize, so that a vertex and fragment bundle can be checked together
+  target string
+  varyings map[string]string
+  writtenVaryings map[string]bool
+  varyingContexts map[string]context.Context
 }
 
 func NewShaderBundle() *ShaderBundle {
-  return &ShaderBundle{nil, nil, make([]ShaderFile, 0)}
+  return &ShaderBundle{nil, nil, make([]ShaderFile, 0), "", nil, nil, nil}
 }
 
 func (b *ShaderBundle) Append(s ShaderFile) {
@@ -283,6 +290,180 @@ func (b *ShaderBundle) CollectVersion() error {
     }
   }
 
+  // the global scope depends on the version
+  if b.version != nil {
+    glsl.VERSION = b.version.Value()
+  } else {
+    glsl.VERSION = "100 es"
+  }
+
+  return nil
+}
+
+// only returns a non-empty string if the bundle actually declares a version
+func (b *ShaderBundle) Version() string {
+  if b.version == nil {
+    return ""
+  }
+
+  return b.version.Value()
+}
+
+func (b *ShaderBundle) CheckLocations() error {
+  ins := glsl.NewLayoutLocations()
+  outs := glsl.NewLayoutLocations()
+
+  for _, s := range b.shaders {
+    if err := s.CollectLocations(ins, outs); err != nil {
+      return err
+    }
+  }
+
+  return outs.CheckRenderTargets()
+}
+
+// context of the main function of the entry shader, available after ResolveNames
+func (b *ShaderBundle) EntryContext() context.Context {
+  for _, s := range b.shaders {
+    if init, ok := s.(*InitShaderFile); ok && init.main != nil {
+      return init.main.Context()
+    }
+  }
+
+  return b.shaders[len(b.shaders) - 1].Module().Context()
+}
+
+// fragment shaders don't have a default float precision
+func (b *ShaderBundle) CheckPrecision() error {
+  if glsl.TARGET != "fragment" {
+    return nil
+  }
+
+  for _, s := range b.shaders {
+    if s.HasDefaultPrecision("float") {
+      return nil
+    }
+  }
+
+  errCtx := b.EntryContext()
+  return errCtx.NewError("Error: fragment shader doesn't declare a float precision (hint: precision mediump float;)")
+}
+
+// the minimum guaranteed limits (eg. gl_MaxFragmentUniformVectors) are used, so that the shader works everywhere
+func (b *ShaderBundle) CheckLimits() error {
+  limits := glsl.MinResourceLimits()
+
+  var uniforms *glsl.VectorPacking
+  if glsl.TARGET == "vertex" {
+    uniforms = glsl.NewVectorPacking("gl_MaxVertexUniformVectors", limits.VertexUniformVectors, true)
+  } else {
+    uniforms = glsl.NewVectorPacking("gl_MaxFragmentUniformVectors", limits.FragmentUniformVectors, true)
+  }
+
+  varyings := glsl.NewVectorPacking("gl_MaxVaryingVectors", limits.VaryingVectors, true)
+  attributes := glsl.NewVectorPacking("gl_MaxVertexAttribs", limits.VertexAttribs, false)
+
+  for _, s := range b.shaders {
+    if err := s.PackUniforms(uniforms); err != nil {
+      return err
+    }
+
+    if err := s.PackVaryings(varyings); err != nil {
+      return err
+    }
+
+    if err := s.PackAttributes(attributes); err != nil {
+      return err
+    }
+  }
+
+  return nil
+}
+
+func (b *ShaderBundle) collectInterface() error {
+  b.target = glsl.TARGET
+  b.varyings = make(map[string]string)
+  b.writtenVaryings = make(map[string]bool)
+  b.varyingContexts = make(map[string]context.Context)
+
+  if err := b.CollectVaryings(b.varyings); err != nil {
+    return err
+  }
+
+  for _, s := range b.shaders {
+    s.CollectVaryingContexts(b.varyingContexts)
+  }
+
+  if b.target == "vertex" {
+    for _, s := range b.shaders {
+      if err := s.CollectWrittenVaryings(b.writtenVaryings); err != nil {
+        return err
+      }
+    }
+  }
+
+  return nil
+}
+
+// falls back to errCtx if the declaration isn't known
+func (b *ShaderBundle) varyingContext(name string, errCtx context.Context) context.Context {
+  if ctx, ok := b.varyingContexts[name]; ok {
+    return ctx
+  }
+
+  return errCtx
+}
+
+// both bundles must be finalized
+func CheckShaderPair(vertex *ShaderBundle, fragment *ShaderBundle, errCtx context.Context) error {
+  if vertex.target != "vertex" || fragment.target != "fragment" {
+    panic("expected a vertex and a fragment bundle")
+  }
+
+  if vertex.Version() != fragment.Version() {
+    return errCtx.NewError("Error: vertex and fragment shader versions differ")
+  }
+
+  vertexVaryings := vertex.varyings
+  fragmentVaryings := fragment.varyings
+
+  for k, typeName := range vertexVaryings {
+    fragTypeName, ok := fragmentVaryings[k] 
+    if !ok {
+      varCtx := vertex.varyingContext(k, errCtx)
+      return varCtx.NewError("Error: varying " + k + " not found in fragment shader")
+    }
+
+    if fragTypeName != typeName {
+      varCtx := fragment.varyingContext(k, errCtx)
+      return varCtx.NewError("Error: varying " + k + " has different type in fragment shader")
+    }
+  }
+
+  for k, typeName := range fragmentVaryings {
+    vertexTypeName, ok := vertexVaryings[k] 
+    if !ok {
+      varCtx := fragment.varyingContext(k, errCtx)
+      return varCtx.NewError("Error: varying " + k + " not found in vertex shader")
+    }
+
+    if vertexTypeName != typeName {
+      varCtx := vertex.varyingContext(k, errCtx)
+      return varCtx.NewError("Error: varying " + k + " has different type in vertex shader")
+    }
+
+    // unused fragment varyings are already errors, so they are all used
+    // the declaration in the vertex shader is where the missing write belongs
+    if !vertex.writtenVaryings[k] {
+      varCtx := vertex.varyingContext(k, errCtx)
+      return varCtx.NewError("Error: varying " + k + " is used by fragment shader, but never written by vertex shader")
+    }
+  }
+
+  if len(vertexVaryings) != len(fragmentVaryings) {
+    return errCtx.NewError("Error: varyings differ")
+  }
+
   return nil
 }
 
@@ -291,6 +472,10 @@ func (b *ShaderBundle) Finalize() error {
     return err
   }
 
+  if err := b.CollectVersion(); err != nil {
+    return err
+  }
+
   if err := b.ResolveNames(); err != nil {
     return err
   }
@@ -299,15 +484,27 @@ func (b *ShaderBundle) Finalize() error {
     return err
   }
 
-  if err := b.ResolveActivity(); err != nil {
+  if err := b.CheckLocations(); err != nil {
     return err
   }
 
-  if err := b.UniqueNames(); err != nil {
+  if err := b.CheckPrecision(); err != nil {
     return err
   }
 
-  if err := b.CollectVersion(); err != nil {
+  if err := b.CheckLimits(); err != nil {
+    return err
+  }
+
+  if err := b.collectInterface(); err != nil {
+    return err
+  }
+
+  if err := b.ResolveActivity(); err != nil {
+    return err
+  }
+
+  if err := b.UniqueNames(); err != nil {
     return err
   }
 
@@ -324,6 +521,26 @@ func (b *ShaderBundle) CollectVaryings(varyings map[string]string) error {
   return nil
 }
 
+func (b *ShaderBundle) CollectUniforms(uniforms map[string]string) error {
+  for _, s := range b.shaders {
+    if err := s.CollectUniforms(uniforms); err != nil {
+      return err
+    }
+  }
+
+  return nil
+}
+
+func (b *ShaderBundle) CollectAttributes(attributes map[string]string) error {
+  for _, s := range b.shaders {
+    if err := s.CollectAttributes(attributes); err != nil {
+      return err
+    }
+  }
+
+  return nil
+}
+
 func (b *ShaderBundle) FindExportedConst(name string) *glsl.Const {
   for _, s := range b.shaders {
     if cSt := s.FindExportedConst(name); cSt != nil {
@@ -377,43 +594,59 @@ func (b *ShaderBundle) InjectConsts(rtName string, consts map[string]jsv.Value,
   return nil
 }
 
-// second return value is the map of all the varying types
-func transpileWebGLShader(callerPath string, shaderPath_ *js.Word, rtName string, consts map[string]jsv.Value) (string, map[string]string, error) {
+// uniforms and attributes map names to glsl type names
+type transpiledShader struct {
+  source string
+  bundle *ShaderBundle
+  uniforms map[string]string
+  attributes map[string]string
+}
+
+func transpileWebGLShader(callerPath string, shaderPath_ *js.Word, rtName string, consts map[string]jsv.Value) (*transpiledShader, error) {
   errCtx := shaderPath_.Context()
 
   shaderPath, err := files.Search(callerPath, shaderPath_.Value())
   if err != nil {
-    return "", nil, errCtx.NewError("Error: shader file \"" + shaderPath_.Value() + "\" not found")
+    return nil, errCtx.NewError("Error: shader file \"" + shaderPath_.Value() + "\" not found")
   }
 
   bundle := NewShaderBundle()
 
   entryShader, err := NewInitShaderFile(shaderPath)
   if err != nil {
-    return "", nil, errCtx.NewError("Error: problem reading shader file \"" + shaderPath_.Value() + "\" (" + err.Error() + ")")
+    return nil, errCtx.NewError("Error: problem reading shader file \"" + shaderPath_.Value() + "\" (" + err.Error() + ")")
   }
 
   bundle.Append(entryShader)
 
   if err := bundle.Finalize(); err != nil {
-    return "", nil, err
+    return nil, err
   }
 
   if len(consts) > 0 {
     if err := bundle.InjectConsts(rtName, consts, errCtx); err != nil {
-      return "", nil, err
+      return nil, err
     }
   }
 
-  varyings := make(map[string]string)
+  res := &transpiledShader{
+    "", 
+    bundle,
+    make(map[string]string), 
+    make(map[string]string),
+  }
+
+  if err := bundle.CollectUniforms(res.uniforms); err != nil {
+    return nil, err
+  }
 
-  if err := bundle.CollectVaryings(varyings); err != nil {
-    return "", nil, err
+  if err := bundle.CollectAttributes(res.attributes); err != nil {
+    return nil, err
   }
 
   shaderSource, err := bundle.Write(patterns.NL, patterns.TAB)
   if err != nil {
-    return "", nil, err
+    return nil, err
   }
 
   var b strings.Builder
@@ -421,51 +654,47 @@ func transpileWebGLShader(callerPath string, shaderPath_ *js.Word, rtName string
   b.WriteString(shaderSource)
   b.WriteString("`")
 
-  return b.String(), varyings, nil
+  res.source = b.String()
+
+  return res, nil
 }
 
 func TranspileWebGLShaders(callerPath string, vertexPath *js.Word, vertexConsts map[string]jsv.Value,
-  fragmentPath *js.Word, fragmentConsts map[string]jsv.Value) (string, string, error) {
+  fragmentPath *js.Word, fragmentConsts map[string]jsv.Value) (*macros.WebGLShaders, error) {
 
   glsl.TARGET = "vertex"
-  vertexSource, vertexVaryings, err := transpileWebGLShader(callerPath, vertexPath, "v", vertexConsts)
+  vertex, err := transpileWebGLShader(callerPath, vertexPath, "v", vertexConsts)
   if err != nil {
-    return "", "", err
+    return nil, err
   }
 
   glsl.TARGET = "fragment"
-  fragmentSource, fragmentVaryings, err := transpileWebGLShader(callerPath, fragmentPath, "f", fragmentConsts)
+  fragment, err := transpileWebGLShader(callerPath, fragmentPath, "f", fragmentConsts)
   if err != nil {
-    return "", "", err
+    return nil, err
   }
 
   errCtx := context.MergeContexts(vertexPath.Context(), fragmentPath.Context())
 
-  for k, typeName := range vertexVaryings {
-    fragTypeName, ok := fragmentVaryings[k] 
-    if !ok {
-      return "", "", errCtx.NewError("Error: varying " + k + " not found in fragment shader")
-    }
-
-    if fragTypeName != typeName {
-      return "", "", errCtx.NewError("Error: varying " + k + " has different type in in fragment shader")
-    }
+  if err := CheckShaderPair(vertex.bundle, fragment.bundle, errCtx); err != nil {
+    return nil, err
   }
 
-  for k, typeName := range fragmentVaryings {
-    vertexTypeName, ok := vertexVaryings[k] 
-    if !ok {
-      return "", "", errCtx.NewError("Error: varying " + k + " not found in vertex shader")
+  // uniforms are shared by both shaders of the program
+  uniforms := vertex.uniforms
+  for k, typeName := range fragment.uniforms {
+    if vertexTypeName, ok := uniforms[k]; ok && vertexTypeName != typeName {
+      return nil, errCtx.NewError("Error: uniform " + k + " has different type in vertex (" + vertexTypeName + ") and fragment (" + typeName + ") shader")
     }
 
-    if vertexTypeName != typeName {
-      return "", "", errCtx.NewError("Error: varying " + k + " has different type in in vertex shader")
-    }
-  }
-
-  if len(vertexVaryings) != len(fragmentVaryings) {
-    return "", "", errCtx.NewError("Error: varyings differ")
+    uniforms[k] = typeName
   }
 
-  return vertexSource, fragmentSource, nil
+  return &macros.WebGLShaders{
+    VertexSource: vertex.source,
+    FragmentSource: fragment.source,
+    Version: vertex.bundle.Version(),
+    Uniforms: uniforms,
+    Attributes: vertex.attributes,
+  }, nil
 }
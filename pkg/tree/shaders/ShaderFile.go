@@ -3,6 +3,7 @@ package shaders
 import (
 	"github.com/computeportal/wtsuite/pkg/files"
 	"github.com/computeportal/wtsuite/pkg/parsers"
+	"github.com/computeportal/wtsuite/pkg/tokens/context"
 	"github.com/computeportal/wtsuite/pkg/tokens/glsl"
 )
 
@@ -21,6 +22,15 @@ type ShaderFile interface {
   UniqueNames(ns glsl.Namespace) error
   CollectVersion(version *glsl.Word) (*glsl.Word, error)
   CollectVaryings(varyings map[string]string) error
+  CollectUniforms(uniforms map[string]string) error
+  CollectAttributes(attributes map[string]string) error
+  CollectWrittenVaryings(written map[string]bool) error
+  CollectVaryingContexts(ctxs map[string]context.Context)
+  CollectLocations(ins *glsl.LayoutLocations, outs *glsl.LayoutLocations) error
+  HasDefaultPrecision(typeName string) bool
+  PackUniforms(p *glsl.VectorPacking) error
+  PackVaryings(p *glsl.VectorPacking) error
+  PackAttributes(p *glsl.VectorPacking) error
   FindExportedConst(name string) *glsl.Const
 
 	Module() glsl.Module
@@ -109,6 +119,42 @@ func (s *ShaderFileData) CollectVaryings(varyings map[string]string) error {
   return s.module.CollectVaryings(varyings)
 }
 
+func (s *ShaderFileData) CollectUniforms(uniforms map[string]string) error {
+  return s.module.CollectUniforms(uniforms)
+}
+
+func (s *ShaderFileData) CollectAttributes(attributes map[string]string) error {
+  return s.module.CollectAttributes(attributes)
+}
+
+func (s *ShaderFileData) CollectWrittenVaryings(written map[string]bool) error {
+  return s.module.CollectWrittenVaryings(written)
+}
+
+func (s *ShaderFileData) CollectVaryingContexts(ctxs map[string]context.Context) {
+  s.module.CollectVaryingContexts(ctxs)
+}
+
+func (s *ShaderFileData) HasDefaultPrecision(typeName string) bool {
+  return s.module.HasDefaultPrecision(typeName)
+}
+
+func (s *ShaderFileData) PackUniforms(p *glsl.VectorPacking) error {
+  return s.module.PackUniforms(p)
+}
+
+func (s *ShaderFileData) PackVaryings(p *glsl.VectorPacking) error {
+  return s.module.PackVaryings(p)
+}
+
+func (s *ShaderFileData) PackAttributes(p *glsl.VectorPacking) error {
+  return s.module.PackAttributes(p)
+}
+
+func (s *ShaderFileData) CollectLocations(ins *glsl.LayoutLocations, outs *glsl.LayoutLocations) error {
+  return s.module.CollectLocations(ins, outs)
+}
+
 func (s *ShaderFileData) FindExportedConst(name string) *glsl.Const {
   return nil
 }
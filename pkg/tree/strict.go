@@ -0,0 +1,376 @@
+package tree
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/computeportal/wtsuite/pkg/tokens/context"
+	tokens "github.com/computeportal/wtsuite/pkg/tokens/html"
+)
+
+// opt-in checks of the html content models and of some basic accessibility rules
+// (the content models are simplified versions of those in the html living standard)
+
+// tags that can be used where only phrasing content is allowed, custom elements are also accepted
+var phrasingTags = map[string]bool{
+	"a": true, "abbr": true, "area": true, "audio": true, "b": true, "bdi": true, "bdo": true,
+	"br": true, "button": true, "canvas": true, "cite": true, "code": true, "data": true,
+	"datalist": true, "del": true, "dfn": true, "em": true, "embed": true, "i": true,
+	"iframe": true, "img": true, "input": true, "ins": true, "kbd": true, "label": true,
+	"link": true, "map": true, "mark": true, "math": true, "meta": true, "meter": true,
+	"noscript": true, "object": true, "output": true, "picture": true, "progress": true,
+	"q": true, "ruby": true, "s": true, "samp": true, "script": true, "select": true,
+	"small": true, "span": true, "strong": true, "sub": true, "sup": true, "svg": true,
+	"template": true, "textarea": true, "time": true, "u": true, "var": true, "video": true,
+	"wbr": true,
+}
+
+// tags whose content must be phrasing content
+var phrasingOnlyTags = map[string]bool{
+	"abbr": true, "b": true, "bdi": true, "bdo": true, "cite": true, "code": true,
+	"data": true, "dfn": true, "dt": true, "em": true, "h1": true, "h2": true, "h3": true,
+	"h4": true, "h5": true, "h6": true, "i": true, "kbd": true, "label": true,
+	"legend": true, "mark": true, "meter": true, "output": true, "p": true, "pre": true,
+	"progress": true, "q": true, "s": true, "samp": true, "small": true, "span": true,
+	"strong": true, "sub": true, "summary": true, "sup": true, "time": true, "u": true,
+	"var": true, "button": true,
+}
+
+// tags that can only be used inside one of the listed tags
+var allowedParents = map[string][]string{
+	"li":         []string{"ul", "ol", "menu"},
+	"dt":         []string{"dl", "div"},
+	"dd":         []string{"dl", "div"},
+	"tr":         []string{"table", "thead", "tbody", "tfoot"},
+	"td":         []string{"tr"},
+	"th":         []string{"tr"},
+	"thead":      []string{"table"},
+	"tbody":      []string{"table"},
+	"tfoot":      []string{"table"},
+	"caption":    []string{"table"},
+	"colgroup":   []string{"table"},
+	"col":        []string{"colgroup", "table"},
+	"option":     []string{"select", "datalist", "optgroup"},
+	"optgroup":   []string{"select"},
+	"figcaption": []string{"figure"},
+	"legend":     []string{"fieldset"},
+	"summary":    []string{"details"},
+	"source":     []string{"audio", "video", "picture"},
+	"track":      []string{"audio", "video"},
+	"param":      []string{"object"},
+	"rt":         []string{"ruby"},
+	"rp":         []string{"ruby"},
+}
+
+// tags that can only contain the listed tags (and script/template), and no text
+var allowedChildren = map[string][]string{
+	"ul":       []string{"li"},
+	"ol":       []string{"li"},
+	"menu":     []string{"li"},
+	"dl":       []string{"dt", "dd", "div"},
+	"table":    []string{"caption", "colgroup", "thead", "tbody", "tfoot", "tr"},
+	"thead":    []string{"tr"},
+	"tbody":    []string{"tr"},
+	"tfoot":    []string{"tr"},
+	"tr":       []string{"td", "th"},
+	"colgroup": []string{"col"},
+	"select":   []string{"option", "optgroup", "hr"},
+	"optgroup": []string{"option"},
+}
+
+// interactive content can't be nested inside a or button
+var interactiveTags = map[string]bool{
+	"a": true, "button": true, "details": true, "embed": true, "iframe": true,
+	"input": true, "label": true, "select": true, "textarea": true,
+}
+
+// input types that don't need a label
+var unlabeledInputTypes = map[string]bool{
+	"hidden": true, "submit": true, "reset": true, "button": true, "image": true,
+}
+
+type strictState struct {
+	idMap       IDMap
+	labelFor    map[string]bool
+	prevHeading Tag
+}
+
+type strictAncestors struct {
+	phrasing    Tag // closest ancestor whose content must be phrasing content, nil if none
+	interactive Tag // closest a or button ancestor, nil if none
+	inLabel     bool
+}
+
+func isCustomTag(name string) bool {
+	return strings.Contains(name, "-")
+}
+
+func isPhrasingTag(t Tag) bool {
+	name := t.Name()
+	return name == "" || phrasingTags[name] || isCustomTag(name)
+}
+
+func isInteractiveTag(t Tag) bool {
+	if t.Name() == "input" {
+		inputType, _ := strictAttribute(t, "type")
+		return inputType != "hidden"
+	}
+
+	return interactiveTags[t.Name()]
+}
+
+// attributes that aren't strings (eg. flags) return ""
+func strictAttribute(t Tag, key string) (string, bool) {
+	attr := t.Attributes()
+	if attr == nil {
+		return "", false
+	}
+
+	token, ok := attr.Get(key)
+	if !ok || tokens.IsNull(token) {
+		return "", false
+	}
+
+	if tokens.IsString(token) {
+		str, err := tokens.AssertString(token)
+		if err == nil {
+			return str.Value(), true
+		}
+	}
+
+	return "", true
+}
+
+func headingLevel(t Tag) int {
+	name := t.Name()
+	if len(name) == 2 && name[0] == 'h' && name[1] >= '1' && name[1] <= '6' {
+		return int(name[1] - '0')
+	}
+
+	return 0
+}
+
+func isWhitespaceText(t Tag) bool {
+	text, ok := t.(*Text)
+	return ok && strings.TrimSpace(text.Value()) == ""
+}
+
+func newStrictError(t Tag, msg string, other Tag, otherMsg string) error {
+	errCtx := t.Context()
+	err := errCtx.NewError(msg)
+	if other != nil {
+		context.AppendContextString(err, otherMsg, other.Context())
+	}
+
+	return err
+}
+
+func (s *strictState) collectLabels(t Tag) error {
+	if t.Name() == "label" {
+		if forID, ok := strictAttribute(t, "for"); ok {
+			if !s.idMap.Has(forID) {
+				return newStrictError(t, "HTML Error: label for unknown id \""+forID+"\"", nil, "")
+			}
+
+			s.labelFor[forID] = true
+		}
+	}
+
+	for _, child := range t.Children() {
+		if err := s.collectLabels(child); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *strictState) validateContentModel(t Tag, parent Tag, anc strictAncestors) error {
+	name := t.Name()
+
+	if anc.phrasing != nil && !isPhrasingTag(t) {
+		return newStrictError(t, "HTML Error: "+name+" not allowed inside "+anc.phrasing.Name()+
+			" (expected phrasing content)", anc.phrasing, "Info: "+anc.phrasing.Name()+" defined here")
+	}
+
+	if anc.interactive != nil && isInteractiveTag(t) {
+		return newStrictError(t, "HTML Error: "+name+" not allowed inside "+anc.interactive.Name()+
+			" (interactive content can't be nested)", anc.interactive, "Info: "+anc.interactive.Name()+" defined here")
+	}
+
+	if parents, ok := allowedParents[name]; ok {
+		parentName := parent.Name()
+		ok := false
+		for _, p := range parents {
+			if p == parentName {
+				ok = true
+				break
+			}
+		}
+
+		if ok && parentName == "div" && (name == "dt" || name == "dd") {
+			ok = parent.Parent() != nil && parent.Parent().Name() == "dl"
+		}
+
+		if !ok {
+			return newStrictError(t, "HTML Error: "+name+" must be a child of "+strings.Join(parents, ", "),
+				parent, "Info: parent defined here")
+		}
+	}
+
+	if children, ok := allowedChildren[name]; ok {
+		for _, child := range t.Children() {
+			if isWhitespaceText(child) {
+				continue
+			}
+
+			childName := child.Name()
+			ok := childName == "script" || childName == "template"
+			for _, c := range children {
+				if c == childName {
+					ok = true
+					break
+				}
+			}
+
+			if !ok {
+				what := childName
+				if what == "" {
+					what = "text"
+				}
+
+				return newStrictError(child, "HTML Error: "+what+" not allowed inside "+name+
+					" (expected "+strings.Join(children, ", ")+")", t, "Info: "+name+" defined here")
+			}
+		}
+	}
+
+	return nil
+}
+
+func (s *strictState) validateAccessibility(t Tag, anc strictAncestors) error {
+	name := t.Name()
+
+	switch name {
+	case "img":
+		if _, ok := strictAttribute(t, "alt"); !ok {
+			return newStrictError(t, "A11y Error: img without alt attribute (use alt=\"\" for decorative images)", nil, "")
+		}
+	case "input", "select", "textarea":
+		if name == "input" {
+			inputType, _ := strictAttribute(t, "type")
+			if inputType == "image" {
+				if _, ok := strictAttribute(t, "alt"); !ok {
+					return newStrictError(t, "A11y Error: image input without alt attribute", nil, "")
+				}
+			}
+
+			if unlabeledInputTypes[inputType] {
+				break
+			}
+		}
+
+		if anc.inLabel || s.labelFor[t.GetID()] {
+			break
+		}
+
+		if _, ok := strictAttribute(t, "aria-label"); ok {
+			break
+		}
+
+		if _, ok := strictAttribute(t, "aria-labelledby"); ok {
+			break
+		}
+
+		if _, ok := strictAttribute(t, "title"); ok {
+			break
+		}
+
+		return newStrictError(t, "A11y Error: "+name+" without label (wrap it in a label, refer to it with label for=..., or use aria-label)", nil, "")
+	}
+
+	if level := headingLevel(t); level > 0 {
+		if s.prevHeading != nil {
+			prevLevel := headingLevel(s.prevHeading)
+			if level > prevLevel+1 {
+				return newStrictError(t, "A11y Error: heading level skipped (h"+strconv.Itoa(prevLevel)+
+					" followed by h"+strconv.Itoa(level)+")", s.prevHeading, "Info: previous heading")
+			}
+		}
+
+		s.prevHeading = t
+	}
+
+	return nil
+}
+
+func (s *strictState) validate(t Tag, parent Tag, anc strictAncestors) error {
+	if t.Name() == "" {
+		return nil
+	}
+
+	if err := s.validateContentModel(t, parent, anc); err != nil {
+		return err
+	}
+
+	if err := s.validateAccessibility(t, anc); err != nil {
+		return err
+	}
+
+	switch t.Name() {
+	case "svg", "math", "script", "style", "template":
+		// other content models
+		return nil
+	}
+
+	if phrasingOnlyTags[t.Name()] {
+		anc.phrasing = t
+	}
+
+	if t.Name() == "a" || t.Name() == "button" {
+		anc.interactive = t
+	}
+
+	if t.Name() == "label" {
+		anc.inLabel = true
+	}
+
+	for _, child := range t.Children() {
+		if err := s.validate(child, t, anc); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// call after Validate(), idMap must be filled by CollectIDs()
+func (t *Root) ValidateStrict(idMap IDMap) error {
+	_, html, err := t.GetDocTypeAndHTML()
+	if err != nil {
+		return err
+	}
+
+	if lang, ok := strictAttribute(html, "lang"); !ok || lang == "" {
+		return newStrictError(html, "A11y Error: html without lang attribute", nil, "")
+	}
+
+	_, body, err := html.getHeadBody()
+	if err != nil {
+		return err
+	}
+
+	s := &strictState{idMap, make(map[string]bool), nil}
+
+	if err := s.collectLabels(body); err != nil {
+		return err
+	}
+
+	for _, child := range body.Children() {
+		if err := s.validate(child, body, strictAncestors{}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
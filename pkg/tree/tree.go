@@ -10,6 +10,8 @@ var (
 
 	AUTO_LINK = false // optimally convert tags containing 'href' attribute to <a>
 
+	STRICT = false // check content models and accessibility after building the tree
+
 	VERBOSITY = 0
 )
 
@@ -1,6 +1,7 @@
 package tree
 
 import (
+	"hash/fnv"
 	"strconv"
 )
 
@@ -20,3 +21,11 @@ func NewUniqueClass() string {
 	_uclass_++
 	return res
 }
+
+// same key gives the same class in every build, so cached output stays valid
+// (separate prefix to avoid collisions with NewUniqueClass)
+func NewStableClass(key string) string {
+	h := fnv.New32a()
+	h.Write([]byte(key))
+	return "_c" + strconv.FormatUint(uint64(h.Sum32()), 36)
+}
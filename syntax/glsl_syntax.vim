@@ -28,10 +28,11 @@ syn keyword Conditional if else
 syn keyword Repeat for
 syn keyword Statement return
 syn keyword Keyword export import from as
-syn keyword Keyword attribute const highp in inout lowp mediump out precision struct uniform varying void
+syn keyword Keyword attribute const flat highp in inout layout lowp mediump out precision smooth struct uniform varying void
 
-syn keyword Type bool int float
-syn keyword Type vec2 vec3 vec4 ivec2 ivec3 ivec4 bvec2 bvec3 bvec4
+syn keyword Type bool int uint float
+syn keyword Type vec2 vec3 vec4 ivec2 ivec3 ivec4 uvec2 uvec3 uvec4 bvec2 bvec3 bvec4
+syn keyword Type sampler2D samplerCube sampler3D sampler2DArray
 syn keyword Type mat2 mat3 mat4
 
 let b:current_syntax = "glsl"
@@ -29,7 +29,7 @@ syn keyword Type Array Boolean Date Error Function Int Map Number Object Promise
 syn keyword Type Uint8Array Uint16Array Uint32Array Int32Array Float32Array Float64Array Blob
 syn keyword Type Element HTMLElement HTMLImageElement HTMLInputElement HTMLTextAreaElement HTMLCanvasElement HTMLSelectElement HTMLLinkElement HTMLIFrameElement
 syn keyword Type Event MouseEvent WheelEvent KeyboardEvent
-syn keyword Type WebGLRenderingContext WebGLProgram CanvasRenderingContext2D
+syn keyword Type WebGLRenderingContext WebGL2RenderingContext WebGLProgram CanvasRenderingContext2D
 syn keyword Type WebAssembly WebAssemblyEnv 
 syn keyword Type IDBDatabase IDBRequest IDBKeyRange IDBCursorWithValue IDBVersionChangeEvent
 syn keyword Type URL